@@ -23,13 +23,16 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/wal"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
 	"github.com/PlatONnetwork/PlatON-Go/miner"
 
@@ -37,14 +40,15 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/console"
 	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/state/pruner"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/eth/downloader"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/trie"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -158,6 +162,23 @@ The first argument must be the directory containing the blockchain to download f
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 Remove blockchain and state databases`,
+	}
+	convertdbCommand = cli.Command{
+		Action:    utils.MigrateFlags(convertDB),
+		Name:      "convertdb",
+		Usage:     "Convert the databases to another database engine",
+		ArgsUsage: "<engine>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    platon convertdb bolt
+
+works offline on the data directory of a stopped node. The chain database, the snapshotdb
+baseDB, the evidence pool, the cbft history and the wal database are rewritten with the
+engine, the databases which are stored by the engine already are skipped. Run the node
+with the same --db.engine afterwards.`,
 	}
 	dumpCommand = cli.Command{
 		Action:    utils.MigrateFlags(dump),
@@ -174,6 +195,74 @@ Remove blockchain and state databases`,
 The arguments are interpreted as block numbers or hashes.
 Use "ethereum dump 0" to dump the genesis block.`,
 	}
+	pruneStateBlocksFlag = cli.Uint64Flag{
+		Name:  "blocks",
+		Usage: "Number of the recent blocks of which the states are retained",
+		Value: 128,
+	}
+	pruneStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(pruneState),
+		Name:      "prune-state",
+		Usage:     "Delete the stale states from the chain database",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+			pruneStateBlocksFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+    platon prune-state --blocks 128
+
+works offline on the chain database of a stopped node. The state trie nodes, contract
+codes and storage values which are not reachable from the genesis state or the states
+of the recent blocks are deleted, the target block is the head minus the blocks.
+The PPOS data in the snapshotdb is not touched.
+
+The pruning is resumable, an interrupted pruning is resumed with its original
+target when the command is run again.`,
+	}
+	ancientCommand = cli.Command{
+		Name:      "ancient",
+		Usage:     "Inspect and migrate the ancient store of the old blocks",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The ancient store holds the canonical blocks which are older than --db.ancient_threshold
+in append-only flat files of <datadir>/platon/chaindata/ancient, the blocks are read from
+it transparently. The genesis block is always kept in leveldb. The ancient store is
+disabled by default, a node moves the blocks only with a positive --db.ancient_threshold.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "inspect",
+				Usage:     "Print the number of the frozen blocks",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(ancientInspect),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.SyncModeFlag,
+				},
+			},
+			{
+				Name:      "migrate",
+				Usage:     "Move the old blocks out of leveldb into the ancient store",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(ancientMigrate),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.SyncModeFlag,
+					utils.DBAncientThresholdFlag,
+				},
+				Description: `
+    platon ancient migrate --db.ancient_threshold 4096
+
+works offline on the chain database of a stopped node. The headers, bodies and receipts
+of the canonical blocks which are older than the threshold are moved out of leveldb at
+once and leveldb is compacted, a running node moves them in the background.`,
+			},
+		},
+	}
 )
 
 // initGenesis will initialise the given JSON format genesis file and writes it as
@@ -220,6 +309,11 @@ func initGenesis(ctx *cli.Context) error {
 		var spath string
 		if name == "chaindata" {
 			spath = stack.ResolvePath(snapshotdb.DBPath)
+			if db, ok := chaindb.(ethdb.Store); ok && common.FileExist(filepath.Join(db.Path(), "ancient")) {
+				if chaindb, err = rawdb.NewDatabaseWithFreezer(db, filepath.Join(db.Path(), "ancient"), 0); err != nil {
+					utils.Fatalf("Failed to open ancient database: %v", err)
+				}
+			}
 		}
 		_, hash, err := core.SetupGenesisBlock(chaindb, spath, genesis)
 		if err != nil {
@@ -308,19 +402,21 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*ethdb.LDBDatabase)
-
-	stats, err := db.LDB().GetProperty("leveldb.stats")
-	if err != nil {
-		utils.Fatalf("Failed to read database stats: %v", err)
-	}
-	fmt.Println(stats)
-
-	ioStats, err := db.LDB().GetProperty("leveldb.iostats")
-	if err != nil {
-		utils.Fatalf("Failed to read database iostats: %v", err)
+	db := chainDb.(ethdb.Store)
+	properties := []string{"leveldb.stats", "leveldb.iostats"}
+	if db.Engine() == ethdb.EngineBolt {
+		properties = []string{"bolt.stats"}
+	}
+	printStats := func() {
+		for _, property := range properties {
+			stats, err := db.Stat(property)
+			if err != nil {
+				utils.Fatalf("Failed to read database %s: %v", property, err)
+			}
+			fmt.Println(stats)
+		}
 	}
-	fmt.Println(ioStats)
+	printStats()
 
 	fmt.Printf("Trie cache misses:  %d\n", trie.CacheMisses())
 	fmt.Printf("Trie cache unloads: %d\n\n", trie.CacheUnloads())
@@ -341,23 +437,15 @@ func importChain(ctx *cli.Context) error {
 	// Compact the entire database to more accurately measure disk io and print the stats
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = db.LDB().CompactRange(util.Range{}); err != nil {
+	if err := db.Compact(nil, nil); err == ethdb.ErrCompactionNotSupported {
+		fmt.Println("Compaction skipped, not supported by the database engine.")
+	} else if err != nil {
 		utils.Fatalf("Compaction failed: %v", err)
+	} else {
+		fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 	}
-	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
-
-	stats, err = db.LDB().GetProperty("leveldb.stats")
-	if err != nil {
-		utils.Fatalf("Failed to read database stats: %v", err)
-	}
-	fmt.Println(stats)
-
-	ioStats, err = db.LDB().GetProperty("leveldb.iostats")
-	if err != nil {
-		utils.Fatalf("Failed to read database iostats: %v", err)
-	}
-	fmt.Println(ioStats)
 
+	printStats()
 	return nil
 }
 
@@ -399,7 +487,7 @@ func importPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := utils.MakeChainDatabase(ctx, stack)
 
 	start := time.Now()
 	if err := utils.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -415,7 +503,7 @@ func exportPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := utils.MakeChainDatabase(ctx, stack).(ethdb.Store)
 
 	start := time.Now()
 	if err := utils.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -439,7 +527,7 @@ func copyDb(ctx *cli.Context) error {
 
 	dl := downloader.New(syncmode, chainDb, localSnapshotDB, new(event.TypeMux), chain, nil, nil, nil)
 	// Create a source peer to satisfy downloader requests from
-	db, err := ethdb.NewLDBDatabase(ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256)
+	db, err := ethdb.OpenStore("", ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256)
 	if err != nil {
 		return err
 	}
@@ -470,10 +558,13 @@ func copyDb(ctx *cli.Context) error {
 	// Compact the entire database to remove any sync overhead
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = chainDb.(*ethdb.LDBDatabase).LDB().CompactRange(util.Range{}); err != nil {
+	if err = chainDb.(ethdb.Store).Compact(nil, nil); err == ethdb.ErrCompactionNotSupported {
+		fmt.Println("Compaction skipped, not supported by the database engine.")
+	} else if err != nil {
 		utils.Fatalf("Compaction failed: %v", err)
+	} else {
+		fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 	}
-	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 	localSnapshotDB.Close()
 	return nil
 }
@@ -507,6 +598,46 @@ func removeDB(ctx *cli.Context) error {
 	return nil
 }
 
+func convertDB(ctx *cli.Context) error {
+	engine := ctx.Args().First()
+	if engine == "" {
+		utils.Fatalf("Must supply the target engine, one of: %s", strings.Join(ethdb.Engines, ", "))
+	}
+	if err := ethdb.CheckEngine(engine); err != nil {
+		utils.Fatalf("%v", err)
+	}
+	stack, cfg := makeConfigNode(ctx)
+
+	dirs := []string{
+		stack.ResolvePath("chaindata"),
+		filepath.Join(stack.ResolvePath(snapshotdb.DBPath), snapshotdb.DBBasePath),
+		stack.ResolvePath(cfg.Eth.CbftConfig.EvidenceDir),
+		wal.MetaPath(stack.ResolvePath("wal")),
+	}
+	if cfg.Eth.CbftConfig.HistoryDir != "" {
+		dirs = append(dirs, stack.ResolvePath(cfg.Eth.CbftConfig.HistoryDir))
+	}
+	for _, dir := range dirs {
+		logger := log.New("database", dir)
+
+		switch existing := ethdb.DetectEngine(dir); existing {
+		case "":
+			logger.Info("Database doesn't exist, skipping")
+			continue
+		case engine:
+			logger.Info("Database is stored by the engine already, skipping", "engine", engine)
+			continue
+		}
+		start := time.Now()
+		copied, err := ethdb.ConvertStore(dir, engine)
+		if err != nil {
+			utils.Fatalf("Failed to convert database %s: %v", dir, err)
+		}
+		logger.Info("Database successfully converted", "engine", engine, "items", copied, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	return nil
+}
+
 func dump(ctx *cli.Context) error {
 	stack := makeFullNode(ctx)
 	chain, chainDb := utils.MakeChain(ctx, stack)
@@ -533,6 +664,56 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+func pruneState(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	db, ok := chainDb.(pruner.Database)
+	if !ok {
+		utils.Fatalf("The chain database doesn't support pruning")
+	}
+	if err := pruner.NewPruner(db).Prune(ctx.Uint64(pruneStateBlocksFlag.Name)); err != nil {
+		utils.Fatalf("Failed to prune state: %v", err)
+	}
+	return nil
+}
+
+func ancientInspect(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	reader, ok := chainDb.(rawdb.AncientReader)
+	if !ok {
+		utils.Fatalf("The chain database has no ancient store")
+	}
+	frozen, _ := reader.Ancients()
+	fmt.Println("Frozen blocks:", frozen)
+	if head := rawdb.ReadHeaderNumber(chainDb, rawdb.ReadHeadBlockHash(chainDb)); head != nil {
+		fmt.Println("Head block:", *head)
+	}
+	return nil
+}
+
+func ancientMigrate(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	threshold := ctx.GlobalUint64(utils.DBAncientThresholdFlag.Name)
+	if threshold == 0 {
+		utils.Fatalf("The ancient threshold must be positive")
+	}
+	start := time.Now()
+	frozen, err := rawdb.FreezeAncient(chainDb, threshold)
+	if err != nil {
+		utils.Fatalf("Failed to migrate the ancient blocks: %v", err)
+	}
+	fmt.Printf("Migrated %d blocks in %v\n", frozen, time.Since(start))
+	return nil
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
@@ -74,11 +74,13 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolGlobalTxCountFlag,
+		utils.TxPoolPrioritySlotsFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.SyncModeFlag,
 		utils.GCModeFlag,
 		utils.LightServFlag,
 		utils.LightPeersFlag,
+		utils.LightQCFlag,
 		utils.LightKDFFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
@@ -160,14 +162,18 @@ var (
 		utils.CbftMaxPingLatency,
 		utils.CbftBlsPriKeyFileFlag,
 		utils.CbftBlacklistDeadlineFlag,
+		utils.CbftEvidenceReporterFlag,
 	}
 
 	dbFlags = []cli.Flag{
+		utils.DBEngineFlag,
 		utils.DBNoGCFlag,
 		utils.DBGCIntervalFlag,
 		utils.DBGCTimeoutFlag,
 		utils.DBGCMptFlag,
 		utils.DBGCBlockFlag,
+		utils.DBSnapshotArchiveFlag,
+		utils.DBAncientThresholdFlag,
 	}
 )
 
@@ -185,7 +191,10 @@ func init() {
 		exportPreimagesCommand,
 		copydbCommand,
 		removedbCommand,
+		convertdbCommand,
 		dumpCommand,
+		pruneStateCommand,
+		ancientCommand,
 		// See monitorcmd.go:
 		monitorCommand,
 		// See accountcmd.go:
@@ -198,6 +207,10 @@ func init() {
 		versionCommand,
 		bugCommand,
 		licenseCommand,
+		// See walcmd.go
+		walCommand,
+		// See snapshotdbcmd.go
+		snapshotdbCommand,
 		// See config.go
 		dumpConfigCommand,
 	}
@@ -0,0 +1,275 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of PlatON-Go.
+//
+// PlatON-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// PlatON-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with PlatON-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PlatONnetwork/PlatON-Go/cmd/utils"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/console"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/node"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+)
+
+var (
+	snapshotdbPathFlag = cli.StringFlag{
+		Name:  "snapshotdb.path",
+		Usage: "Path of the snapshotdb directory (default = <datadir>/platon/snapshotdb)",
+	}
+	snapshotdbToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "The committed block number to rollback to",
+	}
+	snapshotdbYesFlag = cli.BoolFlag{
+		Name:  "yes",
+		Usage: "Rollback the journals without confirmation",
+	}
+
+	snapshotdbCommand = cli.Command{
+		Name:      "snapshotdb",
+		Usage:     "Inspect and repair the snapshotdb journals",
+		ArgsUsage: "",
+		Category:  "MISCELLANEOUS COMMANDS",
+		Description: `
+The snapshotdb commands work offline on the snapshotdb directory of a stopped node,
+they are used to recover a node without wiping and resyncing it.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "inspect",
+				Usage:     "Print the current pointers and the journals",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(snapshotdbInspect),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					snapshotdbPathFlag,
+				},
+				Description: `
+    platon snapshotdb inspect
+
+prints the current base and highest, the committed journals which are loaded
+at startup and the stale journals which are removed at startup.`,
+			},
+			{
+				Name:      "verify",
+				Usage:     "Verify the journals against the chain",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(snapshotdbVerify),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					snapshotdbPathFlag,
+				},
+				Description: `
+    platon snapshotdb verify
+
+checks the current pointers and every committed journal against the canonical chain:
+the block hash and parent hash of the journal must match the header, the records of
+the journal are replayed to the kv hash, which must match the kv hash of the journal
+and the ppos hash stored in the state of the block. The journals written by the older
+versions keep the records in the key order, their records can't be replayed.`,
+			},
+			{
+				Name:      "rollback",
+				Usage:     "Discard the journals above a committed block",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(snapshotdbRollback),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					snapshotdbPathFlag,
+					snapshotdbToFlag,
+					snapshotdbYesFlag,
+				},
+				Description: `
+    platon snapshotdb rollback --to 1000
+
+removes the journals above the block and moves the current highest to it.
+The block can't be lower than the current base, the discarded blocks are
+executed again from the chain when the node is restarted.`,
+			},
+		},
+	}
+)
+
+// snapshotdbPath retrieves the snapshotdb directory of the flags.
+func snapshotdbPath(ctx *cli.Context, stack *node.Node) string {
+	if path := ctx.String(snapshotdbPathFlag.Name); path != "" {
+		return path
+	}
+	if stack == nil {
+		stack, _ = makeConfigNode(ctx)
+	}
+	return stack.ResolvePath(snapshotdb.DBPath)
+}
+
+func inspectSnapshotDB(path string) *snapshotdb.DBInfo {
+	info, err := snapshotdb.Inspect(path)
+	if err != nil {
+		utils.Fatalf("Failed to inspect the snapshotdb: %v", err)
+	}
+	return info
+}
+
+func printJournals(journals []*snapshotdb.JournalInfo) {
+	for _, j := range journals {
+		if j.Err != nil {
+			fmt.Printf("  %d\thash:%s\tstatus:%v\n", j.Number, j.BlockHash.TerminalString(), j.Err)
+			continue
+		}
+		fmt.Printf("  %d\thash:%s\tparent:%s\tkvHash:%s\tkvs:%d\n", j.Number, j.BlockHash.TerminalString(),
+			j.ParentHash.TerminalString(), j.KvHash.TerminalString(), j.KVs)
+	}
+}
+
+func snapshotdbInspect(ctx *cli.Context) error {
+	path := snapshotdbPath(ctx, nil)
+	info := inspectSnapshotDB(path)
+
+	fmt.Println("Snapshotdb directory:", path)
+	fmt.Println("Current base:", info.Base)
+	fmt.Printf("Current highest: %d, hash:%s\n", info.Highest, info.HighestHash.String())
+	fmt.Printf("Committed journals: %d\n", len(info.Committed))
+	printJournals(info.Committed)
+	fmt.Printf("Stale journals: %d\n", len(info.Stale))
+	printJournals(info.Stale)
+	return nil
+}
+
+func snapshotdbVerify(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	info := inspectSnapshotDB(snapshotdbPath(ctx, stack))
+
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+	stateDb := state.NewDatabase(chainDb)
+
+	failed := 0
+	report := func(format string, args ...interface{}) {
+		failed++
+		fmt.Printf(format+"\n", args...)
+	}
+
+	headNumber := rawdb.ReadHeaderNumber(chainDb, rawdb.ReadHeadBlockHash(chainDb))
+	if headNumber == nil {
+		utils.Fatalf("Failed to read the head block of the chain")
+	}
+	fmt.Printf("Current base: %d, highest: %d, chain head: %d\n", info.Base, info.Highest, *headNumber)
+	if info.Base > *headNumber {
+		report("Current base %d is greater than the chain head %d", info.Base, *headNumber)
+	}
+	if info.Highest > *headNumber {
+		fmt.Printf("Current highest %d is greater than the chain head %d, it's reset to the chain head at startup\n", info.Highest, *headNumber)
+	} else if info.Highest > info.Base && rawdb.ReadCanonicalHash(chainDb, info.Highest) != info.HighestHash {
+		report("Current highest %d hash %s is not canonical", info.Highest, info.HighestHash.String())
+	}
+
+	next := info.Base + 1
+	for _, j := range info.Committed {
+		if j.Number != next {
+			report("Journal %d ~ %d: missing", next, j.Number-1)
+		}
+		next = j.Number + 1
+		if j.Err != nil {
+			report("Journal %d: broken, %v", j.Number, j.Err)
+			continue
+		}
+		if j.Number > *headNumber {
+			continue
+		}
+		if hash := rawdb.ReadCanonicalHash(chainDb, j.Number); hash != j.BlockHash {
+			report("Journal %d: block hash %s is not canonical, want %s", j.Number, j.BlockHash.String(), hash.String())
+			continue
+		}
+		header := rawdb.ReadHeader(chainDb, j.BlockHash, j.Number)
+		if header == nil {
+			report("Journal %d: header %s is missing", j.Number, j.BlockHash.String())
+			continue
+		}
+		if header.ParentHash != j.ParentHash {
+			report("Journal %d: parent hash %s mismatch, want %s", j.Number, j.ParentHash.String(), header.ParentHash.String())
+			continue
+		}
+		// the records of the older journals are in the key order, they are replayed to another
+		// hash unless the block put them in that order
+		replayed := j.ReplayedHash == j.KvHash
+		if !replayed && !j.KeyOrdered {
+			report("Journal %d: replayed kv hash %s mismatch, want %s", j.Number, j.ReplayedHash.String(), j.KvHash.String())
+			continue
+		}
+		// the ppos hash is stored in the state only if the block modifies the snapshotdb
+		if j.KvHash == common.ZeroHash {
+			fmt.Printf("Journal %d: ok\n", j.Number)
+			continue
+		}
+		statedb, err := state.New(header.Root, stateDb)
+		if err != nil {
+			fmt.Printf("Journal %d: ppos hash unchecked, the state is unavailable\n", j.Number)
+			continue
+		}
+		pposHash := statedb.GetState(cvm.StakingContractAddr, staking.GetPPOSHASHKey())
+		if !bytes.Equal(pposHash, j.KvHash.Bytes()) {
+			report("Journal %d: kv hash %s mismatch, want the ppos hash %x", j.Number, j.KvHash.String(), pposHash)
+			continue
+		}
+		if !replayed {
+			fmt.Printf("Journal %d: ok, the records in the key order are not replayed\n", j.Number)
+			continue
+		}
+		fmt.Printf("Journal %d: ok\n", j.Number)
+	}
+	if next <= info.Highest {
+		report("Journal %d ~ %d: missing", next, info.Highest)
+	}
+
+	if failed > 0 {
+		utils.Fatalf("%d problems found, run `platon snapshotdb rollback` to discard the journals above the last valid block", failed)
+	}
+	fmt.Println("Snapshotdb is consistent with the chain")
+	return nil
+}
+
+func snapshotdbRollback(ctx *cli.Context) error {
+	if !ctx.IsSet(snapshotdbToFlag.Name) {
+		utils.Fatalf("The block number to rollback to is required (--%s)", snapshotdbToFlag.Name)
+	}
+	var (
+		path = snapshotdbPath(ctx, nil)
+		to   = ctx.Uint64(snapshotdbToFlag.Name)
+		info = inspectSnapshotDB(path)
+	)
+	if !ctx.Bool(snapshotdbYesFlag.Name) {
+		confirm, err := console.Stdin.PromptConfirm(fmt.Sprintf("Rollback the snapshotdb from %d to %d?", info.Highest, to))
+		if err != nil {
+			utils.Fatalf("%v", err)
+		}
+		if !confirm {
+			return nil
+		}
+	}
+	removed, err := snapshotdb.Rollback(path, to)
+	if err != nil {
+		utils.Fatalf("Failed to rollback the snapshotdb: %v", err)
+	}
+	fmt.Printf("Rolled back the snapshotdb to %d, %d journals removed\n", to, removed)
+	return nil
+}
@@ -79,6 +79,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.IdentityFlag,
 			utils.LightServFlag,
 			utils.LightPeersFlag,
+			utils.LightQCFlag,
 			utils.LightKDFFlag,
 		},
 	},
@@ -112,6 +113,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolGlobalTxCountFlag,
+			utils.TxPoolPrioritySlotsFlag,
 			utils.TxPoolLifetimeFlag,
 		},
 	},
@@ -240,16 +242,20 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.CbftMaxPingLatency,
 			utils.CbftBlsPriKeyFileFlag,
 			utils.CbftBlacklistDeadlineFlag,
+			utils.CbftEvidenceReporterFlag,
 		},
 	},
 	{
 		Name: "DB",
 		Flags: []cli.Flag{
+			utils.DBEngineFlag,
 			utils.DBNoGCFlag,
 			utils.DBGCIntervalFlag,
 			utils.DBGCTimeoutFlag,
 			utils.DBGCMptFlag,
 			utils.DBGCBlockFlag,
+			utils.DBSnapshotArchiveFlag,
+			utils.DBAncientThresholdFlag,
 		},
 	},
 	{
@@ -0,0 +1,381 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of PlatON-Go.
+//
+// PlatON-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// PlatON-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with PlatON-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PlatONnetwork/PlatON-Go/cmd/utils"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/protocols"
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/wal"
+	"github.com/PlatONnetwork/PlatON-Go/console"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+)
+
+var (
+	walPathFlag = cli.StringFlag{
+		Name:  "wal.path",
+		Usage: "Path of the wal directory (default = <datadir>/platon/wal)",
+	}
+	walFileFlag = cli.Uint64Flag{
+		Name:  "wal.file",
+		Usage: "Only handle the journal file with the specified fileID (0 = all)",
+	}
+	walEpochFromFlag = cli.Uint64Flag{
+		Name:  "epoch.from",
+		Usage: "Dump the messages whose epoch is not less than the value",
+	}
+	walEpochToFlag = cli.Uint64Flag{
+		Name:  "epoch.to",
+		Usage: "Dump the messages whose epoch is not greater than the value (0 = unlimited)",
+	}
+	walViewFromFlag = cli.Uint64Flag{
+		Name:  "view.from",
+		Usage: "Dump the messages whose viewNumber is not less than the value",
+	}
+	walViewToFlag = cli.Uint64Flag{
+		Name:  "view.to",
+		Usage: "Dump the messages whose viewNumber is not greater than the value (0 = unlimited)",
+	}
+	walMsgTypeFlag = cli.StringFlag{
+		Name:  "type",
+		Usage: "Dump the messages of the type: prepareBlock, prepareVote, viewChange or confirmedViewChange",
+	}
+	walYesFlag = cli.BoolFlag{
+		Name:  "yes",
+		Usage: "Truncate the corrupted journal files without confirmation",
+	}
+
+	walCommand = cli.Command{
+		Name:      "wal",
+		Usage:     "Inspect and repair the cbft write-ahead log",
+		ArgsUsage: "",
+		Category:  "MISCELLANEOUS COMMANDS",
+		Description: `
+The wal commands work offline on the wal directory of a stopped node,
+they are used to diagnose a node which can't restart after a crash.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "list",
+				Usage:     "List the journal files and the wal meta",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(walList),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walPathFlag,
+				},
+				Description: `
+    platon wal list
+
+prints the journal files with the number of valid entries, the position
+where the journal loading starts from and the persisted chain state.`,
+			},
+			{
+				Name:      "dump",
+				Usage:     "Dump the journal messages as JSON",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(walDump),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walPathFlag,
+					walFileFlag,
+					walEpochFromFlag,
+					walEpochToFlag,
+					walViewFromFlag,
+					walViewToFlag,
+					walMsgTypeFlag,
+				},
+				Description: `
+    platon wal dump --epoch.from 2 --view.from 10 --view.to 12
+
+prints one JSON object per line for each decoded message of the journal files.
+The dump of a file stops at its first corrupted entry.`,
+			},
+			{
+				Name:      "verify",
+				Usage:     "Verify the checksums of the journal files",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(walVerify),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walPathFlag,
+					walFileFlag,
+				},
+				Description: `
+    platon wal verify
+
+checks the crc and decodes every entry of the journal files,
+and fails if any of them is corrupted.`,
+			},
+			{
+				Name:      "truncate",
+				Usage:     "Truncate the corrupted tail of the journal files",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(walTruncate),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walPathFlag,
+					walFileFlag,
+					walYesFlag,
+				},
+				Description: `
+    platon wal truncate
+
+drops everything starting from the first corrupted entry of each journal file,
+the valid messages before it are kept and can be loaded by cbft at startup.`,
+			},
+		},
+	}
+)
+
+// walEntry is the JSON form of the journal message.
+type walEntry struct {
+	FileID      uint32      `json:"fileId"`
+	Seq         uint64      `json:"seq"`
+	Timestamp   uint64      `json:"timestamp"`
+	Type        string      `json:"type"`
+	Epoch       uint64      `json:"epoch"`
+	ViewNumber  uint64      `json:"viewNumber"`
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	Msg         interface{} `json:"msg"`
+}
+
+// walPrepareBlock replaces the block of PrepareBlock with its header.
+type walPrepareBlock struct {
+	Epoch         uint64               `json:"epoch"`
+	ViewNumber    uint64               `json:"viewNumber"`
+	Header        *types.Header        `json:"header"`
+	BlockIndex    uint32               `json:"blockIndex"`
+	ProposalIndex uint32               `json:"proposalIndex"`
+	PrepareQC     *ctypes.QuorumCert   `json:"prepareQC"`
+	ViewChangeQC  *ctypes.ViewChangeQC `json:"viewchangeQC"`
+	Signature     ctypes.Signature     `json:"signature"`
+}
+
+// walConfirmedViewChange replaces the block of ConfirmedViewChange with its header.
+type walConfirmedViewChange struct {
+	Epoch        uint64               `json:"epoch"`
+	ViewNumber   uint64               `json:"viewNumber"`
+	Header       *types.Header        `json:"header"`
+	QC           *ctypes.QuorumCert   `json:"qc"`
+	ViewChangeQC *ctypes.ViewChangeQC `json:"viewchangeQC"`
+}
+
+var walMsgTypes = map[uint16]string{
+	protocols.ConfirmedViewChangeMsg: "confirmedViewChange",
+	protocols.SendViewChangeMsg:      "viewChange",
+	protocols.SendPrepareBlockMsg:    "prepareBlock",
+	protocols.SendPrepareVoteMsg:     "prepareVote",
+}
+
+func newWalEntry(entry *wal.JournalEntry) *walEntry {
+	e := &walEntry{
+		FileID:    entry.FileID,
+		Seq:       entry.Seq,
+		Timestamp: entry.Timestamp,
+		Type:      walMsgTypes[entry.MsgType],
+	}
+	switch m := entry.Msg.(type) {
+	case *protocols.ConfirmedViewChange:
+		e.Epoch, e.ViewNumber = m.Epoch, m.ViewNumber
+		msg := &walConfirmedViewChange{
+			Epoch:        m.Epoch,
+			ViewNumber:   m.ViewNumber,
+			QC:           m.QC,
+			ViewChangeQC: m.ViewChangeQC,
+		}
+		if m.Block != nil {
+			e.BlockNumber, e.BlockHash = m.Block.NumberU64(), m.Block.Hash()
+			msg.Header = m.Block.Header()
+		}
+		e.Msg = msg
+	case *protocols.SendViewChange:
+		e.Epoch, e.ViewNumber = m.ViewChange.Epoch, m.ViewChange.ViewNumber
+		e.BlockNumber, e.BlockHash = m.ViewChange.BlockNumber, m.ViewChange.BlockHash
+		e.Msg = m.ViewChange
+	case *protocols.SendPrepareBlock:
+		pb := m.Prepare
+		e.Epoch, e.ViewNumber = pb.Epoch, pb.ViewNumber
+		e.BlockNumber, e.BlockHash = pb.Block.NumberU64(), pb.Block.Hash()
+		e.Msg = &walPrepareBlock{
+			Epoch:         pb.Epoch,
+			ViewNumber:    pb.ViewNumber,
+			Header:        pb.Block.Header(),
+			BlockIndex:    pb.BlockIndex,
+			ProposalIndex: pb.ProposalIndex,
+			PrepareQC:     pb.PrepareQC,
+			ViewChangeQC:  pb.ViewChangeQC,
+			Signature:     pb.Signature,
+		}
+	case *protocols.SendPrepareVote:
+		e.Epoch, e.ViewNumber = m.Vote.Epoch, m.Vote.ViewNumber
+		e.BlockNumber, e.BlockHash = m.Vote.BlockNumber, m.Vote.BlockHash
+		e.Msg = m.Vote
+	}
+	return e
+}
+
+// walPath retrieves the wal directory of the flags.
+func walPath(ctx *cli.Context) string {
+	if path := ctx.String(walPathFlag.Name); path != "" {
+		return path
+	}
+	stack, _ := makeConfigNode(ctx)
+	return stack.ResolvePath("wal")
+}
+
+// walJournalFiles retrieves the journal files to be handled.
+func walJournalFiles(ctx *cli.Context, path string) []*wal.JournalFile {
+	files, err := wal.ListJournalFiles(path)
+	if err != nil {
+		utils.Fatalf("Failed to list the journal files: %v", err)
+	}
+	fileID := ctx.Uint64(walFileFlag.Name)
+	if fileID == 0 {
+		return files
+	}
+	for _, f := range files {
+		if uint64(f.FileID) == fileID {
+			return []*wal.JournalFile{f}
+		}
+	}
+	utils.Fatalf("Journal file wal.%d doesn't exist", fileID)
+	return nil
+}
+
+func walList(ctx *cli.Context) error {
+	path := walPath(ctx)
+	fmt.Println("Wal directory:", path)
+	for _, f := range walJournalFiles(ctx, path) {
+		count, err := wal.VerifyJournal(path, f.FileID)
+		status := "ok"
+		if err != nil {
+			status = err.Error()
+		}
+		fmt.Printf("%s\tsize:%d\tentries:%d\tstatus:%s\n", f.Name, f.Size, count, status)
+	}
+
+	meta, err := wal.ReadWalMeta(path)
+	if err != nil {
+		fmt.Println("Wal meta: unavailable,", err)
+		return nil
+	}
+	if vc := meta.ViewChange; vc != nil {
+		fmt.Printf("View change: epoch:%d, viewNumber:%d, fileID:%d, seq:%d\n", vc.Epoch, vc.ViewNumber, vc.FileID, vc.Seq)
+	}
+	if cs := meta.ChainState; cs != nil {
+		fmt.Println("Chain state:", cs.String())
+	}
+	for _, qc := range meta.ViewChangeQCs {
+		fmt.Println("View change QC:", qc.String())
+	}
+	return nil
+}
+
+func walDump(ctx *cli.Context) error {
+	var (
+		path      = walPath(ctx)
+		epochFrom = ctx.Uint64(walEpochFromFlag.Name)
+		epochTo   = ctx.Uint64(walEpochToFlag.Name)
+		viewFrom  = ctx.Uint64(walViewFromFlag.Name)
+		viewTo    = ctx.Uint64(walViewToFlag.Name)
+		msgType   = ctx.String(walMsgTypeFlag.Name)
+		encoder   = json.NewEncoder(os.Stdout)
+	)
+	if msgType != "" {
+		valid := false
+		for _, t := range walMsgTypes {
+			valid = valid || t == msgType
+		}
+		if !valid {
+			utils.Fatalf("Invalid message type: %s", msgType)
+		}
+	}
+	inRange := func(v, from, to uint64) bool {
+		return v >= from && (to == 0 || v <= to)
+	}
+
+	for _, f := range walJournalFiles(ctx, path) {
+		err := wal.ScanJournal(path, f.FileID, func(entry *wal.JournalEntry) error {
+			e := newWalEntry(entry)
+			if (msgType != "" && e.Type != msgType) || !inRange(e.Epoch, epochFrom, epochTo) || !inRange(e.ViewNumber, viewFrom, viewTo) {
+				return nil
+			}
+			return encoder.Encode(e)
+		})
+		if _, corrupted := err.(*wal.JournalCorruption); corrupted {
+			fmt.Fprintln(os.Stderr, err)
+		} else if err != nil {
+			utils.Fatalf("Failed to dump the journal file %s: %v", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func walVerify(ctx *cli.Context) error {
+	path := walPath(ctx)
+	failed := 0
+	for _, f := range walJournalFiles(ctx, path) {
+		count, err := wal.VerifyJournal(path, f.FileID)
+		if err != nil {
+			failed++
+			fmt.Printf("%s: %d valid entries, %v\n", f.Name, count, err)
+			continue
+		}
+		fmt.Printf("%s: %d valid entries\n", f.Name, count)
+	}
+	if failed > 0 {
+		utils.Fatalf("%d journal files are corrupted, run `platon wal truncate` to drop the corrupted tail", failed)
+	}
+	return nil
+}
+
+func walTruncate(ctx *cli.Context) error {
+	path := walPath(ctx)
+	for _, f := range walJournalFiles(ctx, path) {
+		_, err := wal.VerifyJournal(path, f.FileID)
+		if err == nil {
+			continue
+		}
+		corruption, ok := err.(*wal.JournalCorruption)
+		if !ok {
+			utils.Fatalf("Failed to verify the journal file %s: %v", f.Name, err)
+		}
+		fmt.Println(corruption)
+		if !ctx.Bool(walYesFlag.Name) {
+			confirm, err := console.Stdin.PromptConfirm(fmt.Sprintf("Truncate %s from %d to %d bytes?", f.Name, f.Size, corruption.Seq))
+			if err != nil {
+				utils.Fatalf("%v", err)
+			}
+			if !confirm {
+				continue
+			}
+		}
+		if err := wal.TruncateJournal(path, f.FileID, corruption.Seq); err != nil {
+			utils.Fatalf("Failed to truncate the journal file %s: %v", f.Name, err)
+		}
+		fmt.Printf("Truncated %s to %d bytes\n", f.Name, corruption.Seq)
+	}
+	return nil
+}
@@ -40,6 +40,7 @@ type Ppos_1000 struct {
 	ProgramVersionSign common.VersionSign
 	BlsPubKey          bls.PublicKeyHex
 	BlsProof           bls.SchnorrProofHex
+	RewardPer          uint16
 }
 
 // editorCandidate
@@ -50,6 +51,7 @@ type Ppos_1001 struct {
 	NodeName       string
 	Website        string
 	Details        string
+	RewardPer      uint16
 }
 
 // increaseStaking
@@ -78,6 +80,38 @@ type Ppos_1005 struct {
 	Amount          *big.Int
 }
 
+// redelegate
+type Ppos_1007 struct {
+	StakingBlockNum uint64
+	NodeId          discover.NodeID
+	DstNodeId       discover.NodeID
+	Amount          *big.Int
+}
+
+// unjail
+type Ppos_1008 struct {
+	NodeId discover.NodeID
+}
+
+// setStakingController
+type Ppos_1009 struct {
+	NodeId    discover.NodeID
+	Operators []common.Address
+	Threshold uint32
+}
+
+// approveStakingAction
+type Ppos_1010 struct {
+	NodeId   discover.NodeID
+	ActionId common.Hash
+}
+
+// cancelStakingAction
+type Ppos_1011 struct {
+	NodeId   discover.NodeID
+	ActionId common.Hash
+}
+
 // getRelatedListByDelAddr
 type Ppos_1103 struct {
 	Addr common.Address
@@ -95,6 +129,22 @@ type Ppos_1105 struct {
 	NodeId discover.NodeID
 }
 
+// getDelegateReward
+type Ppos_1106 struct {
+	Addr    common.Address
+	NodeIds []discover.NodeID
+}
+
+// getStakingController
+type Ppos_1107 struct {
+	NodeId discover.NodeID
+}
+
+// getPendingActions
+type Ppos_1108 struct {
+	NodeId discover.NodeID
+}
+
 // submitText
 type Ppos_2000 struct {
 	Verifier discover.NodeID
@@ -127,6 +177,24 @@ type Ppos_2005 struct {
 	TobeCanceled    common.Hash
 }
 
+// submitSpend
+type Ppos_2006 struct {
+	Verifier  discover.NodeID
+	PIPID     string
+	Source    common.Address
+	Recipient common.Address
+	Amount    *big.Int
+	Plans     []restricting.RestrictingPlan
+}
+
+// delegatorVote
+type Ppos_2007 struct {
+	ProposalID      common.Hash
+	NodeId          discover.NodeID
+	StakingBlockNum uint64
+	Option          uint8
+}
+
 // vote
 type Ppos_2003 struct {
 	Verifier       discover.NodeID
@@ -204,6 +272,16 @@ type Ppos_4000 struct {
 	Plans   []restricting.RestrictingPlan
 }
 
+// RevokeRestrictingPlan
+type Ppos_4001 struct {
+	Account common.Address
+}
+
+// TransferRestrictingPlan
+type Ppos_4002 struct {
+	To common.Address
+}
+
 // GetRestrictingInfo
 type Ppos_4100 struct {
 	Account common.Address
@@ -216,13 +294,23 @@ type decDataConfig struct {
 	P1003  Ppos_1003
 	P1004  Ppos_1004
 	P1005  Ppos_1005
+	P1007  Ppos_1007
+	P1008  Ppos_1008
+	P1009  Ppos_1009
+	P1010  Ppos_1010
+	P1011  Ppos_1011
 	P1103  Ppos_1103
 	P1104  Ppos_1104
 	P1105  Ppos_1105
+	P1106  Ppos_1106
+	P1107  Ppos_1107
+	P1108  Ppos_1108
 	P2000  Ppos_2000
 	P2001  Ppos_2001
 	P2002  Ppos_2002
 	P2005  Ppos_2005
+	P2006  Ppos_2006
+	P2007  Ppos_2007
 	P2003  Ppos_2003
 	P20031 []Ppos_20031
 	P2004  Ppos_2004
@@ -236,6 +324,8 @@ type decDataConfig struct {
 	P3000  Ppos_3000
 	P3001  Ppos_3001
 	P4000  Ppos_4000
+	P4001  Ppos_4001
+	P4002  Ppos_4002
 	P4100  Ppos_4100
 }
 
@@ -278,6 +368,7 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			website, _ := rlp.EncodeToBytes(cfg.P1000.Website)
 			details, _ := rlp.EncodeToBytes(cfg.P1000.Details)
 			amount, _ := rlp.EncodeToBytes(cfg.P1000.Amount)
+			rewardPer, _ := rlp.EncodeToBytes(cfg.P1000.RewardPer)
 			programVersion, _ := rlp.EncodeToBytes(cfg.P1000.ProgramVersion)
 			programVersionSign, _ := rlp.EncodeToBytes(cfg.P1000.ProgramVersionSign)
 			blsPubKey, _ := rlp.EncodeToBytes(cfg.P1000.BlsPubKey)
@@ -295,11 +386,13 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, programVersionSign)
 			params = append(params, blsPubKey)
 			params = append(params, blsProof)
+			params = append(params, rewardPer)
 		}
 	case 1001:
 		{
 			benefitAddress, _ := rlp.EncodeToBytes(cfg.P1001.BenefitAddress.Bytes())
 			nodeId, _ := rlp.EncodeToBytes(cfg.P1001.NodeId)
+			rewardPer, _ := rlp.EncodeToBytes(cfg.P1001.RewardPer)
 			externalId, _ := rlp.EncodeToBytes(cfg.P1001.ExternalId)
 			nodeName, _ := rlp.EncodeToBytes(cfg.P1001.NodeName)
 			website, _ := rlp.EncodeToBytes(cfg.P1001.Website)
@@ -311,6 +404,7 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, nodeName)
 			params = append(params, website)
 			params = append(params, details)
+			params = append(params, rewardPer)
 		}
 	case 1002:
 		{
@@ -347,6 +441,47 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, nodeId)
 			params = append(params, amount)
 		}
+	case 1006:
+	case 1007:
+		{
+			stakingBlockNum, _ := rlp.EncodeToBytes(cfg.P1007.StakingBlockNum)
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1007.NodeId)
+			dstNodeId, _ := rlp.EncodeToBytes(cfg.P1007.DstNodeId)
+			amount, _ := rlp.EncodeToBytes(cfg.P1007.Amount)
+
+			params = append(params, stakingBlockNum)
+			params = append(params, nodeId)
+			params = append(params, dstNodeId)
+			params = append(params, amount)
+		}
+	case 1008:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1008.NodeId)
+			params = append(params, nodeId)
+		}
+	case 1009:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1009.NodeId)
+			operators, _ := rlp.EncodeToBytes(cfg.P1009.Operators)
+			threshold, _ := rlp.EncodeToBytes(cfg.P1009.Threshold)
+			params = append(params, nodeId)
+			params = append(params, operators)
+			params = append(params, threshold)
+		}
+	case 1010:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1010.NodeId)
+			actionId, _ := rlp.EncodeToBytes(cfg.P1010.ActionId.Bytes())
+			params = append(params, nodeId)
+			params = append(params, actionId)
+		}
+	case 1011:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1011.NodeId)
+			actionId, _ := rlp.EncodeToBytes(cfg.P1011.ActionId.Bytes())
+			params = append(params, nodeId)
+			params = append(params, actionId)
+		}
 	case 1100:
 	case 1101:
 	case 1102:
@@ -370,6 +505,23 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			nodeId, _ := rlp.EncodeToBytes(cfg.P1105.NodeId)
 			params = append(params, nodeId)
 		}
+	case 1106:
+		{
+			addr, _ := rlp.EncodeToBytes(cfg.P1106.Addr.Bytes())
+			nodeIds, _ := rlp.EncodeToBytes(cfg.P1106.NodeIds)
+			params = append(params, addr)
+			params = append(params, nodeIds)
+		}
+	case 1107:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1107.NodeId)
+			params = append(params, nodeId)
+		}
+	case 1108:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1108.NodeId)
+			params = append(params, nodeId)
+		}
 	case 2000:
 		{
 			verifier, _ := rlp.EncodeToBytes(cfg.P2000.Verifier)
@@ -415,6 +567,32 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, endVotingRounds)
 			params = append(params, tobeCanceled)
 		}
+	case 2006:
+		{
+			verifier, _ := rlp.EncodeToBytes(cfg.P2006.Verifier)
+			pipID, _ := rlp.EncodeToBytes(cfg.P2006.PIPID)
+			source, _ := rlp.EncodeToBytes(cfg.P2006.Source.Bytes())
+			recipient, _ := rlp.EncodeToBytes(cfg.P2006.Recipient.Bytes())
+			amount, _ := rlp.EncodeToBytes(cfg.P2006.Amount)
+			plans, _ := rlp.EncodeToBytes(cfg.P2006.Plans)
+			params = append(params, verifier)
+			params = append(params, pipID)
+			params = append(params, source)
+			params = append(params, recipient)
+			params = append(params, amount)
+			params = append(params, plans)
+		}
+	case 2007:
+		{
+			proposalID, _ := rlp.EncodeToBytes(cfg.P2007.ProposalID.Bytes())
+			nodeId, _ := rlp.EncodeToBytes(cfg.P2007.NodeId)
+			stakingBlockNum, _ := rlp.EncodeToBytes(cfg.P2007.StakingBlockNum)
+			op, _ := rlp.EncodeToBytes(cfg.P2007.Option)
+			params = append(params, proposalID)
+			params = append(params, nodeId)
+			params = append(params, stakingBlockNum)
+			params = append(params, op)
+		}
 	case 2003:
 		{
 			verifier, _ := rlp.EncodeToBytes(cfg.P2003.Verifier)
@@ -518,6 +696,16 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, account)
 			params = append(params, plans)
 		}
+	case 4001:
+		{
+			account, _ := rlp.EncodeToBytes(cfg.P4001.Account.Bytes())
+			params = append(params, account)
+		}
+	case 4002:
+		{
+			to, _ := rlp.EncodeToBytes(cfg.P4002.To.Bytes())
+			params = append(params, to)
+		}
 	case 4100:
 		{
 			account, _ := rlp.EncodeToBytes(cfg.P4100.Account.Bytes())
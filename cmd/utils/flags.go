@@ -38,6 +38,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
 	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
@@ -171,6 +172,10 @@ var (
 		Usage: "Maximum number of LES client peers",
 		Value: eth.DefaultConfig.LightPeers,
 	}
+	LightQCFlag = cli.BoolFlag{
+		Name:  "light.qc",
+		Usage: "Verify the QuorumCert of every header against the validator set in force in light sync mode (the ppos chain needs the ppos commit fork since the first round)",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -229,6 +234,11 @@ var (
 		Usage: "Maximum number of transactions for package",
 		Value: eth.DefaultConfig.TxPool.GlobalTxCount,
 	}
+	TxPoolPrioritySlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.priorityslots",
+		Usage: "Maximum number of transaction slots for the PPOS duties of the verifiers",
+		Value: eth.DefaultConfig.TxPool.PrioritySlots,
+	}
 	TxPoolLifetimeFlag = cli.DurationFlag{
 		Name:  "txpool.lifetime",
 		Usage: "Maximum amount of time non-executable transaction are queued",
@@ -575,6 +585,15 @@ var (
 		Value: "60",
 	}
 
+	CbftEvidenceReporterFlag = cli.StringFlag{
+		Name:  "cbft.evidence_reporter",
+		Usage: "Report the duplicate evidences automatically by the (unlocked) account",
+	}
+
+	DBEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Engine of the new databases (" + strings.Join(ethdb.Engines, ", ") + "), the existing databases are opened with their own engine",
+	}
 	DBNoGCFlag = cli.BoolFlag{
 		Name:  "db.nogc",
 		Usage: "Disables database garbage collection",
@@ -593,11 +612,20 @@ var (
 		Name:  "db.gc_mpt",
 		Usage: "Enables database garbage collection MPT",
 	}
+	DBSnapshotArchiveFlag = cli.BoolFlag{
+		Name:  "db.snapshot_archive",
+		Usage: "Keeps the history of snapshotdb to serve the PPOS contract calls at past blocks",
+	}
 	DBGCBlockFlag = cli.Uint64Flag{
 		Name:  "db.gc_block",
 		Usage: "Number of cache block states, default 10",
 		Value: eth.DefaultConfig.DBGCBlock,
 	}
+	DBAncientThresholdFlag = cli.Uint64Flag{
+		Name:  "db.ancient_threshold",
+		Usage: "Number of recent blocks kept in leveldb, older blocks are moved to the ancient store (0 = disabled)",
+		Value: eth.DefaultConfig.DBAncientThreshold,
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -942,6 +970,12 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(NoUSBFlag.Name) {
 		cfg.NoUSB = ctx.GlobalBool(NoUSBFlag.Name)
 	}
+	if ctx.GlobalIsSet(DBEngineFlag.Name) {
+		cfg.DBEngine = ctx.GlobalString(DBEngineFlag.Name)
+		if err := ethdb.CheckEngine(cfg.DBEngine); err != nil {
+			Fatalf("%v", err)
+		}
+	}
 }
 
 func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
@@ -994,6 +1028,9 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolGlobalTxCountFlag.Name) {
 		cfg.GlobalTxCount = ctx.GlobalUint64(TxPoolGlobalTxCountFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolPrioritySlotsFlag.Name) {
+		cfg.PrioritySlots = ctx.GlobalUint64(TxPoolPrioritySlotsFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
@@ -1111,6 +1148,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(LightPeersFlag.Name) {
 		cfg.LightPeers = ctx.GlobalInt(LightPeersFlag.Name)
 	}
+	if ctx.GlobalIsSet(LightQCFlag.Name) {
+		cfg.LightQC = ctx.GlobalBool(LightQCFlag.Name)
+	}
 	if ctx.GlobalIsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.GlobalUint64(NetworkIdFlag.Name)
 	}
@@ -1188,6 +1228,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 			cfg.DBGCBlock = b
 		}
 	}
+	if ctx.GlobalIsSet(DBSnapshotArchiveFlag.Name) {
+		cfg.DBSnapshotArchive = ctx.GlobalBool(DBSnapshotArchiveFlag.Name)
+	}
+	if ctx.GlobalIsSet(DBAncientThresholdFlag.Name) {
+		cfg.DBAncientThreshold = ctx.GlobalUint64(DBAncientThresholdFlag.Name)
+	}
 }
 
 func SetCbft(ctx *cli.Context, cfg *types.OptionsConfig, nodeCfg *node.Config) {
@@ -1220,6 +1266,13 @@ func SetCbft(ctx *cli.Context, cfg *types.OptionsConfig, nodeCfg *node.Config) {
 	if ctx.GlobalIsSet(CbftBlacklistDeadlineFlag.Name) {
 		cfg.BlacklistDeadline = ctx.GlobalInt64(CbftBlacklistDeadlineFlag.Name)
 	}
+	if ctx.GlobalIsSet(CbftEvidenceReporterFlag.Name) {
+		reporter := ctx.GlobalString(CbftEvidenceReporterFlag.Name)
+		if !common.IsHexAddress(reporter) {
+			Fatalf("Invalid evidence reporter address: %s", reporter)
+		}
+		cfg.EvidenceReporter = common.HexToAddress(reporter)
+	}
 
 }
 
@@ -1314,6 +1367,12 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
 	if err != nil {
 		Fatalf("Could not open database: %v", err)
 	}
+	// The blocks are not moved by the commands, the frozen blocks are readable
+	if db, ok := chainDb.(ethdb.Store); ok {
+		if chainDb, err = rawdb.NewDatabaseWithFreezer(db, filepath.Join(db.Path(), "ancient"), 0); err != nil {
+			Fatalf("Could not open ancient database: %v", err)
+		}
+	}
 	return chainDb
 }
 
@@ -73,6 +73,7 @@ func NewChain() *Chain {
 type MockStateDB struct {
 	Balance      map[common.Address]*big.Int
 	State        map[common.Address]map[string][]byte
+	Logs         []*types.Log
 	thash, bhash common.Hash
 	txIndex      int
 }
@@ -97,7 +98,7 @@ func (s *MockStateDB) AddBalance(adr common.Address, amount *big.Int) {
 	if balance, ok := s.Balance[adr]; ok {
 		balance.Add(balance, amount)
 	} else {
-		s.Balance[adr] = amount
+		s.Balance[adr] = new(big.Int).Set(amount)
 	}
 }
 
@@ -204,8 +205,8 @@ func (s *MockStateDB) Snapshot() int {
 	return 0
 }
 
-func (s *MockStateDB) AddLog(*types.Log) {
-	return
+func (s *MockStateDB) AddLog(log *types.Log) {
+	s.Logs = append(s.Logs, log)
 }
 func (s *MockStateDB) AddPreimage(common.Hash, []byte) {
 	return
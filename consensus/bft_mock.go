@@ -174,7 +174,7 @@ func (bm *BftMock) Seal(chain ChainReader, block *types.Block, results chan<- *t
 		return fmt.Errorf("unknown block")
 	}
 	sign := header.SealHash().Bytes()
-	copy(header.Extra[len(header.Extra)-ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+ExtraSeal], sign[:])
 	sealBlock := block.WithSeal(header)
 	results <- sealBlock
 	bm.EventMux.Post(cbfttypes.CbftResult{
@@ -1,11 +1,19 @@
 package cbft
 
 import (
+	"fmt"
+
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/evidence"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/history"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/state"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
 	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 )
 
+// maxHistoryQueryRange is the maximum number of blocks or views can be queried at once.
+const maxHistoryQueryRange = 1000
+
 type Status struct {
 	Tree      *types.BlockTree `json:"blockTree"`
 	State     *state.ViewState `json:"state"`
@@ -16,7 +24,12 @@ type Status struct {
 type API interface {
 	Status() *Status
 	Evidences() string
+	FilterEvidences(filter *evidence.EvidenceFilter) *evidence.EvidenceData
 	GetPrepareQC(number uint64) *types.QuorumCert
+	GetQuorumCerts(from, to uint64) []*types.QuorumCert
+	GetViewChangeQCs(epoch, fromView, toView uint64) []*types.ViewChangeQC
+	GetViewSummaries(epoch, fromView, toView uint64) []*history.ViewSummary
+	GetLiveness(fromEpoch, toEpoch uint64) []*history.EpochLiveness
 	GetSchnorrNIZKProve() (*bls.SchnorrProof, error)
 }
 
@@ -42,11 +55,42 @@ func (s *PublicConsensusAPI) Evidences() string {
 	return s.engine.Evidences()
 }
 
+// GetEvidences returns the duplicate evidences selected by the filter,
+// e.g. `{"fromEpoch": 10, "nodeId": "0x...", "type": 2}`.
+func (s *PublicConsensusAPI) GetEvidences(filter evidence.EvidenceFilter) *evidence.EvidenceData {
+	return s.engine.FilterEvidences(&filter)
+}
+
 // GetPrepareQC returns the QC certificate corresponding to the blockNumber.
 func (s *PublicConsensusAPI) GetPrepareQC(number uint64) *types.QuorumCert {
 	return s.engine.GetPrepareQC(number)
 }
 
+// GetQuorumCerts returns the QC certificates of the committed blocks in the range [from, to].
+func (s *PublicConsensusAPI) GetQuorumCerts(from, to uint64) ([]*types.QuorumCert, error) {
+	if err := checkQueryRange(from, to); err != nil {
+		return nil, err
+	}
+	return s.engine.GetQuorumCerts(from, to), nil
+}
+
+// GetViewChangeQCs returns the viewChangeQCs which ended the views of the epoch in the range [fromView, toView].
+func (s *PublicConsensusAPI) GetViewChangeQCs(epoch, fromView, toView uint64) ([]*types.ViewChangeQC, error) {
+	if err := checkQueryRange(fromView, toView); err != nil {
+		return nil, err
+	}
+	return s.engine.GetViewChangeQCs(epoch, fromView, toView), nil
+}
+
+// GetViewSummaries returns the summaries of the finished views of the epoch in the range [fromView, toView],
+// including the proposer, the signers of each block and whether the view is timeout.
+func (s *PublicConsensusAPI) GetViewSummaries(epoch, fromView, toView uint64) ([]*history.ViewSummary, error) {
+	if err := checkQueryRange(fromView, toView); err != nil {
+		return nil, err
+	}
+	return s.engine.GetViewSummaries(epoch, fromView, toView), nil
+}
+
 func (s *PublicConsensusAPI) GetSchnorrNIZKProve() string {
 	proof, err := s.engine.GetSchnorrNIZKProve()
 	if nil != err {
@@ -58,3 +102,55 @@ func (s *PublicConsensusAPI) GetSchnorrNIZKProve() string {
 	}
 	return string(proofByte)
 }
+
+// PublicLivenessAPI provides an API to access the participation of the validators,
+// it's recorded by the views observed by the local node.
+type PublicLivenessAPI struct {
+	engine API
+}
+
+// NewPublicLivenessAPI creates a new liveness API.
+func NewPublicLivenessAPI(engine API) *PublicLivenessAPI {
+	return &PublicLivenessAPI{engine: engine}
+}
+
+// GetEpochLiveness returns the participation of the validators in the epoch.
+func (s *PublicLivenessAPI) GetEpochLiveness(epoch uint64) *history.EpochLiveness {
+	if livenesses := s.engine.GetLiveness(epoch, epoch); len(livenesses) > 0 {
+		return livenesses[0]
+	}
+	return nil
+}
+
+// GetLiveness returns the participation of the validators in the epochs [fromEpoch, toEpoch].
+func (s *PublicLivenessAPI) GetLiveness(fromEpoch, toEpoch uint64) ([]*history.EpochLiveness, error) {
+	if err := checkQueryRange(fromEpoch, toEpoch); err != nil {
+		return nil, err
+	}
+	return s.engine.GetLiveness(fromEpoch, toEpoch), nil
+}
+
+// GetValidatorLiveness returns the participation of the validator accumulated in the epochs [fromEpoch, toEpoch],
+// the epochs in which the node is not a validator are skipped.
+func (s *PublicLivenessAPI) GetValidatorLiveness(nodeID discover.NodeID, fromEpoch, toEpoch uint64) (*history.ValidatorLiveness, error) {
+	if err := checkQueryRange(fromEpoch, toEpoch); err != nil {
+		return nil, err
+	}
+	total := &history.ValidatorLiveness{NodeID: nodeID}
+	for _, liveness := range s.engine.GetLiveness(fromEpoch, toEpoch) {
+		if v := liveness.Validator(nodeID); v != nil {
+			total.Add(v)
+		}
+	}
+	return total, nil
+}
+
+func checkQueryRange(from, to uint64) error {
+	if from > to {
+		return fmt.Errorf("invalid range, from:%d, to:%d", from, to)
+	}
+	if to-from >= maxHistoryQueryRange {
+		return fmt.Errorf("the range exceeds the limit %d", maxHistoryQueryRange)
+	}
+	return nil
+}
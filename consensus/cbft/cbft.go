@@ -6,6 +6,7 @@ import (
 	"crypto/elliptic"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strings"
 	"sync/atomic"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/evidence"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/executor"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/fetcher"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/history"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/network"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/protocols"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/rules"
@@ -43,6 +45,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/p2p"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"github.com/PlatONnetwork/PlatON-Go/rpc"
 )
 
@@ -96,6 +99,7 @@ type Cbft struct {
 	peerMsgCh        chan *ctypes.MsgInfo
 	syncMsgCh        chan *ctypes.MsgInfo
 	evPool           evidence.EvidencePool
+	history          history.History
 	log              log.Logger
 	network          *network.EngineManager
 
@@ -191,6 +195,12 @@ func New(sysConfig *params.CbftConfig, optConfig *ctypes.OptionsConfig, eventMux
 		return nil
 	}
 
+	if h, err := history.NewHistory(ctx, optConfig.HistoryDir); err == nil {
+		cbft.history = h
+	} else {
+		return nil
+	}
+
 	return cbft
 }
 
@@ -405,7 +415,8 @@ func (cbft *Cbft) statMessage(msg *ctypes.MsgInfo) error {
 // ReceiveSyncMsg is used to receive messages that are synchronized from other nodes.
 //
 // Possible message types are:
-//  PrepareBlockVotesMsg/GetLatestStatusMsg/LatestStatusMsg/
+//
+//	PrepareBlockVotesMsg/GetLatestStatusMsg/LatestStatusMsg/
 func (cbft *Cbft) ReceiveSyncMsg(msg *ctypes.MsgInfo) error {
 	err := cbft.recordMessage(msg)
 	if err != nil {
@@ -622,6 +633,17 @@ func (cbft *Cbft) VerifyHeader(chain consensus.ChainReader, header *types.Header
 		return fmt.Errorf("verify header fail, missing signature, number:%d, hash:%s", header.Number.Uint64(), header.Hash().String())
 	}
 
+	if len(header.Extra) > consensus.ExtraSeal+int(params.MaximumExtraDataSize) {
+		if !cbft.isHeaderQC(header.Number) {
+			cbft.log.Error("Verify header fail, extra data after the signature", "number", header.Number, "hash", header.Hash())
+			return fmt.Errorf("verify header fail, extra data after the signature before the fork, number:%d, hash:%s", header.Number.Uint64(), header.Hash().String())
+		}
+		if _, err := ctypes.DecodeHeaderQC(header.Extra); err != nil {
+			cbft.log.Error("Verify header fail, invalid QC", "number", header.Number, "hash", header.Hash(), "err", err)
+			return fmt.Errorf("verify header fail, invalid QC, number:%d, hash:%s, err:%s", header.Number.Uint64(), header.Hash().String(), err.Error())
+		}
+	}
+
 	if err := cbft.validatorPool.VerifyHeader(header); err != nil {
 		cbft.log.Error("Verify header fail", "number", header.Number, "hash", header.Hash(), "err", err)
 		return fmt.Errorf("verify header fail, number:%d, hash:%s, err:%s", header.Number.Uint64(), header.Hash().String(), err.Error())
@@ -676,9 +698,41 @@ func (cbft *Cbft) Prepare(chain consensus.ChainReader, header *types.Header) err
 	//init header.Extra[32: 32+65]
 	header.Extra = append(header.Extra, make([]byte, consensus.ExtraSeal)...)
 	cbft.log.Debug("Prepare, add header-extra ExtraSeal bytes(0x00)", "extraLength", len(header.Extra))
+
+	//header.Extra[32+65:] to store the QC of the highest QC block after the fork, the signers of it are counted by the slashing.
+	if !cbft.isHeaderQC(header.Number) {
+		return nil
+	}
+	if qc := cbft.highestQC(); qc != nil && qc.BlockNumber < header.Number.Uint64() {
+		enc, err := rlp.EncodeToBytes(qc)
+		if err != nil {
+			cbft.log.Error("Prepare, encode the highest QC fail", "number", header.Number, "err", err)
+			return err
+		}
+		header.Extra = append(header.Extra, enc...)
+	}
 	return nil
 }
 
+// isHeaderQC returns whether the header of the block number carries the QC after the seal.
+func (cbft *Cbft) isHeaderQC(number *big.Int) bool {
+	if cbft.blockChain == nil || cbft.blockChain.Config() == nil {
+		return false
+	}
+	return cbft.blockChain.Config().IsHeaderQC(number)
+}
+
+// highestQC returns the QC of the highest QC block.
+func (cbft *Cbft) highestQC() *ctypes.QuorumCert {
+	result := make(chan *ctypes.QuorumCert, 1)
+	cbft.asyncCallCh <- func() {
+		block := cbft.state.HighestQCBlock()
+		_, qc := cbft.blockTree.FindBlockAndQC(block.Hash(), block.NumberU64())
+		result <- qc
+	}
+	return <-result
+}
+
 // Finalize implements consensus.Engine, no block
 // rewards given, and returns the final block.
 func (cbft *Cbft) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, receipts []*types.Receipt) (*types.Block, error) {
@@ -702,7 +756,7 @@ func (cbft *Cbft) Seal(chain consensus.ChainReader, block *types.Block, results
 		return err
 	}
 
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sign[:])
 
 	sealBlock := block.WithSeal(header)
 
@@ -828,6 +882,12 @@ func (cbft *Cbft) APIs(chain consensus.ChainReader) []rpc.API {
 			Service:   NewPublicConsensusAPI(cbft),
 			Public:    true,
 		},
+		{
+			Namespace: "liveness",
+			Version:   "1.0",
+			Service:   NewPublicLivenessAPI(cbft),
+			Public:    true,
+		},
 	}
 }
 
@@ -951,6 +1011,41 @@ func (cbft *Cbft) GetPrepareQC(number uint64) *ctypes.QuorumCert {
 	return &ctypes.QuorumCert{}
 }
 
+// GetQuorumCerts returns the QC certificates of the committed blocks in the range [from, to],
+// the blocks which are not found are skipped.
+func (cbft *Cbft) GetQuorumCerts(from, to uint64) []*ctypes.QuorumCert {
+	qcs := make([]*ctypes.QuorumCert, 0)
+	for number := from; number <= to; number++ {
+		header := cbft.blockChain.GetHeaderByNumber(number)
+		if header == nil {
+			break
+		}
+		block := cbft.blockChain.GetBlock(header.Hash(), number)
+		if block == nil {
+			break
+		}
+		if _, qc, err := ctypes.DecodeExtra(block.ExtraData()); err == nil {
+			qcs = append(qcs, qc)
+		}
+	}
+	return qcs
+}
+
+// GetViewChangeQCs returns the recorded viewChangeQCs of the epoch in the view range [fromView, toView].
+func (cbft *Cbft) GetViewChangeQCs(epoch, fromView, toView uint64) []*ctypes.ViewChangeQC {
+	return cbft.history.ViewChangeQCs(epoch, fromView, toView)
+}
+
+// GetViewSummaries returns the recorded summaries of the epoch in the view range [fromView, toView].
+func (cbft *Cbft) GetViewSummaries(epoch, fromView, toView uint64) []*history.ViewSummary {
+	return cbft.history.ViewSummaries(epoch, fromView, toView)
+}
+
+// GetLiveness returns the participation of the validators in the epochs [fromEpoch, toEpoch].
+func (cbft *Cbft) GetLiveness(fromEpoch, toEpoch uint64) []*history.EpochLiveness {
+	return cbft.history.Liveness(fromEpoch, toEpoch)
+}
+
 // GetBlockByHash get the specified block by hash.
 func (cbft *Cbft) GetBlockByHash(hash common.Hash) *types.Block {
 	result := make(chan *types.Block, 1)
@@ -1025,6 +1120,7 @@ func (cbft *Cbft) Close() error {
 		cbft.asyncExecutor.Stop()
 	}
 	cbft.bridge.Close()
+	cbft.history.Close()
 	return nil
 }
 
@@ -1260,6 +1356,16 @@ func (cbft *Cbft) Evidences() string {
 	return string(js)
 }
 
+// FilterEvidences implements functions in API.
+func (cbft *Cbft) FilterEvidences(filter *evidence.EvidenceFilter) *evidence.EvidenceData {
+	return evidence.ClassifyEvidence(cbft.evPool.FilterEvidences(filter))
+}
+
+// EvidencePool returns the pool which records the duplicate evidences.
+func (cbft *Cbft) EvidencePool() evidence.EvidencePool {
+	return cbft.evPool
+}
+
 func (cbft *Cbft) verifySelfSigned(m []byte, sig []byte) bool {
 	recPubKey, err := crypto.Ecrecover(m, sig)
 	if err != nil {
@@ -142,7 +142,7 @@ func NewBadBlock(parent common.Hash, number uint64, node *TestCBFT) *types.Block
 	}
 
 	sign, _ := node.engine.signFn(header.SealHash().Bytes())
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sign[:])
 
 	block := types.NewBlockWithHeader(header)
 	return block
@@ -13,6 +13,7 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/protocols"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/state"
 	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
@@ -735,3 +736,41 @@ func Test_StatMessage(t *testing.T) {
 	}
 	assert.Equal(t, 199, len(node.engine.statQueues))
 }
+
+// headerQCChain overrides the chain config of the chain reader.
+type headerQCChain struct {
+	consensus.ChainReader
+	config *params.ChainConfig
+}
+
+func (c *headerQCChain) Config() *params.ChainConfig {
+	return c.config
+}
+
+func TestVerifyHeaderQC(t *testing.T) {
+	pk, sk, cbftnodes := GenerateCbftNode(4)
+	node := MockNode(pk[0], sk[0], cbftnodes, 10000, 10)
+	assert.Nil(t, node.Start())
+
+	newHeader := func(qc []byte) *types.Header {
+		header := &types.Header{
+			Number: big.NewInt(1),
+			Time:   big.NewInt(time.Now().UnixNano()),
+			Extra:  append(make([]byte, 32+consensus.ExtraSeal), qc...),
+		}
+		sign, _ := node.engine.signFn(header.SealHash().Bytes())
+		copy(header.Extra[32:32+consensus.ExtraSeal], sign)
+		return header
+	}
+	qc, err := rlp.EncodeToBytes(&ctypes.QuorumCert{BlockNumber: 0, ValidatorSet: utils.NewBitArray(4)})
+	assert.Nil(t, err)
+
+	// the header can't carry the QC before the fork
+	assert.Nil(t, node.engine.VerifyHeader(nil, newHeader(nil), false))
+	assert.NotNil(t, node.engine.VerifyHeader(nil, newHeader(qc), false))
+
+	node.engine.blockChain = &headerQCChain{ChainReader: node.chain, config: &params.ChainConfig{HeaderQCBlock: big.NewInt(0)}}
+	assert.Nil(t, node.engine.VerifyHeader(nil, newHeader(nil), false))
+	assert.Nil(t, node.engine.VerifyHeader(nil, newHeader(qc), false))
+	assert.NotNil(t, node.engine.VerifyHeader(nil, newHeader([]byte{0xff}), false))
+}
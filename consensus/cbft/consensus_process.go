@@ -10,6 +10,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/utils"
 
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/executor"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/history"
 
 	"github.com/PlatONnetwork/PlatON-Go/log"
 
@@ -20,6 +21,7 @@ import (
 	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 )
 
 // OnPrepareBlock performs security rule verification，store in blockTree,
@@ -718,6 +720,10 @@ func (cbft *Cbft) changeView(epoch, viewNumber uint64, block *types.Block, qc *c
 	cbft.syncingCache.Purge()
 	cbft.csPool.Purge(epoch, viewNumber)
 
+	// record the finished view before it's reset
+	if !cbft.isLoading() {
+		cbft.recordViewHistory(viewChangeQC)
+	}
 	cbft.state.ResetView(epoch, viewNumber)
 	cbft.state.SetViewTimer(interval())
 	cbft.state.SetLastViewChangeQC(viewChangeQC)
@@ -739,6 +745,58 @@ func (cbft *Cbft) changeView(epoch, viewNumber uint64, block *types.Block, qc *c
 	cbft.log.Info("Success to change view, current view deadline", "deadline", cbft.state.Deadline())
 }
 
+// recordViewHistory writes the summary of the current view and the viewChangeQC ending it to the history,
+// and accumulates the participation of the validators in the epoch.
+func (cbft *Cbft) recordViewHistory(viewChangeQC *ctypes.ViewChangeQC) {
+	epoch, viewNumber := cbft.state.Epoch(), cbft.state.ViewNumber()
+	summary := &history.ViewSummary{
+		Epoch:      epoch,
+		ViewNumber: viewNumber,
+		Blocks:     make([]*history.ViewBlock, 0),
+	}
+	if length := cbft.validatorPool.Len(epoch); length > 0 {
+		summary.ProposerIndex = uint32(viewNumber % uint64(length))
+		if node, err := cbft.validatorPool.GetValidatorByIndex(epoch, summary.ProposerIndex); err == nil {
+			summary.Proposer = node.NodeID
+		}
+	}
+	if maxIndex := cbft.state.MaxQCIndex(); maxIndex != math.MaxUint32 {
+		for i := uint32(0); i <= maxIndex; i++ {
+			if _, qc := cbft.state.ViewBlockAndQC(i); qc != nil {
+				summary.Blocks = append(summary.Blocks, &history.ViewBlock{
+					BlockIndex:  qc.BlockIndex,
+					BlockNumber: qc.BlockNumber,
+					BlockHash:   qc.BlockHash,
+					Signers:     qc.ValidatorSet,
+				})
+			}
+		}
+	}
+	if viewChangeQC != nil && viewChangeQC.Len() > 0 {
+		summary.Timeout = true
+		for _, qc := range viewChangeQC.QCs {
+			summary.ViewChangeSigners = summary.ViewChangeSigners.Or(qc.ValidatorSet)
+		}
+		if err := cbft.history.AddViewChangeQC(viewChangeQC); err != nil {
+			cbft.log.Error("Failed to record viewChangeQC", "viewChangeQC", viewChangeQC.String(), "err", err)
+		}
+	}
+	if err := cbft.history.AddViewSummary(summary); err != nil {
+		cbft.log.Error("Failed to record view summary", "epoch", epoch, "viewNumber", viewNumber, "err", err)
+	}
+
+	validators := make([]discover.NodeID, 0, cbft.validatorPool.Len(epoch))
+	for i := 0; i < cbft.validatorPool.Len(epoch); i++ {
+		validators = append(validators, cbft.validatorPool.GetNodeIDByIndex(epoch, i))
+	}
+	liveness, err := cbft.history.UpdateLiveness(summary, validators, cbft.config.Sys.Amount)
+	if err != nil {
+		cbft.log.Error("Failed to update validator liveness", "epoch", epoch, "viewNumber", viewNumber, "err", err)
+	} else if liveness != nil {
+		updateLivenessMetrics(liveness)
+	}
+}
+
 // Clean up invalid blocks in the previous view
 func (cbft *Cbft) clearInvalidBlocks(newBlock *types.Block) {
 	var rollback []*types.Block
@@ -9,13 +9,14 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
 
+	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/protocols"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/node"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/opt"
 )
 
 var (
@@ -33,6 +34,30 @@ type EvidencePool interface {
 	AddPrepareBlock(pb *protocols.PrepareBlock, node *cbfttypes.ValidateNode) error
 	AddPrepareVote(pv *protocols.PrepareVote, node *cbfttypes.ValidateNode) error
 	AddViewChange(vc *protocols.ViewChange, node *cbfttypes.ValidateNode) error
+	FilterEvidences(filter *EvidenceFilter) consensus.Evidences
+	Prune(blockNumber uint64) (int, error)
+}
+
+// EvidenceFilter selects the duplicate evidences of the pool, the zero value selects all of them.
+type EvidenceFilter struct {
+	FromEpoch uint64                 `json:"fromEpoch"`
+	ToEpoch   uint64                 `json:"toEpoch"` // 0 means unlimited
+	NodeID    *discover.NodeID       `json:"nodeId"`
+	Type      consensus.EvidenceType `json:"type"` // 0 means all types
+}
+
+// Match checks whether the evidence is selected by the filter.
+func (f *EvidenceFilter) Match(e consensus.Evidence) bool {
+	if f == nil {
+		return true
+	}
+	if e.Epoch() < f.FromEpoch || (f.ToEpoch > 0 && e.Epoch() > f.ToEpoch) {
+		return false
+	}
+	if f.NodeID != nil && e.NodeID() != *f.NodeID {
+		return false
+	}
+	return f.Type == 0 || e.Type() == f.Type
 }
 
 // emptyEvidencePool is a empty implementation for EvidencePool
@@ -55,6 +80,14 @@ func (pool *emptyEvidencePool) Evidences() consensus.Evidences {
 	return nil
 }
 
+func (pool *emptyEvidencePool) FilterEvidences(filter *EvidenceFilter) consensus.Evidences {
+	return nil
+}
+
+func (pool *emptyEvidencePool) Prune(blockNumber uint64) (int, error) {
+	return 0, nil
+}
+
 func (pool *emptyEvidencePool) Clear(epoch uint64, viewNumber uint64) {
 }
 
@@ -66,7 +99,7 @@ type baseEvidencePool struct {
 	pb PrepareBlockEvidence
 	pv PrepareVoteEvidence
 	vc ViewChangeEvidence
-	db *leveldb.DB
+	db ethdb.Store
 }
 
 // NewEvidencePool creates a new baseEvidencePool to record duplicate blocks and votes.
@@ -78,12 +111,18 @@ func NewEvidencePool(ctx *node.ServiceContext, evidenceDir string) (EvidencePool
 	if len(path) == 0 {
 		return &emptyEvidencePool{}, nil
 	}
-	return NewBaseEvidencePool(path)
+	return newBaseEvidencePool(ctx.DBEngine(), path)
 }
 
+// NewBaseEvidencePool opens the evidence database in the path with its own engine,
+// a new database is created with the default engine.
 func NewBaseEvidencePool(path string) (*baseEvidencePool, error) {
+	return newBaseEvidencePool("", path)
+}
+
+func newBaseEvidencePool(engine string, path string) (*baseEvidencePool, error) {
 	// Open or create evidence database
-	db, err := leveldb.OpenFile(path, nil)
+	db, err := ethdb.OpenStore(engine, path, 0, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -155,26 +194,16 @@ func (pool *baseEvidencePool) AddViewChange(vc *protocols.ViewChange, node *cbft
 
 // Evidences retrieves the duplicate evidence by querying the database
 func (pool *baseEvidencePool) Evidences() consensus.Evidences {
+	return pool.FilterEvidences(nil)
+}
+
+// FilterEvidences retrieves the duplicate evidence selected by the filter
+func (pool *baseEvidencePool) FilterEvidences(filter *EvidenceFilter) consensus.Evidences {
 	var evds consensus.Evidences
-	it := pool.db.NewIterator(nil, nil)
+	it := pool.db.NewIterator()
 	for it.Next() {
-		flag := it.Key()[0]
-		switch flag {
-		case prepareDualPrefix:
-			var e DuplicatePrepareBlockEvidence
-			if err := rlp.DecodeBytes(it.Value(), &e); err == nil {
-				evds = append(evds, &e)
-			}
-		case voteDualPrefix:
-			var e DuplicatePrepareVoteEvidence
-			if err := rlp.DecodeBytes(it.Value(), &e); err == nil {
-				evds = append(evds, &e)
-			}
-		case viewDualPrefix:
-			var e DuplicateViewChangeEvidence
-			if err := rlp.DecodeBytes(it.Value(), &e); err == nil {
-				evds = append(evds, &e)
-			}
+		if e := decodeEvidence(it.Key()[0], it.Value()); e != nil && filter.Match(e) {
+			evds = append(evds, e)
 		}
 	}
 
@@ -182,6 +211,53 @@ func (pool *baseEvidencePool) Evidences() consensus.Evidences {
 	return evds
 }
 
+// Prune tries to delete the duplicate evidence whose blockNumber is lower than the specified one,
+// they can't be reported any more after MaxEvidenceAge.
+func (pool *baseEvidencePool) Prune(blockNumber uint64) (int, error) {
+	batch := pool.db.NewSyncBatch()
+	pruned := 0
+	it := pool.db.NewIterator()
+	for it.Next() {
+		key := it.Key()
+		// the blockNumber follows prefix, epoch and viewNumber, see encodeKey
+		if len(key) >= 25 && binary.BigEndian.Uint64(key[17:25]) < blockNumber {
+			batch.Delete(common.CopyBytes(key))
+			pruned++
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+
+	if pruned == 0 {
+		return 0, nil
+	}
+	return pruned, batch.Write()
+}
+
+// decodeEvidence decodes the duplicate evidence by the key prefix
+func decodeEvidence(flag byte, data []byte) consensus.Evidence {
+	switch flag {
+	case prepareDualPrefix:
+		var e DuplicatePrepareBlockEvidence
+		if err := rlp.DecodeBytes(data, &e); err == nil {
+			return &e
+		}
+	case voteDualPrefix:
+		var e DuplicatePrepareVoteEvidence
+		if err := rlp.DecodeBytes(data, &e); err == nil {
+			return &e
+		}
+	case viewDualPrefix:
+		var e DuplicateViewChangeEvidence
+		if err := rlp.DecodeBytes(data, &e); err == nil {
+			return &e
+		}
+	}
+	return nil
+}
+
 // NewEvidences retrieves the duplicate evidence by parsing string
 func NewEvidences(data string) (consensus.Evidences, error) {
 	var eds EvidenceData
@@ -298,9 +374,9 @@ func (ev *baseEvidencePool) commit(e consensus.Evidence, id Identity) error {
 	var buf []byte
 	var err error
 	ok := false
-	if ok, err = ev.db.Has(key, nil); !ok {
+	if ok, err = ev.db.Has(key); !ok {
 		if buf, err = rlp.EncodeToBytes(e); err == nil {
-			err = ev.db.Put(key, buf, &opt.WriteOptions{Sync: true})
+			err = ev.db.PutSync(key, buf)
 		}
 	}
 	return err
@@ -123,6 +123,53 @@ func TestDuplicatePrepareBlockEvidence(t *testing.T) {
 	assert.Nil(t, pbEvidence.Validate())
 }
 
+func TestFilterAndPruneEvidences(t *testing.T) {
+	p := path()
+	defer os.RemoveAll(p)
+	pool, err := NewBaseEvidencePool(p)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	validateNodes, secretKeys := createValidateNode(2)
+
+	// duplicate prepare block of node 0 at epoch 1, block 1
+	pb := makePrepareBlock(1, 1, newBlock(1), 0, validateNodes[0].Index, t, secretKeys[0])
+	assert.Nil(t, pool.AddPrepareBlock(pb, validateNodes[0]))
+	pb = makePrepareBlock(1, 1, newBlock(1), 0, validateNodes[0].Index, t, secretKeys[0])
+	assert.IsType(t, &DuplicatePrepareBlockEvidence{}, pool.AddPrepareBlock(pb, validateNodes[0]))
+
+	// duplicate prepare vote of node 1 at epoch 2, block 100
+	pv := makePrepareVote(2, 1, common.BytesToHash(utils.Rand32Bytes(32)), 100, 0, validateNodes[1].Index, t, secretKeys[1])
+	assert.Nil(t, pool.AddPrepareVote(pv, validateNodes[1]))
+	pv = makePrepareVote(2, 1, common.BytesToHash(utils.Rand32Bytes(32)), 100, 0, validateNodes[1].Index, t, secretKeys[1])
+	assert.IsType(t, &DuplicatePrepareVoteEvidence{}, pool.AddPrepareVote(pv, validateNodes[1]))
+
+	assert.Len(t, pool.Evidences(), 2)
+	assert.Len(t, pool.FilterEvidences(&EvidenceFilter{}), 2)
+	assert.Len(t, pool.FilterEvidences(&EvidenceFilter{FromEpoch: 2}), 1)
+	assert.Len(t, pool.FilterEvidences(&EvidenceFilter{ToEpoch: 1}), 1)
+	assert.Len(t, pool.FilterEvidences(&EvidenceFilter{NodeID: &validateNodes[1].NodeID}), 1)
+	assert.Len(t, pool.FilterEvidences(&EvidenceFilter{Type: DuplicateViewChangeType}), 0)
+	evs := pool.FilterEvidences(&EvidenceFilter{Type: DuplicatePrepareVoteType})
+	if assert.Len(t, evs, 1) {
+		assert.Equal(t, validateNodes[1].NodeID, evs[0].NodeID())
+	}
+
+	// nothing is lower than block 1
+	n, err := pool.Prune(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = pool.Prune(100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	evs = pool.Evidences()
+	if assert.Len(t, evs, 1) {
+		assert.Equal(t, uint64(100), evs[0].BlockNumber())
+	}
+}
+
 func TestDuplicatePrepareVoteEvidence(t *testing.T) {
 	p := path()
 	defer os.RemoveAll(p)
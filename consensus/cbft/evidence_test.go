@@ -144,7 +144,7 @@ func (suit *EvidenceTestSuite) TestPrepareBlockDuplicate() {
 		GasLimit:    100000000001,
 	}
 	sign, _ := suit.view.allNode[1].engine.signFn(header.SealHash().Bytes())
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sign[:])
 	block2 := types.NewBlockWithHeader(header)
 	_, qc := suit.view.firstProposer().blockTree.FindBlockAndQC(suit.view.firstProposer().state.HighestQCBlock().Hash(),
 		suit.view.firstProposer().state.HighestQCBlock().NumberU64())
@@ -194,7 +194,7 @@ func (suit *EvidenceTestSuite) TestPrepareBlockDuplicateDifViewNumber() {
 		GasLimit:    100000000001,
 	}
 	sign, _ := suit.view.allNode[1].engine.signFn(header.SealHash().Bytes())
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sign[:])
 	block2 := types.NewBlockWithHeader(header)
 	_, qc := suit.view.firstProposer().blockTree.FindBlockAndQC(suit.view.firstProposer().state.HighestQCBlock().Hash(),
 		suit.view.firstProposer().state.HighestQCBlock().NumberU64())
@@ -232,7 +232,7 @@ func (suit *EvidenceTestSuite) TestPrepareBlockDuplicateDifEpoch() {
 		GasLimit:    100000000001,
 	}
 	sign, _ := suit.view.allNode[1].engine.signFn(header.SealHash().Bytes())
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sign[:])
 	block2 := types.NewBlockWithHeader(header)
 	_, qc := suit.view.firstProposer().blockTree.FindBlockAndQC(suit.view.firstProposer().state.HighestQCBlock().Hash(),
 		suit.view.firstProposer().state.HighestQCBlock().NumberU64())
@@ -0,0 +1,264 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package history implements recording the finished views of cbft consensus,
+// so that the validator participation can be audited after the view is gone.
+package history
+
+import (
+	"encoding/binary"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/utils"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/node"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+var (
+	// ViewChangeQC prefix, followed by epoch and view number
+	viewChangeQCPrefix = byte(0x1)
+	// View summary prefix, followed by epoch and view number
+	viewSummaryPrefix = byte(0x2)
+	// Epoch liveness prefix, followed by epoch
+	livenessPrefix = byte(0x3)
+)
+
+// ViewBlock is a block confirmed in the view.
+type ViewBlock struct {
+	BlockIndex  uint32          `json:"blockIndex"`
+	BlockNumber uint64          `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	Signers     *utils.BitArray `json:"signers" rlp:"nil"` // The validators signed the prepareQC of the block
+}
+
+// ViewSummary records what happened in a finished view.
+type ViewSummary struct {
+	Epoch         uint64          `json:"epoch"`
+	ViewNumber    uint64          `json:"viewNumber"`
+	ProposerIndex uint32          `json:"proposerIndex"`
+	Proposer      discover.NodeID `json:"proposer"`
+	Blocks        []*ViewBlock    `json:"blocks"`
+	// Timeout is true if the view is ended by a viewChangeQC,
+	// ViewChangeSigners are the validators who signed it.
+	Timeout           bool            `json:"timeout"`
+	ViewChangeSigners *utils.BitArray `json:"viewChangeSigners" rlp:"nil"`
+}
+
+// History encapsulates functions required to record and query the finished views.
+type History interface {
+	AddViewChangeQC(qc *ctypes.ViewChangeQC) error
+	AddViewSummary(summary *ViewSummary) error
+	ViewChangeQCs(epoch, fromView, toView uint64) []*ctypes.ViewChangeQC
+	ViewSummaries(epoch, fromView, toView uint64) []*ViewSummary
+	UpdateLiveness(summary *ViewSummary, validators []discover.NodeID, amount uint32) (*EpochLiveness, error)
+	Liveness(fromEpoch, toEpoch uint64) []*EpochLiveness
+	Close()
+}
+
+// emptyHistory is a empty implementation for History
+type emptyHistory struct {
+}
+
+func (h *emptyHistory) AddViewChangeQC(qc *ctypes.ViewChangeQC) error {
+	return nil
+}
+
+func (h *emptyHistory) AddViewSummary(summary *ViewSummary) error {
+	return nil
+}
+
+func (h *emptyHistory) ViewChangeQCs(epoch, fromView, toView uint64) []*ctypes.ViewChangeQC {
+	return nil
+}
+
+func (h *emptyHistory) ViewSummaries(epoch, fromView, toView uint64) []*ViewSummary {
+	return nil
+}
+
+func (h *emptyHistory) UpdateLiveness(summary *ViewSummary, validators []discover.NodeID, amount uint32) (*EpochLiveness, error) {
+	return nil, nil
+}
+
+func (h *emptyHistory) Liveness(fromEpoch, toEpoch uint64) []*EpochLiveness {
+	return nil
+}
+
+func (h *emptyHistory) Close() {
+}
+
+// baseHistory is a default implementation for History
+type baseHistory struct {
+	db ethdb.Store
+}
+
+// NewHistory creates a new baseHistory to record the finished views.
+func NewHistory(ctx *node.ServiceContext, historyDir string) (History, error) {
+	path := ""
+	if ctx != nil && len(historyDir) > 0 {
+		path = ctx.ResolvePath(historyDir)
+	}
+	if len(path) == 0 {
+		return &emptyHistory{}, nil
+	}
+	return newBaseHistory(ctx.DBEngine(), path)
+}
+
+// NewBaseHistory opens the history database in the path with its own engine,
+// a new database is created with the default engine.
+func NewBaseHistory(path string) (*baseHistory, error) {
+	return newBaseHistory("", path)
+}
+
+func newBaseHistory(engine string, path string) (*baseHistory, error) {
+	// Open or create history database
+	db, err := ethdb.OpenStore(engine, path, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &baseHistory{db: db}, nil
+}
+
+// AddViewChangeQC records the viewChangeQC by the view which it ends.
+func (h *baseHistory) AddViewChangeQC(qc *ctypes.ViewChangeQC) error {
+	if qc == nil || qc.Len() == 0 {
+		return nil
+	}
+	data, err := rlp.EncodeToBytes(qc)
+	if err != nil {
+		return err
+	}
+	return h.db.Put(viewKey(viewChangeQCPrefix, qc.QCs[0].Epoch, qc.QCs[0].ViewNumber), data)
+}
+
+// AddViewSummary records the summary of the finished view.
+func (h *baseHistory) AddViewSummary(summary *ViewSummary) error {
+	data, err := rlp.EncodeToBytes(summary)
+	if err != nil {
+		return err
+	}
+	return h.db.Put(viewKey(viewSummaryPrefix, summary.Epoch, summary.ViewNumber), data)
+}
+
+// ViewChangeQCs returns the recorded viewChangeQCs of the epoch in the range [fromView, toView].
+func (h *baseHistory) ViewChangeQCs(epoch, fromView, toView uint64) []*ctypes.ViewChangeQC {
+	qcs := make([]*ctypes.ViewChangeQC, 0)
+	h.iterate(viewChangeQCPrefix, epoch, fromView, toView, func(data []byte) {
+		var qc ctypes.ViewChangeQC
+		if err := rlp.DecodeBytes(data, &qc); err == nil {
+			qcs = append(qcs, &qc)
+		}
+	})
+	return qcs
+}
+
+// ViewSummaries returns the recorded summaries of the epoch in the range [fromView, toView].
+func (h *baseHistory) ViewSummaries(epoch, fromView, toView uint64) []*ViewSummary {
+	summaries := make([]*ViewSummary, 0)
+	h.iterate(viewSummaryPrefix, epoch, fromView, toView, func(data []byte) {
+		var summary ViewSummary
+		if err := rlp.DecodeBytes(data, &summary); err == nil {
+			summaries = append(summaries, &summary)
+		}
+	})
+	return summaries
+}
+
+// UpdateLiveness accumulates the participation of the finished view to its epoch,
+// validators are the node ids of the epoch in the order of validator index.
+func (h *baseHistory) UpdateLiveness(summary *ViewSummary, validators []discover.NodeID, amount uint32) (*EpochLiveness, error) {
+	key := livenessKey(summary.Epoch)
+	liveness := &EpochLiveness{}
+	if data, err := h.db.Get(key); err == nil {
+		if err := rlp.DecodeBytes(data, liveness); err != nil {
+			return nil, err
+		}
+	} else if err != ethdb.ErrNotFound {
+		return nil, err
+	} else {
+		liveness.Epoch = summary.Epoch
+		liveness.Validators = make([]*ValidatorLiveness, 0, len(validators))
+		for _, id := range validators {
+			liveness.Validators = append(liveness.Validators, &ValidatorLiveness{NodeID: id})
+		}
+	}
+	liveness.update(summary, amount)
+
+	data, err := rlp.EncodeToBytes(liveness)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.db.Put(key, data); err != nil {
+		return nil, err
+	}
+	return liveness, nil
+}
+
+// Liveness returns the participation of the validators in the epochs [fromEpoch, toEpoch].
+func (h *baseHistory) Liveness(fromEpoch, toEpoch uint64) []*EpochLiveness {
+	livenesses := make([]*EpochLiveness, 0)
+	if fromEpoch > toEpoch {
+		return livenesses
+	}
+	r := &util.Range{Start: livenessKey(fromEpoch), Limit: []byte{livenessPrefix + 1}}
+	if toEpoch < ^uint64(0) {
+		r.Limit = livenessKey(toEpoch + 1)
+	}
+	it := h.db.NewIteratorWithRange(r)
+	defer it.Release()
+	for it.Next() {
+		var liveness EpochLiveness
+		if err := rlp.DecodeBytes(it.Value(), &liveness); err == nil {
+			livenesses = append(livenesses, &liveness)
+		}
+	}
+	return livenesses
+}
+
+func (h *baseHistory) iterate(prefix byte, epoch, fromView, toView uint64, fn func(data []byte)) {
+	if fromView > toView {
+		return
+	}
+	r := &util.Range{Start: viewKey(prefix, epoch, fromView)}
+	if toView < ^uint64(0) {
+		r.Limit = viewKey(prefix, epoch, toView+1)
+	} else {
+		r.Limit = viewKey(prefix, epoch+1, 0)
+	}
+	it := h.db.NewIteratorWithRange(r)
+	defer it.Release()
+	for it.Next() {
+		fn(it.Value())
+	}
+}
+
+func (h *baseHistory) Close() {
+	h.db.Close()
+}
+
+// viewKey returns the database key: prefix + epoch + viewNumber, the numbers are
+// big endian encoded so that the keys of an epoch are sorted by view number.
+func viewKey(prefix byte, epoch, viewNumber uint64) []byte {
+	key := make([]byte, 17)
+	key[0] = prefix
+	binary.BigEndian.PutUint64(key[1:9], epoch)
+	binary.BigEndian.PutUint64(key[9:], viewNumber)
+	return key
+}
@@ -0,0 +1,153 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/utils"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+func TestHistory(t *testing.T) {
+	path, _ := ioutil.TempDir("", "history")
+	defer os.RemoveAll(path)
+
+	h, err := NewHistory(nil, path)
+	assert.Nil(t, err)
+	assert.IsType(t, &emptyHistory{}, h)
+
+	h, err = NewBaseHistory(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer h.Close()
+
+	signers := utils.NewBitArray(4)
+	signers.SetIndex(0, true)
+	signers.SetIndex(2, true)
+	for view := uint64(0); view < 5; view++ {
+		summary := &ViewSummary{
+			Epoch:         1,
+			ViewNumber:    view,
+			ProposerIndex: uint32(view % 4),
+			Blocks: []*ViewBlock{
+				{BlockIndex: 0, BlockNumber: view*10 + 1, BlockHash: common.BytesToHash([]byte{byte(view)}), Signers: signers},
+			},
+		}
+		if view%2 == 1 {
+			summary.Timeout = true
+			summary.ViewChangeSigners = signers
+			assert.Nil(t, h.AddViewChangeQC(&ctypes.ViewChangeQC{QCs: []*ctypes.ViewChangeQuorumCert{
+				{Epoch: 1, ViewNumber: view, ValidatorSet: signers},
+			}}))
+		}
+		assert.Nil(t, h.AddViewSummary(summary))
+	}
+	// the other epoch is not selected
+	assert.Nil(t, h.AddViewSummary(&ViewSummary{Epoch: 2, ViewNumber: 1}))
+
+	summaries := h.ViewSummaries(1, 1, 3)
+	if !assert.Equal(t, 3, len(summaries)) {
+		return
+	}
+	assert.Equal(t, uint64(1), summaries[0].ViewNumber)
+	assert.True(t, summaries[0].Timeout)
+	assert.True(t, summaries[0].ViewChangeSigners.GetIndex(2))
+	assert.False(t, summaries[1].Timeout)
+	assert.Nil(t, summaries[1].ViewChangeSigners)
+	assert.Equal(t, uint64(21), summaries[1].Blocks[0].BlockNumber)
+	assert.True(t, summaries[1].Blocks[0].Signers.GetIndex(0))
+	assert.False(t, summaries[1].Blocks[0].Signers.GetIndex(1))
+
+	assert.Equal(t, 5, len(h.ViewSummaries(1, 0, ^uint64(0))))
+	assert.Equal(t, 0, len(h.ViewSummaries(1, 3, 1)))
+
+	qcs := h.ViewChangeQCs(1, 0, 4)
+	if !assert.Equal(t, 2, len(qcs)) {
+		return
+	}
+	assert.Equal(t, uint64(3), qcs[1].QCs[0].ViewNumber)
+}
+
+func TestLiveness(t *testing.T) {
+	path, _ := ioutil.TempDir("", "liveness")
+	defer os.RemoveAll(path)
+
+	h, err := NewBaseHistory(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer h.Close()
+
+	validators := []discover.NodeID{{1}, {2}, {3}, {4}}
+	signers := utils.NewBitArray(4)
+	signers.SetIndex(0, true)
+	signers.SetIndex(1, true)
+	signers.SetIndex(2, true)
+
+	// view 0: the proposer 0 produces 2 blocks, the validator 3 misses the votes
+	_, err = h.UpdateLiveness(&ViewSummary{
+		Epoch:         1,
+		ViewNumber:    0,
+		ProposerIndex: 0,
+		Blocks: []*ViewBlock{
+			{BlockIndex: 0, BlockNumber: 1, Signers: signers},
+			{BlockIndex: 1, BlockNumber: 2, Signers: signers},
+		},
+	}, validators, 10)
+	assert.Nil(t, err)
+
+	// view 1: the proposer 1 produces nothing and the view is timeout
+	liveness, err := h.UpdateLiveness(&ViewSummary{
+		Epoch:             1,
+		ViewNumber:        1,
+		ProposerIndex:     1,
+		Blocks:            []*ViewBlock{},
+		Timeout:           true,
+		ViewChangeSigners: signers,
+	}, validators, 10)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, uint64(2), liveness.Views)
+	assert.Equal(t, uint64(1), liveness.Timeouts)
+	assert.Equal(t, uint64(1), liveness.FirstBlockNumber)
+	assert.Equal(t, uint64(2), liveness.LastBlockNumber)
+
+	assert.Equal(t, &ValidatorLiveness{NodeID: validators[0], ProposalsExpected: 10, ProposalsProduced: 2,
+		VotesExpected: 2, VotesIncluded: 2, ViewChangesInitiated: 1}, liveness.Validator(validators[0]))
+	assert.Equal(t, &ValidatorLiveness{NodeID: validators[1], ProposalsExpected: 10, ProposalsProduced: 0,
+		VotesExpected: 2, VotesIncluded: 2, ViewChangesInitiated: 1}, liveness.Validator(validators[1]))
+	assert.Equal(t, &ValidatorLiveness{NodeID: validators[3], VotesExpected: 2}, liveness.Validator(validators[3]))
+	assert.Nil(t, liveness.Validator(discover.NodeID{5}))
+
+	_, err = h.UpdateLiveness(&ViewSummary{Epoch: 2, ViewNumber: 0}, validators, 10)
+	assert.Nil(t, err)
+	livenesses := h.Liveness(1, 2)
+	if !assert.Equal(t, 2, len(livenesses)) {
+		return
+	}
+	assert.Equal(t, liveness, livenesses[0])
+	assert.Equal(t, 1, len(h.Liveness(2, ^uint64(0))))
+}
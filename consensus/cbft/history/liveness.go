@@ -0,0 +1,103 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package history
+
+import (
+	"encoding/binary"
+
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+// ValidatorLiveness is the participation of a validator in the views it has been a validator.
+type ValidatorLiveness struct {
+	NodeID               discover.NodeID `json:"nodeId"`
+	ProposalsExpected    uint64          `json:"proposalsExpected"`    // The blocks expected to be produced as the proposer
+	ProposalsProduced    uint64          `json:"proposalsProduced"`    // The produced blocks which got a prepareQC
+	VotesExpected        uint64          `json:"votesExpected"`        // The prepareQCs of the views
+	VotesIncluded        uint64          `json:"votesIncluded"`        // The prepareQCs which include the validator's vote
+	ViewChangesInitiated uint64          `json:"viewChangesInitiated"` // The viewChangeQCs which include the validator's viewChange
+}
+
+// Add accumulates the participation of the other record.
+func (l *ValidatorLiveness) Add(o *ValidatorLiveness) {
+	l.ProposalsExpected += o.ProposalsExpected
+	l.ProposalsProduced += o.ProposalsProduced
+	l.VotesExpected += o.VotesExpected
+	l.VotesIncluded += o.VotesIncluded
+	l.ViewChangesInitiated += o.ViewChangesInitiated
+}
+
+// EpochLiveness is the participation of the validators in a cbft epoch,
+// the validators are in the order of validator index.
+type EpochLiveness struct {
+	Epoch            uint64               `json:"epoch"`
+	FirstBlockNumber uint64               `json:"firstBlockNumber"`
+	LastBlockNumber  uint64               `json:"lastBlockNumber"`
+	Views            uint64               `json:"views"`
+	Timeouts         uint64               `json:"timeouts"`
+	Validators       []*ValidatorLiveness `json:"validators"`
+}
+
+// Validator returns the participation of the validator in the epoch, nil if it's not a validator.
+func (e *EpochLiveness) Validator(nodeID discover.NodeID) *ValidatorLiveness {
+	for _, v := range e.Validators {
+		if v.NodeID == nodeID {
+			return v
+		}
+	}
+	return nil
+}
+
+// update accumulates the participation of the finished view,
+// amount is the number of blocks a proposer is expected to produce in a view.
+func (e *EpochLiveness) update(summary *ViewSummary, amount uint32) {
+	e.Views++
+	if summary.Timeout {
+		e.Timeouts++
+	}
+	for _, b := range summary.Blocks {
+		if e.FirstBlockNumber == 0 || b.BlockNumber < e.FirstBlockNumber {
+			e.FirstBlockNumber = b.BlockNumber
+		}
+		if b.BlockNumber > e.LastBlockNumber {
+			e.LastBlockNumber = b.BlockNumber
+		}
+	}
+	for i, v := range e.Validators {
+		index := uint32(i)
+		if index == summary.ProposerIndex {
+			v.ProposalsExpected += uint64(amount)
+			v.ProposalsProduced += uint64(len(summary.Blocks))
+		}
+		v.VotesExpected += uint64(len(summary.Blocks))
+		for _, b := range summary.Blocks {
+			if b.Signers.GetIndex(index) {
+				v.VotesIncluded++
+			}
+		}
+		if summary.ViewChangeSigners.GetIndex(index) {
+			v.ViewChangesInitiated++
+		}
+	}
+}
+
+func livenessKey(epoch uint64) []byte {
+	key := make([]byte, 9)
+	key[0] = livenessPrefix
+	binary.BigEndian.PutUint64(key[1:], epoch)
+	return key
+}
@@ -1,6 +1,9 @@
 package cbft
 
 import (
+	"fmt"
+
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/history"
 	"github.com/PlatONnetwork/PlatON-Go/metrics"
 )
 
@@ -27,4 +30,45 @@ var (
 	highestQCNumberGauage     = metrics.NewRegisteredGauge("cbft/gauage/block/qc/number", nil)
 	highestLockedNumberGauage = metrics.NewRegisteredGauge("cbft/gauage/block/locked/number", nil)
 	highestCommitNumberGauage = metrics.NewRegisteredGauge("cbft/gauage/block/commit/number", nil)
+	livenessEpochGauage       = metrics.NewRegisteredGauge("cbft/gauage/liveness/epoch", nil)
 )
+
+// livenessGaugeNames are the names of the gauges registered for each validator,
+// they follow the prefix of the validator.
+var livenessGaugeNames = []string{
+	"/proposal/expected",
+	"/proposal/produced",
+	"/vote/expected",
+	"/vote/included",
+	"/viewchange/initiated",
+}
+
+// livenessPrefixes are the gauge prefixes of the validators reported last time,
+// only accessed by the cbft main loop.
+var livenessPrefixes = make(map[string]struct{})
+
+// updateLivenessMetrics reports the participation of each validator in the current epoch,
+// the gauges are named by the terminal string of the node id. The gauges of the nodes
+// which are no longer validators are unregistered, so they don't grow with the validator changes.
+func updateLivenessMetrics(liveness *history.EpochLiveness) {
+	livenessEpochGauage.Update(int64(liveness.Epoch))
+
+	prefixes := make(map[string]struct{}, len(liveness.Validators))
+	for _, v := range liveness.Validators {
+		prefix := fmt.Sprintf("cbft/gauage/liveness/%s", v.NodeID.TerminalString())
+		prefixes[prefix] = struct{}{}
+		values := []uint64{v.ProposalsExpected, v.ProposalsProduced, v.VotesExpected, v.VotesIncluded, v.ViewChangesInitiated}
+		for i, name := range livenessGaugeNames {
+			metrics.GetOrRegisterGauge(prefix+name, nil).Update(int64(values[i]))
+		}
+	}
+
+	for prefix := range livenessPrefixes {
+		if _, ok := prefixes[prefix]; !ok {
+			for _, name := range livenessGaugeNames {
+				metrics.Unregister(prefix + name)
+			}
+		}
+	}
+	livenessPrefixes = prefixes
+}
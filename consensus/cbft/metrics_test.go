@@ -0,0 +1,41 @@
+package cbft
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/history"
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+func TestUpdateLivenessMetrics(t *testing.T) {
+	nodes := []discover.NodeID{{0x01}, {0x02}, {0x03}}
+	gauge := func(node discover.NodeID) interface{} {
+		return metrics.Get(fmt.Sprintf("cbft/gauage/liveness/%s/vote/included", node.TerminalString()))
+	}
+
+	updateLivenessMetrics(&history.EpochLiveness{
+		Epoch: 1,
+		Validators: []*history.ValidatorLiveness{
+			{NodeID: nodes[0], VotesIncluded: 1},
+			{NodeID: nodes[1], VotesIncluded: 2},
+		},
+	})
+	assert.NotNil(t, gauge(nodes[0]))
+	assert.NotNil(t, gauge(nodes[1]))
+
+	// the validator set changes on the next epoch
+	updateLivenessMetrics(&history.EpochLiveness{
+		Epoch: 2,
+		Validators: []*history.ValidatorLiveness{
+			{NodeID: nodes[1], VotesIncluded: 3},
+			{NodeID: nodes[2], VotesIncluded: 4},
+		},
+	})
+	assert.Nil(t, gauge(nodes[0]))
+	assert.NotNil(t, gauge(nodes[1]))
+	assert.NotNil(t, gauge(nodes[2]))
+}
@@ -383,7 +383,7 @@ func (suit *PrepareBlockTestSuite) TestPrepareBlockOneWithDifHash() {
 		GasLimit:    100000000001,
 	}
 	sign, _ := suit.view.allNode[1].engine.signFn(header.SealHash().Bytes())
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sign[:])
 	block2 := types.NewBlockWithHeader(header)
 	fmt.Println(common.Bytes2Hex(block2.Extra()))
 	_, qc := suit.view.firstProposer().blockTree.FindBlockAndQC(suit.view.firstProposer().state.HighestQCBlock().Hash(),
@@ -19,9 +19,14 @@ package types
 import (
 	"errors"
 
+	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
+// headerQCOffset is the offset of the `QuorumCert` in the header extra,
+// it follows the 32 bytes vanity and the seal of the producer.
+const headerQCOffset = 32 + common.ExtraSeal
+
 // EncodeExtra encode cbft version and `QuorumCert` as extra data.
 func EncodeExtra(cbftVersion byte, qc *QuorumCert) ([]byte, error) {
 	extra := []byte{cbftVersion}
@@ -46,3 +51,16 @@ func DecodeExtra(extra []byte) (byte, *QuorumCert, error) {
 	}
 	return version, &qc, nil
 }
+
+// DecodeHeaderQC decode the `QuorumCert` carried by the header extra after the seal,
+// it returns nil if the header doesn't carry any `QuorumCert`.
+func DecodeHeaderQC(extra []byte) (*QuorumCert, error) {
+	if len(extra) <= headerQCOffset {
+		return nil, nil
+	}
+	var qc QuorumCert
+	if err := rlp.DecodeBytes(extra[headerQCOffset:], &qc); err != nil {
+		return nil, err
+	}
+	return &qc, nil
+}
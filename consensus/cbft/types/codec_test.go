@@ -23,6 +23,7 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/utils"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
 func TestCodec(t *testing.T) {
@@ -47,3 +48,28 @@ func TestCodec(t *testing.T) {
 	assert.Equal(t, byte(cbftVersion), version)
 	assert.Equal(t, qc.BlockHash, cert.BlockHash)
 }
+
+func TestDecodeHeaderQC(t *testing.T) {
+	extra := make([]byte, 97)
+	qc, err := DecodeHeaderQC(extra)
+	assert.Nil(t, err)
+	assert.Nil(t, qc)
+
+	want := &QuorumCert{
+		Epoch:        1,
+		ViewNumber:   2,
+		BlockHash:    common.BytesToHash(utils.Rand32Bytes(32)),
+		BlockNumber:  10,
+		BlockIndex:   3,
+		ValidatorSet: utils.NewBitArray(25),
+	}
+	enc, err := rlp.EncodeToBytes(want)
+	assert.Nil(t, err)
+	qc, err = DecodeHeaderQC(append(extra, enc...))
+	assert.Nil(t, err)
+	assert.Equal(t, want.BlockHash, qc.BlockHash)
+	assert.Equal(t, want.BlockNumber, qc.BlockNumber)
+
+	_, err = DecodeHeaderQC(append(extra, 0xff))
+	assert.NotNil(t, err)
+}
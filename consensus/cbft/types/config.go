@@ -19,6 +19,7 @@ package types
 import (
 	"crypto/ecdsa"
 
+	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/params"
@@ -32,9 +33,11 @@ type OptionsConfig struct {
 
 	PeerMsgQueueSize  uint64
 	EvidenceDir       string
-	MaxPingLatency    int64 // maxPingLatency is the time in milliseconds between Ping and Pong
-	MaxQueuesLimit    int64 // The maximum value that a single node can send a message.
-	BlacklistDeadline int64 // Blacklist expiration time. unit: minute.
+	HistoryDir        string         // The directory to record the finished views, disabled if empty.
+	MaxPingLatency    int64          // maxPingLatency is the time in milliseconds between Ping and Pong
+	MaxQueuesLimit    int64          // The maximum value that a single node can send a message.
+	BlacklistDeadline int64          // Blacklist expiration time. unit: minute.
+	EvidenceReporter  common.Address // The account to report the duplicate evidences automatically, disabled if empty.
 
 	Period uint64
 	Amount uint32
@@ -538,13 +538,13 @@ func TestValidatorPoolVerify(t *testing.T) {
 	}
 	sig, err := crypto.Sign(header.SealHash().Bytes(), priKey)
 	assert.Nil(t, err)
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sig[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sig[:])
 
 	assert.Nil(t, vp.VerifyHeader(&header))
 
 	priKey1, _ := crypto.GenerateKey()
 	sigWrong, _ := crypto.Sign(header.SealHash().Bytes(), priKey1)
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sigWrong[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sigWrong[:])
 }
 
 type mockAgency struct {
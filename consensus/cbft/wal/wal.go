@@ -148,6 +148,10 @@ func NewWal(ctx *node.ServiceContext, specifiedPath string) (Wal, error) {
 	if originPath == "" {
 		originPath = ctx.ResolvePath(walDir)
 	}
+	var engine string
+	if ctx != nil {
+		engine = ctx.DBEngine()
+	}
 
 	// Make sure the wal directory exists,If not exist create it.
 	ensureWalDir := func(path string) (string, error) {
@@ -161,7 +165,7 @@ func NewWal(ctx *node.ServiceContext, specifiedPath string) (Wal, error) {
 	}
 	// Open or create WAL Database
 	createMetaDB := func(path, name string) (IWALDatabase, error) {
-		db, err := createWalDB(filepath.Join(path, name))
+		db, err := openDatabase(engine, filepath.Join(path, name))
 		if err != nil {
 			return nil, err
 		}
@@ -17,13 +17,11 @@
 package wal
 
 import (
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/log"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
-	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
-	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 type IWALDatabase interface {
@@ -37,7 +35,7 @@ type IWALDatabase interface {
 
 type WALDatabase struct {
 	fn  string      // filename for reporting
-	db  *leveldb.DB // LevelDB instance
+	db  ethdb.Store // Database instance of the configured engine
 	log log.Logger  // Contextual logger tracking the database path
 }
 
@@ -45,70 +43,55 @@ func createWalDB(file string) (IWALDatabase, error) {
 	if file == "" {
 		return nil, errors.New("create waldb error,file is empty")
 	}
-	db, err := openDatabase(file)
+	db, err := openDatabase("", file)
 	if err != nil {
 		return nil, err
 	}
 	return db, nil
 }
 
-func openDatabase(file string) (IWALDatabase, error) {
-	db, err := newWALDatabase(file, 0, 0)
+// openDatabase opens the wal database with the engine, an empty engine opens the
+// existing database with its own engine.
+func openDatabase(engine string, file string) (IWALDatabase, error) {
+	db, err := newWALDatabase(engine, file, 0, 0)
 	if err != nil {
 		return nil, err
 	}
 	return db, nil
 }
 
-func newWALDatabase(file string, cache int, handles int) (*WALDatabase, error) {
-	logger := log.New("Wal_database", file)
-
-	// Ensure we have some minimal caching and file guarantees
-	if cache < 16 {
-		cache = 16
-	}
-	if handles < 16 {
-		handles = 16
-	}
-	logger.Info("Allocated cache and file handles", "cache", cache, "handles", handles)
-
-	// Open the db and recover any potential corruptions
-	db, err := leveldb.OpenFile(file, &opt.Options{
-		OpenFilesCacheCapacity: handles,
-		BlockCacheCapacity:     cache / 2 * opt.MiB,
-		WriteBuffer:            cache / 4 * opt.MiB, // Two of these are used internally
-		Filter:                 filter.NewBloomFilter(10),
-	})
-	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
-		db, err = leveldb.RecoverFile(file, nil)
-	}
-	// (Re)check for errors and abort if opening of the db failed
+func newWALDatabase(engine string, file string, cache int, handles int) (*WALDatabase, error) {
+	db, err := ethdb.OpenStore(engine, file, cache, handles)
 	if err != nil {
 		return nil, err
 	}
 	return &WALDatabase{
 		fn:  file,
 		db:  db,
-		log: logger,
+		log: log.New("Wal_database", file),
 	}, nil
 }
 
-// Put puts the given key / value to the queue
+// Put puts the given key / value to the queue, the write is flushed to the disk
+// if it's synchronous.
 func (db *WALDatabase) Put(key []byte, value []byte, wo *opt.WriteOptions) error {
-	return db.db.Put(key, value, wo)
+	if wo.GetSync() {
+		return db.db.PutSync(key, value)
+	}
+	return db.db.Put(key, value)
 }
 
 func (db *WALDatabase) Has(key []byte) (bool, error) {
-	return db.db.Has(key, nil)
+	return db.db.Has(key)
 }
 
 func (db *WALDatabase) NewIterator(key []byte, wo *opt.ReadOptions) iterator.Iterator {
-	return db.db.NewIterator(util.BytesPrefix(key), wo)
+	return db.db.NewIteratorWithPrefix(key)
 }
 
 // Get returns the given key if it's present.
 func (db *WALDatabase) Get(key []byte) ([]byte, error) {
-	dat, err := db.db.Get(key, nil)
+	dat, err := db.db.Get(key)
 	if err != nil {
 		return nil, err
 	}
@@ -117,15 +100,9 @@ func (db *WALDatabase) Get(key []byte) ([]byte, error) {
 
 // Delete deletes the key from the queue and database
 func (db *WALDatabase) Delete(key []byte) error {
-	return db.db.Delete(key, nil)
+	return db.db.Delete(key)
 }
 
 func (db *WALDatabase) Close() {
-	// Stop the metrics collection to avoid internal database races
-	err := db.db.Close()
-	if err == nil {
-		db.log.Info("Database closed")
-	} else {
-		db.log.Error("Failed to close database", "err", err)
-	}
+	db.db.Close()
 }
@@ -0,0 +1,229 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/protocols"
+
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+// The offline inspection of the wal directory, it is used by the `platon wal` command
+// to diagnose a node which can't restart after a crash.
+// None of the functions below may be used while the node is running.
+
+// JournalFile describes a journal file of the wal directory.
+type JournalFile struct {
+	Name   string
+	FileID uint32
+	Size   int64
+}
+
+// JournalEntry is a verified and decoded message of the journal file.
+type JournalEntry struct {
+	FileID    uint32
+	Seq       uint64 // The offset of the entry in the journal file
+	Timestamp uint64
+	MsgType   uint16
+	Msg       interface{}
+}
+
+// JournalCorruption reports the first invalid entry of the journal file,
+// the tail of the file starting from Seq can't be loaded by cbft.
+type JournalCorruption struct {
+	FileID uint32
+	Seq    uint64
+	Reason string
+}
+
+func (c *JournalCorruption) Error() string {
+	return fmt.Sprintf("corrupted journal entry, fileID:%d, seq:%d, reason:%s", c.FileID, c.Seq, c.Reason)
+}
+
+// WalMeta is the consensus state stored in the wal database.
+type WalMeta struct {
+	ChainState    *protocols.ChainState
+	ViewChange    *ViewChangeMessage // The journal position where the loading starts from
+	ViewChangeQCs []*ctypes.ViewChangeQC
+}
+
+// journalMessage is used to decode the timestamp and keep the raw message.
+type journalMessage struct {
+	Timestamp uint64
+	Data      rlp.RawValue
+}
+
+// ListJournalFiles retrieves the journal files of the wal directory in ascending order.
+func ListJournalFiles(path string) ([]*JournalFile, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	files := make([]*JournalFile, 0)
+	for _, f := range listJournalFiles(path) {
+		info, err := os.Stat(filepath.Join(path, f.name))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &JournalFile{
+			Name:   f.name,
+			FileID: f.num,
+			Size:   info.Size(),
+		})
+	}
+	return files, nil
+}
+
+// ScanJournal reads the journal file from the beginning, verifies the crc
+// and decodes each message. fn is called for every valid entry in order.
+// The scan stops at the first invalid entry and returns a *JournalCorruption.
+func ScanJournal(path string, fileID uint32, fn func(entry *JournalEntry) error) error {
+	file, err := os.Open(filepath.Join(path, fmt.Sprintf("wal.%d", fileID)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	size := uint64(info.Size())
+
+	bufReader := bufio.NewReaderSize(file, readBufferLimitSize)
+	corrupted := func(seq uint64, reason string) error {
+		return &JournalCorruption{FileID: fileID, Seq: seq, Reason: reason}
+	}
+
+	index := make([]byte, 10)
+	for seq := uint64(0); seq < size; {
+		if _, err := io.ReadFull(bufReader, index); err != nil {
+			return corrupted(seq, "incomplete entry header")
+		}
+		crc := binary.BigEndian.Uint32(index[0:4])      // 4 byte
+		length := binary.BigEndian.Uint32(index[4:8])   // 4 byte
+		msgType := binary.BigEndian.Uint16(index[8:10]) // 2 byte
+
+		if seq+10+uint64(length) > size {
+			return corrupted(seq, fmt.Sprintf("entry length %d exceeds the file size", length))
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(bufReader, data); err != nil {
+			return corrupted(seq, "incomplete entry data")
+		}
+
+		// check crc
+		if _crc := crc32.Checksum(data, crc32c); crc != _crc {
+			return corrupted(seq, fmt.Sprintf("crc mismatch, expect:%d, actual:%d", crc, _crc))
+		}
+		if msgType < protocols.ConfirmedViewChangeMsg || msgType > protocols.SendPrepareVoteMsg {
+			return corrupted(seq, fmt.Sprintf("invalid msg type %d", msgType))
+		}
+		var jm journalMessage
+		if err := rlp.DecodeBytes(data, &jm); err != nil {
+			return corrupted(seq, err.Error())
+		}
+		msg, err := WALDecode(data, msgType)
+		if err != nil {
+			return corrupted(seq, err.Error())
+		}
+
+		if err := fn(&JournalEntry{
+			FileID:    fileID,
+			Seq:       seq,
+			Timestamp: jm.Timestamp,
+			MsgType:   msgType,
+			Msg:       msg,
+		}); err != nil {
+			return err
+		}
+		seq += 10 + uint64(length)
+	}
+	return nil
+}
+
+// VerifyJournal scans the journal file and returns the number of valid entries.
+// If the file is corrupted, the *JournalCorruption is returned as well.
+func VerifyJournal(path string, fileID uint32) (int, error) {
+	count := 0
+	err := ScanJournal(path, fileID, func(entry *JournalEntry) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// TruncateJournal drops the tail of the journal file starting from the specified seq,
+// the seq is expected to be the one reported by JournalCorruption.
+func TruncateJournal(path string, fileID uint32, seq uint64) error {
+	return os.Truncate(filepath.Join(path, fmt.Sprintf("wal.%d", fileID)), int64(seq))
+}
+
+// MetaPath returns the path of the wal database in the wal directory.
+func MetaPath(path string) string {
+	return filepath.Join(path, metaDBName)
+}
+
+// ReadWalMeta reads the consensus state of the wal database.
+// The missing items are left nil.
+func ReadWalMeta(path string) (*WalMeta, error) {
+	metaPath := MetaPath(path)
+	if _, err := os.Stat(metaPath); err != nil {
+		return nil, err
+	}
+	db, err := createWalDB(metaPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	meta := &WalMeta{
+		ViewChangeQCs: make([]*ctypes.ViewChangeQC, 0),
+	}
+	if data, err := db.Get(chainStateKey); err == nil {
+		var cs protocols.ChainState
+		if err := rlp.DecodeBytes(data, &cs); err != nil {
+			return nil, errGetChainState
+		}
+		meta.ChainState = &cs
+	}
+	if data, err := db.Get(viewChangeKey); err == nil {
+		var vc ViewChangeMessage
+		if err := rlp.DecodeBytes(data, &vc); err != nil {
+			return nil, errGetViewChangeMeta
+		}
+		meta.ViewChange = &vc
+	}
+	it := db.NewIterator(viewChangeQCPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		var qc ctypes.ViewChangeQC
+		if err := rlp.DecodeBytes(it.Value(), &qc); err != nil {
+			return nil, errGetViewChangeQC
+		}
+		meta.ViewChangeQCs = append(meta.ViewChangeQCs, &qc)
+	}
+	return meta, nil
+}
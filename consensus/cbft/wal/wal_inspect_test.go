@@ -0,0 +1,90 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/protocols"
+)
+
+func TestInspectJournal(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "wal")
+	defer os.RemoveAll(tempDir)
+
+	wal, _ := NewWal(nil, tempDir)
+	assert.Nil(t, testWalUpdateViewChange(wal))
+	assert.Nil(t, wal.WriteSync(buildSendPrepareBlock()))
+	assert.Nil(t, wal.WriteSync(buildSendPrepareVote()))
+	assert.Nil(t, wal.WriteSync(buildSendViewChange()))
+	wal.Close()
+
+	files, err := ListJournalFiles(tempDir)
+	assert.Nil(t, err)
+	if !assert.Equal(t, 1, len(files)) {
+		return
+	}
+	fileID, size := files[0].FileID, files[0].Size
+
+	entries := make([]*JournalEntry, 0)
+	assert.Nil(t, ScanJournal(tempDir, fileID, func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	if !assert.Equal(t, 3, len(entries)) {
+		return
+	}
+	assert.Equal(t, uint64(0), entries[0].Seq)
+	assert.Equal(t, uint16(protocols.SendPrepareBlockMsg), entries[0].MsgType)
+	assert.IsType(t, &protocols.SendPrepareVote{}, entries[1].Msg)
+	assert.Equal(t, epoch, entries[2].Msg.(*protocols.SendViewChange).Epoch())
+	assert.NotZero(t, entries[2].Timestamp)
+
+	meta, err := ReadWalMeta(tempDir)
+	assert.Nil(t, err)
+	assert.Equal(t, epoch, meta.ViewChange.Epoch)
+	assert.Nil(t, meta.ChainState)
+
+	// a crash in the middle of writing leaves an incomplete entry
+	file, _ := os.OpenFile(filepath.Join(tempDir, files[0].Name), os.O_WRONLY|os.O_APPEND, 0755)
+	file.Write([]byte{1, 2, 3, 4, 0, 0, 0, 100, 0, 3, 5, 6})
+	file.Close()
+
+	count, err := VerifyJournal(tempDir, fileID)
+	assert.Equal(t, 3, count)
+	corruption, ok := err.(*JournalCorruption)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, uint64(size), corruption.Seq)
+
+	assert.Nil(t, TruncateJournal(tempDir, fileID, corruption.Seq))
+	count, err = VerifyJournal(tempDir, fileID)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	// the repaired journal can be loaded by cbft
+	wal, _ = NewWal(nil, tempDir)
+	loaded, err := testWalLoad(wal)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, loaded)
+}
@@ -6,45 +6,22 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
-	"github.com/PlatONnetwork/PlatON-Go/crypto/sha3"
-	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	lru "github.com/hashicorp/golang-lru"
 )
 
 var (
-	// ExtraSeal fixed number of extra-data suffix bytes reserved for signer seal
+	// ExtraSeal fixed number of extra-data bytes reserved for signer seal after the 32 bytes vanity
 	ExtraSeal = 65
 	// ErrMissingSignature is returned if a block's extra-data section doesn't seem
 	// to contain a 65 byte secp256k1 signature.
-	ErrMissingSignature = errors.New("extra-data 65 byte signature suffix missing")
+	ErrMissingSignature = errors.New("extra-data 65 byte signature missing")
 )
 
 // SigHash returns the hash which is used as input for the proof-of-authority
 // signing. It is the hash of the entire header apart from the 65 byte signature
-// contained at the end of the extra data.
-//
-// Note, the method requires the extra data to be at least 65 bytes, otherwise it
-// panics. This is done to avoid accidentally using both forms (signature present
-// or not), which could be abused to produce different hashes for the same header.
+// contained in extra[32:97], the data after the signature is hashed too.
 func SigHash(header *types.Header) (hash common.Hash) {
-	hasher := sha3.NewKeccak256()
-
-	rlp.Encode(hasher, []interface{}{
-		header.ParentHash,
-		header.Coinbase,
-		header.Root,
-		header.TxHash,
-		header.ReceiptHash,
-		header.Bloom,
-		header.Number,
-		header.GasLimit,
-		header.GasUsed,
-		header.Time,
-		header.Extra[:len(header.Extra)-65], // Yes, this will panic if extra is too short
-		header.Nonce,
-	})
-	hasher.Sum(hash[:0])
-	return hash
+	return header.SealHash()
 }
 
 // Ecrecover extracts the Ethereum account address from a signed header.
@@ -55,10 +32,10 @@ func Ecrecover(header *types.Header, sigcache *lru.ARCCache) (common.Address, er
 		return address.(common.Address), nil
 	}
 	// Retrieve the signature from the header extra-data
-	if len(header.Extra) < ExtraSeal {
+	if len(header.Extra) < 32+ExtraSeal {
 		return common.Address{}, ErrMissingSignature
 	}
-	signature := header.Extra[len(header.Extra)-ExtraSeal:]
+	signature := header.Signature()
 
 	// Recover the public key and the Ethereum address
 	pubkey, err := crypto.Ecrecover(SigHash(header).Bytes(), signature)
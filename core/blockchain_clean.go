@@ -140,9 +140,9 @@ func (c *Cleaner) loop() {
 func (c *Cleaner) cleanup() {
 	defer c.cleaning.Set(false)
 
-	db, ok := c.blockchain.db.(*ethdb.LDBDatabase)
+	db, ok := c.blockchain.db.(ethdb.Store)
 	if !ok {
-		log.Warn("The database not a leveldb, discard cleanup operation")
+		log.Warn("The database not an on-disk store, discard cleanup operation")
 		return
 	}
 
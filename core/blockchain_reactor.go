@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/consensus"
 	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
 	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
@@ -15,6 +16,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/x/handler"
 	"github.com/PlatONnetwork/PlatON-Go/x/staking"
 	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
@@ -37,6 +39,7 @@ type BlockChainReactor struct {
 	NodeId        discover.NodeID           // The nodeId of current node
 	exitCh        chan chan struct{}        // Used to receive an exit signal
 	exitOnce      sync.Once
+	chainConfig   *params.ChainConfig // Used to check the forks of the ppos state
 }
 
 var (
@@ -155,6 +158,10 @@ func (bcr *BlockChainReactor) SetVRFhandler(vher *handler.VrfHandler) {
 	bcr.vh = vher
 }
 
+func (bcr *BlockChainReactor) SetChainConfig(config *params.ChainConfig) {
+	bcr.chainConfig = config
+}
+
 func (bcr *BlockChainReactor) SetPrivateKey(privateKey *ecdsa.PrivateKey) {
 	if bcr.validatorMode == common.PPOS_VALIDATOR_MODE && nil != privateKey {
 		if nil != bcr.vh {
@@ -302,6 +309,14 @@ func (bcr *BlockChainReactor) EndBlocker(header *types.Header, state xcom.StateD
 			"pposHash", hex.EncodeToString(pposHash))
 	}
 
+	// commit the ppos state to the state trie, after all the ppos state of the block is written
+	if nil != bcr.chainConfig && bcr.chainConfig.IsPPOSCommit(header.Number) {
+		if err := commitPPOSState(blockHash, isPPOSCommitForkBlock(bcr.chainConfig, header.Number), state); nil != err {
+			log.Error("Failed to commit ppos state", "blockHash", blockHash.Hex(), "blockNumber", header.Number.Uint64(), "err", err)
+			return err
+		}
+	}
+
 	// This must not be deleted
 	root := state.IntermediateRoot(true)
 	log.Debug("EndBlock StateDB root, end", "blockHash", blockHash.Hex(), "blockNumber",
@@ -337,17 +352,84 @@ func (bcr *BlockChainReactor) VerifyTx(tx *types.Transaction, to common.Address)
 		return nil
 	}
 	if contract != nil {
-		if fcode, _, _, err := plugin.VerifyTxData(input, contract.FnSigns()); nil != err {
+		fcode, _, _, err := plugin.VerifyTxData(input, contract.FnSigns())
+		if stkc, ok := contract.(*vm.StakingContract); ok && nil != err {
+			// the staking txs without the commission rate are taken until the delegate reward is activated
+			fcode, _, _, err = plugin.VerifyTxData(input, stkc.FnSignsV0())
+		}
+		if nil != err {
 			return err
-		} else {
-			return contract.CheckGasPrice(tx.GasPrice(), fcode)
 		}
+		return contract.CheckGasPrice(tx.GasPrice(), fcode)
 	} else {
 		log.Warn("Cannot find an appropriate PlatONPrecompiledContract!")
 		return nil
 	}
 }
 
+// IsPriorityTx checks whether the tx is a PPOS duty with a deadline, which are the
+// votes and version declarations sent by the staking accounts of the current
+// verifiers and the duplicate sign reports against the recent verifiers, which
+// are not slashed yet in the state of the header.
+func (bcr *BlockChainReactor) IsPriorityTx(tx *types.Transaction, from common.Address, header *types.Header, state *state.StateDB) bool {
+	if bcr.validatorMode != common.PPOS_VALIDATOR_MODE || tx.To() == nil {
+		return false
+	}
+	to := *tx.To()
+	if to != cvm.GovContractAddr && to != cvm.SlashingContractAddr {
+		return false
+	}
+	contract := vm.PlatONPrecompiledContracts[to].(vm.PlatONPrecompiledContract)
+	fcode, _, params, err := plugin.VerifyTxData(tx.Data(), contract.FnSigns())
+	if nil != err {
+		return false
+	}
+
+	switch {
+	case to == cvm.GovContractAddr && (fcode == vm.Vote || fcode == vm.Declare):
+		nodeID, ok := params[0].Interface().(discover.NodeID)
+		if !ok {
+			return false
+		}
+		verifiers, err := plugin.StakingInstance().GetVerifierList(header.Hash(), header.Number.Uint64(), plugin.QueryStartNotIrr)
+		if nil != err {
+			log.Debug("Failed to check the priority tx: query verifiers is failed", "hash", tx.Hash(), "err", err)
+			return false
+		}
+		for _, v := range verifiers {
+			if v.NodeId == nodeID {
+				return v.StakingAddress == from
+			}
+		}
+		return false
+	case to == cvm.SlashingContractAddr && fcode == vm.TxReportDuplicateSign:
+		dupType, ok := params[0].Interface().(uint8)
+		if !ok {
+			return false
+		}
+		data, ok := params[1].Interface().(string)
+		if !ok {
+			return false
+		}
+		evidence, err := plugin.SlashInstance().DecodeEvidence(consensus.EvidenceType(dupType), data)
+		if nil != err {
+			return false
+		}
+		// the reports of the slashed evidences are rejected by the contract
+		if txHash, _ := plugin.SlashInstance().CheckDuplicateSign(evidence.Address(), evidence.BlockNumber(), evidence.Type(), state); len(txHash) > 0 {
+			return false
+		}
+		// the forged reports must not take the lane
+		if err := evidence.Validate(); nil != err {
+			log.Debug("Failed to check the priority tx: the evidence is invalid", "hash", tx.Hash(), "err", err)
+			return false
+		}
+		return plugin.StakingInstance().IsCandidateNode(evidence.NodeID())
+	default:
+		return false
+	}
+}
+
 func (bcr *BlockChainReactor) Sign(msg interface{}) error {
 	return nil
 }
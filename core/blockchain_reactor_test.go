@@ -1,15 +1,27 @@
 package core
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 	"time"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
 	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/event"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
+	"github.com/PlatONnetwork/PlatON-Go/x/handler"
+	"github.com/PlatONnetwork/PlatON-Go/x/plugin"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 )
 
 func TestBlockChainReactor_Close(t *testing.T) {
@@ -44,3 +56,84 @@ func TestBlockChainReactor_Close(t *testing.T) {
 		snapshotdb.Instance().Clear()
 	})
 }
+
+// endBlockWriter writes a ppos key in EndBlock, like the election of the staking plugin.
+type endBlockWriter struct {
+	key, value []byte
+}
+
+func (w *endBlockWriter) BeginBlock(blockHash common.Hash, header *types.Header, state xcom.StateDB) error {
+	return nil
+}
+
+func (w *endBlockWriter) EndBlock(blockHash common.Hash, header *types.Header, state xcom.StateDB) error {
+	return snapshotdb.Instance().Put(blockHash, w.key, w.value)
+}
+
+func (w *endBlockWriter) Confirmed(nodeId discover.NodeID, block *types.Block) error {
+	return nil
+}
+
+func TestBlockChainReactor_CommitPPOSState(t *testing.T) {
+	defer snapshotdb.Instance().Clear()
+	if err := gov.InitGenesisGovernParam(snapshotdb.Instance()); nil != err {
+		t.Fatal(err)
+	}
+
+	sk, err := crypto.GenerateKey()
+	if nil != err {
+		t.Fatal(err)
+	}
+	vh := handler.NewVrfHandler(hexutil.MustDecode("0x0376e56dffd12ab53bb149bda4e0cbce2b6aabe4cccc0df0b5a39e12977a2fcd23"))
+	vh.SetPrivateKey(sk)
+
+	writer := &endBlockWriter{
+		key:   staking.CanMutableKeyByAddr(common.BytesToAddress([]byte("endBlock"))),
+		value: []byte("election"),
+	}
+	reactor := &BlockChainReactor{
+		validatorMode: common.PPOS_VALIDATOR_MODE,
+		basePluginMap: map[int]plugin.BasePlugin{xcom.StakingRule: writer},
+		endRule:       []int{xcom.StakingRule},
+		vh:            vh,
+		chainConfig:   &params.ChainConfig{PPOSCommitBlock: big.NewInt(0)},
+	}
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		ParentHash: common.BytesToHash([]byte("genesis")),
+		Time:       big.NewInt(1),
+		Extra:      make([]byte, 97),
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+
+	if err := reactor.BeginBlocker(header, statedb); nil != err {
+		t.Fatal(err)
+	}
+	// the write of a staking tx executed between the begin and end blockers
+	txKey := staking.CanBaseKeyByAddr(common.BytesToAddress([]byte("stakingTx")))
+	txValue := []byte("candidate")
+	if err := snapshotdb.Instance().Put(common.ZeroHash, txKey, txValue); nil != err {
+		t.Fatal(err)
+	}
+	if err := reactor.EndBlocker(header, statedb); nil != err {
+		t.Fatal(err)
+	}
+
+	root, err := statedb.Commit(true)
+	if nil != err {
+		t.Fatal(err)
+	}
+	for _, kv := range [][2][]byte{{txKey, txValue}, {writer.key, writer.value}} {
+		proof, err := GetPPOSProof(statedb, kv[0])
+		if nil != err {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(proof.Value, kv[1]) {
+			t.Fatalf("ppos state of key %x mismatch, want %x, have %x", kv[0], kv[1], []byte(proof.Value))
+		}
+		if err := proof.Verify(root); nil != err {
+			t.Fatal(err)
+		}
+	}
+}
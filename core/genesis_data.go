@@ -118,6 +118,14 @@ func genesisStakingData(snapdb snapshotdb.DB, g *Genesis, stateDB *state.StateDB
 			ReleasedHes:        new(big.Int).SetInt64(0),
 			RestrictingPlan:    new(big.Int).SetInt64(0),
 			RestrictingPlanHes: new(big.Int).SetInt64(0),
+			DelegateTotal:      new(big.Int).SetInt64(0),
+			DelegateTotalHes:   new(big.Int).SetInt64(0),
+		}
+
+		// the genesis version before the delegate reward stores the candidates in the layout before it
+		if !params.IsFeatureActive(plugin.FeatureDelegateReward, programVersion) {
+			base.SetLegacy(true)
+			mutable.SetLegacy(true)
 		}
 
 		nodeAddr, err := xutil.NodeId2Addr(base.NodeId)
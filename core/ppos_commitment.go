@@ -0,0 +1,174 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/trie"
+	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
+)
+
+// The ppos state of the snapshotdb is committed to the state trie by mirroring the keys
+// to the storage of cvm.PPOSCommitAddr after the PPOSCommitBlock fork, the storage root
+// of the account is the merkle commitment of the ppos state and it's covered by the state
+// root of the header. A ppos record is proven by the account proof and the storage proof.
+
+// snapshotdbMetaPrefix is the prefix of the keys used by the snapshotdb itself, they are not ppos state.
+var snapshotdbMetaPrefix = []byte("snapshotdb")
+
+var errPPOSCommitNotActivated = errors.New("the ppos state commitment is not activated")
+
+// isPPOSCommitForkBlock returns whether all the ppos keys are mirrored in the block,
+// the genesis is not executed so the keys are mirrored in the first block if the fork is 0.
+func isPPOSCommitForkBlock(config *params.ChainConfig, number *big.Int) bool {
+	if config.PPOSCommitBlock == nil {
+		return false
+	}
+	if config.PPOSCommitBlock.Sign() == 0 {
+		return number.Cmp(common.Big1) == 0
+	}
+	return number.Cmp(config.PPOSCommitBlock) == 0
+}
+
+// commitPPOSState mirrors the ppos keys written by the block to the storage of the commitment account,
+// all the ppos keys are mirrored in the fork block.
+func commitPPOSState(blockHash common.Hash, fork bool, state xcom.StateDB) error {
+	if state.GetNonce(cvm.PPOSCommitAddr) == 0 {
+		// keep the account from being deleted as an empty account
+		state.SetNonce(cvm.PPOSCommitAddr, 1)
+	}
+	if !fork {
+		return snapshotdb.Instance().WalkBlock(blockHash, func(key, value []byte) error {
+			state.SetState(cvm.PPOSCommitAddr, common.CopyBytes(key), common.CopyBytes(value))
+			return nil
+		})
+	}
+	itr := snapshotdb.Instance().Ranking(blockHash, nil, 0)
+	defer itr.Release()
+	for itr.Next() {
+		if bytes.HasPrefix(itr.Key(), snapshotdbMetaPrefix) {
+			continue
+		}
+		state.SetState(cvm.PPOSCommitAddr, common.CopyBytes(itr.Key()), common.CopyBytes(itr.Value()))
+	}
+	return itr.Error()
+}
+
+// PPOSProof is the merkle proof of a ppos key against the state root of a block,
+// the value is empty if the key doesn't exist.
+type PPOSProof struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	Key          hexutil.Bytes   `json:"key"`
+	Value        hexutil.Bytes   `json:"value"`
+	StorageProof []hexutil.Bytes `json:"storageProof"`
+}
+
+// GetPPOSProof builds the proof of the ppos key from the state of a block.
+func GetPPOSProof(statedb *state.StateDB, key []byte) (*PPOSProof, error) {
+	storageTrie := statedb.StorageTrie(cvm.PPOSCommitAddr)
+	if storageTrie == nil {
+		return nil, errPPOSCommitNotActivated
+	}
+	accountProof, err := statedb.GetProof(cvm.PPOSCommitAddr)
+	if err != nil {
+		return nil, err
+	}
+	storageProof, err := statedb.GetStorageProof(cvm.PPOSCommitAddr, key)
+	if err != nil {
+		return nil, err
+	}
+	return &PPOSProof{
+		Address:      cvm.PPOSCommitAddr,
+		AccountProof: toHexProof(accountProof),
+		StorageHash:  storageTrie.Hash(),
+		Key:          common.CopyBytes(key),
+		Value:        statedb.GetState(cvm.PPOSCommitAddr, key),
+		StorageProof: toHexProof(storageProof),
+	}, nil
+}
+
+// Verify checks the proof against the state root, the storage leaf of a key is
+// the rlp encoding of keccak256(value) without the leading zero bytes.
+func (p *PPOSProof) Verify(root common.Hash) error {
+	if p.Address != cvm.PPOSCommitAddr {
+		return fmt.Errorf("invalid address %s", p.Address.String())
+	}
+	data, _, err := trie.VerifyProof(root, crypto.Keccak256(p.Address.Bytes()), proofDB(p.AccountProof))
+	if err != nil {
+		return fmt.Errorf("invalid account proof: %v", err)
+	}
+	if data == nil {
+		return errPPOSCommitNotActivated
+	}
+	var account state.Account
+	if err := rlp.DecodeBytes(data, &account); err != nil {
+		return fmt.Errorf("invalid account: %v", err)
+	}
+	if account.Root != p.StorageHash {
+		return fmt.Errorf("storage hash mismatch, want %s, have %s", account.Root.String(), p.StorageHash.String())
+	}
+
+	leaf, _, err := trie.VerifyProof(account.Root, crypto.Keccak256(p.Key), proofDB(p.StorageProof))
+	if err != nil {
+		return fmt.Errorf("invalid storage proof: %v", err)
+	}
+	if len(p.Value) == 0 {
+		if leaf != nil {
+			return errors.New("the key exists but the value is empty")
+		}
+		return nil
+	}
+	var valueKey []byte
+	if err := rlp.DecodeBytes(leaf, &valueKey); err != nil {
+		return fmt.Errorf("invalid storage leaf: %v", err)
+	}
+	if !bytes.Equal(valueKey, bytes.TrimLeft(crypto.Keccak256(p.Value), "\x00")) {
+		return errors.New("value mismatch")
+	}
+	return nil
+}
+
+func toHexProof(proof [][]byte) []hexutil.Bytes {
+	nodes := make([]hexutil.Bytes, 0, len(proof))
+	for _, node := range proof {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func proofDB(proof []hexutil.Bytes) *ethdb.MemDatabase {
+	db := ethdb.NewMemDatabase()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
@@ -30,6 +30,9 @@ import (
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
 	data, _ := db.Get(headerHashKey(number))
+	if len(data) == 0 {
+		data = readAncient(db, freezerHashTable, number)
+	}
 
 	if len(data) == 0 {
 		return common.Hash{}
@@ -37,6 +40,40 @@ func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
 	return common.BytesToHash(data)
 }
 
+// readAncient retrieves the item of the kind of the block from the ancient store
+// of the database, nil is returned if the database has no ancient store or the
+// block isn't frozen.
+func readAncient(db DatabaseReader, kind string, number uint64) []byte {
+	reader, ok := db.(AncientReader)
+	if !ok {
+		return nil
+	}
+	data, _ := reader.Ancient(kind, number)
+	return data
+}
+
+// readAncientByHash retrieves the item of the kind of the frozen block, nil is
+// returned if the frozen canonical block of the number has a different hash.
+func readAncientByHash(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	if !bytes.Equal(readAncient(db, freezerHashTable, number), hash.Bytes()) {
+		return nil
+	}
+	return readAncient(db, kind, number)
+}
+
+// hasAncientByHash returns whether the item of the kind of the block is frozen.
+func hasAncientByHash(db DatabaseReader, kind string, hash common.Hash, number uint64) bool {
+	reader, ok := db.(AncientReader)
+	if !ok {
+		return false
+	}
+	if !bytes.Equal(readAncient(db, freezerHashTable, number), hash.Bytes()) {
+		return false
+	}
+	has, err := reader.HasAncient(kind, number)
+	return has && err == nil
+}
+
 // WriteCanonicalHash stores the hash assigned to a canonical block number.
 func WriteCanonicalHash(db DatabaseWriter, hash common.Hash, number uint64) {
 
@@ -128,16 +165,61 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 	}
 }
 
+// PruneStateProgress is the marker of a state pruning which is not finished yet,
+// it's persisted before the first node is deleted and removed once all the stale
+// nodes are deleted.
+type PruneStateProgress struct {
+	Target uint64 // The lowest block of which the state is retained
+	Next   []byte // The database key the deletion is resumed from
+}
+
+// ReadPruneStateProgress retrieves the progress of an interrupted state pruning,
+// nil is returned if there is no state pruning in progress.
+func ReadPruneStateProgress(db DatabaseReader) *PruneStateProgress {
+	data, _ := db.Get(pruneStateProgressKey)
+	if len(data) == 0 {
+		return nil
+	}
+	progress := new(PruneStateProgress)
+	if err := rlp.DecodeBytes(data, progress); err != nil {
+		log.Error("Invalid prune state progress RLP", "err", err)
+		return nil
+	}
+	return progress
+}
+
+// WritePruneStateProgress stores the progress of the state pruning to support
+// resuming it across restarts.
+func WritePruneStateProgress(db DatabaseWriter, progress *PruneStateProgress) {
+	data, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		log.Crit("Failed to RLP encode prune state progress", "err", err)
+	}
+	if err := db.Put(pruneStateProgressKey, data); err != nil {
+		log.Crit("Failed to store prune state progress", "err", err)
+	}
+}
+
+// DeletePruneStateProgress removes the progress of a finished state pruning.
+func DeletePruneStateProgress(db DatabaseDeleter) {
+	if err := db.Delete(pruneStateProgressKey); err != nil {
+		log.Crit("Failed to delete prune state progress", "err", err)
+	}
+}
+
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerHeaderTable, hash, number)
+	}
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
-		return false
+		return hasAncientByHash(db, freezerHeaderTable, hash, number)
 	}
 	return true
 }
@@ -193,6 +275,9 @@ func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 // ReadBodyRLP retrieves the block body (transactions) in RLP encoding.
 func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(blockBodyKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerBodiesTable, hash, number)
+	}
 	return data
 }
 
@@ -206,7 +291,7 @@ func WriteBodyRLP(db DatabaseWriter, hash common.Hash, number uint64, rlp rlp.Ra
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
-		return false
+		return hasAncientByHash(db, freezerBodiesTable, hash, number)
 	}
 	return true
 }
@@ -245,6 +330,9 @@ func DeleteBody(db DatabaseDeleter, hash common.Hash, number uint64) {
 func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Receipts {
 	// Retrieve the flattened receipt slice
 	data, _ := db.Get(blockReceiptsKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerReceiptTable, hash, number)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -1,8 +1,16 @@
 package rawdb
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb/memorydb"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
 // NewMemoryDatabase creates an ephemeral in-memory key-value database without a
@@ -27,3 +35,90 @@ type nofreezedb struct {
 func (n *nofreezedb) Close() {
 	n.KeyValueStore.Close()
 }
+
+// freezerdb is a database wrapper that enables freezer data retrievals.
+type freezerdb struct {
+	ethdb.Store
+	*freezer
+}
+
+// Close closes both the key-value store and the ancient store.
+func (frdb *freezerdb) Close() {
+	if err := frdb.freezer.Close(); err != nil {
+		log.Error("Failed to close ancient database", "err", err)
+	}
+	frdb.Store.Close()
+}
+
+// NewDatabaseWithFreezer creates a high level database on top of the on-disk store
+// with a freezer moving the canonical blocks which are older than the threshold
+// into the ancient directory. A zero threshold disables the moving, the frozen
+// blocks are still retrieved.
+func NewDatabaseWithFreezer(db ethdb.Store, freezer string, threshold uint64) (ethdb.Database, error) {
+	frdb, err := newFreezer(freezer)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the ancient store belongs to the chain in the key-value store, the
+	// first frozen block is the child of the genesis block
+	if frozen, _ := frdb.Ancients(); frozen > 0 {
+		var ancient common.Hash
+		if data, _ := frdb.Ancient(freezerHeaderTable, 1); len(data) > 0 {
+			header := new(types.Header)
+			if err := rlp.DecodeBytes(data, header); err != nil {
+				frdb.Close()
+				return nil, fmt.Errorf("invalid first ancient header: %v", err)
+			}
+			ancient = header.ParentHash
+		}
+		if kvgenesis, _ := db.Get(headerHashKey(0)); len(kvgenesis) > 0 && !bytes.Equal(kvgenesis, ancient.Bytes()) {
+			frdb.Close()
+			return nil, fmt.Errorf("genesis mismatch: %#x (leveldb) != %#x (ancients)", kvgenesis, ancient)
+		}
+	}
+	if threshold > 0 {
+		frdb.wg.Add(1)
+		go frdb.freeze(db, threshold)
+	}
+	return &freezerdb{
+		Store:   db,
+		freezer: frdb,
+	}, nil
+}
+
+// FreezeAncient moves all the canonical blocks which are older than the threshold
+// from the key-value store of the database into its ancient store and compacts the
+// key-value store, it returns the number of the moved blocks.
+func FreezeAncient(db ethdb.Database, threshold uint64) (uint64, error) {
+	frdb, ok := db.(*freezerdb)
+	if !ok {
+		return 0, errors.New("database has no ancient store")
+	}
+	head := ReadHeaderNumber(frdb.Store, ReadHeadBlockHash(frdb.Store))
+	if head == nil {
+		return 0, errors.New("head block is missing")
+	}
+	if *head <= threshold {
+		return 0, nil
+	}
+	var total uint64
+	for {
+		frozen, err := frdb.freezeBlocks(frdb.Store, *head-threshold, freezerBatchLimit)
+		if err != nil {
+			return total, err
+		}
+		total += frozen
+		if frozen < freezerBatchLimit {
+			break
+		}
+	}
+	if total > 0 {
+		log.Info("Compacting database")
+		if err := frdb.Store.Compact(nil, nil); err == ethdb.ErrCompactionNotSupported {
+			log.Info("Skipped database compaction, not supported by the engine")
+		} else if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,294 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+)
+
+// errUnknownTable is returned if the user attempts to read from a table that is
+// not tracked by the freezer.
+var errUnknownTable = errors.New("unknown table")
+
+const (
+	// freezerRecheckInterval is the frequency to check the key-value database for
+	// chain progression that might permit new blocks to be frozen into the ancient store.
+	freezerRecheckInterval = time.Minute
+
+	// freezerBatchLimit is the maximum number of blocks to freeze in one batch
+	// before doing an fsync and deleting them from the key-value store.
+	freezerBatchLimit = 30000
+)
+
+// freezer is the ancient store of the canonical blocks, it holds the hashes, the
+// headers, the bodies and the receipts of the blocks in append-only flat files.
+//
+// The blocks are frozen in ascending order without gaps, a block is deleted from
+// the key-value store only after it has been frozen and synced to the disk, so a
+// block can always be found in one of the stores.
+//
+// The genesis block is never frozen, it's always kept in the key-value store. The
+// item i of the tables is the block i+1.
+type freezer struct {
+	frozen uint64 // Number of blocks already frozen, the last frozen block is the number (atomic)
+	tables map[string]*freezerTable
+
+	quit      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// newFreezer opens the ancient store in the directory, creating it if it doesn't
+// exist. The tables are truncated to the same number of blocks.
+func newFreezer(datadir string) (*freezer, error) {
+	freezer := &freezer{
+		tables: make(map[string]*freezerTable),
+		quit:   make(chan struct{}),
+	}
+	for name, disableSnappy := range freezerNoSnappy {
+		table, err := newTable(datadir, name, disableSnappy)
+		if err != nil {
+			for _, table := range freezer.tables {
+				table.Close()
+			}
+			return nil, err
+		}
+		freezer.tables[name] = table
+	}
+	if err := freezer.repair(); err != nil {
+		for _, table := range freezer.tables {
+			table.Close()
+		}
+		return nil, err
+	}
+	log.Info("Opened ancient database", "path", datadir, "frozen", freezer.frozen)
+	return freezer, nil
+}
+
+// repair truncates all the tables to the number of blocks in the shortest one,
+// the tables may be out of sync if the node crashed while freezing a block.
+func (f *freezer) repair() error {
+	min := uint64(math.MaxUint64)
+	for _, table := range f.tables {
+		if items := table.Items(); items < min {
+			min = items
+		}
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(min); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, min)
+	return nil
+}
+
+// Close terminates the background freezing and closes all the tables.
+func (f *freezer) Close() error {
+	var errs []error
+	f.closeOnce.Do(func() {
+		close(f.quit)
+		f.wg.Wait()
+		for _, table := range f.tables {
+			if err := table.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// HasAncient returns whether the item of the kind of the block is frozen.
+func (f *freezer) HasAncient(kind string, number uint64) (bool, error) {
+	if table := f.tables[kind]; table != nil && number > 0 {
+		return table.has(number - 1), nil
+	}
+	return false, nil
+}
+
+// Ancient retrieves the item of the kind of the block from the ancient store.
+func (f *freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	table := f.tables[kind]
+	if table == nil {
+		return nil, errUnknownTable
+	}
+	if number == 0 {
+		return nil, errOutOfBounds
+	}
+	return table.Retrieve(number - 1)
+}
+
+// Ancients returns the number of the frozen blocks.
+func (f *freezer) Ancients() (uint64, error) {
+	return atomic.LoadUint64(&f.frozen), nil
+}
+
+// appendAncient appends all the items of the block to the tables, the block must
+// be the next one. The tables are truncated back if any of them fails.
+func (f *freezer) appendAncient(number uint64, hash, header, body, receipts []byte) (err error) {
+	if atomic.LoadUint64(&f.frozen)+1 != number {
+		return errOutOrder
+	}
+	item := number - 1
+	defer func() {
+		if err != nil {
+			if rerr := f.repair(); rerr != nil {
+				log.Crit("Failed to repair the ancient store", "err", rerr)
+			}
+			log.Error("Failed to append the ancient block", "number", number, "err", err)
+		}
+	}()
+	if err := f.tables[freezerHashTable].Append(item, hash); err != nil {
+		return err
+	}
+	if err := f.tables[freezerHeaderTable].Append(item, header); err != nil {
+		return err
+	}
+	if err := f.tables[freezerBodiesTable].Append(item, body); err != nil {
+		return err
+	}
+	if err := f.tables[freezerReceiptTable].Append(item, receipts); err != nil {
+		return err
+	}
+	atomic.AddUint64(&f.frozen, 1)
+	return nil
+}
+
+// sync pushes the data of all the tables to the disk.
+func (f *freezer) sync() error {
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// freeze is the background loop moving the canonical blocks which are older than
+// the threshold from the key-value store into the ancient store. The cbft blocks
+// are final once they are committed, the threshold only has to cover the blocks
+// the node may still rewind.
+func (f *freezer) freeze(db ethdb.Database, threshold uint64) {
+	defer f.wg.Done()
+
+	backoff := false
+	for {
+		select {
+		case <-f.quit:
+			return
+		default:
+		}
+		if backoff {
+			timer := time.NewTimer(freezerRecheckInterval)
+			select {
+			case <-timer.C:
+			case <-f.quit:
+				timer.Stop()
+				return
+			}
+		}
+		head := ReadHeaderNumber(db, ReadHeadBlockHash(db))
+		if head == nil || *head <= threshold {
+			backoff = true
+			continue
+		}
+		frozen, err := f.freezeBlocks(db, *head-threshold, freezerBatchLimit)
+		if err != nil {
+			log.Error("Failed to freeze the ancient blocks", "err", err)
+		}
+		backoff = err != nil || frozen < freezerBatchLimit
+	}
+}
+
+// freezeBlocks moves at most count canonical blocks below the limit from the
+// key-value store into the ancient store, it returns the number of the moved
+// blocks. The hash to number mappings are kept in the key-value store, and the
+// genesis block is never moved.
+func (f *freezer) freezeBlocks(db ethdb.Database, limit uint64, count uint64) (uint64, error) {
+	first := atomic.LoadUint64(&f.frozen) + 1
+	if first >= limit {
+		return 0, nil
+	}
+	if limit-first > count {
+		limit = first + count
+	}
+	start := time.Now()
+
+	hashes := make([]common.Hash, 0, limit-first)
+	for number := first; number < limit; number++ {
+		hash := ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return 0, fmt.Errorf("canonical hash missing, can't freeze block %d", number)
+		}
+		header := ReadHeaderRLP(db, hash, number)
+		if len(header) == 0 {
+			return 0, fmt.Errorf("block header missing, can't freeze block %d", number)
+		}
+		body := ReadBodyRLP(db, hash, number)
+		if len(body) == 0 {
+			return 0, fmt.Errorf("block body missing, can't freeze block %d", number)
+		}
+		// The receipts may be deleted by the cleaner already, they are frozen as
+		// an empty item which is read as no receipts.
+		receipts, _ := db.Get(blockReceiptsKey(number, hash))
+		if err := f.appendAncient(number, hash.Bytes(), header, body, receipts); err != nil {
+			return 0, err
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := f.sync(); err != nil {
+		return 0, err
+	}
+
+	// The blocks are persisted in the ancient store, wipe them out of the key-value store
+	batch := db.NewBatch()
+	for i, hash := range hashes {
+		number := first + uint64(i)
+		for _, key := range [][]byte{headerHashKey(number), headerKey(number, hash), blockBodyKey(number, hash), blockReceiptsKey(number, hash)} {
+			if err := batch.Delete(key); err != nil {
+				return 0, err
+			}
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return 0, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	log.Info("Froze the ancient blocks", "from", first, "to", limit-1, "blocks", len(hashes), "elapsed", common.PrettyDuration(time.Since(start)))
+	return uint64(len(hashes)), nil
+}
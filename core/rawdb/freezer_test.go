@@ -0,0 +1,173 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+)
+
+func newTestDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir(os.TempDir(), "freezer_test_")
+	if err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// Tests that the items of a freezer table can be retrieved after reopening and
+// the partially written items are dropped.
+func TestFreezerTable(t *testing.T) {
+	dir, remove := newTestDir(t)
+	defer remove()
+
+	for _, noCompression := range []bool{false, true} {
+		table, err := newTable(dir, "test", noCompression)
+		if err != nil {
+			t.Fatalf("failed to open table: %v", err)
+		}
+		for i := uint64(0); i < 10; i++ {
+			if err := table.Append(i, bytes.Repeat([]byte{byte(i)}, int(i))); err != nil {
+				t.Fatalf("failed to append item %d: %v", i, err)
+			}
+		}
+		if err := table.Append(11, []byte{0x01}); err != errOutOrder {
+			t.Fatalf("append error mismatch: have %v, want %v", err, errOutOrder)
+		}
+		// Write a partial item as if the node crashed before writing the index
+		table.data.WriteAt([]byte{0x01, 0x02}, int64(table.size))
+		table.Close()
+
+		if table, err = newTable(dir, "test", noCompression); err != nil {
+			t.Fatalf("failed to reopen table: %v", err)
+		}
+		if items := table.Items(); items != 10 {
+			t.Fatalf("items mismatch: have %d, want 10", items)
+		}
+		for i := uint64(0); i < 10; i++ {
+			blob, err := table.Retrieve(i)
+			if err != nil {
+				t.Fatalf("failed to retrieve item %d: %v", i, err)
+			}
+			if !bytes.Equal(blob, bytes.Repeat([]byte{byte(i)}, int(i))) {
+				t.Fatalf("item %d mismatch: have %x", i, blob)
+			}
+		}
+		if _, err := table.Retrieve(10); err != errOutOfBounds {
+			t.Fatalf("retrieve error mismatch: have %v, want %v", err, errOutOfBounds)
+		}
+		if err := table.truncate(5); err != nil {
+			t.Fatalf("failed to truncate table: %v", err)
+		}
+		if err := table.Append(5, []byte{0x05}); err != nil {
+			t.Fatalf("failed to append item after truncating: %v", err)
+		}
+		if blob, _ := table.Retrieve(5); !bytes.Equal(blob, []byte{0x05}) {
+			t.Fatalf("item mismatch after truncating: have %x", blob)
+		}
+		table.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// Tests that the frozen blocks are moved out of leveldb and read transparently.
+func TestFreezeAncient(t *testing.T) {
+	dir, remove := newTestDir(t)
+	defer remove()
+
+	ldb, err := ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	var blocks []*types.Block
+	for i := 0; i < 10; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte("test block")}
+		if i > 0 {
+			header.ParentHash = blocks[i-1].Hash()
+		}
+		block := types.NewBlockWithHeader(header)
+		WriteBlock(ldb, block)
+		WriteCanonicalHash(ldb, block.Hash(), block.NumberU64())
+		WriteReceipts(ldb, block.Hash(), block.NumberU64(), types.Receipts{{CumulativeGasUsed: uint64(i), Logs: []*types.Log{}}})
+		WriteHeadBlockHash(ldb, block.Hash())
+		blocks = append(blocks, block)
+	}
+	if _, err := FreezeAncient(ldb, 4); err == nil {
+		t.Fatal("froze the blocks of a database without ancient store")
+	}
+	db, err := NewDatabaseWithFreezer(ldb, filepath.Join(dir, "chaindata", "ancient"), 0)
+	if err != nil {
+		t.Fatalf("failed to open ancient store: %v", err)
+	}
+	// The genesis block is never frozen
+	if frozen, err := FreezeAncient(db, 4); err != nil || frozen != 4 {
+		t.Fatalf("frozen blocks mismatch: have %d, %v, want 4", frozen, err)
+	}
+	db.Close()
+
+	// Reopen the database to make sure the ancient store is persisted
+	if ldb, err = ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0); err != nil {
+		t.Fatalf("failed to reopen test database: %v", err)
+	}
+	if db, err = NewDatabaseWithFreezer(ldb, filepath.Join(dir, "chaindata", "ancient"), 0); err != nil {
+		t.Fatalf("failed to reopen ancient store: %v", err)
+	}
+	defer db.Close()
+
+	if frozen, _ := db.(AncientReader).Ancients(); frozen != 4 {
+		t.Fatalf("ancients mismatch: have %d, want 4", frozen)
+	}
+	if has, _ := db.(AncientReader).HasAncient(freezerHeaderTable, 0); has {
+		t.Error("the genesis block is frozen")
+	}
+	if hash, _ := ldb.Get(headerHashKey(0)); !bytes.Equal(hash, blocks[0].Hash().Bytes()) {
+		t.Errorf("genesis canonical hash in leveldb mismatch: have %x", hash)
+	}
+	for _, block := range blocks {
+		hash, number := block.Hash(), block.NumberU64()
+		inLeveldb := number == 0 || number >= 5
+		if has, _ := ldb.Has(headerKey(number, hash)); has != inLeveldb {
+			t.Errorf("block %d in leveldb mismatch: have %v, want %v", number, has, inLeveldb)
+		}
+		if have := ReadCanonicalHash(db, number); have != hash {
+			t.Errorf("canonical hash %d mismatch: have %x, want %x", number, have, hash)
+		}
+		if !HasHeader(db, hash, number) || !HasBody(db, hash, number) {
+			t.Errorf("block %d is missing", number)
+		}
+		if have := ReadBlock(db, hash, number); have == nil || have.Hash() != hash {
+			t.Errorf("block %d mismatch: have %v", number, have)
+		}
+		if receipts := ReadReceipts(db, hash, number); len(receipts) != 1 || receipts[0].CumulativeGasUsed != number {
+			t.Errorf("receipts %d mismatch: have %v", number, receipts)
+		}
+		if header := ReadHeader(db, common.Hash{0x01}, number); header != nil {
+			t.Errorf("header %d is returned for an unknown hash", number)
+		}
+	}
+	if frozen, err := FreezeAncient(db, 4); err != nil || frozen != 0 {
+		t.Fatalf("frozen blocks mismatch: have %d, %v, want 0", frozen, err)
+	}
+}
@@ -31,3 +31,16 @@ type DatabaseWriter interface {
 type DatabaseDeleter interface {
 	Delete(key []byte) error
 }
+
+// AncientReader wraps the read methods of the ancient store holding the frozen
+// canonical blocks.
+type AncientReader interface {
+	// HasAncient returns whether the item of the kind of the block is frozen.
+	HasAncient(kind string, number uint64) (bool, error)
+
+	// Ancient retrieves the item of the kind of the block from the ancient store.
+	Ancient(kind string, number uint64) ([]byte, error)
+
+	// Ancients returns the number of the frozen blocks.
+	Ancients() (uint64, error)
+}
@@ -0,0 +1,301 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+// The archive mode keeps a reverse diff for each block written to the baseDB,
+// the diff holds the values of the keys before the block is applied.
+// The state of a block behind the base is restored by applying the diffs
+// from the base down to the block over the baseDB.
+
+const (
+	// ArchiveDiffPrefix + blockNumber -> the reverse diff of the block
+	ArchiveDiffPrefix = "snapshotdbArchiveDiff"
+	// ArchiveFrom is the first block which has the reverse diff
+	ArchiveFrom = "snapshotdbArchiveFrom"
+)
+
+var (
+	archiveMode bool
+
+	ErrArchiveDisabled     = errors.New("snapshotDB: archive mode is disabled")
+	ErrArchiveNotAvailable = errors.New("snapshotDB: the block is not archived")
+)
+
+// SetDBArchive enables the archive mode, it must be called before the db is opened.
+func SetDBArchive(enable bool) {
+	archiveMode = enable
+}
+
+// archiveKV is the value of the key before the block is applied, empty if the key didn't exist.
+type archiveKV struct {
+	Key   []byte
+	Value []byte
+}
+
+func archiveDiffKey(blockNumber uint64) []byte {
+	key := make([]byte, len(ArchiveDiffPrefix)+8)
+	copy(key, ArchiveDiffPrefix)
+	binary.BigEndian.PutUint64(key[len(ArchiveDiffPrefix):], blockNumber)
+	return key
+}
+
+// initArchive records the first archived block when the archive mode is enabled,
+// or forgets it when the archive mode is disabled since the following blocks are not archived.
+func (s *snapshotDB) initArchive() error {
+	_, err := s.baseDB.Get([]byte(ArchiveFrom))
+	if err != nil && err != ethdb.ErrNotFound {
+		return err
+	}
+	if !archiveMode {
+		if err == nil {
+			logger.Warn("Archive mode is disabled, the archived history is dropped")
+			return s.baseDB.Delete([]byte(ArchiveFrom))
+		}
+		return nil
+	}
+	if err == ethdb.ErrNotFound {
+		from := s.current.GetBase(false).Num.Uint64() + 1
+		logger.Info("Archive mode is enabled", "from", from)
+		return s.baseDB.Put([]byte(ArchiveFrom), common.Uint64ToBytes(from))
+	}
+	return nil
+}
+
+// resetArchive moves the first archived block after the base if the base is not archived,
+// e.g. the base is set by the fast sync.
+func (s *snapshotDB) resetArchive(base uint64) error {
+	if !archiveMode || base == 0 {
+		return nil
+	}
+	if _, err := s.baseDB.Get(archiveDiffKey(base)); err == nil {
+		return nil
+	} else if err != ethdb.ErrNotFound {
+		return err
+	}
+	return s.baseDB.Put([]byte(ArchiveFrom), common.Uint64ToBytes(base+1))
+}
+
+// writeArchive adds the reverse diffs of the blocks and the new base to the batch,
+// so that they are written to the baseDB atomically with the blocks.
+func (s *snapshotDB) writeArchive(batch ethdb.Batch, blocks []*blockData) error {
+	pending := make(map[string][]byte)
+	for _, block := range blocks {
+		diff := make([]archiveKV, 0, block.data.Len())
+		itr := block.data.NewIterator(nil)
+		for itr.Next() {
+			key := string(itr.Key())
+			old, ok := pending[key]
+			if !ok {
+				v, err := s.baseDB.Get(itr.Key())
+				if err != nil && err != ethdb.ErrNotFound {
+					itr.Release()
+					return err
+				}
+				old = v
+			}
+			diff = append(diff, archiveKV{Key: []byte(key), Value: old})
+			pending[key] = common.CopyBytes(itr.Value())
+		}
+		itr.Release()
+
+		enc, err := rlp.EncodeToBytes(diff)
+		if err != nil {
+			return err
+		}
+		batch.Put(archiveDiffKey(block.Number.Uint64()), enc)
+	}
+	base, err := rlp.EncodeToBytes(&CurrentBase{Num: new(big.Int).Set(blocks[len(blocks)-1].Number)})
+	if err != nil {
+		return err
+	}
+	batch.Put([]byte(CurrentBaseNum), base)
+	return nil
+}
+
+// archivedBlockNumber returns the number of the committed block which is behind the highest,
+// its state can only be read from the archive.
+func (s *snapshotDB) archivedBlockNumber(hash common.Hash) (uint64, bool) {
+	if !archiveMode || blockchain == nil || hash == s.getUnRecognizedHash() {
+		return 0, false
+	}
+	if s.unCommit.Get(hash) != nil {
+		return 0, false
+	}
+	header := blockchain.GetHeaderByHash(hash)
+	if header == nil || header.Number.Cmp(s.current.GetHighest(false).Num) >= 0 {
+		return 0, false
+	}
+	return header.Number.Uint64(), true
+}
+
+// archiveView is a consistent view of the db at a committed block, the base
+// isn't written until the view is released.
+type archiveView struct {
+	base ethdb.Store
+	lock *sync.RWMutex
+	// the committed blocks after the base, newest first
+	committed []*blockData
+	// the values of the keys modified after the block, empty if the key didn't exist
+	diff *memdb.DB
+}
+
+func (v *archiveView) Release() {
+	v.lock.RUnlock()
+}
+
+// archiveAt builds the view of the committed block, the view must be released after use.
+func (s *snapshotDB) archiveAt(blockNumber uint64) (*archiveView, error) {
+	if !archiveMode {
+		return nil, ErrArchiveDisabled
+	}
+	// the base and the reverse diffs are written atomically with the blocks, and
+	// they are not written until the view is released
+	s.baseLock.RLock()
+	s.commitLock.RLock()
+	defer s.commitLock.RUnlock()
+
+	view := &archiveView{base: s.baseDB, lock: &s.baseLock, committed: make([]*blockData, 0)}
+	base, err := s.baseDB.Get([]byte(CurrentBaseNum))
+	if err != nil {
+		view.Release()
+		return nil, err
+	}
+	var cb CurrentBase
+	if err := rlp.DecodeBytes(base, &cb); err != nil {
+		view.Release()
+		return nil, err
+	}
+	baseNum := cb.Num.Uint64()
+
+	if blockNumber >= baseNum {
+		for i := len(s.committed) - 1; i >= 0; i-- {
+			number := s.committed[i].Number.Uint64()
+			if number > baseNum && number <= blockNumber {
+				view.committed = append(view.committed, s.committed[i])
+			}
+		}
+		return view, nil
+	}
+
+	from, err := s.baseDB.Get([]byte(ArchiveFrom))
+	if err != nil || blockNumber+1 < common.BytesToUint64(from) {
+		view.Release()
+		return nil, ErrArchiveNotAvailable
+	}
+	// the older diff overwrites the newer one
+	view.diff = memdb.New(DefaultComparer, 0)
+	for number := baseNum; number > blockNumber; number-- {
+		enc, err := s.baseDB.Get(archiveDiffKey(number))
+		if err != nil {
+			view.Release()
+			return nil, fmt.Errorf("get the archive diff of block %d fail:%v", number, err)
+		}
+		var diff []archiveKV
+		if err := rlp.DecodeBytes(enc, &diff); err != nil {
+			view.Release()
+			return nil, err
+		}
+		for _, kv := range diff {
+			if err := view.diff.Put(kv.Key, kv.Value); err != nil {
+				view.Release()
+				return nil, err
+			}
+		}
+	}
+	return view, nil
+}
+
+// GetAtBlock returns the value of the key at the committed block.
+func (s *snapshotDB) GetAtBlock(blockNumber uint64, key []byte) ([]byte, error) {
+	view, err := s.archiveAt(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	for _, block := range view.committed {
+		if v, err := block.data.Get(key); err == nil {
+			if len(v) == 0 {
+				return nil, ErrNotFound
+			}
+			return v, nil
+		} else if err != memdb.ErrNotFound {
+			return nil, err
+		}
+	}
+	if view.diff != nil {
+		if v, err := view.diff.Get(key); err == nil {
+			if len(v) == 0 {
+				return nil, ErrNotFound
+			}
+			return v, nil
+		} else if err != memdb.ErrNotFound {
+			return nil, err
+		}
+	}
+	if v, err := view.base.Get(key); err == nil {
+		return v, nil
+	} else if err != ethdb.ErrNotFound {
+		return nil, err
+	}
+	return nil, ErrNotFound
+}
+
+// RankingAtBlock is same as Ranking, but iterates the keys at the committed block.
+func (s *snapshotDB) RankingAtBlock(blockNumber uint64, key []byte, rangeNumber int) iterator.Iterator {
+	view, err := s.archiveAt(blockNumber)
+	if err != nil {
+		return iterator.NewEmptyIterator(err)
+	}
+	defer view.Release()
+
+	prefix := util.BytesPrefix(key)
+	rankingHeap := newRankingHeap(rangeNumber)
+	for _, block := range view.committed {
+		rankingHeap.itr2Heap(block.data.NewIterator(prefix), false, false)
+	}
+	if view.diff != nil {
+		rankingHeap.itr2Heap(view.diff.NewIterator(prefix), false, false)
+	}
+	rankingHeap.itr2Heap(view.base.NewIteratorWithRange(prefix), true, true)
+
+	mdb := memdb.New(DefaultComparer, rangeNumber)
+	for rankingHeap.heap.Len() > 0 {
+		kv := heap.Pop(&rankingHeap.heap).(kv)
+		if err := mdb.Put(kv.key, kv.value); err != nil {
+			return iterator.NewEmptyIterator(errors.New("put to mdb fail" + err.Error()))
+		}
+	}
+	return mdb.NewIterator(nil)
+}
@@ -0,0 +1,104 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnapshotDB_Archive(t *testing.T) {
+	SetDBArchive(true)
+	defer SetDBArchive(false)
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+
+	k1, k2 := []byte("archive-k1"), []byte("archive-k2")
+	// block 1 ~ 3 are written to baseDB, block 4 is committed only
+	blocks := []kvs{
+		{kv{k1, []byte("v1")}},
+		{kv{k1, []byte("v2")}, kv{k2, []byte("v2")}},
+		{kv{k1, nil}},
+	}
+	for _, b := range blocks {
+		if err := ch.insert(true, b, newBlockBaseDB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ch.insert(true, kvs{kv{k2, []byte("v4")}}, newBlockCommited); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(number int, key []byte) []byte {
+		v, err := ch.db.Get(ch.h[number-1].Hash(), key)
+		if err != nil && err != ErrNotFound {
+			t.Fatal(err)
+		}
+		return v
+	}
+	if v := get(1, k1); !bytes.Equal(v, []byte("v1")) {
+		t.Errorf("block 1 k1 want v1, have %s", v)
+	}
+	if v := get(1, k2); v != nil {
+		t.Errorf("block 1 k2 want nil, have %s", v)
+	}
+	if v := get(2, k1); !bytes.Equal(v, []byte("v2")) {
+		t.Errorf("block 2 k1 want v2, have %s", v)
+	}
+	if v := get(3, k1); v != nil {
+		t.Errorf("block 3 k1 want nil, have %s", v)
+	}
+	if v := get(3, k2); !bytes.Equal(v, []byte("v2")) {
+		t.Errorf("block 3 k2 want v2, have %s", v)
+	}
+	if v := get(4, k2); !bytes.Equal(v, []byte("v4")) {
+		t.Errorf("block 4 k2 want v4, have %s", v)
+	}
+
+	count := func(number uint64) int {
+		itr := ch.db.RankingAtBlock(number, []byte("archive-"), 0)
+		defer itr.Release()
+		n := 0
+		for itr.Next() {
+			n++
+		}
+		return n
+	}
+	if n := count(1); n != 1 {
+		t.Errorf("block 1 ranking want 1, have %d", n)
+	}
+	if n := count(2); n != 2 {
+		t.Errorf("block 2 ranking want 2, have %d", n)
+	}
+	if n := count(3); n != 1 {
+		t.Errorf("block 3 ranking want 1, have %d", n)
+	}
+
+	// the history is dropped once the archive mode is disabled
+	if err := ch.db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	SetDBArchive(false)
+	ch.reOpenSnapshotDB()
+	if _, err := ch.db.GetAtBlock(1, k1); err != ErrArchiveDisabled {
+		t.Errorf("want ErrArchiveDisabled, have %v", err)
+	}
+	SetDBArchive(true)
+	if _, err := ch.db.GetAtBlock(1, k1); err != ErrArchiveNotAvailable {
+		t.Errorf("want ErrArchiveNotAvailable, have %v", err)
+	}
+}
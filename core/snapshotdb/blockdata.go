@@ -31,6 +31,9 @@ type blockData struct {
 	data       *memdb.DB
 	readOnly   bool
 	kvHash     common.Hash
+	// puts are the records in the put order, they are written to the journal so
+	// the kv hash can be replayed from the journal
+	puts []journalData
 }
 
 type unCommitBlocks struct {
@@ -206,5 +206,6 @@ func (s *snapshotDB) put(hash common.Hash, key, value []byte) error {
 	if err := block.data.Put(key, value); err != nil {
 		return err
 	}
+	block.puts = append(block.puts, journalData{Key: common.CopyBytes(key), Value: common.CopyBytes(value)})
 	return nil
 }
@@ -0,0 +1,230 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/syndtr/goleveldb/leveldb/journal"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+)
+
+// The functions in this file work offline on the db directory of a stopped node,
+// the directory is locked so that they fail if the db is in use.
+
+// JournalInfo describes the journal of a committed block.
+type JournalInfo struct {
+	Number     uint64
+	BlockHash  common.Hash
+	ParentHash common.Hash
+	KvHash     common.Hash
+	KVs        int
+	// ReplayedHash is the kv hash replayed from the records of the journal, it equals KvHash
+	// if the records are intact. The journals written by the older versions keep the records
+	// in the key order instead of the put order, KeyOrdered is set if the records are so.
+	ReplayedHash common.Hash
+	KeyOrdered   bool
+	// Err is not nil if the journal can't be decoded
+	Err error
+}
+
+// DBInfo describes the current pointers and the journals of the db.
+type DBInfo struct {
+	Base        uint64
+	Highest     uint64
+	HighestHash common.Hash
+	// Committed are the journals in (base, highest], they are loaded when the db is opened
+	Committed []*JournalInfo
+	// Stale are the journals out of (base, highest], they are removed when the db is opened
+	Stale []*JournalInfo
+}
+
+// offlineDB opens the db directory without recovering it.
+func offlineDB(path string, readOnly bool) (*snapshotDB, error) {
+	s, err := openFile(path, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	var baseDB ethdb.Store
+	if readOnly {
+		baseDB, err = ethdb.OpenStoreReadOnly(getBaseDBPath(path))
+	} else if ethdb.DetectEngine(getBaseDBPath(path)) == "" {
+		err = fmt.Errorf("no baseDB in %s", getBaseDBPath(path))
+	} else {
+		baseDB, err = ethdb.OpenStore("", getBaseDBPath(path), 0, 0)
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	db := &snapshotDB{
+		path:    path,
+		storage: s,
+		baseDB:  baseDB,
+		current: new(current),
+	}
+	if err := db.current.loadFromBaseDB(baseDB); err != nil {
+		db.closeOffline()
+		return nil, err
+	}
+	return db, nil
+}
+
+func (s *snapshotDB) closeOffline() {
+	s.baseDB.Close()
+	s.storage.Close()
+}
+
+func (s *snapshotDB) inspect() (*DBInfo, error) {
+	fds, err := s.storage.List(TypeJournal)
+	if err != nil {
+		return nil, err
+	}
+	sortFds(fds)
+
+	info := &DBInfo{
+		Base:        s.current.GetBase(false).Num.Uint64(),
+		Highest:     s.current.GetHighest(false).Num.Uint64(),
+		HighestHash: s.current.GetHighest(false).Hash,
+		Committed:   make([]*JournalInfo, 0),
+		Stale:       make([]*JournalInfo, 0),
+	}
+	for _, fd := range fds {
+		journal := &JournalInfo{Number: fd.Num, BlockHash: fd.BlockHash}
+		if block, err := s.getBlockFromJournal(fd); err != nil {
+			journal.Err = err
+		} else if journal.ReplayedHash, journal.KeyOrdered, err = s.replayJournal(fd); err != nil {
+			journal.Err = err
+		} else {
+			journal.ParentHash = block.ParentHash
+			journal.KvHash = block.kvHash
+			journal.KVs = block.data.Len()
+		}
+		if info.Base < fd.Num && fd.Num <= info.Highest {
+			info.Committed = append(info.Committed, journal)
+		} else {
+			info.Stale = append(info.Stale, journal)
+		}
+	}
+	return info, nil
+}
+
+// replayJournal replays the records of the journal to the kv hash, in the order they are written.
+// It also reports whether the keys of the records are in strictly ascending order.
+func (s *snapshotDB) replayJournal(fd fileDesc) (common.Hash, bool, error) {
+	reader, err := s.storage.Open(fd)
+	if err != nil {
+		return common.ZeroHash, false, err
+	}
+	defer reader.Close()
+	journals := journal.NewReader(reader, nil, false, false)
+	// skip the header
+	if _, err := journals.Next(); err != nil {
+		return common.ZeroHash, false, err
+	}
+	var (
+		kvHash     = common.ZeroHash
+		keyOrdered = true
+		lastKey    []byte
+		records    int
+	)
+	for {
+		j, err := journals.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return common.ZeroHash, false, err
+		}
+		var body journalData
+		if err := decode(j, &body); err != nil {
+			return common.ZeroHash, false, err
+		}
+		if records > 0 && bytes.Compare(lastKey, body.Key) >= 0 {
+			keyOrdered = false
+		}
+		lastKey = body.Key
+		records++
+		kvHash = s.generateKVHash(body.Key, body.Value, kvHash)
+	}
+	return kvHash, keyOrdered, nil
+}
+
+// Inspect reads the current pointers and the journals of the db at path.
+func Inspect(path string) (*DBInfo, error) {
+	db, err := offlineDB(path, true)
+	if err != nil {
+		return nil, err
+	}
+	defer db.closeOffline()
+	return db.inspect()
+}
+
+// Rollback discards the journals above the committed block number and moves the highest to it,
+// the blocks are executed again from the chain when the node is restarted.
+// It returns the number of the removed journals.
+func Rollback(path string, number uint64) (int, error) {
+	db, err := offlineDB(path, false)
+	if err != nil {
+		return 0, err
+	}
+	defer db.closeOffline()
+
+	info, err := db.inspect()
+	if err != nil {
+		return 0, err
+	}
+	if number < info.Base {
+		return 0, fmt.Errorf("can't rollback to %d, the blocks until the base %d are written to the baseDB", number, info.Base)
+	}
+	if number > info.Highest {
+		return 0, fmt.Errorf("can't rollback to %d, the highest is %d", number, info.Highest)
+	}
+	// the hash of the highest is unknown if it's the base, same as the recovery
+	hash := common.ZeroHash
+	for _, journal := range info.Committed {
+		if journal.Number == number {
+			if journal.Err != nil {
+				return 0, fmt.Errorf("can't rollback to %d, the journal is broken:%v", number, journal.Err)
+			}
+			hash = journal.BlockHash
+		}
+	}
+	if number > info.Base && hash == common.ZeroHash {
+		return 0, fmt.Errorf("can't rollback to %d, the journal is not found", number)
+	}
+
+	nc := newCurrent(new(big.Int).SetUint64(number), nil, hash)
+	if err := nc.saveCurrentToBaseDB(CurrentHighestBlock, db.baseDB, false); err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, journal := range append(info.Committed, info.Stale...) {
+		if journal.Number > number {
+			if err := db.rmJournalFile(new(big.Int).SetUint64(journal.Number), journal.BlockHash); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSnapshotDB_InspectAndRollback(t *testing.T) {
+	ch := newTestchain(dbpath)
+	defer os.RemoveAll(dbpath)
+
+	// block 1 ~ 2 are written to baseDB, block 3 ~ 5 are committed only
+	for i := 0; i < 2; i++ {
+		if err := ch.insert(true, kvs{kv{[]byte("inspect-base"), []byte{byte(i)}}}, newBlockBaseDB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if err := ch.insert(true, kvs{kv{[]byte("inspect-k"), []byte{byte(i)}}, kv{[]byte{byte(i)}, []byte("v")}}, newBlockCommited); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ch.db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := Inspect(dbpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Base != 2 || info.Highest != 5 || info.HighestHash != ch.h[4].Hash() {
+		t.Fatalf("want base 2 highest 5, have base %d highest %d", info.Base, info.Highest)
+	}
+	// the journals of the blocks written to baseDB are removed at the next open
+	if len(info.Committed) != 3 || len(info.Stale) != 2 {
+		t.Fatalf("want 3 committed 2 stale journals, have %d committed %d stale", len(info.Committed), len(info.Stale))
+	}
+	for i, journal := range info.Committed {
+		header := ch.h[i+2]
+		if journal.Err != nil || journal.BlockHash != header.Hash() || journal.ParentHash != header.ParentHash || journal.KVs != 2 {
+			t.Errorf("journal %d mismatch: %+v", journal.Number, journal)
+		}
+		// the records are written in the put order, so they are replayed to the kv hash
+		if journal.ReplayedHash != journal.KvHash || journal.KeyOrdered {
+			t.Errorf("journal %d replayed kv hash mismatch: have %s, want %s", journal.Number, journal.ReplayedHash.String(), journal.KvHash.String())
+		}
+	}
+
+	if _, err := Rollback(dbpath, 1); err == nil {
+		t.Error("rollback below the base should fail")
+	}
+	if _, err := Rollback(dbpath, 6); err == nil {
+		t.Error("rollback above the highest should fail")
+	}
+	removed, err := Rollback(dbpath, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("want 2 journals removed, have %d", removed)
+	}
+
+	ch.reOpenSnapshotDB()
+	highest := ch.db.GetCurrent().GetHighest(false)
+	if highest.Num.Uint64() != 3 || highest.Hash != ch.h[2].Hash() {
+		t.Errorf("want highest 3, have %d", highest.Num)
+	}
+	if len(ch.db.committed) != 1 {
+		t.Errorf("want 1 committed block, have %d", len(ch.db.committed))
+	}
+	ch.db.Close()
+}
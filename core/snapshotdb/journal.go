@@ -170,21 +170,17 @@ func (s *snapshotDB) writeJournal(block *blockData) error {
 		return err
 	}
 
-	itr := block.data.NewIterator(nil)
-	defer itr.Release()
-	kvhash := common.ZeroHash
-	for itr.Next() {
+	// the records are written in the put order, the later ones overwrite the former ones
+	// when the journal is loaded, and they are replayed to the kv hash of the header
+	for _, jData := range block.puts {
 		toWrite, err := jwriters.Next()
 		if err != nil {
 			return errors.New("next err:" + err.Error())
 		}
-		key, val := common.CopyBytes(itr.Key()), common.CopyBytes(itr.Value())
-		kvhash = s.generateKVHash(key, val, kvhash)
-		jData := journalData{
-			Key:   key,
-			Value: val,
-		}
 		data, err := encode(jData)
+		if err != nil {
+			return err
+		}
 		if _, err := toWrite.Write(data); err != nil {
 			return err
 		}
@@ -26,16 +26,12 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/metrics"
 
-	"github.com/syndtr/goleveldb/leveldb/filter"
-	"github.com/syndtr/goleveldb/leveldb/opt"
-
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/robfig/cron"
-	"github.com/syndtr/goleveldb/leveldb"
-	leveldbError "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/memdb"
 	"github.com/syndtr/goleveldb/leveldb/util"
@@ -75,6 +71,9 @@ type DB interface {
 	Has(hash common.Hash, key []byte) (bool, error)
 	Flush(hash common.Hash, blocknumber *big.Int) error
 	Ranking(hash common.Hash, key []byte, ranges int) iterator.Iterator
+	// GetAtBlock and RankingAtBlock read at the committed block, the blocks behind the base need the archive mode
+	GetAtBlock(blockNumber uint64, key []byte) ([]byte, error)
+	RankingAtBlock(blockNumber uint64, key []byte, ranges int) iterator.Iterator
 	//notice , iter.key or iter.value is slice，if you want to save it to a slice,you can use copy
 	// container:=make([]byte,0)
 	// for iter.next{
@@ -100,6 +99,8 @@ type DB interface {
 	GetCurrent() *current
 
 	GetLastKVHash(blockHash common.Hash) []byte
+	// WalkBlock iterates the keys written by the uncommitted block in key order, the value of the deleted key is empty
+	WalkBlock(blockHash common.Hash, f func(key, value []byte) error) error
 	BaseNum() (*big.Int, error)
 	Close() error
 	Compaction() error
@@ -115,6 +116,7 @@ var (
 
 	instance sync.Mutex
 
+	baseDBengine  string
 	baseDBcache   int
 	baseDBhandles int
 
@@ -134,7 +136,10 @@ type snapshotDB struct {
 
 	current *current
 
-	baseDB *leveldb.DB
+	baseDB ethdb.Store
+	// baseLock serializes the writes of the baseDB with the long reads of it, so the
+	// reads are consistent without holding a snapshot, which pins the pages of bolt
+	baseLock sync.RWMutex
 
 	unCommit *unCommitBlocks
 
@@ -170,7 +175,10 @@ func SetDBBlockChain(c Chain) {
 	logger.Info("set blockchain")
 }
 
-func SetDBOptions(cache int, handles int) {
+// SetDBOptions sets the engine, the cache and the file handles of the baseDB, an
+// empty engine opens the existing baseDB with its own engine.
+func SetDBOptions(engine string, cache int, handles int) {
+	baseDBengine = engine
 	baseDBcache = cache
 	baseDBhandles = handles
 }
@@ -198,23 +206,9 @@ func open(path string, cache int, handles int) (*snapshotDB, error) {
 		logger.Error("open db file fail", "error", err, "path", path)
 		return nil, err
 	}
-	logger.Info("Allocated cache and file handles", "cache", cache, "handles", handles)
-
-	baseDB, err := leveldb.OpenFile(getBaseDBPath(path), &opt.Options{
-		OpenFilesCacheCapacity: handles,
-		BlockCacheCapacity:     cache / 2 * opt.MiB,
-		WriteBuffer:            cache / 4 * opt.MiB, // Two of these are used internally
-		Filter:                 filter.NewBloomFilter(10),
-	})
+	baseDB, err := ethdb.OpenStore(baseDBengine, getBaseDBPath(path), cache, handles)
 	if err != nil {
-		if _, corrupted := err.(*leveldbError.ErrCorrupted); corrupted {
-			baseDB, err = leveldb.RecoverFile(getBaseDBPath(path), nil)
-			if err != nil {
-				return nil, fmt.Errorf("[SnapshotDB.recover]RecoverFile baseDB fail:%v", err)
-			}
-		} else {
-			return nil, err
-		}
+		return nil, err
 	}
 	unCommitBlock := new(unCommitBlocks)
 	unCommitBlock.blocks = make(map[common.Hash]*blockData)
@@ -229,7 +223,7 @@ func open(path string, cache int, handles int) (*snapshotDB, error) {
 		journalWriteExitCh: make(chan struct{}),
 	}
 
-	_, getCurrentError := baseDB.Get([]byte(CurrentSet), nil)
+	_, getCurrentError := baseDB.Get([]byte(CurrentSet))
 	if getCurrentError == nil {
 		logger.Info("begin recover", "path", path)
 		if err := db.loadCurrent(); err != nil {
@@ -240,7 +234,7 @@ func open(path string, cache int, handles int) (*snapshotDB, error) {
 			logger.Error("recover db fail:", "error", err)
 			return nil, err
 		}
-	} else if getCurrentError == leveldb.ErrNotFound {
+	} else if getCurrentError == ethdb.ErrNotFound {
 		logger.Info("begin init db current", "path", path)
 		if err := db.SetCurrent(common.ZeroHash, *common.Big0, *common.Big0); err != nil {
 			return nil, err
@@ -248,6 +242,9 @@ func open(path string, cache int, handles int) (*snapshotDB, error) {
 	} else {
 		return nil, getCurrentError
 	}
+	if err := db.initArchive(); err != nil {
+		return nil, err
+	}
 	return db, nil
 }
 
@@ -313,21 +310,28 @@ func (s *snapshotDB) cornStart() error {
 }
 
 func (s *snapshotDB) WriteBaseDB(kvs [][2][]byte) error {
-	batch := new(leveldb.Batch)
+	s.baseLock.Lock()
+	defer s.baseLock.Unlock()
+	batch := s.baseDB.NewBatch()
 	for _, value := range kvs {
 		batch.Put(value[0], value[1])
 	}
-	if err := s.baseDB.Write(batch, nil); err != nil {
+	if err := batch.Write(); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (s *snapshotDB) SetCurrent(highestHash common.Hash, base, height big.Int) error {
+	s.baseLock.Lock()
+	defer s.baseLock.Unlock()
 	current := newCurrent(&height, &base, highestHash)
 	if err := current.saveCurrentToBaseDB(CurrentAll, s.baseDB, true); err != nil {
 		return err
 	}
+	if err := s.resetArchive(base.Uint64()); err != nil {
+		return err
+	}
 	s.current = current
 	logger.Debug("SetCurrent", "base", s.current.base, "height", s.current.highest)
 	return nil
@@ -358,7 +362,7 @@ func (s *snapshotDB) SetEmpty() error {
 }
 
 func (s *snapshotDB) PutBaseDB(key, value []byte) error {
-	err := s.baseDB.Put(key, value, nil)
+	err := s.baseDB.Put(key, value)
 	if err != nil {
 		return err
 	}
@@ -366,7 +370,7 @@ func (s *snapshotDB) PutBaseDB(key, value []byte) error {
 }
 
 func (s *snapshotDB) DelBaseDB(key []byte) error {
-	err := s.baseDB.Delete(key, nil)
+	err := s.baseDB.Delete(key)
 	if err != nil {
 		return err
 	}
@@ -384,6 +388,21 @@ func (s *snapshotDB) GetLastKVHash(blockHash common.Hash) []byte {
 	return block.kvHash.Bytes()
 }
 
+func (s *snapshotDB) WalkBlock(blockHash common.Hash, f func(key, value []byte) error) error {
+	block := s.unCommit.Get(blockHash)
+	if block == nil {
+		return fmt.Errorf("not find the block by hash:%v", blockHash.String())
+	}
+	itr := block.data.NewIterator(nil)
+	defer itr.Release()
+	for itr.Next() {
+		if err := f(itr.Key(), itr.Value()); err != nil {
+			return err
+		}
+	}
+	return itr.Error()
+}
+
 // Del del key,val from  snapshotDB
 // if hash is nil, unRecognizedBlockData > recognizedBlockData
 // if hash is not nil,it will del in recognized BlockData
@@ -407,17 +426,22 @@ func (s *snapshotDB) Compaction() error {
 	if commitNum == 0 {
 		return nil
 	}
+	// the compaction waits for the walks of the base
+	s.baseLock.Lock()
 	if err := s.writeToBasedb(commitNum); err != nil {
+		s.baseLock.Unlock()
 		return err
 	}
 	s.commitLock.Lock()
 	s.committed = s.committed[commitNum:]
 	if err := s.current.increaseBase(uint64(commitNum), s.baseDB); err != nil {
 		s.commitLock.Unlock()
+		s.baseLock.Unlock()
 		logger.Error("save base to current fail", "err", err)
 		return err
 	}
 	s.commitLock.Unlock()
+	s.baseLock.Unlock()
 	if err := s.rmExpireForkBlockJournal(); err != nil {
 		return err
 	}
@@ -512,7 +536,7 @@ func (s *snapshotDB) findToWrite() int {
 }
 
 func (s *snapshotDB) writeToBasedb(commitNum int) error {
-	batch := new(leveldb.Batch)
+	batch := s.baseDB.NewBatch()
 	for i := 0; i < commitNum; i++ {
 		itr := s.committed[i].data.NewIterator(nil)
 		for itr.Next() {
@@ -524,8 +548,14 @@ func (s *snapshotDB) writeToBasedb(commitNum int) error {
 		}
 		itr.Release()
 	}
+	if archiveMode {
+		if err := s.writeArchive(batch, s.committed[:commitNum]); err != nil {
+			logger.Error("write archive fail", "err", err)
+			return errors.New("[SnapshotDB]write archive fail:" + err.Error())
+		}
+	}
 	logger.Debug("write to basedb", "from", s.committed[0].Number, "to", s.committed[commitNum-1].Number, "len", len(s.committed), "commitNum", commitNum)
-	if err := s.baseDB.Write(batch, nil); err != nil {
+	if err := batch.Write(); err != nil {
 		logger.Error("write to baseDB fail", "err", err)
 		return errors.New("[SnapshotDB]write to baseDB fail:" + err.Error())
 	}
@@ -619,7 +649,11 @@ func (s *snapshotDB) getFromCommit(key []byte) ([]byte, error) {
 // Get get key,val from  snapshotDB
 // if hash is nil, unRecognizedBlockData > RecognizedBlockData > CommittedBlockData > baseDB
 // if hash is not nil,it will find from the chain, RecognizedBlockData > CommittedBlockData > baseDB
+// if hash is a committed block behind the highest and the archive mode is enabled, it will find from the archive
 func (s *snapshotDB) Get(hash common.Hash, key []byte) ([]byte, error) {
+	if number, ok := s.archivedBlockNumber(hash); ok {
+		return s.GetAtBlock(number, key)
+	}
 	v, err := s.getFromUnCommit(hash, key)
 	if err != nil && err != ErrNotFound {
 		return nil, err
@@ -644,9 +678,9 @@ func (s *snapshotDB) Get(hash common.Hash, key []byte) ([]byte, error) {
 }
 
 func (s *snapshotDB) GetBaseDB(key []byte) ([]byte, error) {
-	if v, err := s.baseDB.Get(key, nil); err == nil {
+	if v, err := s.baseDB.Get(key); err == nil {
 		return v, nil
-	} else if err != leveldb.ErrNotFound {
+	} else if err != ethdb.ErrNotFound {
 		return nil, err
 	} else {
 		return nil, ErrNotFound
@@ -772,12 +806,11 @@ func (s *snapshotDB) BaseNum() (*big.Int, error) {
 // slice
 func (s *snapshotDB) WalkBaseDB(slice *util.Range, f func(num *big.Int, iter iterator.Iterator) error) error {
 	logger.Debug("begin walkbase db")
-	snapshot, err := s.baseDB.GetSnapshot()
-	if err != nil {
-		return errors.New("[snapshotdb] get snapshot fail:" + err.Error())
-	}
-	defer snapshot.Release()
-	t := snapshot.NewIterator(slice, nil)
+	// the base isn't written during the walk, the iterator of bolt reads it in short
+	// transactions instead of holding a snapshot
+	s.baseLock.RLock()
+	defer s.baseLock.RUnlock()
+	t := s.baseDB.NewIteratorWithRange(slice)
 	defer func() {
 		logger.Debug("WalkBaseDB release ")
 		t.Release()
@@ -807,6 +840,9 @@ func (s *snapshotDB) Clear() error {
 // The iterator must be released after use, by calling Release method.t
 // Also read Iterator documentation of the leveldb/iterator package.
 func (s *snapshotDB) Ranking(hash common.Hash, key []byte, rangeNumber int) iterator.Iterator {
+	if number, ok := s.archivedBlockNumber(hash); ok {
+		return s.RankingAtBlock(number, key, rangeNumber)
+	}
 	prefix := util.BytesPrefix(key)
 	var itrs []iterator.Iterator
 	var parentHash common.Hash
@@ -846,7 +882,7 @@ func (s *snapshotDB) Ranking(hash common.Hash, key []byte, rangeNumber int) iter
 
 	//	t = time.Now()
 	//put baseDB itr to heap
-	itr := s.baseDB.NewIterator(prefix, nil)
+	itr := s.baseDB.NewIteratorWithPrefix(key)
 	rankingHeap.itr2Heap(itr, true, true)
 	//	logger.Info("Ranking base", "rangeNumber", rangeNumber, "hash", hash, "duration", time.Since(t))
 
@@ -877,9 +913,7 @@ func (s *snapshotDB) Close() error {
 	close(s.journalWriteExitCh)
 
 	if s.baseDB != nil {
-		if err := s.baseDB.Close(); err != nil {
-			return fmt.Errorf("[snapshotdb]close base db fail:%v", err)
-		}
+		s.baseDB.Close()
 	}
 
 	if err := s.storage.Close(); err != nil {
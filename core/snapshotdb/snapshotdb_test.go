@@ -838,6 +838,35 @@ func TestSnapshotDB_GetLastKVHash(t *testing.T) {
 	})
 }
 
+func TestSnapshotDB_WalkBlock(t *testing.T) {
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+	blockHash := generateHash("walkBlock")
+	ch.db.NewBlock(big.NewInt(1), common.ZeroHash, blockHash)
+	ch.db.Put(blockHash, []byte("b"), []byte("b"))
+	ch.db.Put(blockHash, []byte("a"), []byte("a"))
+	ch.db.Del(blockHash, []byte("c"))
+
+	var keys, values [][]byte
+	err := ch.db.WalkBlock(blockHash, func(key, value []byte) error {
+		keys = append(keys, common.CopyBytes(key))
+		values = append(values, common.CopyBytes(value))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 || string(keys[0]) != "a" || string(keys[1]) != "b" || string(keys[2]) != "c" {
+		t.Fatalf("keys must be walked in order, have %q", keys)
+	}
+	if len(values[2]) != 0 {
+		t.Error("the value of the deleted key must be empty", values[2])
+	}
+	if err := ch.db.WalkBlock(generateHash("notExist"), func(key, value []byte) error { return nil }); err == nil {
+		t.Error("walk the block not exist must fail")
+	}
+}
+
 func TestSnapshotDB_BaseNum(t *testing.T) {
 	ch := newTestchain(dbpath)
 	defer ch.clear()
@@ -930,7 +959,7 @@ func TestSnapshotDB_Compaction222222(t *testing.T) {
 			t.Error("must be 14:", len(ch.db.committed))
 		}
 		for _, kv := range kvs1 {
-			v, err := ch.db.baseDB.Get(kv.key, nil)
+			v, err := ch.db.baseDB.Get(kv.key)
 			if err != nil {
 				t.Error(err)
 			}
@@ -952,7 +981,7 @@ func TestSnapshotDB_Compaction222222(t *testing.T) {
 		}
 		for _, kvs := range [][]kv{kvs2, kvs3, kvs4} {
 			for _, kv := range kvs {
-				v, err := ch.db.baseDB.Get(kv.key, nil)
+				v, err := ch.db.baseDB.Get(kv.key)
 				if err != nil {
 					t.Error(err)
 				}
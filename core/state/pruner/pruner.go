@@ -0,0 +1,265 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package pruner implements the offline pruning of the stale state trie nodes
+// in the chain database.
+package pruner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+var (
+	emptyRoot     = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+
+	// logInterval is the interval of the progress logs.
+	logInterval = 8 * time.Second
+
+	ErrHeadMissing    = errors.New("head block is missing")
+	ErrNothingToPrune = errors.New("not enough blocks to prune")
+	ErrTargetMissing  = errors.New("state of the target block is missing")
+)
+
+// Database is the chain database the pruner works on, it must be able to iterate
+// over all the keys and to compact the deleted ranges.
+type Database interface {
+	ethdb.Database
+	NewIteratorWithStart(start []byte) iterator.Iterator
+	Compact(start []byte, limit []byte) error
+}
+
+// Pruner deletes the state trie nodes which are not reachable from the states of
+// the genesis and of the blocks between the target block and the current head.
+//
+// The state trie nodes, the contract codes and abis and the storage values are all
+// stored by their hashes in the chain database, every key with the length of a hash
+// which is not marked as live is deleted. The other data of the chain database and
+// the snapshotdb holding the PPOS data are not touched.
+type Pruner struct {
+	db      Database
+	stateDB state.Database
+	live    map[common.Hash]struct{}
+}
+
+// NewPruner creates a pruner on the chain database, the node must be stopped.
+func NewPruner(db Database) *Pruner {
+	return &Pruner{
+		db:      db,
+		stateDB: state.NewDatabase(db),
+		live:    make(map[common.Hash]struct{}),
+	}
+}
+
+// Prune deletes the states of the blocks below head-retain. An interrupted pruning
+// is resumed with its original target, the retain is ignored in that case.
+func (p *Pruner) Prune(retain uint64) error {
+	headHash := rawdb.ReadHeadBlockHash(p.db)
+	headNumber := rawdb.ReadHeaderNumber(p.db, headHash)
+	if headNumber == nil {
+		return ErrHeadMissing
+	}
+	progress := rawdb.ReadPruneStateProgress(p.db)
+	if progress != nil {
+		log.Info("Resuming the interrupted state pruning", "target", progress.Target, "next", common.Bytes2Hex(progress.Next))
+	} else {
+		if *headNumber <= retain {
+			return ErrNothingToPrune
+		}
+		progress = &rawdb.PruneStateProgress{Target: *headNumber - retain}
+	}
+	if progress.Target > *headNumber {
+		return fmt.Errorf("the prune target %d is higher than the head %d", progress.Target, *headNumber)
+	}
+
+	// The retained states are marked again on resuming, the node may be started
+	// after the interruption, the states written since then are kept as well.
+	start := time.Now()
+	if err := p.markBlock(0, false); err != nil {
+		return err
+	}
+	for number := progress.Target; number <= *headNumber; number++ {
+		if err := p.markBlock(number, number == progress.Target); err != nil {
+			return err
+		}
+	}
+	log.Info("Marked the live state nodes", "target", progress.Target, "head", *headNumber, "nodes", len(p.live), "elapsed", common.PrettyDuration(time.Since(start)))
+
+	rawdb.WritePruneStateProgress(p.db, progress)
+	return p.sweep(progress)
+}
+
+// markBlock marks the state of the canonical block, the state is skipped if it
+// isn't persisted unless the block is required.
+func (p *Pruner) markBlock(number uint64, required bool) error {
+	hash := rawdb.ReadCanonicalHash(p.db, number)
+	header := rawdb.ReadHeader(p.db, hash, number)
+	if header == nil {
+		return fmt.Errorf("canonical header %d is missing", number)
+	}
+	if header.Root != emptyRoot {
+		if ok, _ := p.db.Has(header.Root.Bytes()); !ok {
+			if required {
+				return ErrTargetMissing
+			}
+			log.Debug("Skip the missing state", "number", number, "root", header.Root)
+			return nil
+		}
+	}
+	if err := p.markState(header.Root); err != nil {
+		return fmt.Errorf("failed to mark the state of block %d: %v", number, err)
+	}
+	return nil
+}
+
+// markState marks the nodes of the account trie, the storage tries, the codes
+// and abis of the contracts. The subtries which are marked already are skipped.
+func (p *Pruner) markState(root common.Hash) error {
+	tr, err := p.stateDB.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for descend := true; it.Next(descend); {
+		if descend = p.mark(it.Hash()); !descend || !it.Leaf() {
+			continue
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+			return err
+		}
+		if err := p.markStorage(common.BytesToHash(it.LeafKey()), account.Root); err != nil {
+			return err
+		}
+		if len(account.CodeHash) > 0 {
+			p.mark(common.BytesToHash(account.CodeHash))
+		}
+		if len(account.AbiHash) > 0 {
+			p.mark(common.BytesToHash(account.AbiHash))
+		}
+	}
+	return it.Error()
+}
+
+// markStorage marks the nodes of the storage trie and the values they refer to,
+// the leaves of a storage trie only hold the keys of the values.
+func (p *Pruner) markStorage(addrHash, root common.Hash) error {
+	if root == emptyRoot {
+		return nil
+	}
+	tr, err := p.stateDB.OpenStorageTrie(addrHash, root)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for descend := true; it.Next(descend); {
+		if descend = p.mark(it.Hash()); !descend || !it.Leaf() {
+			continue
+		}
+		_, content, _, err := rlp.Split(it.LeafBlob())
+		if err != nil {
+			return err
+		}
+		p.mark(common.BytesToHash(content))
+	}
+	return it.Error()
+}
+
+// mark adds the hash to the live nodes, false is returned if it's marked already.
+// The embedded nodes and the leaves have no hashes, they are always visited.
+func (p *Pruner) mark(hash common.Hash) bool {
+	if hash == (common.Hash{}) || hash == emptyCodeHash {
+		return true
+	}
+	if _, ok := p.live[hash]; ok {
+		return false
+	}
+	p.live[hash] = struct{}{}
+	return true
+}
+
+// sweep deletes the hash keys which are not marked, the progress is written with
+// every batch so that the deletion can be resumed.
+func (p *Pruner) sweep(progress *rawdb.PruneStateProgress) error {
+	var (
+		start   = time.Now()
+		logged  = time.Now()
+		batch   = p.db.NewBatch()
+		deleted int
+		size    common.StorageSize
+	)
+	it := p.db.NewIteratorWithStart(progress.Next)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != common.HashLength {
+			continue
+		}
+		if _, ok := p.live[common.BytesToHash(key)]; ok {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		deleted++
+		size += common.StorageSize(len(key) + len(it.Value()))
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			progress.Next = common.CopyBytes(key)
+			rawdb.WritePruneStateProgress(batch, progress)
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > logInterval {
+			log.Info("Pruning state data", "deleted", deleted, "size", size, "key", common.Bytes2Hex(key), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	rawdb.DeletePruneStateProgress(batch)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Pruned state data", "deleted", deleted, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	start = time.Now()
+	log.Info("Compacting database")
+	if err := p.db.Compact(nil, nil); err == ethdb.ErrCompactionNotSupported {
+		log.Info("Skipped database compaction, not supported by the engine")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	log.Info("Compacted database", "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+)
+
+var (
+	testAccount  = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	testContract = common.HexToAddress("0x1000000000000000000000000000000000000002")
+	testCode     = []byte{0x00, 0x61, 0x73, 0x6d, 0x01}
+)
+
+func newTestLDB(t *testing.T) (*ethdb.LDBDatabase, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "pruner_test_")
+	if err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+	db, err := ethdb.NewLDBDatabase(dirname, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
+// writeTestBlock commits the state to the database and writes the canonical
+// header of it as the new head.
+func writeTestBlock(t *testing.T, db ethdb.Database, sdb state.Database, statedb *state.StateDB, number uint64) common.Hash {
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := sdb.TrieDB().Commit(root, false, false); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Root: root}
+	rawdb.WriteHeader(db, header)
+	rawdb.WriteCanonicalHash(db, header.Hash(), number)
+	rawdb.WriteHeadBlockHash(db, header.Hash())
+	return root
+}
+
+// makeTestChain writes the blocks of which the states update the same storage,
+// the values of the old blocks become stale.
+func makeTestChain(t *testing.T, db ethdb.Database, blocks int) []common.Hash {
+	sdb := state.NewDatabase(db)
+	statedb, _ := state.New(common.Hash{}, sdb)
+	statedb.AddBalance(testAccount, big.NewInt(1))
+	roots := []common.Hash{writeTestBlock(t, db, sdb, statedb, 0)}
+
+	for i := 1; i <= blocks; i++ {
+		statedb, _ = state.New(roots[i-1], sdb)
+		statedb.AddBalance(testAccount, big.NewInt(1))
+		statedb.SetCode(testContract, testCode)
+		statedb.SetState(testContract, []byte("key"), []byte{byte(i)})
+		statedb.SetState(testContract, []byte{byte(i)}, []byte("value"))
+		roots = append(roots, writeTestBlock(t, db, sdb, statedb, uint64(i)))
+	}
+	return roots
+}
+
+func checkTestState(t *testing.T, db ethdb.Database, root common.Hash, number int) {
+	statedb, err := state.New(root, state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("state of block %d is missing: %v", number, err)
+	}
+	if balance := statedb.GetBalance(testAccount); balance.Cmp(big.NewInt(int64(number+1))) != 0 {
+		t.Errorf("balance mismatch of block %d: have %v, want %d", number, balance, number+1)
+	}
+	if number == 0 {
+		return
+	}
+	if code := statedb.GetCode(testContract); !bytes.Equal(code, testCode) {
+		t.Errorf("code mismatch of block %d: have %x, want %x", number, code, testCode)
+	}
+	if value := statedb.GetState(testContract, []byte("key")); !bytes.Equal(value, []byte{byte(number)}) {
+		t.Errorf("storage mismatch of block %d: have %x, want %x", number, value, []byte{byte(number)})
+	}
+	for i := 1; i <= number; i++ {
+		if value := statedb.GetState(testContract, []byte{byte(i)}); !bytes.Equal(value, []byte("value")) {
+			t.Errorf("storage %d mismatch of block %d: have %x", i, number, value)
+		}
+	}
+}
+
+func TestPruner_Prune(t *testing.T) {
+	db, remove := newTestLDB(t)
+	defer remove()
+
+	roots := makeTestChain(t, db, 8)
+	if err := NewPruner(db).Prune(uint64(len(roots))); err != ErrNothingToPrune {
+		t.Fatalf("prune error mismatch: have %v, want %v", err, ErrNothingToPrune)
+	}
+
+	if err := NewPruner(db).Prune(2); err != nil {
+		t.Fatalf("failed to prune state: %v", err)
+	}
+	if rawdb.ReadPruneStateProgress(db) != nil {
+		t.Error("prune progress is not removed")
+	}
+	checkTestState(t, db, roots[0], 0)
+	for i := 6; i <= 8; i++ {
+		checkTestState(t, db, roots[i], i)
+	}
+	for i := 1; i < 6; i++ {
+		if ok, _ := db.Has(roots[i].Bytes()); ok {
+			t.Errorf("state root of block %d is not pruned", i)
+		}
+		if ok, _ := db.Has(crypto.Keccak256([]byte{byte(i)})); ok {
+			t.Errorf("stale storage value of block %d is not pruned", i)
+		}
+	}
+	if ok, _ := db.Has(crypto.Keccak256(testCode)); !ok {
+		t.Error("contract code is pruned")
+	}
+	if ok, _ := db.Has(crypto.Keccak256([]byte("value"))); !ok {
+		t.Error("live storage value is pruned")
+	}
+}
+
+func TestPruner_Resume(t *testing.T) {
+	db, remove := newTestLDB(t)
+	defer remove()
+
+	roots := makeTestChain(t, db, 8)
+
+	// The interrupted pruning retains the states from block 4, the deletion is
+	// resumed from the middle of the key space.
+	next := common.HexToHash("0x8000000000000000000000000000000000000000000000000000000000000000")
+	rawdb.WritePruneStateProgress(db, &rawdb.PruneStateProgress{Target: 4, Next: next.Bytes()})
+
+	if err := NewPruner(db).Prune(1); err != nil {
+		t.Fatalf("failed to resume pruning: %v", err)
+	}
+	if rawdb.ReadPruneStateProgress(db) != nil {
+		t.Error("prune progress is not removed")
+	}
+	for i := 4; i <= 8; i++ {
+		checkTestState(t, db, roots[i], i)
+	}
+	for i := 1; i < 4; i++ {
+		ok, _ := db.Has(roots[i].Bytes())
+		if pruned := bytes.Compare(roots[i].Bytes(), next.Bytes()) >= 0; ok == pruned {
+			t.Errorf("state root of block %d mismatch: have %v, want %v", i, ok, !pruned)
+		}
+	}
+}
+
+func TestPruner_TargetMissing(t *testing.T) {
+	db, remove := newTestLDB(t)
+	defer remove()
+
+	roots := makeTestChain(t, db, 4)
+	db.Delete(roots[2].Bytes())
+
+	if err := NewPruner(db).Prune(2); err != ErrTargetMissing {
+		t.Fatalf("prune error mismatch: have %v, want %v", err, ErrTargetMissing)
+	}
+	if rawdb.ReadPruneStateProgress(db) != nil {
+		t.Error("prune progress is written on failure")
+	}
+	checkTestState(t, db, roots[1], 1)
+}
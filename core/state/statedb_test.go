@@ -35,7 +35,10 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/trie"
 )
 
 var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
@@ -354,6 +357,53 @@ func TestStateStorageValueUpdate(t *testing.T) {
 
 // Tests that no intermediate state of an object is stored into the database,
 // only the one right before the commit.
+func TestStorageProof(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(ethdb.NewMemDatabase()))
+	addr := common.BytesToAddress([]byte{0x01})
+	state.SetNonce(addr, 1)
+	state.SetState(addr, []byte("key"), []byte("value"))
+	root, _ := state.Commit(false)
+
+	proofDB := func(proof [][]byte) *ethdb.MemDatabase {
+		db := ethdb.NewMemDatabase()
+		for _, node := range proof {
+			db.Put(crypto.Keccak256(node), node)
+		}
+		return db
+	}
+	accountProof, err := state.GetProof(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := trie.VerifyProof(root, crypto.Keccak256(addr.Bytes()), proofDB(accountProof))
+	if err != nil || data == nil {
+		t.Fatalf("invalid account proof: %v", err)
+	}
+	var account Account
+	if err := rlp.DecodeBytes(data, &account); err != nil {
+		t.Fatal(err)
+	}
+
+	storageProof, err := state.GetStorageProof(addr, []byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, _, err := trie.VerifyProof(account.Root, crypto.Keccak256([]byte("key")), proofDB(storageProof))
+	if err != nil {
+		t.Fatalf("invalid storage proof: %v", err)
+	}
+	var valueKey []byte
+	if err := rlp.DecodeBytes(leaf, &valueKey); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(valueKey, bytes.TrimLeft(crypto.Keccak256([]byte("value")), "\x00")) {
+		t.Errorf("storage leaf mismatch, have %x", valueKey)
+	}
+	if _, err := state.GetStorageProof(common.BytesToAddress([]byte{0x02}), []byte("key")); err == nil {
+		t.Error("proof of the account not exist must fail")
+	}
+}
+
 func TestIntermediateLeaks(t *testing.T) {
 	// Create two state databases, one transitioning to the final state, the other final from the beginning
 	transDb := ethdb.NewMemDatabase()
@@ -397,19 +397,27 @@ func (h *priceHeap) Pop() interface{} {
 // txPricedList is a price-sorted heap to allow operating on transactions pool
 // contents in a price-incrementing way.
 type txPricedList struct {
-	all    *txLookup  // Pointer to the map of all transactions
-	items  *priceHeap // Heap of prices of all the stored transactions
-	stales int        // Number of stale price points to (re-heap trigger)
+	all      *txLookup  // Pointer to the map of all transactions
+	priority *txLookup  // Pointer to the map of the priority transactions exempt from the price rules
+	items    *priceHeap // Heap of prices of all the stored transactions
+	stales   int        // Number of stale price points to (re-heap trigger)
 }
 
 // newTxPricedList creates a new price-sorted transaction heap.
-func newTxPricedList(all *txLookup) *txPricedList {
+func newTxPricedList(all *txLookup, priority *txLookup) *txPricedList {
 	return &txPricedList{
-		all:   all,
-		items: new(priceHeap),
+		all:      all,
+		priority: priority,
+		items:    new(priceHeap),
 	}
 }
 
+// exempt checks whether the transaction is kept regardless of its price, the
+// local and the priority transactions are.
+func (l *txPricedList) exempt(tx *types.Transaction, local *accountSet) bool {
+	return local.containsTx(tx) || l.priority.Get(tx.Hash()) != nil
+}
+
 // Put inserts a new transaction into the heap.
 func (l *txPricedList) Put(tx *types.Transaction) {
 	heap.Push(l.items, tx)
@@ -453,8 +461,8 @@ func (l *txPricedList) Cap(threshold *big.Int, local *accountSet) types.Transact
 			save = append(save, tx)
 			break
 		}
-		// Non stale transaction found, discard unless local
-		if local.containsTx(tx) {
+		// Non stale transaction found, discard unless local or priority
+		if l.exempt(tx, local) {
 			save = append(save, tx)
 		} else {
 			drop = append(drop, tx)
@@ -469,8 +477,8 @@ func (l *txPricedList) Cap(threshold *big.Int, local *accountSet) types.Transact
 // Underpriced checks whether a transaction is cheaper than (or as cheap as) the
 // lowest priced transaction currently being tracked.
 func (l *txPricedList) Underpriced(tx *types.Transaction, local *accountSet) bool {
-	// Local transactions cannot be underpriced
-	if local.containsTx(tx) {
+	// Local and priority transactions cannot be underpriced
+	if l.exempt(tx, local) {
 		return false
 	}
 	// Discard stale price points if found at the heap start
@@ -505,8 +513,8 @@ func (l *txPricedList) Discard(count int, local *accountSet) types.Transactions
 			l.stales--
 			continue
 		}
-		// Non stale transaction found, discard unless local
-		if local.containsTx(tx) {
+		// Non stale transaction found, discard unless local or priority
+		if l.exempt(tx, local) {
 			save = append(save, tx)
 		} else {
 			drop = append(drop, tx)
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"math/big"
 	"math/rand"
 	"testing"
 
@@ -49,3 +50,43 @@ func TestStrictTxListAdd(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the priority transactions are never discarded or underpriced by the
+// price-sorted list.
+func TestTxPricedListPriority(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	all, priority := newTxLookup(), newTxLookup()
+	priced := newTxPricedList(all, priority)
+	locals := newAccountSet(types.NewEIP155Signer(new(big.Int)))
+
+	txs := make(types.Transactions, 4)
+	for i := 0; i < len(txs); i++ {
+		txs[i] = pricedTransaction(uint64(i), 0, big.NewInt(int64(i+1)), key)
+		all.Add(txs[i])
+		priced.Put(txs[i])
+	}
+	// The cheapest transaction is in the priority lane
+	priority.Add(txs[0])
+
+	if priced.Underpriced(txs[0], locals) {
+		t.Error("priority transaction is underpriced")
+	}
+	if !priced.Underpriced(pricedTransaction(4, 0, big.NewInt(1), key), locals) {
+		t.Error("normal transaction as cheap as the priority one isn't underpriced")
+	}
+	drop := priced.Discard(2, locals)
+	if len(drop) != 2 || drop[0] != txs[1] || drop[1] != txs[2] {
+		t.Fatalf("discarded transactions mismatch: have %v, want %v", drop, txs[1:3])
+	}
+	for _, tx := range drop {
+		all.Remove(tx.Hash())
+		priced.Removed()
+	}
+	if drop := priced.Cap(big.NewInt(10), locals); len(drop) != 1 || drop[0] != txs[3] {
+		t.Fatalf("capped transactions mismatch: have %v, want %v", drop, txs[3:])
+	}
+	if priced.items.Len() != 1 || (*priced.items)[0] != txs[0] {
+		t.Errorf("priority transaction is dropped from the priced list")
+	}
+}
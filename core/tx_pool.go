@@ -109,6 +109,10 @@ var (
 	knowingTxCounter     = metrics.NewRegisteredCounter("txpool/knowing", nil)
 	invalidTxCounter     = metrics.NewRegisteredCounter("txpool/invalid", nil)
 	underpricedTxCounter = metrics.NewRegisteredCounter("txpool/underpriced", nil)
+
+	// Metrics for the priority lane
+	priorityTxCounter       = metrics.NewRegisteredCounter("txpool/priority", nil)
+	priorityOverflowCounter = metrics.NewRegisteredCounter("txpool/priority/overflow", nil) // Admitted as normal due to the full lane
 )
 
 // TxStatus is the current status of a transaction as seen by the pool.
@@ -171,6 +175,7 @@ type TxPoolConfig struct {
 	AccountQueue  uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue   uint64 // Maximum number of non-executable transaction slots for all accounts
 	GlobalTxCount uint64 // Maximum number of transactions for package
+	PrioritySlots uint64 // Maximum number of transaction slots for the PPOS duties of the verifiers
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 
@@ -192,6 +197,7 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	AccountQueue:  64,
 	GlobalQueue:   1024,
 	GlobalTxCount: 3000,
+	PrioritySlots: 256,
 
 	Lifetime: 3 * time.Hour,
 
@@ -243,6 +249,7 @@ type TxPool struct {
 	currentState  *state.StateDB      // Current state in the blockchain head
 	pendingState  *state.ManagedState // Pending state tracking virtual nonces
 	currentMaxGas uint64              // Current gas limit for transaction caps
+	currentHead   *types.Header       // Current head to check the priority transactions against
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -253,6 +260,15 @@ type TxPool struct {
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
 
+	// The priority lane holds the PPOS duties of the verifiers which have to be
+	// packed before their deadlines, they keep the price floor but are never
+	// evicted as underpriced, and they are packed ahead of the other transactions.
+	// The lane is a subset of the executable transactions, the ones which left the
+	// pool or became future ones are dropped from it lazily.
+	priority        *txLookup
+	priorityChecker priorityChecker      // Checks the priority transactions, the blockchain reactor is used if nil
+	duties          map[common.Hash]bool // The PPOS duties of the adding transactions, checked without the pool lock
+
 	wg sync.WaitGroup // for shutdown sync
 
 	txExtBuffer chan *txExt
@@ -286,6 +302,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain *Bloc
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
 		all:         newTxLookup(),
+		priority:    newTxLookup(),
 		// modified by PlatON
 		// chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
 		chainHeadCh: make(chan *types.Block, config.ChainHeadChanSize),
@@ -299,7 +316,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain *Bloc
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
-	pool.priced = newTxPricedList(pool.all)
+	pool.priced = newTxPricedList(pool.all, pool.priority)
 	pool.reset(nil, chain.currentBlock.Load().(*types.Block).Header())
 
 	go pool.txExtBufferReadLoop()
@@ -576,6 +593,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
+	pool.currentHead = newHead
 
 	// Inject any transactions discarded due to reorgs
 	t := time.Now()
@@ -598,6 +616,9 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// or remove those that have become invalid
 	// or remove those that have become invalid
 	pool.promoteExecutables(nil)
+
+	// Drop the included and invalidated transactions out of the priority lane
+	pool.truncatePriority()
 }
 
 // Stop terminates the transaction pool.
@@ -650,6 +671,15 @@ func (pool *TxPool) State() *state.ManagedState {
 	return pool.pendingState
 }
 
+// Nonce returns the next nonce of an account, with all transactions executable
+// by the pool already applied on top.
+func (pool *TxPool) Nonce(addr common.Address) uint64 {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.pendingState.GetNonce(addr)
+}
+
 // Stats retrieves the current pool stats, namely the number of pending and the
 // number of queued (non-executable) transactions.
 func (pool *TxPool) Stats() (int, int) {
@@ -725,6 +755,36 @@ func (pool *TxPool) PendingLimited() (map[common.Address]types.Transactions, err
 	return pending, nil
 }
 
+// PendingPriority retrieves the processable transactions of the priority lane,
+// grouped by origin account and sorted by nonce. The transactions of an account
+// end with its last priority transaction, the ones in front of it are included
+// since they have to be packed first. The returned transaction set is a copy and
+// can be freely modified by calling code.
+func (pool *TxPool) PendingPriority() map[common.Address]types.Transactions {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pending := make(map[common.Address]types.Transactions)
+	pool.priority.Range(func(hash common.Hash, tx *types.Transaction) bool {
+		addr, _ := types.Sender(pool.signer, tx) // already validated
+		list := pool.pending[addr]
+		if list == nil {
+			return true
+		}
+		// The lane transaction may be queued or replaced already, it's packed
+		// only if it's still pending
+		txs := list.Flatten()
+		for i := len(pending[addr]); i < len(txs); i++ {
+			if txs[i].Hash() == hash {
+				pending[addr] = txs[:i+1]
+				break
+			}
+		}
+		return true
+	})
+	return pending
+}
+
 // Locals retrieves the accounts currently considered local by the pool.
 func (pool *TxPool) Locals() []common.Address {
 	pool.mu.Lock()
@@ -823,6 +883,10 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		log.Trace("Discarding already known transaction", "hash", hash)
 		return false, fmt.Errorf("known transaction: %x", hash)
 	}
+	// The priority transactions are never discarded as underpriced if the pool is
+	// full, but they have to pay the minimal accepted gas price
+	priority := pool.isPriorityTx(tx)
+
 	// If the transaction fails basic validation, discard it
 	if err := pool.validateTx(tx, local); err != nil {
 		log.Trace("Discarding invalid transaction", "hash", hash, "err", err)
@@ -832,7 +896,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
-		if !local && pool.priced.Underpriced(tx, pool.locals) {
+		if !local && !priority && pool.priced.Underpriced(tx, pool.locals) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
 			underpricedTxCounter.Inc(1)
 			return false, ErrUnderpriced
@@ -863,6 +927,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
 		pool.journalTx(from, tx)
+		pool.addPriority(tx, priority)
 
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
@@ -884,11 +949,112 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		}
 	}
 	pool.journalTx(from, tx)
+	pool.addPriority(tx, priority)
 
 	log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return replace, nil
 }
 
+// priorityChecker checks whether the transaction is a PPOS duty of the verifiers
+// with a deadline, it's implemented by the BlockChainReactor.
+type priorityChecker interface {
+	IsPriorityTx(tx *types.Transaction, from common.Address, header *types.Header, state *state.StateDB) bool
+}
+
+// checkDuties checks which of the transactions are PPOS duties against the current
+// head. It's called before the pool lock is held, since the checking queries the
+// verifiers and verifies the signatures of the evidences.
+func (pool *TxPool) checkDuties(txs []*types.Transaction) map[common.Hash]bool {
+	pool.mu.RLock()
+	head := pool.currentHead
+	pool.mu.RUnlock()
+
+	if pool.config.PrioritySlots == 0 || head == nil {
+		return nil
+	}
+	// The reactor is created after the pool
+	checker := pool.priorityChecker
+	if checker == nil {
+		if bcr == nil {
+			return nil
+		}
+		checker = bcr
+	}
+	statedb, err := pool.chain.GetState(head)
+	if err != nil {
+		log.Debug("Failed to check the priority transactions", "number", head.Number, "hash", head.Hash(), "err", err)
+		return nil
+	}
+	duties := make(map[common.Hash]bool)
+	for _, tx := range txs {
+		from, err := types.Sender(pool.signer, tx)
+		if err == nil && checker.IsPriorityTx(tx, from, head, statedb) {
+			duties[tx.Hash()] = true
+		}
+	}
+	return duties
+}
+
+// isPriorityTx checks whether the transaction can be admitted into the priority
+// lane, the transaction must be a PPOS duty checked by checkDuties, and it must
+// be executable. It's treated as a normal one if the lane is full.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) isPriorityTx(tx *types.Transaction) bool {
+	if !pool.duties[tx.Hash()] {
+		return false
+	}
+	from, _ := types.Sender(pool.signer, tx) // already checked
+	if !pool.isExecutable(from, tx) {
+		return false
+	}
+	if uint64(pool.priority.Count()) >= pool.config.PrioritySlots {
+		pool.truncatePriority()
+		if uint64(pool.priority.Count()) >= pool.config.PrioritySlots {
+			log.Debug("Priority lane is full, admitting as normal transaction", "hash", tx.Hash(), "from", from)
+			priorityOverflowCounter.Inc(1)
+			return false
+		}
+	}
+	return true
+}
+
+// addPriority puts the pooled transaction into the priority lane if it's admitted.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) addPriority(tx *types.Transaction, priority bool) {
+	if priority {
+		pool.priority.Add(tx)
+		priorityTxCounter.Inc(1)
+	}
+}
+
+// isExecutable checks whether the transaction is pending, or it's the next one
+// of the account to be promoted.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) isExecutable(from common.Address, tx *types.Transaction) bool {
+	return tx.Nonce() <= pool.pendingState.GetNonce(from)
+}
+
+// truncatePriority drops the transactions which have left the pool or have been
+// demoted to the future ones out of the priority lane.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) truncatePriority() {
+	var stales []common.Hash
+	pool.priority.Range(func(hash common.Hash, tx *types.Transaction) bool {
+		from, _ := types.Sender(pool.signer, tx) // already validated
+		if pool.all.Get(hash) == nil || !pool.isExecutable(from, tx) {
+			stales = append(stales, hash)
+		}
+		return true
+	})
+	for _, hash := range stales {
+		pool.priority.Remove(hash)
+	}
+}
+
 // enqueueTx inserts a new transaction into the non-executable transaction queue.
 //
 // Note, this method assumes the pool lock is held!
@@ -1116,9 +1282,14 @@ func (pool *TxPool) recoverTx(tx *types.Transaction, from common.Address, local
 
 // addTx enqueues a single transaction into the pool if it is valid.
 func (pool *TxPool) addTx(tx *types.Transaction, local bool) error {
+	duties := pool.checkDuties([]*types.Transaction{tx})
+
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	pool.duties = duties
+	defer func() { pool.duties = nil }()
+
 	return pool.addTxLocked(tx, local)
 	/*// Try to inject the transaction and update any state
 	replace, err := pool.add(tx, local)
@@ -1149,9 +1320,19 @@ func (pool *TxPool) addTxLocked(tx *types.Transaction, local bool) error {
 }
 
 func (pool *TxPool) addTxExt(txExt *txExt) interface{} {
+	var duties map[common.Hash]bool
+	if tx, ok := txExt.tx.(*types.Transaction); ok {
+		duties = pool.checkDuties([]*types.Transaction{tx})
+	} else if txs, ok := txExt.tx.([]*types.Transaction); ok {
+		duties = pool.checkDuties(txs)
+	}
+
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	pool.duties = duties
+	defer func() { pool.duties = nil }()
+
 	if tx, ok := txExt.tx.(*types.Transaction); ok {
 		err := pool.addTxLocked(tx, txExt.local)
 		if txExt.local && err != nil {
@@ -1170,9 +1351,14 @@ func (pool *TxPool) addTxExt(txExt *txExt) interface{} {
 
 // addTxs attempts to queue a batch of transactions if they are valid.
 func (pool *TxPool) addTxs(txs []*types.Transaction, local bool) []error {
+	duties := pool.checkDuties(txs)
+
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	pool.duties = duties
+	defer func() { pool.duties = nil }()
+
 	return pool.addTxsLocked(txs, local)
 }
 
@@ -21,13 +21,18 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
 // testTxPoolConfig is a transaction pool configuration without stateful disk
@@ -1858,3 +1863,62 @@ func benchmarkPoolBatchInsert(b *testing.B, size int) {
 		pool.AddRemotes(batch)
 	}*/
 }
+
+// Tests that the priority transactions still have to pay the minimal accepted gas price,
+// and only the executable ones are admitted into the lane.
+func TestTransactionPriorityPriceLimit(t *testing.T) {
+	NewBlockChainReactor(new(event.TypeMux))
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	signer := types.NewEIP155Signer(new(big.Int))
+	pool := &TxPool{
+		config:        testTxPoolConfig,
+		signer:        signer,
+		currentState:  statedb,
+		pendingState:  state.ManageState(statedb),
+		currentMaxGas: 1000000,
+		currentHead:   &types.Header{Number: big.NewInt(1)},
+		gasPrice:      big.NewInt(1),
+		pending:       make(map[common.Address]*txList),
+		queue:         make(map[common.Address]*txList),
+		beats:         make(map[common.Address]time.Time),
+		all:           newTxLookup(),
+		priority:      newTxLookup(),
+		locals:        newAccountSet(signer),
+	}
+	pool.priced = newTxPricedList(pool.all, pool.priority)
+
+	key, _ := crypto.GenerateKey()
+	statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+
+	fnType, _ := rlp.EncodeToBytes(uint16(vm.TxReportDuplicateSign))
+	dupType, _ := rlp.EncodeToBytes(uint8(1))
+	data, _ := rlp.EncodeToBytes("{}")
+	input, _ := rlp.EncodeToBytes([][]byte{fnType, dupType, data})
+	// all the reports are checked as the PPOS duties
+	pool.duties = make(map[common.Hash]bool)
+	report := func(nonce uint64, gasPrice *big.Int) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, cvm.SlashingContractAddr, common.Big0, 100000, gasPrice, input), signer, key)
+		pool.duties[tx.Hash()] = true
+		return tx
+	}
+
+	if _, err := pool.add(report(0, common.Big0), false); err != ErrUnderpriced {
+		t.Errorf("zero price report error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	if pool.priority.Count() != 0 {
+		t.Errorf("priority lane size mismatch: have %d, want 0", pool.priority.Count())
+	}
+	if _, err := pool.add(report(0, common.Big1), false); err != nil {
+		t.Fatalf("failed to add the report: %v", err)
+	}
+	if pool.priority.Count() != 1 {
+		t.Errorf("priority lane size mismatch: have %d, want 1", pool.priority.Count())
+	}
+	if _, err := pool.add(report(2, common.Big1), false); err != nil {
+		t.Fatalf("failed to add the future report: %v", err)
+	}
+	if pool.priority.Count() != 1 {
+		t.Errorf("priority lane size mismatch: have %d, want 1", pool.priority.Count())
+	}
+}
@@ -113,7 +113,12 @@ func (header *Header) _sealHash() (hash common.Hash) {
 	extra := header.Extra
 
 	hasher := sha3.NewKeccak256()
-	if len(header.Extra) > 32 {
+	if len(header.Extra) > 32+common.ExtraSeal {
+		// the data after the seal (e.g. QC) is signed together with the vanity
+		extra = make([]byte, 0, len(header.Extra)-common.ExtraSeal)
+		extra = append(extra, header.Extra[0:32]...)
+		extra = append(extra, header.Extra[32+common.ExtraSeal:]...)
+	} else if len(header.Extra) > 32 {
 		extra = header.Extra[0:32]
 	}
 	rlp.Encode(hasher, []interface{}{
@@ -146,6 +151,9 @@ func (h *Header) Signature() []byte {
 	if len(h.Extra) < 32 {
 		return []byte{}
 	}
+	if len(h.Extra) > 32+common.ExtraSeal {
+		return h.Extra[32 : 32+common.ExtraSeal]
+	}
 	return h.Extra[32:]
 }
 
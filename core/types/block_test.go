@@ -67,3 +67,30 @@ func TestBlockEncoding(t *testing.T) {
 		t.Errorf("encoded block mismatch:\ngot:  %x\nwant: %x", ourBlockEnc, blockEnc)
 	}
 }
+
+func TestHeaderSealHash(t *testing.T) {
+	newHeader := func(extra []byte) *Header {
+		return &Header{Number: big.NewInt(1), Time: big.NewInt(1), Extra: extra}
+	}
+	extra := make([]byte, 32+common.ExtraSeal)
+	sealed := common.CopyBytes(extra)
+	sealed[32] = 1
+	if newHeader(extra).SealHash() != newHeader(sealed).SealHash() {
+		t.Error("seal hash should not cover the seal")
+	}
+	if newHeader(extra).SealHash() != newHeader(extra[:32]).SealHash() {
+		t.Error("seal hash of the unsealed header mismatch")
+	}
+
+	qc := append(common.CopyBytes(extra), 0xc1, 0x01)
+	otherQC := append(common.CopyBytes(sealed), 0xc1, 0x02)
+	if newHeader(qc).SealHash() == newHeader(extra).SealHash() {
+		t.Error("seal hash should cover the data after the seal")
+	}
+	if newHeader(qc).SealHash() == newHeader(otherQC).SealHash() {
+		t.Error("seal hash should cover the data after the seal")
+	}
+	if !bytes.Equal(newHeader(otherQC).Signature(), sealed[32:]) {
+		t.Error("signature mismatch")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 )
 
 // emptyCodeHash is used by create to ensure deployment is disallowed to already
@@ -478,6 +479,12 @@ func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *
 // ChainConfig returns the environment's chain configuration
 func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
 
+// IsFeatureActive reports whether the feature is switched on at the current block,
+// it's activated by the version proposal instead of the fork block of the ChainConfig.
+func (evm *EVM) IsFeatureActive(feature params.Feature) bool {
+	return gov.IsFeatureActive(feature, evm.BlockNumber.Uint64(), evm.StateDB)
+}
+
 func (evm *EVM) GetStateDB() StateDB {
 	return evm.StateDB
 }
@@ -26,6 +26,7 @@ func (s StructLog) MarshalJSON() ([]byte, error) {
 		Storage     map[common.Hash]common.Hash `json:"-"`
 		Depth       int                         `json:"depth"`
 		Err         error                       `json:"-"`
+		Wasm        *WasmLog                    `json:"wasm,omitempty"`
 		OpName      string                      `json:"opName"`
 		ErrorString string                      `json:"error"`
 	}
@@ -45,6 +46,7 @@ func (s StructLog) MarshalJSON() ([]byte, error) {
 	enc.Storage = s.Storage
 	enc.Depth = s.Depth
 	enc.Err = s.Err
+	enc.Wasm = s.Wasm
 	enc.OpName = s.OpName()
 	enc.ErrorString = s.ErrorString()
 	return json.Marshal(&enc)
@@ -63,6 +65,7 @@ func (s *StructLog) UnmarshalJSON(input []byte) error {
 		Storage    map[common.Hash]common.Hash `json:"-"`
 		Depth      *int                        `json:"depth"`
 		Err        error                       `json:"-"`
+		Wasm       *WasmLog                    `json:"wasm,omitempty"`
 	}
 	var dec StructLog
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -101,5 +104,8 @@ func (s *StructLog) UnmarshalJSON(input []byte) error {
 	if dec.Err != nil {
 		s.Err = dec.Err
 	}
+	if dec.Wasm != nil {
+		s.Wasm = dec.Wasm
+	}
 	return nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 	"github.com/PlatONnetwork/PlatON-Go/x/plugin"
+	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
 )
 
 const (
@@ -21,6 +22,8 @@ const (
 	Vote                  = uint16(2003)
 	Declare               = uint16(2004)
 	SubmitCancel          = uint16(2005)
+	SubmitSpend           = uint16(2006)
+	DelegatorVote         = uint16(2007)
 	GetProposal           = uint16(2100)
 	GetResult             = uint16(2101)
 	ListProposal          = uint16(2102)
@@ -34,6 +37,12 @@ var (
 	Delimiter = []byte("")
 )
 
+// govFeatures are the features switching on the functions added after the genesis version.
+var govFeatures = map[uint16]params.Feature{
+	SubmitSpend:   gov.FeatureSpendProposal,
+	DelegatorVote: gov.FeatureDelegatorVote,
+}
+
 type GovContract struct {
 	Plugin   *plugin.GovPlugin
 	Contract *Contract
@@ -45,7 +54,7 @@ func (gc *GovContract) RequiredGas(input []byte) uint64 {
 }
 
 func (gc *GovContract) Run(input []byte) ([]byte, error) {
-	return execPlatonContract(input, gc.FnSigns())
+	return execPlatonContract(input, activeFnSigns(gc.Evm, gc.FnSigns(), govFeatures))
 }
 
 func (gc *GovContract) FnSigns() map[uint16]interface{} {
@@ -57,6 +66,8 @@ func (gc *GovContract) FnSigns() map[uint16]interface{} {
 		Declare:       gc.declareVersion,
 		SubmitCancel:  gc.submitCancel,
 		SubmitParam:   gc.submitParam,
+		SubmitSpend:   gc.submitSpend,
+		DelegatorVote: gc.delegatorVote,
 
 		// Get
 		GetProposal:           gc.getProposal,
@@ -87,6 +98,10 @@ func (gc *GovContract) CheckGasPrice(gasPrice *big.Int, fcode uint16) error {
 		if gasPrice.Cmp(params.SubmitParamProposalGasPrice) < 0 {
 			return common.InvalidParameter.Wrap("Gas price under the min gas price.")
 		}
+	case SubmitSpend:
+		if gasPrice.Cmp(params.SubmitSpendProposalGasPrice) < 0 {
+			return common.InvalidParameter.Wrap("Gas price under the min gas price.")
+		}
 	}
 
 	return nil
@@ -121,7 +136,8 @@ func (gc *GovContract) submitText(verifier discover.NodeID, pipID string) ([]byt
 		Proposer:     verifier,
 	}
 	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB)
-	return gc.nonCallHandler("submitText", SubmitText, err)
+	return gc.nonCallHandler("submitText", SubmitText, err,
+		submitProposalEvent(txHash, verifier, from, uint8(gov.Text), pipID))
 }
 
 func (gc *GovContract) submitVersion(verifier discover.NodeID, pipID string, newVersion uint32, endVotingRounds uint64) ([]byte, error) {
@@ -159,7 +175,8 @@ func (gc *GovContract) submitVersion(verifier discover.NodeID, pipID string, new
 		NewVersion:      newVersion,
 	}
 	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB)
-	return gc.nonCallHandler("submitVersion", SubmitVersion, err)
+	return gc.nonCallHandler("submitVersion", SubmitVersion, err,
+		submitProposalEvent(txHash, verifier, from, uint8(gov.Version), pipID))
 }
 
 func (gc *GovContract) submitCancel(verifier discover.NodeID, pipID string, endVotingRounds uint64, tobeCanceledProposalID common.Hash) ([]byte, error) {
@@ -196,7 +213,8 @@ func (gc *GovContract) submitCancel(verifier discover.NodeID, pipID string, endV
 		TobeCanceled:    tobeCanceledProposalID,
 	}
 	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB)
-	return gc.nonCallHandler("submitCancel", SubmitCancel, err)
+	return gc.nonCallHandler("submitCancel", SubmitCancel, err,
+		submitProposalEvent(txHash, verifier, from, uint8(gov.Cancel), pipID))
 }
 
 func (gc *GovContract) submitParam(verifier discover.NodeID, pipID string, module, name, newValue string) ([]byte, error) {
@@ -234,7 +252,49 @@ func (gc *GovContract) submitParam(verifier discover.NodeID, pipID string, modul
 		NewValue:     newValue,
 	}
 	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB)
-	return gc.nonCallHandler("submitParam", SubmitText, err)
+	return gc.nonCallHandler("submitParam", SubmitText, err,
+		submitProposalEvent(txHash, verifier, from, uint8(gov.Param), pipID))
+}
+
+func (gc *GovContract) submitSpend(verifier discover.NodeID, pipID string, source, recipient common.Address, amount *big.Int, plans []restricting.RestrictingPlan) ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.BlockNumber.Uint64()
+	blockHash := gc.Evm.BlockHash
+	txHash := gc.Evm.StateDB.TxHash()
+
+	log.Debug("call submitSpend of GovContract",
+		"from", from.Hex(),
+		"txHash", txHash,
+		"blockNumber", blockNumber,
+		"PIPID", pipID,
+		"verifierID", verifier.TerminalString(),
+		"source", source.Hex(),
+		"recipient", recipient.Hex(),
+		"amount", amount,
+		"plans", len(plans))
+
+	if !gc.Contract.UseGas(params.SubmitSpendProposalGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	p := &gov.SpendProposal{
+		PIPID:        pipID,
+		ProposalType: gov.Spend,
+		SubmitBlock:  blockNumber,
+		ProposalID:   txHash,
+		Proposer:     verifier,
+		Source:       source,
+		Recipient:    recipient,
+		Amount:       amount,
+		Plans:        plans,
+	}
+	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB)
+	return gc.nonCallHandler("submitSpend", SubmitSpend, err,
+		submitProposalEvent(txHash, verifier, from, uint8(gov.Spend), pipID))
 }
 
 func (gc *GovContract) vote(verifier discover.NodeID, proposalID common.Hash, op uint8, programVersion uint32, programVersionSign common.VersionSign) ([]byte, error) {
@@ -270,7 +330,53 @@ func (gc *GovContract) vote(verifier discover.NodeID, proposalID common.Hash, op
 
 	err := gov.Vote(from, v, blockHash, blockNumber, programVersion, programVersionSign, plugin.StakingInstance(), gc.Evm.StateDB)
 
-	return gc.nonCallHandler("vote", Vote, err)
+	return gc.nonCallHandler("vote", Vote, err,
+		voteEvent(proposalID, verifier, from, uint8(option)))
+}
+
+func (gc *GovContract) delegatorVote(proposalID common.Hash, nodeId discover.NodeID, stakingBlockNum uint64, op uint8) ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.BlockNumber.Uint64()
+	blockHash := gc.Evm.BlockHash
+	txHash := gc.Evm.StateDB.TxHash()
+
+	log.Debug("call delegatorVote of GovContract",
+		"from", from.Hex(),
+		"txHash", txHash,
+		"blockNumber", blockNumber,
+		"proposalID", proposalID,
+		"nodeId", nodeId.TerminalString(),
+		"stakingBlockNum", stakingBlockNum,
+		"option", op)
+
+	option := gov.ParseVoteOption(op)
+
+	size, err := gov.DelegatorVoteSize(proposalID, gov.DelegatorVoteValue{
+		Delegator:       from,
+		VoteNodeID:      nodeId,
+		StakingBlockNum: stakingBlockNum,
+		VoteOption:      option,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !gc.Contract.UseGas(params.DelegatorVoteGas + uint64(size)*params.DelegatorVoteByteGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	v := gov.VoteInfo{}
+	v.ProposalID = proposalID
+	v.VoteNodeID = nodeId
+	v.VoteOption = option
+
+	err = gov.DelegatorVote(from, v, stakingBlockNum, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB)
+
+	return gc.nonCallHandler("delegatorVote", DelegatorVote, err,
+		voteEvent(proposalID, nodeId, from, uint8(option)))
 }
 
 func (gc *GovContract) declareVersion(activeNode discover.NodeID, programVersion uint32, programVersionSign common.VersionSign) ([]byte, error) {
@@ -296,7 +402,8 @@ func (gc *GovContract) declareVersion(activeNode discover.NodeID, programVersion
 
 	err := gov.DeclareVersion(from, activeNode, programVersion, programVersionSign, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB)
 
-	return gc.nonCallHandler("declareVersion", Declare, err)
+	return gc.nonCallHandler("declareVersion", Declare, err,
+		declareVersionEvent(activeNode, from, programVersion))
 }
 
 func (gc *GovContract) getProposal(proposalID common.Hash) ([]byte, error) {
@@ -432,7 +539,7 @@ func (gc *GovContract) listGovernParam(module string) ([]byte, error) {
 	return gc.callHandler("listGovernParam", paramList, err)
 }
 
-func (gc *GovContract) nonCallHandler(funcName string, fcode uint16, err error) ([]byte, error) {
+func (gc *GovContract) nonCallHandler(funcName string, fcode uint16, err error, events ...*contractEvent) ([]byte, error) {
 	if err != nil {
 		if bizErr, ok := err.(*common.BizError); ok {
 			return txResultHandler(vm.GovContractAddr, gc.Evm, funcName+" of GovContract",
@@ -443,7 +550,7 @@ func (gc *GovContract) nonCallHandler(funcName string, fcode uint16, err error)
 			return nil, err
 		}
 	} else {
-		return txResultHandler(vm.GovContractAddr, gc.Evm, "", "", int(fcode), int(common.NoErr.Code)), nil
+		return txResultHandler(vm.GovContractAddr, gc.Evm, "", "", int(fcode), int(common.NoErr.Code), events...), nil
 	}
 }
 
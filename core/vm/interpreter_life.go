@@ -10,6 +10,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/lru"
 	"github.com/PlatONnetwork/PlatON-Go/life/utils"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"math/big"
 	"reflect"
@@ -21,15 +22,22 @@ import (
 )
 
 var (
-	errReturnInvalidRlpFormat = errors.New("interpreter_life: invalid rlp format.")
+	errReturnInvalidRlpFormat   = errors.New("interpreter_life: invalid rlp format.")
 	errReturnInsufficientParams = errors.New("interpreter_life: invalid input. ele must greater than 2")
-	errReturnInvalidAbi = errors.New("interpreter_life: invalid abi, encoded fail.")
+	errReturnInvalidAbi         = errors.New("interpreter_life: invalid abi, encoded fail.")
+	errUnsupportedAbiVersion    = errors.New("interpreter_life: unsupported abi version.")
+	errReturnInvalidOutput      = errors.New("interpreter_life: invalid output, must be a rlp list.")
 )
 
 const (
 	CALL_CANTRACT_FLAG = 9
 )
 
+// FeatureWasmAbiV2 switches on the abi version element of the wasm code and
+// the host functions added with ABI v2. Until it is active every contract runs
+// on ABI v1 and the new host functions are unknown imports, as on old nodes.
+var FeatureWasmAbiV2 = params.RegisterFeature("vm.wasmAbiV2", 0<<16|8<<8|0)
+
 var DEFAULT_VM_CONFIG = exec.VMConfig{
 	EnableJIT:          false,
 	DefaultMemoryPages: exec.DefaultMemoryPages,
@@ -38,12 +46,13 @@ var DEFAULT_VM_CONFIG = exec.VMConfig{
 
 // WASMInterpreter represents an WASM interpreter
 type WASMInterpreter struct {
-	evm         *EVM
-	cfg         Config
-	wasmStateDB *WasmStateDB
-	WasmLogger  log.Logger
-	resolver    exec.ImportResolver
-	returnData  []byte
+	evm            *EVM
+	cfg            Config
+	wasmStateDB    *WasmStateDB
+	WasmLogger     log.Logger
+	resolver       exec.ImportResolver
+	legacyResolver exec.ImportResolver
+	returnData     []byte
 }
 
 // NewWASMInterpreter returns a new instance of the Interpreter
@@ -55,11 +64,12 @@ func NewWASMInterpreter(evm *EVM, cfg Config) *WASMInterpreter {
 		cfg:     &cfg,
 	}
 	return &WASMInterpreter{
-		evm:         evm,
-		cfg:         cfg,
-		WasmLogger:  NewWasmLogger(cfg, log.WasmRoot()),
-		wasmStateDB: wasmStateDB,
-		resolver:    resolver.NewResolver(0x01),
+		evm:            evm,
+		cfg:            cfg,
+		WasmLogger:     NewWasmLogger(cfg, log.WasmRoot()),
+		wasmStateDB:    wasmStateDB,
+		resolver:       resolver.NewResolver(0x01),
+		legacyResolver: resolver.NewLegacyResolver(0x01),
 	}
 }
 
@@ -89,10 +99,16 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 	if len(contract.Code) == 0 {
 		return nil, nil
 	}
-	_, abi, code, er := parseRlpData(contract.Code)
+	_, abi, code, abiVersion, er := parseRlpData(contract.Code)
 	if er != nil {
 		return nil, er
 	}
+	importResolver := in.resolver
+	if !in.evm.IsFeatureActive(FeatureWasmAbiV2) {
+		abiVersion, importResolver = utils.AbiVersion1, in.legacyResolver
+	} else if abiVersion != utils.AbiVersion1 && abiVersion != utils.AbiVersion2 {
+		return nil, errUnsupportedAbiVersion
+	}
 
 	context := &exec.VMContext{
 		Config:   DEFAULT_VM_CONFIG,
@@ -101,6 +117,9 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 		StateDB:  NewWasmStateDB(in.wasmStateDB, contract),
 		Log:      in.WasmLogger,
 	}
+	if tracer, ok := in.cfg.Tracer.(WasmTracer); ok && in.cfg.Debug {
+		context.Tracer = newWasmHooks(tracer, in.evm, contract)
+	}
 
 	var lvm *exec.VirtualMachine
 	var module *lru.WasmModule
@@ -115,7 +134,7 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 		lru.WasmCache().Add(contract.Address(), module)
 	}
 
-	lvm, err = exec.NewVirtualMachineWithModule(module.Module, module.FunctionCode, context, in.resolver, nil)
+	lvm, err = exec.NewVirtualMachineWithModule(module.Module, module.FunctionCode, context, importResolver, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -129,13 +148,18 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 		txType     int
 		params     []int64
 		returnType string
+		outputs    []utils.OutputsParam
 	)
 
 	if input == nil {
 		funcName = "init" // init function.
 	} else {
 		// parse input.
-		txType, funcName, params, returnType, err = parseInputFromAbi(lvm, input, abi)
+		if abiVersion == utils.AbiVersion2 {
+			txType, funcName, params, outputs, err = parseInputFromAbiV2(lvm, input, abi)
+		} else {
+			txType, funcName, params, returnType, err = parseInputFromAbi(lvm, input, abi)
+		}
 		if err != nil {
 			if err == errReturnInsufficientParams && txType == 0 { // transfer to contract address.
 				return nil, nil
@@ -165,6 +189,11 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 		return contract.Code, nil
 	}
 
+	// the output of ABI v2 is same for the contracts and the external callers
+	if abiVersion == utils.AbiVersion2 {
+		return checkOutputFromAbiV2(context.Output, outputs)
+	}
+
 	// todo: more type need to be completed
 	switch returnType {
 	case "void", "int8", "int", "int32", "int64":
@@ -307,27 +336,99 @@ func parseInputFromAbi(vm *exec.VirtualMachine, input []byte, abi []byte) (txTyp
 	return txType, funcName, params, returnType, nil
 }
 
-// rlpData=RLP([txType][code][abi])
-func parseRlpData(rlpData []byte) (int64, []byte, []byte, error) {
+// parse input(payload) of ABI v2, the arguments are passed to the function as
+// the pointer and the length of their rlp list: [txType][funcName][args1][args2]
+func parseInputFromAbiV2(vm *exec.VirtualMachine, input []byte, abi []byte) (txType int, funcName string, params []int64, outputs []utils.OutputsParam, err error) {
+	if input == nil || len(input) <= 1 {
+		return -1, "", nil, nil, fmt.Errorf("invalid input.")
+	}
+	var iRlpList []interface{}
+	if err := rlp.DecodeBytes(input, &iRlpList); err != nil {
+		return -1, "", nil, nil, errReturnInvalidRlpFormat
+	}
+	if len(iRlpList) < 2 {
+		if len(iRlpList) != 0 {
+			if v, ok := iRlpList[0].([]byte); ok {
+				txType = int(common.BytesToInt64(v))
+			}
+		} else {
+			txType = -1
+		}
+		return txType, "", nil, nil, errReturnInsufficientParams
+	}
+	if v, ok := iRlpList[0].([]byte); ok {
+		txType = int(common.BytesToInt64(v))
+	}
+	if v, ok := iRlpList[1].([]byte); ok {
+		funcName = string(v)
+	}
+	if txType == 0 {
+		return txType, funcName, nil, nil, nil
+	}
+
+	wasmabi := new(utils.WasmAbi)
+	if err := wasmabi.FromJson(abi); err != nil {
+		return -1, "", nil, nil, errReturnInvalidAbi
+	}
+	var fn *utils.AbiStruct
+	for i, v := range wasmabi.AbiArr {
+		if strings.EqualFold(funcName, v.Name) && strings.EqualFold(v.Type, "function") {
+			fn = &wasmabi.AbiArr[i]
+			break
+		}
+	}
+	if fn == nil {
+		return -1, "", nil, nil, fmt.Errorf("function %s not found in abi.", funcName)
+	}
+	args := iRlpList[2:]
+	if err := utils.CheckValues(fn.Inputs, args); err != nil {
+		return -1, "", nil, nil, fmt.Errorf("invalid input: %v", err)
+	}
+	data, err := rlp.EncodeToBytes(args)
+	if err != nil {
+		return -1, "", nil, nil, err
+	}
+	pos := resolver.MallocBytes(vm, data)
+	return txType, funcName, []int64{pos, int64(len(data))}, fn.Outputs, nil
+}
+
+// checkOutputFromAbiV2 checks the rlp list returned by platonReturn against the outputs of the function.
+func checkOutputFromAbiV2(output []byte, outputs []utils.OutputsParam) ([]byte, error) {
+	if len(output) == 0 && len(outputs) == 0 {
+		return nil, nil
+	}
+	var values []interface{}
+	if err := rlp.DecodeBytes(output, &values); err != nil {
+		return nil, errReturnInvalidOutput
+	}
+	if err := utils.CheckValues(outputs, values); err != nil {
+		return nil, fmt.Errorf("invalid output: %v", err)
+	}
+	return output, nil
+}
+
+// rlpData=RLP([txType][code][abi][abiVersion]), the contracts without abiVersion are ABI v1
+func parseRlpData(rlpData []byte) (int64, []byte, []byte, uint64, error) {
 	ptr := new(interface{})
 	err := rlp.Decode(bytes.NewReader(rlpData), &ptr)
 	if err != nil {
-		return -1, nil, nil, err
+		return -1, nil, nil, 0, err
 	}
 	rlpList := reflect.ValueOf(ptr).Elem().Interface()
 
 	if _, ok := rlpList.([]interface{}); !ok {
-		return -1, nil, nil, fmt.Errorf("invalid rlp format.")
+		return -1, nil, nil, 0, fmt.Errorf("invalid rlp format.")
 	}
 
 	iRlpList := rlpList.([]interface{})
 	if len(iRlpList) <= 2 {
-		return -1, nil, nil, fmt.Errorf("invalid input. ele must greater than 2")
+		return -1, nil, nil, 0, fmt.Errorf("invalid input. ele must greater than 2")
 	}
 	var (
-		txType int64
-		code   []byte
-		abi    []byte
+		txType     int64
+		code       []byte
+		abi        []byte
+		abiVersion uint64 = utils.AbiVersion1
 	)
 	if v, ok := iRlpList[0].([]byte); ok {
 		txType = utils.BytesToInt64(v)
@@ -340,7 +441,12 @@ func parseRlpData(rlpData []byte) (int64, []byte, []byte, error) {
 		abi = v
 		//fmt.Println("dstAbi:", common.Bytes2Hex(abi))
 	}
-	return txType, abi, code, nil
+	if len(iRlpList) > 3 {
+		if v, ok := iRlpList[3].([]byte); ok {
+			abiVersion = new(big.Int).SetBytes(v).Uint64()
+		}
+	}
+	return txType, abi, code, abiVersion, nil
 }
 
 func stack() string {
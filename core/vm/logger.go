@@ -68,6 +68,7 @@ type StructLog struct {
 	Storage    map[common.Hash]common.Hash `json:"-"`
 	Depth      int                         `json:"depth"`
 	Err        error                       `json:"-"`
+	Wasm       *WasmLog                    `json:"wasm,omitempty"`
 }
 
 // overrides for gencodec
@@ -82,6 +83,9 @@ type structLogMarshaling struct {
 
 // OpName formats the operand name in a human-readable format.
 func (s *StructLog) OpName() string {
+	if s.Wasm != nil {
+		return s.Wasm.Op
+	}
 	return s.Op.String()
 }
 
@@ -115,6 +119,7 @@ type StructLogger struct {
 
 	logs          []StructLog
 	changedValues map[common.Address]Storage
+	wasmCost      uint64 // The gas of the WASM instructions since the last log
 	output        []byte
 	err           error
 }
@@ -179,7 +184,7 @@ func (l *StructLogger) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost ui
 		storage = l.changedValues[contract.Address()].Copy()
 	}
 	// create a new snaptshot of the EVM.
-	log := StructLog{pc, op, gas, cost, mem, memory.Len(), stck, storage, depth, err}
+	log := StructLog{pc, op, gas, cost, mem, memory.Len(), stck, storage, depth, err, nil}
 
 	l.logs = append(l.logs, log)
 	return nil
@@ -216,7 +221,7 @@ func (l *StructLogger) Output() []byte { return l.output }
 // WriteTrace writes a formatted trace to the given writer
 func WriteTrace(writer io.Writer, logs []StructLog) {
 	for _, log := range logs {
-		fmt.Fprintf(writer, "%-16spc=%08d gas=%v cost=%v", log.Op, log.Pc, log.Gas, log.GasCost)
+		fmt.Fprintf(writer, "%-16spc=%08d gas=%v cost=%v", log.OpName(), log.Pc, log.Gas, log.GasCost)
 		if log.Err != nil {
 			fmt.Fprintf(writer, " ERROR: %v", log.Err)
 		}
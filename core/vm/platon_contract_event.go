@@ -0,0 +1,280 @@
+package vm
+
+import (
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
+	"github.com/PlatONnetwork/PlatON-Go/x/reward"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+// The typed event logs of the PPOS system contracts.
+//
+// Every succeeded state-changing call of the staking, gov, restricting and slashing contracts
+// emits two logs. The first one is the receipt log, as before:
+//
+//	Topics: [Keccak256(fnCode)]
+//	Data:   RLP([errCode])
+//
+// The second one is the event log, it follows the receipt log:
+//
+//	Topics: [Keccak256(EventName), indexed fields...]
+//	Data:   RLP([data fields...])
+//
+// The indexed fields are 32 bytes, left padded with zeros:
+//
+//	node:       the node address of the nodeId, see xutil.NodeId2Addr
+//	address:    the account address
+//	proposalId: the proposal ID
+//	actionId:   the ID of the pending staking action
+//
+// e.g. all the delegations to a node can be subscribed by `platon_getLogs` with the filter:
+//
+//	{"address": StakingContractAddr, "topics": [Keccak256("Delegate"), node]}
+//
+// The events are:
+//
+//	CreateStaking       indexed: [node, stakingAddr]      data: [nodeId, benefitAddr, stakingBlockNum, typ, amount, rewardPer]
+//	EditCandidate       indexed: [node, stakingAddr]      data: [nodeId, benefitAddr, rewardPer]
+//	IncreaseStaking     indexed: [node, stakingAddr]      data: [nodeId, stakingBlockNum, typ, amount]
+//	WithdrewStaking     indexed: [node, stakingAddr]      data: [nodeId, stakingBlockNum]
+//	Delegate            indexed: [node, delAddr]          data: [nodeId, stakingBlockNum, typ, amount]
+//	WithdrewDelegate    indexed: [node, delAddr]          data: [nodeId, stakingBlockNum, released, restrictingPlan]
+//	WithdrewDelReward   indexed: [delAddr]                data: [[nodeId, stakingBlockNum, reward]...]
+//	Redelegate          indexed: [node, delAddr, dstNode] data: [nodeId, stakingBlockNum, dstNodeId, dstStakingBlockNum, amount]
+//	Unjail              indexed: [node, stakingAddr]      data: [nodeId, stakingBlockNum]
+//	SetController       indexed: [node, stakingAddr]      data: [nodeId, operators, threshold]
+//	ActionPending       indexed: [node, actionId]         data: [nodeId, fnType, proposer, approvals, threshold]
+//	ActionCancelled     indexed: [node, actionId]         data: [nodeId, proposer]
+//	SubmitProposal      indexed: [proposalId, node]       data: [nodeId, proposer, proposalType, pipId]
+//	Vote                indexed: [proposalId, node]       data: [nodeId, voter, option]
+//	DeclareVersion      indexed: [node]                   data: [nodeId, declarer, programVersion]
+//	CreateRestricting   indexed: [from, account]          data: [[epoch, amount]...]
+//	RevokeRestricting   indexed: [from, account]          data: [amount]
+//	TransferRestricting indexed: [account, to]            data: [amount]
+//	ReportDuplicateSign indexed: [node, reporter]         data: [nodeId, dupType, evidenceBlockNum]
+//
+// The released and restrictingPlan of WithdrewDelegate are the von really refunded to the balance and to
+// the restricting plan, they may sum up to the whole delegation when the remain is less than the operating threshold.
+// The staking operation of the controlled candidate emits ActionPending until enough operators approve it,
+// then the receipt and the event are the ones of the operation, and the stakingAddr is still the staking address.
+const (
+	EventCreateStaking       = "CreateStaking"
+	EventEditCandidate       = "EditCandidate"
+	EventIncreaseStaking     = "IncreaseStaking"
+	EventWithdrewStaking     = "WithdrewStaking"
+	EventDelegate            = "Delegate"
+	EventWithdrewDelegate    = "WithdrewDelegate"
+	EventWithdrewDelReward   = "WithdrewDelReward"
+	EventRedelegate          = "Redelegate"
+	EventUnjail              = "Unjail"
+	EventSetController       = "SetController"
+	EventActionPending       = "ActionPending"
+	EventActionCancelled     = "ActionCancelled"
+	EventSubmitProposal      = "SubmitProposal"
+	EventVote                = "Vote"
+	EventDeclareVersion      = "DeclareVersion"
+	EventCreateRestricting   = "CreateRestricting"
+	EventRevokeRestricting   = "RevokeRestricting"
+	EventTransferRestricting = "TransferRestricting"
+	EventReportDuplicateSign = "ReportDuplicateSign"
+)
+
+// contractEvent is the typed event log which is emitted by txResultHandler of the succeeded call
+type contractEvent struct {
+	name    string
+	indexed []common.Hash
+	data    []interface{}
+}
+
+func (e *contractEvent) addLog(evm *EVM, contractAddr common.Address) {
+	xcom.AddEventLog(evm.StateDB, evm.BlockNumber.Uint64(), contractAddr, e.name, e.indexed, e.data)
+}
+
+func nodeTopic(nodeId discover.NodeID) common.Hash {
+	addr, _ := xutil.NodeId2Addr(nodeId)
+	return common.BytesToHash(addr.Bytes())
+}
+
+func addrTopic(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+func createStakingEvent(nodeId discover.NodeID, stakingAddr, benefitAddr common.Address, stakingBlockNum uint64,
+	typ uint16, amount *big.Int, rewardPer uint16) *contractEvent {
+	return &contractEvent{
+		name:    EventCreateStaking,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(stakingAddr)},
+		data:    []interface{}{nodeId, benefitAddr, stakingBlockNum, typ, amount, rewardPer},
+	}
+}
+
+func editCandidateEvent(nodeId discover.NodeID, stakingAddr, benefitAddr common.Address, rewardPer uint16) *contractEvent {
+	return &contractEvent{
+		name:    EventEditCandidate,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(stakingAddr)},
+		data:    []interface{}{nodeId, benefitAddr, rewardPer},
+	}
+}
+
+func increaseStakingEvent(nodeId discover.NodeID, stakingAddr common.Address, stakingBlockNum uint64,
+	typ uint16, amount *big.Int) *contractEvent {
+	return &contractEvent{
+		name:    EventIncreaseStaking,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(stakingAddr)},
+		data:    []interface{}{nodeId, stakingBlockNum, typ, amount},
+	}
+}
+
+func withdrewStakingEvent(nodeId discover.NodeID, stakingAddr common.Address, stakingBlockNum uint64) *contractEvent {
+	return &contractEvent{
+		name:    EventWithdrewStaking,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(stakingAddr)},
+		data:    []interface{}{nodeId, stakingBlockNum},
+	}
+}
+
+func delegateEvent(nodeId discover.NodeID, delAddr common.Address, stakingBlockNum uint64,
+	typ uint16, amount *big.Int) *contractEvent {
+	return &contractEvent{
+		name:    EventDelegate,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(delAddr)},
+		data:    []interface{}{nodeId, stakingBlockNum, typ, amount},
+	}
+}
+
+func withdrewDelegateEvent(nodeId discover.NodeID, delAddr common.Address, stakingBlockNum uint64,
+	released, restrictingPlan *big.Int) *contractEvent {
+	return &contractEvent{
+		name:    EventWithdrewDelegate,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(delAddr)},
+		data:    []interface{}{nodeId, stakingBlockNum, released, restrictingPlan},
+	}
+}
+
+// the reward item of WithdrewDelReward event
+type delegateRewardItem struct {
+	NodeId          discover.NodeID
+	StakingBlockNum uint64
+	Reward          *big.Int
+}
+
+func withdrewDelRewardEvent(delAddr common.Address, rewards reward.DelegateRewardQueue) *contractEvent {
+	items := make([]*delegateRewardItem, len(rewards))
+	for i, r := range rewards {
+		items[i] = &delegateRewardItem{
+			NodeId:          r.NodeId,
+			StakingBlockNum: r.StakingBlockNum,
+			Reward:          (*big.Int)(r.Reward),
+		}
+	}
+	return &contractEvent{
+		name:    EventWithdrewDelReward,
+		indexed: []common.Hash{addrTopic(delAddr)},
+		data:    []interface{}{items},
+	}
+}
+
+func redelegateEvent(nodeId discover.NodeID, delAddr common.Address, stakingBlockNum uint64,
+	dstNodeId discover.NodeID, dstStakingBlockNum uint64, amount *big.Int) *contractEvent {
+	return &contractEvent{
+		name:    EventRedelegate,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(delAddr), nodeTopic(dstNodeId)},
+		data:    []interface{}{nodeId, stakingBlockNum, dstNodeId, dstStakingBlockNum, amount},
+	}
+}
+
+func unjailEvent(nodeId discover.NodeID, stakingAddr common.Address, stakingBlockNum uint64) *contractEvent {
+	return &contractEvent{
+		name:    EventUnjail,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(stakingAddr)},
+		data:    []interface{}{nodeId, stakingBlockNum},
+	}
+}
+
+func setStakingControllerEvent(nodeId discover.NodeID, stakingAddr common.Address, operators []common.Address,
+	threshold uint32) *contractEvent {
+	return &contractEvent{
+		name:    EventSetController,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(stakingAddr)},
+		data:    []interface{}{nodeId, operators, threshold},
+	}
+}
+
+func stakingActionPendingEvent(nodeId discover.NodeID, action *staking.PendingAction, threshold uint32) *contractEvent {
+	return &contractEvent{
+		name:    EventActionPending,
+		indexed: []common.Hash{nodeTopic(nodeId), action.ActionId},
+		data:    []interface{}{nodeId, action.FuncType, action.Proposer, uint32(len(action.Approvals)), threshold},
+	}
+}
+
+func stakingActionCancelledEvent(nodeId discover.NodeID, actionId common.Hash, proposer common.Address) *contractEvent {
+	return &contractEvent{
+		name:    EventActionCancelled,
+		indexed: []common.Hash{nodeTopic(nodeId), actionId},
+		data:    []interface{}{nodeId, proposer},
+	}
+}
+
+func submitProposalEvent(proposalID common.Hash, nodeId discover.NodeID, proposer common.Address,
+	proposalType uint8, pipID string) *contractEvent {
+	return &contractEvent{
+		name:    EventSubmitProposal,
+		indexed: []common.Hash{proposalID, nodeTopic(nodeId)},
+		data:    []interface{}{nodeId, proposer, proposalType, pipID},
+	}
+}
+
+func voteEvent(proposalID common.Hash, nodeId discover.NodeID, voter common.Address, option uint8) *contractEvent {
+	return &contractEvent{
+		name:    EventVote,
+		indexed: []common.Hash{proposalID, nodeTopic(nodeId)},
+		data:    []interface{}{nodeId, voter, option},
+	}
+}
+
+func declareVersionEvent(nodeId discover.NodeID, declarer common.Address, programVersion uint32) *contractEvent {
+	return &contractEvent{
+		name:    EventDeclareVersion,
+		indexed: []common.Hash{nodeTopic(nodeId)},
+		data:    []interface{}{nodeId, declarer, programVersion},
+	}
+}
+
+func createRestrictingEvent(from, account common.Address, plans []restricting.RestrictingPlan) *contractEvent {
+	return &contractEvent{
+		name:    EventCreateRestricting,
+		indexed: []common.Hash{addrTopic(from), addrTopic(account)},
+		data:    []interface{}{plans},
+	}
+}
+
+func revokeRestrictingEvent(from, account common.Address, amount *big.Int) *contractEvent {
+	return &contractEvent{
+		name:    EventRevokeRestricting,
+		indexed: []common.Hash{addrTopic(from), addrTopic(account)},
+		data:    []interface{}{amount},
+	}
+}
+
+func transferRestrictingEvent(account, to common.Address, amount *big.Int) *contractEvent {
+	return &contractEvent{
+		name:    EventTransferRestricting,
+		indexed: []common.Hash{addrTopic(account), addrTopic(to)},
+		data:    []interface{}{amount},
+	}
+}
+
+func reportDuplicateSignEvent(nodeId discover.NodeID, reporter common.Address, dupType uint8,
+	evidenceBlockNum uint64) *contractEvent {
+	return &contractEvent{
+		name:    EventReportDuplicateSign,
+		indexed: []common.Hash{nodeTopic(nodeId), addrTopic(reporter)},
+		data:    []interface{}{nodeId, dupType, evidenceBlockNum},
+	}
+}
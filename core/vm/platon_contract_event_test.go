@@ -0,0 +1,67 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+func TestTxResultHandler_Event(t *testing.T) {
+	state, _, err := newChainState()
+	defer func() {
+		snapshotdb.Instance().Clear()
+	}()
+	if nil != err {
+		t.Fatal(err)
+	}
+	evm := newEvm(blockNumber, blockHash, state)
+
+	nodeId := nodeIdArr[0]
+	delAddr := addrArr[1]
+	amount := big.NewInt(1000)
+
+	// the failed call only has the receipt log
+	txResultHandler(vm.StakingContractAddr, evm, "delegate", "failed", TxDelegate,
+		int(staking.ErrDelegateVonTooLow.Code), delegateEvent(nodeId, delAddr, 1, 0, amount))
+	assert.Equal(t, 1, len(state.Logs))
+
+	state.Logs = nil
+	txResultHandler(vm.StakingContractAddr, evm, "", "", TxDelegate,
+		int(common.NoErr.Code), delegateEvent(nodeId, delAddr, 1, 0, amount))
+	if !assert.Equal(t, 2, len(state.Logs)) {
+		return
+	}
+
+	// the event log follows the receipt log
+	eventLog := state.Logs[1]
+	nodeAddr, _ := xutil.NodeId2Addr(nodeId)
+	assert.Equal(t, vm.StakingContractAddr, eventLog.Address)
+	assert.Equal(t, []common.Hash{
+		common.BytesToHash(crypto.Keccak256([]byte(EventDelegate))),
+		common.BytesToHash(nodeAddr.Bytes()),
+		common.BytesToHash(delAddr.Bytes()),
+	}, eventLog.Topics)
+
+	var data struct {
+		NodeId          discover.NodeID
+		StakingBlockNum uint64
+		Typ             uint16
+		Amount          *big.Int
+	}
+	if err := rlp.DecodeBytes(eventLog.Data, &data); nil != err {
+		t.Fatal(err)
+	}
+	assert.Equal(t, nodeId, data.NodeId)
+	assert.Equal(t, uint64(1), data.StakingBlockNum)
+	assert.Equal(t, amount, data.Amount)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/x/plugin"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 )
@@ -27,13 +28,29 @@ func execPlatonContract(input []byte, command map[uint16]interface{}) (ret []byt
 	return result[0].Bytes(), nil
 }
 
-func txResultHandler(contractAddr common.Address, evm *EVM, title, reason string, fncode, errCode int) []byte {
+// activeFnSigns removes the functions whose features are not active at the current block,
+// the calls to them fail like the calls to an unknown function on the nodes before the features.
+func activeFnSigns(evm *EVM, fnSigns map[uint16]interface{}, features map[uint16]params.Feature) map[uint16]interface{} {
+	for fcode, feature := range features {
+		if !evm.IsFeatureActive(feature) {
+			delete(fnSigns, fcode)
+		}
+	}
+	return fnSigns
+}
+
+// txResultHandler adds the receipt log of the call,
+// and the typed event logs after it if the call is succeeded.
+func txResultHandler(contractAddr common.Address, evm *EVM, title, reason string, fncode, errCode int, events ...*contractEvent) []byte {
 	event := strconv.Itoa(fncode)
 	receipt := strconv.Itoa(errCode)
 
 	if errCode == 0 {
 		blockNumber := evm.BlockNumber.Uint64()
 		xcom.AddLog(evm.StateDB, blockNumber, contractAddr, event, receipt)
+		for _, e := range events {
+			e.addLog(evm, contractAddr)
+		}
 	} else {
 		txHash := evm.StateDB.TxHash()
 		blockNumber := evm.BlockNumber.Uint64()
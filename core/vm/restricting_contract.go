@@ -13,10 +13,18 @@ import (
 )
 
 const (
-	TxCreateRestrictingPlan = 4000
-	QueryRestrictingInfo    = 4100
+	TxCreateRestrictingPlan   = 4000
+	TxRevokeRestrictingPlan   = 4001
+	TxTransferRestrictingPlan = 4002
+	QueryRestrictingInfo      = 4100
 )
 
+// restrictingFeatures are the features switching on the functions added after the genesis version.
+var restrictingFeatures = map[uint16]params.Feature{
+	TxRevokeRestrictingPlan:   plugin.FeatureRestrictingRevoke,
+	TxTransferRestrictingPlan: plugin.FeatureRestrictingTransfer,
+}
+
 type RestrictingContract struct {
 	Plugin   *plugin.RestrictingPlugin
 	Contract *Contract
@@ -28,13 +36,15 @@ func (rc *RestrictingContract) RequiredGas(input []byte) uint64 {
 }
 
 func (rc *RestrictingContract) Run(input []byte) ([]byte, error) {
-	return execPlatonContract(input, rc.FnSigns())
+	return execPlatonContract(input, activeFnSigns(rc.Evm, rc.FnSigns(), restrictingFeatures))
 }
 
 func (rc *RestrictingContract) FnSigns() map[uint16]interface{} {
 	return map[uint16]interface{}{
 		// Set
-		TxCreateRestrictingPlan: rc.createRestrictingPlan,
+		TxCreateRestrictingPlan:   rc.createRestrictingPlan,
+		TxRevokeRestrictingPlan:   rc.revokeRestrictingPlan,
+		TxTransferRestrictingPlan: rc.transferRestrictingPlan,
 
 		// Get
 		QueryRestrictingInfo: rc.getRestrictingInfo,
@@ -72,7 +82,8 @@ func (rc *RestrictingContract) createRestrictingPlan(account common.Address, pla
 	switch err.(type) {
 	case nil:
 		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "",
-			"", TxCreateRestrictingPlan, int(common.NoErr.Code)), nil
+			"", TxCreateRestrictingPlan, int(common.NoErr.Code),
+			createRestrictingEvent(from, account, plans)), nil
 	case *common.BizError:
 		bizErr := err.(*common.BizError)
 		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "createRestrictingPlan",
@@ -84,6 +95,89 @@ func (rc *RestrictingContract) createRestrictingPlan(account common.Address, pla
 	}
 }
 
+// revokeRestrictingPlan is a PlatON precompiled contract function, used for revoking the unreleased
+// restricting plans created by the sender for the account, the unpledged funds are returned to the sender.
+func (rc *RestrictingContract) revokeRestrictingPlan(account common.Address) ([]byte, error) {
+
+	from := rc.Contract.CallerAddress
+	txHash := rc.Evm.StateDB.TxHash()
+	blockNum := rc.Evm.BlockNumber
+	blockHash := rc.Evm.BlockHash
+	state := rc.Evm.StateDB
+
+	log.Debug("Call revokeRestrictingPlan of RestrictingContract", "blockNumber", blockNum.Uint64(),
+		"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "from", from.String(), "account", account.String())
+
+	if !rc.Contract.UseGas(params.RevokeRestrictingPlanGas) {
+		return nil, ErrOutOfGas
+	}
+	epochs, funders := rc.Plugin.GetRestrictingPlanSize(account, state)
+	if !rc.Contract.UseGas(params.ReleasePlanGas*uint64(epochs) + params.RestrictingFunderGas*uint64(funders)) {
+		return nil, ErrOutOfGas
+	}
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	amount, err := rc.Plugin.RevokeRestrictingRecord(from, account, state)
+	switch err.(type) {
+	case nil:
+		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "",
+			"", TxRevokeRestrictingPlan, int(common.NoErr.Code),
+			revokeRestrictingEvent(from, account, amount)), nil
+	case *common.BizError:
+		bizErr := err.(*common.BizError)
+		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "revokeRestrictingPlan",
+			bizErr.Error(), TxRevokeRestrictingPlan, int(bizErr.Code)), nil
+	default:
+		log.Error("Failed to cal RevokeRestrictingRecord on revokeRestrictingPlan", "blockNumber", blockNum.Uint64(),
+			"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "error", err)
+		return nil, err
+	}
+}
+
+// transferRestrictingPlan is a PlatON precompiled contract function, used for transferring the unreleased
+// restricting plans of the sender to a new account.
+func (rc *RestrictingContract) transferRestrictingPlan(to common.Address) ([]byte, error) {
+
+	from := rc.Contract.CallerAddress
+	txHash := rc.Evm.StateDB.TxHash()
+	blockNum := rc.Evm.BlockNumber
+	blockHash := rc.Evm.BlockHash
+	state := rc.Evm.StateDB
+
+	log.Debug("Call transferRestrictingPlan of RestrictingContract", "blockNumber", blockNum.Uint64(),
+		"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "from", from.String(), "to", to.String())
+
+	if !rc.Contract.UseGas(params.TransferRestrictingPlanGas) {
+		return nil, ErrOutOfGas
+	}
+	// the amounts of every funder are moved at each epoch
+	epochs, funders := rc.Plugin.GetRestrictingPlanSize(from, state)
+	if !rc.Contract.UseGas(params.ReleasePlanGas*uint64(epochs) + params.RestrictingFunderGas*uint64(epochs*funders)) {
+		return nil, ErrOutOfGas
+	}
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	amount, err := rc.Plugin.TransferRestrictingRecord(from, to, state)
+	switch err.(type) {
+	case nil:
+		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "",
+			"", TxTransferRestrictingPlan, int(common.NoErr.Code),
+			transferRestrictingEvent(from, to, amount)), nil
+	case *common.BizError:
+		bizErr := err.(*common.BizError)
+		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "transferRestrictingPlan",
+			bizErr.Error(), TxTransferRestrictingPlan, int(bizErr.Code)), nil
+	default:
+		log.Error("Failed to cal TransferRestrictingRecord on transferRestrictingPlan", "blockNumber", blockNum.Uint64(),
+			"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "error", err)
+		return nil, err
+	}
+}
+
 // createRestrictingPlan is a PlatON precompiled contract function, used for getting restricting info.
 // first output param is a slice of byte of restricting info;
 // the secend output param is the result what plugin executed GetRestrictingInfo returns.
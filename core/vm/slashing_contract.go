@@ -85,7 +85,8 @@ func (sc *SlashingContract) reportDuplicateSign(dupType uint8, data string) ([]b
 		}
 	}
 	return txResultHandler(vm.SlashingContractAddr, sc.Evm, "",
-		"", TxReportDuplicateSign, int(common.NoErr.Code)), nil
+		"", TxReportDuplicateSign, int(common.NoErr.Code),
+		reportDuplicateSignEvent(evidence.NodeID(), from, dupType, evidence.BlockNumber())), nil
 }
 
 // Check if the node has double sign behavior at a certain block height
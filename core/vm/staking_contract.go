@@ -29,12 +29,21 @@ const (
 	TxWithdrewCandidate = 1003
 	TxDelegate          = 1004
 	TxWithdrewDelegate  = 1005
+	TxWithdrewDelReward = 1006
+	TxRedelegate        = 1007
+	TxUnjail            = 1008
+	TxSetController     = 1009
+	TxApproveAction     = 1010
+	TxCancelAction      = 1011
 	QueryVerifierList   = 1100
 	QueryValidatorList  = 1101
 	QueryCandidateList  = 1102
 	QueryRelateList     = 1103
 	QueryDelegateInfo   = 1104
 	QueryCandidateInfo  = 1105
+	QueryDelegateReward = 1106
+	QueryController     = 1107
+	QueryPendingActions = 1108
 )
 
 const (
@@ -46,6 +55,32 @@ type StakingContract struct {
 	Plugin   *plugin.StakingPlugin
 	Contract *Contract
 	Evm      *EVM
+
+	// the input of the call, it's kept as the pending action of the controlled candidate
+	input []byte
+	// the call is executing the pending action approved by the operators
+	approved bool
+	// the approved action of the call, it's removed from the pending actions after the execution
+	action *approvedAction
+}
+
+// stakingFeatures are the features switching on the functions added after the genesis version.
+var stakingFeatures = map[uint16]params.Feature{
+	TxWithdrewDelReward: plugin.FeatureDelegateReward,
+	TxRedelegate:        plugin.FeatureRedelegate,
+	TxUnjail:            plugin.FeatureJail,
+	TxSetController:     plugin.FeatureStakingController,
+	TxApproveAction:     plugin.FeatureStakingController,
+	TxCancelAction:      plugin.FeatureStakingController,
+	QueryDelegateReward: plugin.FeatureDelegateReward,
+	QueryController:     plugin.FeatureStakingController,
+	QueryPendingActions: plugin.FeatureStakingController,
+}
+
+type approvedAction struct {
+	canAddr         common.Address
+	stakingBlockNum uint64
+	actionId        common.Hash
 }
 
 func (stkc *StakingContract) RequiredGas(input []byte) uint64 {
@@ -53,7 +88,19 @@ func (stkc *StakingContract) RequiredGas(input []byte) uint64 {
 }
 
 func (stkc *StakingContract) Run(input []byte) ([]byte, error) {
-	return execPlatonContract(input, stkc.FnSigns())
+	stkc.input = input
+	ret, err := execPlatonContract(input, activeFnSigns(stkc.Evm, stkc.FnSigns(), stakingFeatures))
+	if nil != err || nil == stkc.action {
+		return ret, err
+	}
+	// the executed action is dropped even if it's failed, so it can't be executed again by any single operator
+	action := stkc.action
+	if err := stkc.Plugin.RemoveStakingAction(stkc.Evm.BlockHash, stkc.Evm.BlockNumber, action.canAddr, action.stakingBlockNum, action.actionId); nil != err {
+		log.Error("Failed to remove the executed staking action", "txHash", stkc.Evm.StateDB.TxHash(),
+			"blockNumber", stkc.Evm.BlockNumber, "canAddr", action.canAddr.Hex(), "actionId", action.actionId.Hex(), "err", err)
+		return nil, err
+	}
+	return ret, nil
 }
 
 func (stkc *StakingContract) CheckGasPrice(gasPrice *big.Int, fcode uint16) error {
@@ -61,6 +108,22 @@ func (stkc *StakingContract) CheckGasPrice(gasPrice *big.Int, fcode uint16) erro
 }
 
 func (stkc *StakingContract) FnSigns() map[uint16]interface{} {
+	if nil != stkc.Evm && !stkc.Evm.IsFeatureActive(plugin.FeatureDelegateReward) {
+		return stkc.FnSignsV0()
+	}
+	return stkc.fnSigns()
+}
+
+// FnSignsV0 returns the methods signs before the delegate reward,
+// the params of createStaking and editCandidate have no commission rate.
+func (stkc *StakingContract) FnSignsV0() map[uint16]interface{} {
+	fnSigns := stkc.fnSigns()
+	fnSigns[TxCreateStaking] = stkc.createStakingV0
+	fnSigns[TxEditorCandidate] = stkc.editCandidateV0
+	return fnSigns
+}
+
+func (stkc *StakingContract) fnSigns() map[uint16]interface{} {
 	return map[uint16]interface{}{
 		// Set
 		TxCreateStaking:     stkc.createStaking,
@@ -69,20 +132,37 @@ func (stkc *StakingContract) FnSigns() map[uint16]interface{} {
 		TxWithdrewCandidate: stkc.withdrewStaking,
 		TxDelegate:          stkc.delegate,
 		TxWithdrewDelegate:  stkc.withdrewDelegate,
+		TxWithdrewDelReward: stkc.withdrewDelegateReward,
+		TxRedelegate:        stkc.redelegate,
+		TxUnjail:            stkc.unjail,
+		TxSetController:     stkc.setStakingController,
+		TxApproveAction:     stkc.approveStakingAction,
+		TxCancelAction:      stkc.cancelStakingAction,
 
 		// Get
-		QueryVerifierList:  stkc.getVerifierList,
-		QueryValidatorList: stkc.getValidatorList,
-		QueryCandidateList: stkc.getCandidateList,
-		QueryRelateList:    stkc.getRelatedListByDelAddr,
-		QueryDelegateInfo:  stkc.getDelegateInfo,
-		QueryCandidateInfo: stkc.getCandidateInfo,
+		QueryVerifierList:   stkc.getVerifierList,
+		QueryValidatorList:  stkc.getValidatorList,
+		QueryCandidateList:  stkc.getCandidateList,
+		QueryRelateList:     stkc.getRelatedListByDelAddr,
+		QueryDelegateInfo:   stkc.getDelegateInfo,
+		QueryCandidateInfo:  stkc.getCandidateInfo,
+		QueryDelegateReward: stkc.getDelegateReward,
+		QueryController:     stkc.getStakingController,
+		QueryPendingActions: stkc.getPendingActions,
 	}
 }
 
-func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Address, nodeId discover.NodeID,
+// createStakingV0 is the createStaking before the delegate reward, the candidate keeps all the rewards
+func (stkc *StakingContract) createStakingV0(typ uint16, benefitAddress common.Address, nodeId discover.NodeID,
 	externalId, nodeName, website, details string, amount *big.Int, programVersion uint32,
 	programVersionSign common.VersionSign, blsPubKey bls.PublicKeyHex, blsProof bls.SchnorrProofHex) ([]byte, error) {
+	return stkc.createStaking(typ, benefitAddress, nodeId, externalId, nodeName, website, details, amount,
+		programVersion, programVersionSign, blsPubKey, blsProof, 0)
+}
+
+func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Address, nodeId discover.NodeID,
+	externalId, nodeName, website, details string, amount *big.Int, programVersion uint32,
+	programVersionSign common.VersionSign, blsPubKey bls.PublicKeyHex, blsProof bls.SchnorrProofHex, rewardPer uint16) ([]byte, error) {
 
 	txHash := stkc.Evm.StateDB.TxHash()
 	txIndex := stkc.Evm.StateDB.TxIdx()
@@ -95,7 +175,7 @@ func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Add
 		"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "typ", typ,
 		"benefitAddress", benefitAddress.String(), "nodeId", nodeId.String(), "externalId", externalId,
 		"nodeName", nodeName, "website", website, "details", details, "amount", amount,
-		"programVersion", programVersion, "programVersionSign", programVersionSign.Hex(),
+		"rewardPer", rewardPer, "programVersion", programVersion, "programVersionSign", programVersionSign.Hex(),
 		"from", from.Hex(), "blsPubKey", blsPubKey, "blsProof", blsProof)
 
 	if !stkc.Contract.UseGas(params.CreateStakeGas) {
@@ -147,6 +227,12 @@ func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Add
 			TxCreateStaking, int(staking.ErrStakeVonTooLow.Code)), nil
 	}
 
+	if rewardPer > staking.MaxRewardPer {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "createStaking",
+			fmt.Sprintf("got rewardPer: %d, max rewardPer: %d", rewardPer, staking.MaxRewardPer),
+			TxCreateStaking, int(staking.ErrWrongRewardPer.Code)), nil
+	}
+
 	// check Description length
 	desc := &staking.Description{
 		NodeName:   nodeName,
@@ -211,6 +297,7 @@ func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Add
 		StakingBlockNum: blockNumber.Uint64(),
 		StakingTxIndex:  txIndex,
 		ProgramVersion:  currVersion,
+		RewardPer:       rewardPer,
 		Description:     *desc,
 	}
 
@@ -220,8 +307,15 @@ func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Add
 		ReleasedHes:        new(big.Int).SetInt64(0),
 		RestrictingPlan:    new(big.Int).SetInt64(0),
 		RestrictingPlanHes: new(big.Int).SetInt64(0),
+		DelegateTotal:      new(big.Int).SetInt64(0),
+		DelegateTotalHes:   new(big.Int).SetInt64(0),
 	}
 
+	// stored in the layout before the delegate reward until it's activated
+	legacy := !stkc.Evm.IsFeatureActive(plugin.FeatureDelegateReward)
+	canBase.SetLegacy(legacy)
+	canMutable.SetLegacy(legacy)
+
 	can := &staking.Candidate{}
 	can.CandidateBase = canBase
 	can.CandidateMutable = canMutable
@@ -262,7 +356,8 @@ func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Add
 	}
 
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
-		"", TxCreateStaking, int(common.NoErr.Code)), nil
+		"", TxCreateStaking, int(common.NoErr.Code),
+		createStakingEvent(nodeId, from, benefitAddress, blockNumber.Uint64(), typ, amount, rewardPer)), nil
 }
 
 func verifyBlsProof(proofHex bls.SchnorrProofHex, pubKey *bls.PublicKey) error {
@@ -282,8 +377,14 @@ func verifyBlsProof(proofHex bls.SchnorrProofHex, pubKey *bls.PublicKey) error {
 	return proof.VerifySchnorrNIZK(*pubKey)
 }
 
-func (stkc *StakingContract) editCandidate(benefitAddress common.Address, nodeId discover.NodeID,
+// editCandidateV0 is the editCandidate before the delegate reward, there is no commission rate to edit
+func (stkc *StakingContract) editCandidateV0(benefitAddress common.Address, nodeId discover.NodeID,
 	externalId, nodeName, website, details string) ([]byte, error) {
+	return stkc.editCandidate(benefitAddress, nodeId, externalId, nodeName, website, details, 0)
+}
+
+func (stkc *StakingContract) editCandidate(benefitAddress common.Address, nodeId discover.NodeID,
+	externalId, nodeName, website, details string, rewardPer uint16) ([]byte, error) {
 
 	txHash := stkc.Evm.StateDB.TxHash()
 	blockNumber := stkc.Evm.BlockNumber
@@ -292,7 +393,7 @@ func (stkc *StakingContract) editCandidate(benefitAddress common.Address, nodeId
 
 	log.Debug("Call editCandidate of stakingContract", "txHash", txHash.Hex(),
 		"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(),
-		"benefitAddress", benefitAddress.String(), "nodeId", nodeId.String(),
+		"benefitAddress", benefitAddress.String(), "nodeId", nodeId.String(), "rewardPer", rewardPer,
 		"externalId", externalId, "nodeName", nodeName, "website", website,
 		"details", details, "from", from.Hex())
 
@@ -329,16 +430,24 @@ func (stkc *StakingContract) editCandidate(benefitAddress common.Address, nodeId
 			TxEditorCandidate, int(staking.ErrCanStatusInvalid.Code)), nil
 	}
 
-	if from != canOld.StakingAddress {
-		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "editCandidate",
-			fmt.Sprintf("contract sender: %s, can stake addr: %s", from.Hex(), canOld.StakingAddress.Hex()),
-			TxEditorCandidate, int(staking.ErrNoSameStakingAddr.Code)), nil
+	if ret, err := stkc.checkStakingAuth("editCandidate", TxEditorCandidate, nodeId, canAddr, canOld); nil != ret || nil != err {
+		return ret, err
 	}
 
 	if canOld.BenefitAddress != vm.RewardManagerPoolAddr {
 		canOld.BenefitAddress = benefitAddress
 	}
 
+	if stkc.Evm.IsFeatureActive(plugin.FeatureDelegateReward) {
+		if rewardPer > staking.MaxRewardPer {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "editCandidate",
+				fmt.Sprintf("got rewardPer: %d, max rewardPer: %d", rewardPer, staking.MaxRewardPer),
+				TxEditorCandidate, int(staking.ErrWrongRewardPer.Code)), nil
+		}
+		// the rate delegators have seen is kept until the end of the epoch
+		canOld.SetNextRewardPer(rewardPer, xutil.CalculateEpoch(blockNumber.Uint64()))
+	}
+
 	// check Description length
 	desc := &staking.Description{
 		NodeName:   nodeName,
@@ -368,7 +477,8 @@ func (stkc *StakingContract) editCandidate(benefitAddress common.Address, nodeId
 	}
 
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
-		"", TxEditorCandidate, int(common.NoErr.Code)), nil
+		"", TxEditorCandidate, int(common.NoErr.Code),
+		editCandidateEvent(nodeId, canOld.StakingAddress, canOld.BenefitAddress, rewardPer)), nil
 }
 
 func (stkc *StakingContract) increaseStaking(nodeId discover.NodeID, typ uint16, amount *big.Int) ([]byte, error) {
@@ -422,10 +532,8 @@ func (stkc *StakingContract) increaseStaking(nodeId discover.NodeID, typ uint16,
 			TxIncreaseStaking, int(staking.ErrCanStatusInvalid.Code)), nil
 	}
 
-	if from != canOld.StakingAddress {
-		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "increaseStaking",
-			fmt.Sprintf("contract sender: %s, can stake addr: %s", from.Hex(), canOld.StakingAddress.Hex()),
-			TxIncreaseStaking, int(staking.ErrNoSameStakingAddr.Code)), nil
+	if ret, err := stkc.checkStakingAuth("increaseStaking", TxIncreaseStaking, nodeId, canAddr, canOld); nil != ret || nil != err {
+		return ret, err
 	}
 
 	err = stkc.Plugin.IncreaseStaking(state, blockHash, blockNumber, amount, typ, canAddr, canOld)
@@ -443,7 +551,8 @@ func (stkc *StakingContract) increaseStaking(nodeId discover.NodeID, typ uint16,
 
 	}
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
-		"", TxIncreaseStaking, int(common.NoErr.Code)), nil
+		"", TxIncreaseStaking, int(common.NoErr.Code),
+		increaseStakingEvent(nodeId, canOld.StakingAddress, canOld.StakingBlockNum, typ, amount)), nil
 }
 
 func (stkc *StakingContract) withdrewStaking(nodeId discover.NodeID) ([]byte, error) {
@@ -484,16 +593,15 @@ func (stkc *StakingContract) withdrewStaking(nodeId discover.NodeID) ([]byte, er
 			"can is nil", TxWithdrewCandidate, int(staking.ErrCanNoExist.Code)), nil
 	}
 
-	if canOld.IsInvalid() {
+	// the jailed candidate is allowed to withdrew
+	if canOld.IsInvalid() && !canOld.IsJailed() {
 		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "withdrewStaking",
 			fmt.Sprintf("can status is: %d", canOld.Status),
 			TxWithdrewCandidate, int(staking.ErrCanStatusInvalid.Code)), nil
 	}
 
-	if from != canOld.StakingAddress {
-		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "withdrewStaking",
-			fmt.Sprintf("contract sender: %s, can stake addr: %s", from.Hex(), canOld.StakingAddress.Hex()),
-			TxWithdrewCandidate, int(staking.ErrNoSameStakingAddr.Code)), nil
+	if ret, err := stkc.checkStakingAuth("withdrewStaking", TxWithdrewCandidate, nodeId, canAddr, canOld); nil != ret || nil != err {
+		return ret, err
 	}
 
 	err = stkc.Plugin.WithdrewStaking(state, blockHash, blockNumber, canAddr, canOld)
@@ -510,7 +618,8 @@ func (stkc *StakingContract) withdrewStaking(nodeId discover.NodeID) ([]byte, er
 	}
 
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
-		"", TxWithdrewCandidate, int(common.NoErr.Code)), nil
+		"", TxWithdrewCandidate, int(common.NoErr.Code),
+		withdrewStakingEvent(nodeId, canOld.StakingAddress, canOld.StakingBlockNum)), nil
 }
 
 func (stkc *StakingContract) delegate(typ uint16, nodeId discover.NodeID, amount *big.Int) ([]byte, error) {
@@ -598,6 +707,10 @@ func (stkc *StakingContract) delegate(typ uint16, nodeId discover.NodeID, amount
 		del.RestrictingPlan = new(big.Int).SetInt64(0)
 		del.ReleasedHes = new(big.Int).SetInt64(0)
 		del.RestrictingPlanHes = new(big.Int).SetInt64(0)
+		del.CumulativeIncome = new(big.Int).SetInt64(0)
+		del.RewardPerUnit = new(big.Int).SetInt64(0)
+		// stored in the layout before the delegate reward until it's activated
+		del.SetLegacy(!stkc.Evm.IsFeatureActive(plugin.FeatureDelegateReward))
 	}
 	can := &staking.Candidate{}
 	can.CandidateBase = canBase
@@ -615,7 +728,8 @@ func (stkc *StakingContract) delegate(typ uint16, nodeId discover.NodeID, amount
 	}
 
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
-		"", TxDelegate, int(common.NoErr.Code)), nil
+		"", TxDelegate, int(common.NoErr.Code),
+		delegateEvent(nodeId, from, canBase.StakingBlockNum, typ, amount)), nil
 }
 
 func (stkc *StakingContract) withdrewDelegate(stakingBlockNum uint64, nodeId discover.NodeID, amount *big.Int) ([]byte, error) {
@@ -658,7 +772,7 @@ func (stkc *StakingContract) withdrewDelegate(stakingBlockNum uint64, nodeId dis
 			"del is nil", TxWithdrewDelegate, int(staking.ErrDelegateNoExist.Code)), nil
 	}
 
-	err = stkc.Plugin.WithdrewDelegate(state, blockHash, blockNumber, amount, from, nodeId, stakingBlockNum, del)
+	released, restrictingPlan, err := stkc.Plugin.WithdrewDelegate(state, blockHash, blockNumber, amount, from, nodeId, stakingBlockNum, del)
 	if nil != err {
 		if bizErr, ok := err.(*common.BizError); ok {
 
@@ -672,7 +786,526 @@ func (stkc *StakingContract) withdrewDelegate(stakingBlockNum uint64, nodeId dis
 	}
 
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
-		"", TxWithdrewDelegate, int(common.NoErr.Code)), nil
+		"", TxWithdrewDelegate, int(common.NoErr.Code),
+		withdrewDelegateEvent(nodeId, from, stakingBlockNum, released, restrictingPlan)), nil
+}
+
+func (stkc *StakingContract) withdrewDelegateReward() ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+	state := stkc.Evm.StateDB
+
+	log.Debug("Call withdrewDelegateReward of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "delAddr", from.Hex())
+
+	// every delegation of the delegator is settled, so the gas grows with them
+	relatedList, err := stkc.Plugin.GetRelatedListByDelAddr(blockHash, from)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to withdrewDelegateReward by GetRelatedListByDelAddr", "txHash", txHash,
+			"blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	gas := params.WithdrewDelRewardGas + uint64(len(relatedList))*params.WithdrewDelRewardItemGas
+	if !stkc.Contract.UseGas(gas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	rewards, err := stkc.Plugin.WithdrewDelegateReward(state, blockHash, blockNumber, from)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "withdrewDelegateReward",
+				bizErr.Error(), TxWithdrewDelReward, int(bizErr.Code)), nil
+
+		} else {
+			log.Error("Failed to withdrewDelegateReward by WithdrewDelegateReward", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxWithdrewDelReward, int(common.NoErr.Code),
+		withdrewDelRewardEvent(from, rewards)), nil
+}
+
+func (stkc *StakingContract) redelegate(stakingBlockNum uint64, nodeId, dstNodeId discover.NodeID, amount *big.Int) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+	state := stkc.Evm.StateDB
+
+	log.Debug("Call redelegate of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "delAddr", from.Hex(), "nodeId", nodeId.String(),
+		"stakingNum", stakingBlockNum, "dstNodeId", dstNodeId.String(), "amount", amount)
+
+	if !stkc.Contract.UseGas(params.RedelegateGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	if ok, threshold := plugin.CheckOperatingThreshold(blockNumber.Uint64(), blockHash, amount); !ok {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("redelegate threshold: %d, deposit: %d", threshold, amount),
+			TxRedelegate, int(staking.ErrDelegateVonTooLow.Code)), nil
+	}
+
+	// check account
+	hasStake, err := stkc.Plugin.HasStake(blockHash, from)
+	if nil != err {
+		return nil, err
+	}
+
+	if hasStake {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("'%s' has staking, so don't allow to delegate", from.Hex()),
+			TxRedelegate, int(staking.ErrAccountNoAllowToDelegate.Code)), nil
+	}
+
+	if nodeId == dstNodeId {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			"the source and target nodeId are the same", TxRedelegate, int(staking.ErrRedelegateSameNode.Code)), nil
+	}
+
+	srcDel, err := stkc.Plugin.GetDelegateInfo(blockHash, from, nodeId, stakingBlockNum)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to redelegate by GetDelegateInfo of source",
+			"txHash", txHash.Hex(), "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	if srcDel.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			"del is nil", TxRedelegate, int(staking.ErrDelegateNoExist.Code)), nil
+	}
+
+	dstCanAddr, err := xutil.NodeId2Addr(dstNodeId)
+	if nil != err {
+		log.Error("Failed to redelegate by parse nodeId", "txHash", txHash, "blockNumber",
+			blockNumber, "blockHash", blockHash.Hex(), "dstNodeId", dstNodeId.String(), "err", err)
+		return nil, err
+	}
+
+	dstCanMutable, err := stkc.Plugin.GetCanMutable(blockHash, dstCanAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to redelegate by GetCanMutable of target", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	if dstCanMutable.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			"can is nil", TxRedelegate, int(staking.ErrCanNoExist.Code)), nil
+	}
+
+	if dstCanMutable.IsInvalid() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("can status is: %d", dstCanMutable.Status),
+			TxRedelegate, int(staking.ErrCanStatusInvalid.Code)), nil
+	}
+
+	dstCanBase, err := stkc.Plugin.GetCanBase(blockHash, dstCanAddr)
+	if nil != err {
+		log.Error("Failed to redelegate by GetCanBase of target", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	// If the candidate’s benefitaAddress is the RewardManagerPoolAddr, no delegation is allowed
+	if dstCanBase.BenefitAddress == vm.RewardManagerPoolAddr {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			"the can benefitAddr is reward addr",
+			TxRedelegate, int(staking.ErrCanNoAllowDelegate.Code)), nil
+	}
+
+	dstDel, err := stkc.Plugin.GetDelegateInfo(blockHash, from, dstNodeId, dstCanBase.StakingBlockNum)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to redelegate by GetDelegateInfo of target", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	if dstDel.IsEmpty() {
+		// build delegate
+		dstDel = new(staking.Delegation)
+		// Prevent null pointer initialization
+		dstDel.Released = new(big.Int).SetInt64(0)
+		dstDel.RestrictingPlan = new(big.Int).SetInt64(0)
+		dstDel.ReleasedHes = new(big.Int).SetInt64(0)
+		dstDel.RestrictingPlanHes = new(big.Int).SetInt64(0)
+		dstDel.CumulativeIncome = new(big.Int).SetInt64(0)
+		dstDel.RewardPerUnit = new(big.Int).SetInt64(0)
+	}
+	dstCan := &staking.Candidate{}
+	dstCan.CandidateBase = dstCanBase
+	dstCan.CandidateMutable = dstCanMutable
+
+	moved, err := stkc.Plugin.Redelegate(state, blockHash, blockNumber, amount, from, nodeId, stakingBlockNum, srcDel,
+		dstCanAddr, dstCan, dstDel)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+				bizErr.Error(), TxRedelegate, int(bizErr.Code)), nil
+
+		} else {
+			log.Error("Failed to redelegate by Redelegate", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxRedelegate, int(common.NoErr.Code),
+		redelegateEvent(nodeId, from, stakingBlockNum, dstNodeId, dstCanBase.StakingBlockNum, moved)), nil
+}
+
+func (stkc *StakingContract) unjail(nodeId discover.NodeID) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	log.Debug("Call unjail of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "nodeId", nodeId.String(), "from", from.Hex())
+
+	if !stkc.Contract.UseGas(params.UnjailGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		log.Error("Failed to unjail by parse nodeId", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	canOld, err := stkc.Plugin.GetCandidateInfo(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to unjail by GetCandidateInfo", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if canOld.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "unjail",
+			"can is nil", TxUnjail, int(staking.ErrCanNoExist.Code)), nil
+	}
+
+	if ret, err := stkc.checkStakingAuth("unjail", TxUnjail, nodeId, canAddr, canOld); nil != ret || nil != err {
+		return ret, err
+	}
+
+	if !canOld.IsJailed() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "unjail",
+			fmt.Sprintf("can status is: %d", canOld.Status),
+			TxUnjail, int(staking.ErrCanNotJailed.Code)), nil
+	}
+
+	err = stkc.Plugin.Unjail(blockHash, blockNumber, canAddr, canOld)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "unjail",
+				bizErr.Error(), TxUnjail, int(bizErr.Code)), nil
+		} else {
+			log.Error("Failed to unjail by Unjail", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxUnjail, int(common.NoErr.Code),
+		unjailEvent(nodeId, canOld.StakingAddress, canOld.StakingBlockNum)), nil
+}
+
+// checkStakingAuth checks the sender of the staking operation of the candidate. Without the controller,
+// the sender must be the staking address. Otherwise the sender must be one of the operators, the operation
+// is pending until enough operators approve it, by calling it with the same input or by approveStakingAction.
+// The approved operation is executed at once and dropped from the pending ones, whether it is succeeded or not.
+// The result is not nil if the operation should not go on.
+func (stkc *StakingContract) checkStakingAuth(title string, fnType uint16, nodeId discover.NodeID,
+	canAddr common.Address, can *staking.Candidate) ([]byte, error) {
+
+	// the operation has been approved by the operators
+	if stkc.approved {
+		return nil, nil
+	}
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	controller, err := stkc.Plugin.GetStakingController(blockHash, canAddr, can.StakingBlockNum)
+	if nil != err {
+		log.Error("Failed to "+title+" by GetStakingController", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if nil == controller {
+		if from != can.StakingAddress {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, title,
+				fmt.Sprintf("contract sender: %s, can stake addr: %s", from.Hex(), can.StakingAddress.Hex()),
+				int(fnType), int(staking.ErrNoSameStakingAddr.Code)), nil
+		}
+		return nil, nil
+	}
+
+	if !controller.IsOperator(from) {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, title,
+			fmt.Sprintf("contract sender: %s is not the operator", from.Hex()),
+			int(fnType), int(staking.ErrNotStakingOperator.Code)), nil
+	}
+
+	action, approved, err := stkc.Plugin.SubmitStakingAction(blockHash, blockNumber, canAddr, controller, fnType, stkc.input, from)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, title,
+				bizErr.Error(), int(fnType), int(bizErr.Code)), nil
+		} else {
+			log.Error("Failed to "+title+" by SubmitStakingAction", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+	if approved {
+		stkc.action = &approvedAction{canAddr: canAddr, stakingBlockNum: controller.StakingBlockNum, actionId: action.ActionId}
+		return nil, nil
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", int(fnType), int(common.NoErr.Code),
+		stakingActionPendingEvent(nodeId, action, controller.Threshold)), nil
+}
+
+func (stkc *StakingContract) setStakingController(nodeId discover.NodeID, operators []common.Address, threshold uint32) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	log.Debug("Call setStakingController of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "nodeId", nodeId.String(), "operators", operators,
+		"threshold", threshold, "from", from.Hex())
+
+	if !stkc.Contract.UseGas(params.SetControllerGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		log.Error("Failed to setStakingController by parse nodeId", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	canOld, err := stkc.Plugin.GetCandidateInfo(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to setStakingController by GetCandidateInfo", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if canOld.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "setStakingController",
+			"can is nil", TxSetController, int(staking.ErrCanNoExist.Code)), nil
+	}
+
+	if canOld.IsInvalid() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "setStakingController",
+			fmt.Sprintf("can status is: %d", canOld.Status),
+			TxSetController, int(staking.ErrCanStatusInvalid.Code)), nil
+	}
+
+	if ret, err := stkc.checkStakingAuth("setStakingController", TxSetController, nodeId, canAddr, canOld); nil != ret || nil != err {
+		return ret, err
+	}
+
+	err = stkc.Plugin.SetStakingController(blockHash, blockNumber, canAddr, canOld, operators, threshold)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "setStakingController",
+				bizErr.Error(), TxSetController, int(bizErr.Code)), nil
+		} else {
+			log.Error("Failed to setStakingController by SetStakingController", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxSetController, int(common.NoErr.Code),
+		setStakingControllerEvent(nodeId, canOld.StakingAddress, operators, threshold)), nil
+}
+
+func (stkc *StakingContract) approveStakingAction(nodeId discover.NodeID, actionId common.Hash) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	log.Debug("Call approveStakingAction of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "nodeId", nodeId.String(), "actionId", actionId.Hex(), "from", from.Hex())
+
+	if !stkc.Contract.UseGas(params.ApproveActionGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		log.Error("Failed to approveStakingAction by parse nodeId", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	canOld, err := stkc.Plugin.GetCandidateInfo(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to approveStakingAction by GetCandidateInfo", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if canOld.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "approveStakingAction",
+			"can is nil", TxApproveAction, int(staking.ErrCanNoExist.Code)), nil
+	}
+
+	controller, err := stkc.Plugin.GetStakingController(blockHash, canAddr, canOld.StakingBlockNum)
+	if nil != err {
+		log.Error("Failed to approveStakingAction by GetStakingController", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if nil == controller {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "approveStakingAction",
+			"the candidate is not controlled", TxApproveAction, int(staking.ErrPendingActionNoExist.Code)), nil
+	}
+
+	if !controller.IsOperator(from) {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "approveStakingAction",
+			fmt.Sprintf("contract sender: %s is not the operator", from.Hex()),
+			TxApproveAction, int(staking.ErrNotStakingOperator.Code)), nil
+	}
+
+	action, approved, err := stkc.Plugin.ApproveStakingAction(blockHash, blockNumber, canAddr, controller, actionId, from)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "approveStakingAction",
+				bizErr.Error(), TxApproveAction, int(bizErr.Code)), nil
+		} else {
+			log.Error("Failed to approveStakingAction by ApproveStakingAction", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
+	if !approved {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+			"", TxApproveAction, int(common.NoErr.Code),
+			stakingActionPendingEvent(nodeId, action, controller.Threshold)), nil
+	}
+
+	// execute the approved action, the receipt is the one of the action
+	stkc.action = &approvedAction{canAddr: canAddr, stakingBlockNum: controller.StakingBlockNum, actionId: action.ActionId}
+	input := stkc.input
+	stkc.input, stkc.approved = action.Input, true
+	defer func() {
+		stkc.input, stkc.approved = input, false
+	}()
+	return execPlatonContract(action.Input, activeFnSigns(stkc.Evm, stkc.FnSigns(), stakingFeatures))
+}
+
+func (stkc *StakingContract) cancelStakingAction(nodeId discover.NodeID, actionId common.Hash) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	log.Debug("Call cancelStakingAction of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "nodeId", nodeId.String(), "actionId", actionId.Hex(), "from", from.Hex())
+
+	if !stkc.Contract.UseGas(params.CancelActionGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		log.Error("Failed to cancelStakingAction by parse nodeId", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	canOld, err := stkc.Plugin.GetCandidateInfo(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to cancelStakingAction by GetCandidateInfo", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if canOld.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "cancelStakingAction",
+			"can is nil", TxCancelAction, int(staking.ErrCanNoExist.Code)), nil
+	}
+
+	controller, err := stkc.Plugin.GetStakingController(blockHash, canAddr, canOld.StakingBlockNum)
+	if nil != err {
+		log.Error("Failed to cancelStakingAction by GetStakingController", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if nil == controller {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "cancelStakingAction",
+			"the candidate is not controlled", TxCancelAction, int(staking.ErrPendingActionNoExist.Code)), nil
+	}
+
+	err = stkc.Plugin.CancelStakingAction(blockHash, blockNumber, canAddr, controller, actionId, from)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "cancelStakingAction",
+				bizErr.Error(), TxCancelAction, int(bizErr.Code)), nil
+		} else {
+			log.Error("Failed to cancelStakingAction by CancelStakingAction", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxCancelAction, int(common.NoErr.Code),
+		stakingActionCancelledEvent(nodeId, actionId, from)), nil
 }
 
 func (stkc *StakingContract) getVerifierList() ([]byte, error) {
@@ -803,3 +1436,82 @@ func (stkc *StakingContract) getCandidateInfo(nodeId discover.NodeID) ([]byte, e
 	return callResultHandler(stkc.Evm, fmt.Sprintf("getCandidateInfo, nodeId: %s",
 		nodeId), can, nil), nil
 }
+
+func (stkc *StakingContract) getStakingController(nodeId discover.NodeID) ([]byte, error) {
+
+	blockHash := stkc.Evm.BlockHash
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getStakingController, nodeId: %s",
+			nodeId), nil, staking.ErrQueryStakingController.Wrap(err.Error())), nil
+	}
+	can, err := stkc.Plugin.GetCanBase(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getStakingController, nodeId: %s",
+			nodeId), nil, staking.ErrQueryStakingController.Wrap(err.Error())), nil
+	}
+	if snapshotdb.IsDbNotFoundErr(err) || can.IsEmpty() {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getStakingController, nodeId: %s",
+			nodeId), nil, staking.ErrQueryStakingController.Wrap("Candidate info is not found")), nil
+	}
+
+	controller, err := stkc.Plugin.GetStakingController(blockHash, canAddr, can.StakingBlockNum)
+	if nil != err {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getStakingController, nodeId: %s",
+			nodeId), nil, staking.ErrQueryStakingController.Wrap(err.Error())), nil
+	}
+
+	return callResultHandler(stkc.Evm, fmt.Sprintf("getStakingController, nodeId: %s",
+		nodeId), controller, nil), nil
+}
+
+func (stkc *StakingContract) getPendingActions(nodeId discover.NodeID) ([]byte, error) {
+
+	blockHash := stkc.Evm.BlockHash
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getPendingActions, nodeId: %s",
+			nodeId), nil, staking.ErrQueryPendingActions.Wrap(err.Error())), nil
+	}
+	can, err := stkc.Plugin.GetCanBase(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getPendingActions, nodeId: %s",
+			nodeId), nil, staking.ErrQueryPendingActions.Wrap(err.Error())), nil
+	}
+	if snapshotdb.IsDbNotFoundErr(err) || can.IsEmpty() {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getPendingActions, nodeId: %s",
+			nodeId), nil, staking.ErrQueryPendingActions.Wrap("Candidate info is not found")), nil
+	}
+
+	actions, err := stkc.Plugin.ListPendingActions(blockHash, stkc.Evm.BlockNumber, canAddr, can.StakingBlockNum)
+	if nil != err {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getPendingActions, nodeId: %s",
+			nodeId), nil, staking.ErrQueryPendingActions.Wrap(err.Error())), nil
+	}
+
+	return callResultHandler(stkc.Evm, fmt.Sprintf("getPendingActions, nodeId: %s",
+		nodeId), actions, nil), nil
+}
+
+func (stkc *StakingContract) getDelegateReward(delAddr common.Address, nodeIds []discover.NodeID) ([]byte, error) {
+
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	state := stkc.Evm.StateDB
+
+	rewards, err := stkc.Plugin.GetDelegateRewardList(state, blockHash, blockNumber.Uint64(), delAddr, nodeIds)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getDelegateReward, delAddr: %s, nodeIds: %s",
+			delAddr, nodeIds), rewards, staking.ErrQueryDelegateReward.Wrap(err.Error())), nil
+	}
+
+	if snapshotdb.IsDbNotFoundErr(err) || len(rewards) == 0 {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getDelegateReward, delAddr: %s, nodeIds: %s",
+			delAddr, nodeIds), rewards, staking.ErrQueryDelegateReward.Wrap("Delegate reward is not found")), nil
+	}
+
+	return callResultHandler(stkc.Evm, fmt.Sprintf("getDelegateReward, delAddr: %s, nodeIds: %s",
+		delAddr, nodeIds), rewards, nil), nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	_ "fmt"
 	"math/big"
+	"strconv"
 	"testing"
 
 	"github.com/PlatONnetwork/PlatON-Go/node"
@@ -18,9 +19,12 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 	"github.com/PlatONnetwork/PlatON-Go/x/plugin"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
 )
 
 func runContractSendTransaction(contract *StakingContract, params [][]byte, title string, t *testing.T) {
@@ -85,6 +89,7 @@ func create_staking(blockNumber *big.Int, blockHash common.Hash, state *mock.Moc
 	details, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 	StakeThreshold, _ := new(big.Int).SetString(balanceStr[index], 10) // equal or more than "1000000000000000000000000"
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -122,6 +127,7 @@ func create_staking(blockNumber *big.Int, blockHash common.Hash, state *mock.Moc
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	runContractSendTransaction(contract, params, "createStaking", t)
 
@@ -231,6 +237,7 @@ func TestStakingContract_editCandidate(t *testing.T) {
 
 	benefitAddress, _ := rlp.EncodeToBytes(addrArr[0])
 	nodeId, _ := rlp.EncodeToBytes(nodeIdArr[index])
+	rewardPer, _ := rlp.EncodeToBytes(uint16(2000))
 	externalId, _ := rlp.EncodeToBytes("I am Xu !?")
 	nodeName, _ := rlp.EncodeToBytes("Xu, China")
 	website, _ := rlp.EncodeToBytes("https://www.Xu.net")
@@ -243,6 +250,7 @@ func TestStakingContract_editCandidate(t *testing.T) {
 	params = append(params, nodeName)
 	params = append(params, website)
 	params = append(params, details)
+	params = append(params, rewardPer)
 
 	runContractSendTransaction(contract2, params, "editCandidate", t)
 
@@ -527,6 +535,104 @@ func TestStakingContract_withdrewDelegate(t *testing.T) {
 	getCandidate(contract2, index, t)
 }
 
+func TestStakingContract_approveStakingAction(t *testing.T) {
+
+	state, genesis, _ := newChainState()
+	newPlugins()
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+
+	index := 1
+
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+	state.Prepare(txHashArr[0], blockHash, 0)
+	contract := create_staking(blockNumber, blockHash, state, index, t)
+
+	canAddr, _ := xutil.NodeId2Addr(nodeIdArr[index])
+	can, err := plugin.StakingInstance().GetCandidateInfo(blockHash, canAddr)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if err := plugin.StakingInstance().SetStakingController(blockHash, blockNumber, canAddr, can, []common.Address{sender}, 1); nil != err {
+		t.Errorf("Failed to SetStakingController: %v", err)
+		return
+	}
+
+	// the approved action is failed, because the von of the operator is not enough
+	state.Prepare(txHashArr[1], blockHash, 1)
+	amount := new(big.Int).Add(state.GetBalance(sender), common.Big1)
+	fnType, _ := rlp.EncodeToBytes(uint16(TxIncreaseStaking))
+	nodeId, _ := rlp.EncodeToBytes(nodeIdArr[index])
+	typ, _ := rlp.EncodeToBytes(uint16(0))
+	amountBytes, _ := rlp.EncodeToBytes(amount)
+	input, _ := rlp.EncodeToBytes([][]byte{fnType, nodeId, typ, amountBytes})
+
+	res, err := contract.Run(input)
+	assert.Nil(t, err)
+	assert.NotEqual(t, strconv.Itoa(int(common.OkCode)), string(res))
+
+	// the failed action is dropped, so it can't be executed again by any single operator
+	actions, err := plugin.StakingInstance().ListPendingActions(blockHash, blockNumber, canAddr, can.StakingBlockNum)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actions))
+
+	// the operator submits the action again, and it's removed after it is succeeded
+	state.AddBalance(sender, amount)
+	state.Prepare(txHashArr[2], blockHash, 2)
+	contract = &StakingContract{
+		Plugin:   plugin.StakingInstance(),
+		Contract: newContract(common.Big0, sender),
+		Evm:      newEvm(blockNumber, blockHash, state),
+	}
+	runContractSendTransaction(contract, [][]byte{fnType, nodeId, typ, amountBytes}, "increaseStaking", t)
+
+	actions, err = plugin.StakingInstance().ListPendingActions(blockHash, blockNumber, canAddr, can.StakingBlockNum)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actions))
+}
+
+func TestStakingContract_featureGated(t *testing.T) {
+	state, genesis, _ := newChainState()
+	newPlugins()
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+	state.Prepare(txHashArr[0], blockHash, 0)
+
+	// the chain is still at the genesis version
+	gov.AddActiveVersion(params.GenesisVersion, 0, state)
+	contract := &StakingContract{
+		Plugin:   plugin.StakingInstance(),
+		Contract: newContract(common.Big0, sender),
+		Evm:      &EVM{StateDB: state, Context: Context{BlockNumber: blockNumber, BlockHash: blockHash}},
+	}
+	fnType, _ := rlp.EncodeToBytes(uint16(TxUnjail))
+	nodeId, _ := rlp.EncodeToBytes(nodeIdArr[0])
+	input, _ := rlp.EncodeToBytes([][]byte{fnType, nodeId})
+
+	_, err := contract.Run(input)
+	assert.Equal(t, plugin.FuncNotExistErr, err)
+
+	// the function is known once the feature is activated
+	version, _ := params.FeatureVersion(plugin.FeatureJail)
+	gov.AddActiveVersion(version, blockNumber.Uint64(), state)
+	res, err := contract.Run(input)
+	assert.Nil(t, err)
+	assert.NotEqual(t, strconv.Itoa(int(common.OkCode)), string(res))
+}
+
 func TestStakingContract_getVerifierList(t *testing.T) {
 
 	state, genesis, _ := newChainState()
@@ -68,6 +68,7 @@ func Test_CreateStake_HighThreshold_by_freeVon(t *testing.T) {
 	details, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 	StakeThreshold, _ := new(big.Int).SetString(balanceStr[index], 10) // equal or more than "1000000000000000000000000"
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -105,6 +106,7 @@ func Test_CreateStake_HighThreshold_by_freeVon(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	runContractSendTransaction(contract, params, "createStaking", t)
 
@@ -151,6 +153,7 @@ func Test_CreateStake_HighThreshold_by_restrictplanVon(t *testing.T) {
 	details, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 	StakeThreshold, _ := new(big.Int).SetString(balanceStr[index], 10) // equal or more than "1000000000000000000000000"
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -188,6 +191,7 @@ func Test_CreateStake_HighThreshold_by_restrictplanVon(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	runContractSendTransaction(contract, params, "createStaking", t)
 
@@ -230,6 +234,7 @@ func Test_CreateStake_RightVersion(t *testing.T) {
 	details, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 	StakeThreshold, _ := new(big.Int).SetString(balanceStr[index], 10) // equal or more than "1000000000000000000000000"
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -267,6 +272,7 @@ func Test_CreateStake_RightVersion(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	runContractSendTransaction(contract, params, "createStaking", t)
 }
@@ -311,6 +317,7 @@ func Test_CreateStake_RepeatStake(t *testing.T) {
 	details, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 	StakeThreshold, _ := new(big.Int).SetString(balanceStr[index], 10) // equal or more than "1000000000000000000000000"
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -348,6 +355,7 @@ func Test_CreateStake_RepeatStake(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	runContractSendTransaction(contract, params, "createStaking", t)
 
@@ -365,6 +373,7 @@ func Test_CreateStake_RepeatStake(t *testing.T) {
 	details2, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 	StakeThreshold2, _ := new(big.Int).SetString(balanceStr[index], 10) // equal or more than "1000000000000000000000000"
 	amount2, _ := rlp.EncodeToBytes(StakeThreshold2)
+	rewardPer2, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion2, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -402,6 +411,7 @@ func Test_CreateStake_RepeatStake(t *testing.T) {
 	args = append(args, sign2)
 	args = append(args, blsPkm2)
 	args = append(args, proofRlp2)
+	args = append(args, rewardPer2)
 
 	buf2 := new(bytes.Buffer)
 	err := rlp.Encode(buf2, args)
@@ -468,6 +478,7 @@ func Test_CreateStake_LowBalance_by_freeVon(t *testing.T) {
 	details, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -505,6 +516,7 @@ func Test_CreateStake_LowBalance_by_freeVon(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	buf := new(bytes.Buffer)
 	err := rlp.Encode(buf, params)
@@ -565,6 +577,7 @@ func Test_CreateStake_LowThreshold_by_freeVon(t *testing.T) {
 	StakeThreshold := new(big.Int).Sub(xcom.StakeThreshold(), common.Big1) // equal or more than "1000000000000000000000000"
 
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -602,6 +615,7 @@ func Test_CreateStake_LowThreshold_by_freeVon(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	buf := new(bytes.Buffer)
 	err := rlp.Encode(buf, params)
@@ -666,6 +680,7 @@ func Test_CreateStake_LowBalance_by_restrictplanVon(t *testing.T) {
 	details, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -703,6 +718,7 @@ func Test_CreateStake_LowBalance_by_restrictplanVon(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	buf := new(bytes.Buffer)
 	err := rlp.Encode(buf, params)
@@ -767,6 +783,7 @@ func Test_CreateStake_LowThreshold_by_restrictplanVon(t *testing.T) {
 	details, _ := rlp.EncodeToBytes(nodeNameArr[index] + " super node")
 
 	amount, _ := rlp.EncodeToBytes(initBalance)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -804,6 +821,7 @@ func Test_CreateStake_LowThreshold_by_restrictplanVon(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	buf := new(bytes.Buffer)
 	err := rlp.Encode(buf, params)
@@ -870,6 +888,7 @@ func Test_CreateStake_by_InvalidNodeId(t *testing.T) {
 	StakeThreshold, _ := new(big.Int).SetString(balanceStr[index], 10) // equal or more than "1000000000000000000000000"
 
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -907,6 +926,7 @@ func Test_CreateStake_by_InvalidNodeId(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	buf := new(bytes.Buffer)
 	err := rlp.Encode(buf, params)
@@ -967,6 +987,7 @@ func Test_CreateStake_by_FlowDescLen(t *testing.T) {
 	StakeThreshold, _ := new(big.Int).SetString(balanceStr[index], 10) // equal or more than "1000000000000000000000000"
 
 	amount, _ := rlp.EncodeToBytes(StakeThreshold)
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(initProgramVersion)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -1004,6 +1025,7 @@ func Test_CreateStake_by_FlowDescLen(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	buf := new(bytes.Buffer)
 	err := rlp.Encode(buf, params)
@@ -1067,6 +1089,7 @@ func Test_CreateStake_by_LowVersionSign(t *testing.T) {
 
 	version := uint32(0<<16 | 9<<8 | 0)
 
+	rewardPer, _ := rlp.EncodeToBytes(uint16(1000))
 	programVersion, _ := rlp.EncodeToBytes(version)
 
 	node.GetCryptoHandler().SetPrivateKey(priKeyArr[index])
@@ -1104,6 +1127,7 @@ func Test_CreateStake_by_LowVersionSign(t *testing.T) {
 	params = append(params, sign)
 	params = append(params, blsPkm)
 	params = append(params, proofRlp)
+	params = append(params, rewardPer)
 
 	buf := new(bytes.Buffer)
 	err := rlp.Encode(buf, params)
@@ -1153,6 +1177,7 @@ func Test_EditStake_by_RightParams(t *testing.T) {
 
 	benefitAddress, _ := rlp.EncodeToBytes(addrArr[index])
 	nodeId, _ := rlp.EncodeToBytes(nodeIdArr[index])
+	rewardPer, _ := rlp.EncodeToBytes(uint16(2000))
 	externalId, _ := rlp.EncodeToBytes("test low version")
 	nodeName, _ := rlp.EncodeToBytes(nodeNameArr[index] + ", Low version")
 	website, _ := rlp.EncodeToBytes("https://www." + nodeNameArr[index] + ".lowVersion.com")
@@ -1165,6 +1190,7 @@ func Test_EditStake_by_RightParams(t *testing.T) {
 	params = append(params, nodeName)
 	params = append(params, website)
 	params = append(params, details)
+	params = append(params, rewardPer)
 
 	buf := new(bytes.Buffer)
 	err := rlp.Encode(buf, params)
@@ -0,0 +1,247 @@
+package vm
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler/opcodes"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+)
+
+// The ops of the WASM struct logs.
+const (
+	WasmEnter = "ENTER" // a function of the contract is entered
+	WasmExit  = "EXIT"  // a function of the contract returns
+	WasmHost  = "HOST"  // a host function is invoked by the contract
+)
+
+// WasmTracer is a Tracer which traces the WASM contracts as well. The WASM
+// interpreter doesn't run opcodes, it reports the function calls, the host
+// calls touching the state or the other contracts, and the gas of the
+// instructions instead.
+type WasmTracer interface {
+	Tracer
+
+	// CaptureWasmEnter is called when a function of the contract is entered.
+	CaptureWasmEnter(env *EVM, contract *Contract, function string, gas uint64, depth int) error
+
+	// CaptureWasmExit is called when a function of the contract returns.
+	CaptureWasmExit(env *EVM, contract *Contract, function string, ret int64, gas uint64, depth int) error
+
+	// CaptureWasmHostCall is called before the host function is invoked.
+	CaptureWasmHostCall(env *EVM, contract *Contract, call *WasmHostCall, gas uint64, depth int) error
+
+	// CaptureWasmHostReturn is called after the host function returns, the
+	// results are filled in the same call.
+	CaptureWasmHostReturn(env *EVM, contract *Contract, call *WasmHostCall, gas uint64, depth int) error
+
+	// CaptureWasmGas is called with the gas cost of each instruction before it's executed.
+	CaptureWasmGas(env *EVM, contract *Contract, op string, gas, cost uint64, depth int) error
+}
+
+// WasmHostCall is a host function invoked by a WASM contract, the fields are
+// decoded from the parameters according to the function.
+type WasmHostCall struct {
+	Name   string          `json:"name"`
+	Key    hexutil.Bytes   `json:"key,omitempty"`    // setState, getState
+	Value  hexutil.Bytes   `json:"value,omitempty"`  // setState, getState
+	To     *common.Address `json:"to,omitempty"`     // platonCall, callTransfer
+	Input  hexutil.Bytes   `json:"input,omitempty"`  // platonCall
+	Output hexutil.Bytes   `json:"output,omitempty"` // platonCallBytes
+	Amount *hexutil.Big    `json:"amount,omitempty"` // callTransfer
+	Topic  hexutil.Bytes   `json:"topic,omitempty"`  // emitEvent
+	Data   hexutil.Bytes   `json:"data,omitempty"`   // emitEvent
+	Return int64           `json:"return"`
+}
+
+// IsCall reports whether the host function calls another contract.
+func (c *WasmHostCall) IsCall() bool {
+	return strings.HasPrefix(c.Name, "platonCall") || strings.HasPrefix(c.Name, "platonDelegateCall")
+}
+
+// IsDelegateCall reports whether the host function calls another contract with
+// the context of the caller.
+func (c *WasmHostCall) IsDelegateCall() bool {
+	return strings.HasPrefix(c.Name, "platonDelegateCall")
+}
+
+// WasmLog is the WASM part of a StructLog. The WASM contracts are logged at the
+// function calls and the host calls, the GasCost of the log is the gas of the
+// instructions executed since the previous log.
+type WasmLog struct {
+	Op       string        `json:"op"`
+	Function string        `json:"function,omitempty"`
+	Return   int64         `json:"return,omitempty"`
+	HostCall *WasmHostCall `json:"hostCall,omitempty"`
+}
+
+// wasmHostDecoders decode the parameters of the traced host functions, the
+// other host functions are accounted as instructions.
+var wasmHostDecoders = map[string]func(vm *exec.VirtualMachine, call *WasmHostCall){
+	"setState":     decodeStateCall,
+	"getState":     decodeStateCall,
+	"emitEvent":    decodeEventCall,
+	"callTransfer": decodeTransferCall,
+
+	"platonCall":               decodeContractCall,
+	"platonCallInt64":          decodeContractCall,
+	"platonCallString":         decodeContractCall,
+	"platonCallBytes":          decodeContractCall,
+	"platonDelegateCall":       decodeContractCall,
+	"platonDelegateCallInt64":  decodeContractCall,
+	"platonDelegateCallString": decodeContractCall,
+	"platonDelegateCallBytes":  decodeContractCall,
+}
+
+// wasmParam returns the parameter of the host function as a memory offset or size.
+func wasmParam(vm *exec.VirtualMachine, i int) int {
+	return int(int32(vm.GetCurrentFrame().Locals[i]))
+}
+
+// wasmMemory returns a copy of the memory range, or nil if it's out of bounds.
+// The host function panics on an invalid range, the tracer mustn't.
+func wasmMemory(vm *exec.VirtualMachine, offset, size int) []byte {
+	if offset < 0 || size < 0 || offset+size > len(vm.Memory.Memory) {
+		return nil
+	}
+	return common.CopyBytes(vm.Memory.Memory[offset : offset+size])
+}
+
+// void setState(const uint8_t *key, size_t keyLen, const uint8_t *value, size_t valueLen);
+// void getState(const uint8_t *key, size_t keyLen, uint8_t *value, size_t valueLen);
+func decodeStateCall(vm *exec.VirtualMachine, call *WasmHostCall) {
+	call.Key = wasmMemory(vm, wasmParam(vm, 0), wasmParam(vm, 1))
+	if call.Name == "setState" {
+		call.Value = wasmMemory(vm, wasmParam(vm, 2), wasmParam(vm, 3))
+	}
+}
+
+// void emitEvent(const char *topic, size_t topicLen, const uint8_t *data, size_t dataLen);
+func decodeEventCall(vm *exec.VirtualMachine, call *WasmHostCall) {
+	call.Topic = wasmMemory(vm, wasmParam(vm, 0), wasmParam(vm, 1))
+	call.Data = wasmMemory(vm, wasmParam(vm, 2), wasmParam(vm, 3))
+}
+
+// int callTransfer(const uint8_t *to, size_t toLen, const uint8_t value[32]);
+func decodeTransferCall(vm *exec.VirtualMachine, call *WasmHostCall) {
+	if to := wasmMemory(vm, wasmParam(vm, 0), wasmParam(vm, 1)); to != nil {
+		addr := common.BytesToAddress(to)
+		call.To = &addr
+	}
+	if value := wasmMemory(vm, int(vm.GetCurrentFrame().Locals[2]), 32); value != nil {
+		call.Amount = (*hexutil.Big)(new(big.Int).SetBytes(value))
+	}
+}
+
+// platonCall(const uint8_t to[20], const uint8_t *params, size_t paramsLen) and its variants.
+func decodeContractCall(vm *exec.VirtualMachine, call *WasmHostCall) {
+	if to := wasmMemory(vm, wasmParam(vm, 0), common.AddressLength); to != nil {
+		addr := common.BytesToAddress(to)
+		call.To = &addr
+	}
+	call.Input = wasmMemory(vm, wasmParam(vm, 1), wasmParam(vm, 2))
+}
+
+// wasmHooks forwards the hooks of the Life VM running a contract to the WasmTracer.
+type wasmHooks struct {
+	tracer   WasmTracer
+	evm      *EVM
+	contract *Contract
+	call     *WasmHostCall // The traced host call in progress
+}
+
+func newWasmHooks(tracer WasmTracer, evm *EVM, contract *Contract) *wasmHooks {
+	return &wasmHooks{
+		tracer:   tracer,
+		evm:      evm,
+		contract: contract,
+	}
+}
+
+// gas returns the gas left to the contract.
+func (h *wasmHooks) gas(vm *exec.VirtualMachine) uint64 {
+	if vm.Context.GasUsed >= vm.Context.GasLimit {
+		return 0
+	}
+	return vm.Context.GasLimit - vm.Context.GasUsed
+}
+
+func (h *wasmHooks) CaptureEnter(vm *exec.VirtualMachine, functionID int) {
+	h.tracer.CaptureWasmEnter(h.evm, h.contract, vm.FunctionName(functionID), h.gas(vm), h.evm.depth)
+}
+
+func (h *wasmHooks) CaptureExit(vm *exec.VirtualMachine, functionID int, ret int64) {
+	h.tracer.CaptureWasmExit(h.evm, h.contract, vm.FunctionName(functionID), ret, h.gas(vm), h.evm.depth)
+}
+
+func (h *wasmHooks) CaptureHostCall(vm *exec.VirtualMachine, name string) {
+	decode, ok := wasmHostDecoders[name]
+	if !ok {
+		h.call = nil
+		return
+	}
+	h.call = &WasmHostCall{Name: name}
+	decode(vm, h.call)
+	h.tracer.CaptureWasmHostCall(h.evm, h.contract, h.call, h.gas(vm), h.evm.depth)
+}
+
+func (h *wasmHooks) CaptureHostReturn(vm *exec.VirtualMachine, name string, ret int64) {
+	call := h.call
+	if call == nil {
+		return
+	}
+	h.call = nil
+
+	call.Return = ret
+	switch {
+	case call.Name == "getState":
+		call.Value = vm.Context.StateDB.GetState(call.Key)
+	case call.IsCall() && strings.HasSuffix(call.Name, "Bytes"):
+		call.Output = common.CopyBytes(vm.Context.CallOutput)
+	}
+	h.tracer.CaptureWasmHostReturn(h.evm, h.contract, call, h.gas(vm), h.evm.depth)
+}
+
+func (h *wasmHooks) CaptureGas(vm *exec.VirtualMachine, op opcodes.Opcode, cost uint64) {
+	h.tracer.CaptureWasmGas(h.evm, h.contract, op.String(), h.gas(vm), cost, h.evm.depth)
+}
+
+// captureWasm appends the WASM log to the struct logs.
+func (l *StructLogger) captureWasm(wasm *WasmLog, gas uint64, depth int) error {
+	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs) {
+		return ErrTraceLimitReached
+	}
+	l.logs = append(l.logs, StructLog{Gas: gas, GasCost: l.wasmCost, Depth: depth, Wasm: wasm})
+	l.wasmCost = 0
+	return nil
+}
+
+// CaptureWasmEnter implements the WasmTracer interface to log a function entry.
+func (l *StructLogger) CaptureWasmEnter(env *EVM, contract *Contract, function string, gas uint64, depth int) error {
+	return l.captureWasm(&WasmLog{Op: WasmEnter, Function: function}, gas, depth)
+}
+
+// CaptureWasmExit implements the WasmTracer interface to log a function exit.
+func (l *StructLogger) CaptureWasmExit(env *EVM, contract *Contract, function string, ret int64, gas uint64, depth int) error {
+	return l.captureWasm(&WasmLog{Op: WasmExit, Function: function, Return: ret}, gas, depth)
+}
+
+// CaptureWasmHostCall implements the WasmTracer interface to log a host call.
+func (l *StructLogger) CaptureWasmHostCall(env *EVM, contract *Contract, call *WasmHostCall, gas uint64, depth int) error {
+	return l.captureWasm(&WasmLog{Op: WasmHost, HostCall: call}, gas, depth)
+}
+
+// CaptureWasmHostReturn implements the WasmTracer interface, the results of the
+// host call are filled in the call logged already.
+func (l *StructLogger) CaptureWasmHostReturn(env *EVM, contract *Contract, call *WasmHostCall, gas uint64, depth int) error {
+	return nil
+}
+
+// CaptureWasmGas implements the WasmTracer interface to account the gas of the
+// instructions to the next log.
+func (l *StructLogger) CaptureWasmGas(env *EVM, contract *Contract, op string, gas, cost uint64, depth int) error {
+	l.wasmCost += cost
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 
@@ -101,6 +102,8 @@ type Ethereum struct {
 	networkID     uint64
 	netRPCService *ethapi.PublicNetAPI
 
+	evidenceReporter *evidenceReporter
+
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and etherbase)
 }
 
@@ -128,7 +131,8 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
-	snapshotdb.SetDBOptions(config.DatabaseCache, config.DatabaseHandles)
+	snapshotdb.SetDBOptions(ctx.DBEngine(), config.DatabaseCache, config.DatabaseHandles)
+	snapshotdb.SetDBArchive(config.DBSnapshotArchive)
 
 	chainConfig, _, genesisErr := core.SetupGenesisBlock(chainDb, ctx.ResolvePath(snapshotdb.DBPath), config.Genesis)
 
@@ -273,6 +277,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			reactor.SetVRFhandler(handler.NewVrfHandler(eth.blockchain.Genesis().Nonce()))
 			reactor.SetPluginEventMux()
 			reactor.SetPrivateKey(config.CbftConfig.NodePriKey)
+			reactor.SetChainConfig(chainConfig)
 			handlePlugin(reactor)
 			agency = reactor
 
@@ -289,6 +294,11 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			log.Error("Init cbft consensus engine fail", "error", err)
 			return nil, errors.New("Failed to init cbft consensus engine")
 		}
+
+		if c, ok := eth.engine.(*cbft.Cbft); ok && chainConfig.Cbft.ValidatorMode == common.PPOS_VALIDATOR_MODE {
+			eth.evidenceReporter = newEvidenceReporter(c.EvidencePool(), eth.blockchain, chainDb, eth.txPool,
+				eth.accountManager, chainConfig.ChainID, config.CbftConfig.EvidenceReporter)
+		}
 	}
 
 	if eth.protocolManager, err = NewProtocolManager(eth.chainConfig, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb); err != nil {
@@ -332,8 +342,13 @@ func CreateDB(ctx *node.ServiceContext, config *Config, name string) (ethdb.Data
 	if err != nil {
 		return nil, err
 	}
-	if db, ok := db.(*ethdb.LDBDatabase); ok {
+	if db, ok := db.(ethdb.Store); ok {
 		db.Meter("eth/db/chaindata/")
+		// The ancient store is opt-in, it's opened if the moving is enabled or it holds the moved blocks
+		ancient := filepath.Join(db.Path(), "ancient")
+		if config.DBAncientThreshold > 0 || common.FileExist(ancient) {
+			return rawdb.NewDatabaseWithFreezer(db, ancient, config.DBAncientThreshold)
+		}
 	}
 	return db, nil
 }
@@ -538,6 +553,10 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	}
 	srvr.StartWatching(s.eventMux)
 
+	if s.evidenceReporter != nil {
+		s.evidenceReporter.Start()
+	}
+
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
@@ -548,6 +567,9 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
+	if s.evidenceReporter != nil {
+		s.evidenceReporter.Stop()
+	}
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
 	s.engine.Close()
@@ -42,6 +42,7 @@ var DefaultConfig = Config{
 		WalMode:           true,
 		PeerMsgQueueSize:  1024,
 		EvidenceDir:       "evidence",
+		HistoryDir:        "history",
 		MaxPingLatency:    5000,
 		MaxQueuesLimit:    4096,
 		BlacklistDeadline: 60,
@@ -55,13 +56,14 @@ var DefaultConfig = Config{
 	TrieTimeout:   60 * time.Minute,
 	MinerGasFloor: params.GenesisGasLimit,
 	//MinerGasCeil:  4000 * 21000 * 1.2,
-	DBDisabledGC:  false,
-	DBGCInterval:  86400,
-	DBGCTimeout:   time.Minute,
-	DBGCMpt:       true,
-	DBGCBlock:     10,
-	MinerGasPrice: big.NewInt(params.GVon),
-	MinerRecommit: 3 * time.Second,
+	DBDisabledGC:       false,
+	DBGCInterval:       86400,
+	DBGCTimeout:        time.Minute,
+	DBGCMpt:            true,
+	DBGCBlock:          10,
+	DBAncientThreshold: 0,
+	MinerGasPrice:      big.NewInt(params.GVon),
+	MinerRecommit:      3 * time.Second,
 
 	MiningLogAtDepth:       7,
 	TxChanSize:             4096,
@@ -117,8 +119,9 @@ type Config struct {
 	NoPruning bool
 
 	// Light client options
-	LightServ  int `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
-	LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
+	LightServ  int  `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
+	LightPeers int  `toml:",omitempty"` // Maximum number of LES client peers
+	LightQC    bool `toml:",omitempty"` // Verify the QuorumCerts of the headers in light sync mode
 
 	// Database options
 	SkipBcVersionCheck bool `toml:"-"`
@@ -131,6 +134,8 @@ type Config struct {
 	DBGCTimeout        time.Duration
 	DBGCMpt            bool
 	DBGCBlock          uint64
+	DBSnapshotArchive  bool   // Keeps the history of snapshotdb to read the PPOS state at any committed block
+	DBAncientThreshold uint64 // Number of the recent blocks kept in leveldb, the older blocks are moved to the ancient store (0 = disabled)
 
 	// Mining-related options
 	MinerExtraData []byte `toml:",omitempty"`
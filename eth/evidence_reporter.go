@@ -0,0 +1,261 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/consensus"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/evidence"
+	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/event"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
+	xplugin "github.com/PlatONnetwork/PlatON-Go/x/plugin"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+const (
+	// evidenceChainHeadChanSize is the size of channel listening to ChainHeadEvent.
+	evidenceChainHeadChanSize = 10
+)
+
+// evidenceReporter prunes the expired evidences of the cbft evidence pool on each new chain head,
+// and reports the unslashed ones by the configured reporter account if enabled.
+type evidenceReporter struct {
+	pool       evidence.EvidencePool
+	blockchain *core.BlockChain
+	chainDb    ethdb.Database
+	txPool     *core.TxPool
+	am         *accounts.Manager
+	chainID    *big.Int
+	reporter   common.Address
+
+	submitted map[common.Hash]common.Hash // The evidences which have been submitted by the reporter, to the hash of the tx
+	failed    map[common.Hash]struct{}    // The evidences whose reports were mined but failed, they are not submitted again
+
+	chainHeadCh  chan core.ChainHeadEvent
+	chainHeadSub event.Subscription
+	quit         chan struct{}
+}
+
+func newEvidenceReporter(pool evidence.EvidencePool, blockchain *core.BlockChain, chainDb ethdb.Database, txPool *core.TxPool,
+	am *accounts.Manager, chainID *big.Int, reporter common.Address) *evidenceReporter {
+	return &evidenceReporter{
+		pool:        pool,
+		blockchain:  blockchain,
+		chainDb:     chainDb,
+		txPool:      txPool,
+		am:          am,
+		chainID:     chainID,
+		reporter:    reporter,
+		submitted:   make(map[common.Hash]common.Hash),
+		failed:      make(map[common.Hash]struct{}),
+		chainHeadCh: make(chan core.ChainHeadEvent, evidenceChainHeadChanSize),
+		quit:        make(chan struct{}),
+	}
+}
+
+func (r *evidenceReporter) Start() {
+	if r.reporter != (common.Address{}) {
+		if _, err := r.am.Find(accounts.Account{Address: r.reporter}); err != nil {
+			log.Warn("The evidence reporter account is not found, reporting is disabled", "reporter", r.reporter, "err", err)
+			r.reporter = common.Address{}
+		} else {
+			log.Info("Report the duplicate evidences automatically", "reporter", r.reporter)
+		}
+	}
+	r.chainHeadSub = r.blockchain.SubscribeChainHeadEvent(r.chainHeadCh)
+	go r.loop()
+}
+
+func (r *evidenceReporter) Stop() {
+	r.chainHeadSub.Unsubscribe()
+	close(r.quit)
+}
+
+func (r *evidenceReporter) loop() {
+	for {
+		select {
+		case ev := <-r.chainHeadCh:
+			r.prune(ev.Block)
+			if r.reporter != (common.Address{}) {
+				r.report(ev.Block)
+			}
+		case <-r.chainHeadSub.Err():
+			return
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// prune deletes the evidences which are older than MaxEvidenceAge,
+// they are refused by the slashing contract.
+func (r *evidenceReporter) prune(head *types.Block) {
+	evidenceAge, err := gov.GovernMaxEvidenceAge(head.NumberU64(), head.Hash())
+	if nil != err {
+		log.Error("Failed to prune evidences, query Gov MaxEvidenceAge is failed", "blockNumber", head.NumberU64(), "err", err)
+		return
+	}
+	epoch := xutil.CalculateEpoch(head.NumberU64())
+	if epoch <= uint64(evidenceAge)+1 {
+		return
+	}
+	// the evidences of the epoch before (epoch - evidenceAge) are expired
+	lowest := (epoch-uint64(evidenceAge)-1)*xutil.CalcBlocksEachEpoch() + 1
+	if n, err := r.pool.Prune(lowest); err != nil {
+		log.Error("Failed to prune evidences", "blockNumber", head.NumberU64(), "lowest", lowest, "err", err)
+	} else if n > 0 {
+		log.Info("Pruned the expired evidences", "blockNumber", head.NumberU64(), "lowest", lowest, "count", n)
+	}
+}
+
+// report submits the slashing transaction for each evidence which is not reported by anyone yet.
+// The evidence is submitted again if its transaction has left the txPool without being mined,
+// it is given up if its transaction was mined but failed, the slashing contract refuses it again.
+func (r *evidenceReporter) report(head *types.Block) {
+	evs := r.pool.Evidences()
+	if len(evs) == 0 {
+		r.submitted = make(map[common.Hash]common.Hash)
+		r.failed = make(map[common.Hash]struct{})
+		return
+	}
+	state, err := r.blockchain.StateAt(head.Root())
+	if err != nil {
+		log.Error("Failed to report evidences, get state is failed", "blockNumber", head.NumberU64(), "err", err)
+		return
+	}
+
+	submitted := make(map[common.Hash]common.Hash)
+	failed := make(map[common.Hash]struct{})
+	for _, ev := range evs {
+		hash := common.BytesToHash(ev.Hash())
+		if _, ok := r.failed[hash]; ok {
+			failed[hash] = struct{}{}
+			continue
+		}
+		if txHash, ok := r.submitted[hash]; ok {
+			if r.txPool.Get(txHash) != nil {
+				submitted[hash] = txHash
+				continue
+			}
+			if receipt, _, _, _ := rawdb.ReadReceipt(r.chainDb, txHash); receipt != nil && reportFailed(receipt) {
+				log.Warn("The evidence report is failed, give it up", "evidenceHash", hash.TerminalString(), "type", ev.Type(),
+					"nodeId", ev.NodeID().TerminalString(), "evidenceBlockNum", ev.BlockNumber(), "txHash", txHash.TerminalString())
+				failed[hash] = struct{}{}
+				continue
+			}
+		}
+		if ev.BlockNumber() > head.NumberU64() {
+			continue
+		}
+		if txHash, _ := xplugin.SlashInstance().CheckDuplicateSign(ev.Address(), ev.BlockNumber(), ev.Type(), state); len(txHash) > 0 {
+			continue
+		}
+		txHash, err := r.submit(ev)
+		if err != nil {
+			log.Warn("Failed to report evidence", "evidenceHash", hash.TerminalString(), "type", ev.Type(),
+				"nodeId", ev.NodeID().TerminalString(), "evidenceBlockNum", ev.BlockNumber(), "err", err)
+			continue
+		}
+		log.Info("Reported evidence", "evidenceHash", hash.TerminalString(), "type", ev.Type(),
+			"nodeId", ev.NodeID().TerminalString(), "evidenceBlockNum", ev.BlockNumber(), "reporter", r.reporter,
+			"txHash", txHash.TerminalString())
+		submitted[hash] = txHash
+	}
+	// forget the pruned evidences
+	r.submitted = submitted
+	r.failed = failed
+}
+
+// reportFailed returns whether the mined reportDuplicateSign transaction failed,
+// either by the execution or by the result code of the slashing contract.
+func reportFailed(receipt *types.Receipt) bool {
+	if receipt.Status == types.ReceiptStatusFailed {
+		return true
+	}
+	topic := common.BytesToHash(crypto.Keccak256([]byte(strconv.Itoa(vm.TxReportDuplicateSign))))
+	for _, l := range receipt.Logs {
+		if l.Address != cvm.SlashingContractAddr || len(l.Topics) == 0 || l.Topics[0] != topic {
+			continue
+		}
+		var data [][]byte
+		if err := rlp.DecodeBytes(l.Data, &data); err != nil || len(data) == 0 {
+			return true
+		}
+		return string(data[0]) != strconv.Itoa(int(common.NoErr.Code))
+	}
+	return false
+}
+
+// submit signs the reportDuplicateSign transaction by the reporter account and adds it to the txPool.
+// It returns the hash of the transaction.
+func (r *evidenceReporter) submit(ev consensus.Evidence) (common.Hash, error) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	input, err := encodeReportDuplicateSign(ev.Type(), string(data))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gas, err := core.IntrinsicGas(input, false)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gas += params.SlashingGas + params.ReportDuplicateSignGas + params.DuplicateEvidencesGas
+
+	account := accounts.Account{Address: r.reporter}
+	wallet, err := r.am.Find(account)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	// the pending nonce, so the transactions of the reporter already in the txPool are not replaced
+	nonce := r.txPool.Nonce(r.reporter)
+	tx := types.NewTransaction(nonce, cvm.SlashingContractAddr, big.NewInt(0), gas, r.txPool.GasPrice(), input)
+	signed, err := wallet.SignTx(account, tx, r.chainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return signed.Hash(), r.txPool.AddLocal(signed)
+}
+
+// encodeReportDuplicateSign builds the input of the slashing contract: [fnType, dupType, data]
+func encodeReportDuplicateSign(dupType consensus.EvidenceType, data string) ([]byte, error) {
+	var fields [][]byte
+	for _, f := range []interface{}{uint16(vm.TxReportDuplicateSign), uint8(dupType), data} {
+		b, err := rlp.EncodeToBytes(f)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, b)
+	}
+	return rlp.EncodeToBytes(fields)
+}
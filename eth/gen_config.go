@@ -25,6 +25,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		NoPruning                bool
 		LightServ                int  `toml:",omitempty"`
 		LightPeers               int  `toml:",omitempty"`
+		LightQC                  bool `toml:",omitempty"`
 		SkipBcVersionCheck       bool `toml:"-"`
 		DatabaseHandles          int  `toml:"-"`
 		DatabaseCache            int
@@ -68,6 +69,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.NoPruning = c.NoPruning
 	enc.LightServ = c.LightServ
 	enc.LightPeers = c.LightPeers
+	enc.LightQC = c.LightQC
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
@@ -115,6 +117,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		NoPruning                *bool
 		LightServ                *int  `toml:",omitempty"`
 		LightPeers               *int  `toml:",omitempty"`
+		LightQC                  *bool `toml:",omitempty"`
 		SkipBcVersionCheck       *bool `toml:"-"`
 		DatabaseHandles          *int  `toml:"-"`
 		DatabaseCache            *int
@@ -175,6 +178,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.LightPeers != nil {
 		c.LightPeers = *dec.LightPeers
 	}
+	if dec.LightQC != nil {
+		c.LightQC = *dec.LightQC
+	}
 	if dec.SkipBcVersionCheck != nil {
 		c.SkipBcVersionCheck = *dec.SkipBcVersionCheck
 	}
@@ -9,6 +9,7 @@
 // prestate_tracer.js
 // trigram_tracer.js
 // unigram_tracer.js
+// wasm_call_tracer.js
 
 package tracers
 
@@ -257,6 +258,26 @@ func unigram_tracerJs() (*asset, error) {
 	return a, nil
 }
 
+var _wasm_call_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x58\xdd\x53\x1b\x39\x12\x7f\x36\x7f\x45\x6f\x1e\x62\x53\x31\x36\xdc\xd5\x6d\xdd\x99\x78\xb7\xbc\xc4\x10\x57\xb1\x40\x19\x73\xa9\x14\xc5\x83\xec\xd1\xd8\xda\x8c\x47\x53\x92\x06\xe3\x4b\xf8\xdf\xb7\x5b\x1f\x63\x0d\x0c\x21\xf7\xb0\x55\xfb\x40\x60\xd4\xad\xfe\xfc\xf5\x4f\x52\xfa\x7d\x38\x91\xc5\x56\x89\xe5\xca\xc0\x3f\x0e\x8f\xfe\x7d\x80\xff\xfc\x07\x66\x2b\x0e\x57\x19\x33\x97\x17\x70\xc1\xcd\x46\xaa\x2f\x30\x2a\xcd\x4a\x2a\xbd\xd7\xef\xa3\x54\x68\x48\x45\xc6\x01\x7f\x17\x4c\x19\x90\x29\x98\x6a\xcb\xc1\x99\x84\x4c\xcc\x15\x53\xdb\x1e\xaa\xbb\x1d\x0d\x42\xda\x9d\x2a\xce\x41\xcb\xd4\x6c\x98\xe2\x03\xd8\xca\x12\x16\x2c\x07\xc5\x13\xa1\x8d\x12\xf3\xd2\xa0\x13\x03\x2c\x4f\xfa\x52\xc1\x5a\x26\x22\xdd\x92\x41\x5c\x2b\xf3\x84\x2b\xeb\xd6\x70\xb5\xd6\x21\x86\xb3\x8b\x1b\x38\xe7\x5a\xa3\xec\x8c\xe7\x5c\xb1\x0c\xae\xca\x79\x26\x16\x70\x2e\x16\x3c\xd7\x1c\x18\x06\x4d\x2b\x7a\xc5\x13\x98\x5b\x73\xb4\xf1\x94\x42\xb9\xf6\xa1\xc0\xa9\x44\xfb\xcc\x08\x99\x77\x81\x0b\x94\x2b\xb8\xe7\x4a\xe3\x37\xfc\x33\xb8\xf2\x06\xbb\x20\x15\x19\xe9\x30\x43\x09\x28\x90\x05\xed\xdb\xc7\xa8\xb7\x80\x49\xef\xb6\xbe\x5a\x8e\x5d\xd6\x09\x88\xdc\x3a\x59\xc9\x02\x33\x5c\xa1\x6d\xcc\x79\x23\xb2\x0c\xe6\x1c\x4a\xcd\xd3\x32\xeb\x92\x2d\x54\x86\x4f\x93\xd9\xc7\xcb\x9b\x19\x8c\x2e\x3e\xc3\xa7\xd1\x74\x3a\xba\x98\x7d\x3e\x46\x65\xec\x18\x4a\xf9\x3d\x77\xa6\xc4\xba\xc8\x04\x5a\xc6\x04\x15\xcb\xcd\x16\xf3\x20\x0b\xbf\x8f\xa7\x27\x1f\x71\xcb\xe8\xb7\xc9\xf9\x64\xf6\x19\xb3\x81\xd3\xc9\xec\x62\x7c\x7d\x0d\xa7\x97\x53\x18\xc1\xd5\x68\x3a\x9b\x9c\xdc\x9c\x8f\xa6\x70\x75\x33\xbd\xba\xbc\x1e\xf7\xe0\x9a\x53\x54\x9c\xf6\xbf\x5e\xf1\xd4\xf6\x0e\xab\x9a\x70\xc3\x44\xa6\x43\x1d\x3e\x63\xbb\x35\xc6\x98\x25\xb0\x62\xf7\x1c\xdb\xbe\xe0\xe2\x1e\x23\x64\xb0\x40\x54\xfe\x70\x4b\xc9\x16\xcb\x64\xbe\xb4\x39\xbf\x00\x45\x98\xa4\x90\x4b\xd3\x05\x8d\xa1\xbf\x5f\x19\x53\x0c\xfa\xfd\xcd\x66\xd3\x5b\xe6\x65\x4f\xaa\x65\x3f\x73\xc6\x74\xff\x97\xde\x1e\x59\xdc\x30\xbd\x3e\x61\x59\x36\x53\x6c\x81\xae\xb1\x3d\x0c\x0c\x16\x4e\xb3\x05\xf5\x97\xfe\xa6\x75\x4a\x8e\x3c\x7e\x1a\x5d\xff\x8e\x61\xe7\xb4\x6c\xb4\x6b\x19\x7f\xf0\x5f\x08\x60\x32\xa9\x78\x21\x95\x15\x62\x37\x72\x44\x46\x8e\xb9\x2c\xd0\x87\xee\x3a\xd3\x29\x02\x85\x94\x6d\xd3\x50\x71\xcd\x12\x8e\x18\xad\x7b\xee\xba\x64\x6d\x8c\x21\x5f\x91\xdf\xcb\x2f\x58\xb9\x10\x00\xa4\x65\x6e\x95\xb1\xd8\x5f\xf7\x5a\xa8\x6a\xdd\x18\xb6\xf8\x42\x99\xd0\x96\x45\xa9\x14\x3a\xa1\xaa\x97\x08\x4f\xac\x3f\xa9\x80\xd3\xf1\xa5\xb7\x49\xf1\x07\xd4\x30\x16\xbf\xad\xca\xca\x00\x6e\xbf\x3e\xde\x75\xf7\xac\x6d\x6d\x78\x41\x66\x43\x14\x54\x13\xcc\x00\x41\x2d\x8b\x85\x4c\x3c\x80\xc9\xe0\xf8\xbf\xc1\x1e\xa7\x9c\xfd\x4a\x55\x36\x6b\x8d\xe6\x0f\x3b\xe5\x0a\x9c\xa0\x53\x32\x3f\xa8\x32\xea\x64\x72\xd9\x85\x64\xbe\x0f\x5f\x1f\xbd\xff\x94\x95\x99\x89\x03\xd8\xac\x3c\xe6\xd1\x68\x89\x35\xae\x52\xa0\xc4\x90\x60\xc8\xa9\x0f\x2d\x75\x88\x6c\x59\x1b\xdf\xf5\x42\x80\x18\x53\xd7\x9e\x79\x62\xd5\x36\x6b\xbf\x8e\x05\xd2\xe6\xb4\xcd\xe6\x52\x19\x89\x5c\xd1\x9a\xf7\xb5\xd7\x6a\x79\x82\x48\x85\xd2\xbb\x36\x06\x0b\x44\x0b\xcc\x35\xca\xb7\x91\x3f\x10\xa6\x50\x82\x1a\xaa\x9a\x99\xe0\x1c\xed\xdd\x33\xe5\x36\x0c\x51\x22\x74\xaf\x6a\xe1\x6d\xfd\xb3\x97\xf1\x7c\x89\x70\x3a\x80\xa3\xbb\x63\xdc\x27\x52\xe8\x90\xb0\x47\x31\xc0\x70\x38\xb4\x94\x9b\x8a\x1c\x7d\xbd\x7d\x6b\xab\xd1\x5b\x72\xf3\x81\x17\x66\xd5\xd9\x47\x05\x68\xb4\xe7\x92\x6a\x45\x96\xaa\xad\xa7\xa1\x00\xfb\xe4\x6f\xe7\x70\xc9\xf4\x24\x87\x9f\x62\x8f\xde\x4a\x2b\xc8\x23\x2b\x67\x4c\x7b\x03\x8f\x7b\xf6\x27\xee\xd7\x47\xa9\xcd\x89\xaf\x56\x68\xd9\x9c\xa7\x44\x46\x0c\xb9\x35\xae\x70\xac\xb1\x7d\xda\x44\xdf\xb9\x60\xee\xc5\xe6\x51\xb1\x73\xb6\xe6\x21\x3e\x72\x41\x41\x5e\xe0\x9a\x8b\x92\xb2\x24\x8d\x9e\xc0\xdc\x1e\x2e\xd3\x4e\xbb\x40\xae\x92\x39\x99\x6d\xdb\x32\x1e\xc2\xb7\x6f\xd0\xa4\xf2\x81\x67\x7c\x89\x07\x4a\xa4\xea\xeb\xf2\xa4\xf2\x45\xa9\x57\x1d\x57\x30\xb3\x2d\xf0\x60\xfd\x51\x73\xf0\x2b\xb4\x3f\x8c\xcf\xc7\x67\xa3\xd9\xf8\x64\x74\x7e\xde\x86\x01\xb4\xed\x1f\x5d\x6b\x2d\x55\x72\x8d\xd6\x8c\xfc\xc8\x1f\x6c\xe6\xbd\xaa\x40\x98\xe4\x28\x49\x14\xf2\x74\x67\x7f\xdf\x69\x1b\x89\xba\x35\xed\x50\x8e\x99\xac\x94\x44\x5e\x94\x38\x77\x0d\x4a\x13\x92\x54\x7a\x16\x14\x83\x7a\xd7\x9d\x84\xf4\x7d\x8a\x16\x05\xb6\xcc\x0d\x38\x98\x72\x53\xaa\x5a\x9f\x59\x4a\xd3\xfc\x0c\x08\x2f\xa2\x00\xb9\x92\x4c\xe8\x08\x0d\xce\xe8\xdf\x0d\x0f\x2f\xce\x7c\xaf\x90\x85\x9f\x96\x30\x4a\x37\x1a\x73\x1d\x42\xfb\xf0\xa1\x0d\xef\x60\x2e\x96\x93\xdc\xc4\x73\x78\x50\xaf\xf9\x7e\xcf\xc8\x6b\xbc\xa5\xe4\xcb\xce\xd1\xcf\x68\x89\x4c\x61\x91\x2f\xf3\x6c\x6b\x99\x67\xbe\x45\x6a\x07\x0c\x40\x30\x3a\xc1\xdc\x91\x67\x25\x78\x19\xc1\x86\xda\xe3\x8d\x3e\x89\x78\x4b\x9c\xc3\xc0\x58\xe8\x31\xb0\x80\x4d\x56\xd3\x81\xdc\x39\xf8\x97\x4d\xac\xfd\x1b\x99\x6d\x07\x1a\x20\xa5\x5a\x3d\x5d\x1b\x90\x86\xde\x57\x35\xf0\x19\x72\xa5\xf0\x34\x1a\xc2\x9b\xea\xc0\xf5\x8e\xdf\xd8\x2a\xb4\x1e\x81\x67\x78\x47\x89\xb7\xf8\x40\x87\x4d\xa0\xbc\xb4\x32\x2c\x83\xdf\x1d\x78\xc7\x8d\xa0\xc8\xff\xe0\x0b\x57\x3d\xa7\xe0\x00\xe3\x01\x59\xe5\x66\x33\x5a\xb8\xbb\x85\x3d\xf4\xdb\x51\xdf\xc2\x45\x00\xfd\xd7\x46\xb8\x3d\xc3\x8b\xdd\xf5\xe9\x78\xfa\xd7\xcd\xe2\x3d\xcb\x4a\x74\xe5\xa1\x50\xd3\x1a\xad\xf1\x46\x8c\x79\xd7\xba\x6f\x93\xaf\xb8\xbb\xb9\x21\x3f\xed\x50\xd9\x0a\xa9\xed\x9a\x52\x25\x4b\x4d\xe1\xc9\x9b\xe3\xc6\x7a\x06\xad\x57\x6b\xca\xd7\xc2\x8c\xe9\xea\xd4\x7e\x6d\x10\x5e\x39\xfc\x76\x87\x91\xbf\x89\x0d\x5f\x3c\x8d\x82\x02\xdc\xde\xed\xa2\x8f\x24\x9e\x8d\x8d\x2c\xc4\x62\xd0\xdc\x00\x94\x50\x0f\x00\x5f\x1c\xac\x51\xe5\x03\x0a\x50\xe3\x19\xb5\xb9\xfa\x00\x4b\x12\x77\x19\xc0\xe8\xdc\xbb\xc6\xdd\x10\x72\x23\x6b\x57\x3d\x99\x73\xa4\x2e\xb7\x29\xa2\x2c\x0b\x57\x9b\x52\x7c\x04\x37\x1e\xc0\xd1\xf9\xdb\x4c\x18\x4f\xe9\xc1\xd5\x23\x41\xa6\x32\x6e\xc6\x1d\xab\x1c\x43\xbc\x44\x69\x5a\x26\xa1\x76\x65\x3c\x35\xf0\xc2\x65\x22\x10\xe6\x93\x5e\xd2\x96\x83\xa3\x3b\xb7\xd2\xd8\xaa\xef\x6f\xf0\xad\xa3\x48\xbf\xa7\xe8\x3a\x19\x86\x3b\xf4\x00\xc7\xec\xa5\xdb\xe7\xee\xda\x89\x52\x79\x4f\x67\x0d\x92\x9f\x3f\x44\x42\xc3\x90\x91\x9c\x09\x6b\xcd\xb3\x21\xcd\x31\xd6\x10\xbb\xe5\x64\x71\xb7\xcc\x43\xed\xb2\x38\xf2\xb3\xb3\x3b\xa5\xd8\xbc\x7a\x65\x6c\x56\x32\x8b\xe2\xe8\x56\x54\x4b\xd1\x42\xa1\xe4\x92\x58\x82\xae\xdc\xce\x98\x37\x85\x0d\xde\x70\xcb\xc7\x9b\x15\x3d\xf4\x3b\xcd\xd3\xf2\x0b\x1c\xfd\xbf\x07\x4e\x35\xf9\xbb\xda\xc4\xa3\x1f\xa3\xc2\x8d\xcf\xf1\x8b\xe4\xfa\xe8\x01\xe3\x1b\xe0\xe9\xd2\xb3\x25\x00\xd6\xa9\x47\x1f\x96\xda\x3c\x59\x06\x02\x24\x19\x2d\x39\xde\xf3\xdc\x18\x0b\x8d\x74\x22\xcf\x88\x10\xd0\x4e\x32\xbb\x56\x83\xb9\x55\x45\x5c\x3b\x33\x4f\x06\x03\x77\x3c\x9b\x8b\xb0\x81\xce\xde\x41\xf3\x06\x12\x35\x6c\xf2\xd7\xa5\x38\x56\xbb\xe4\xa4\xee\xe4\x1a\xc4\x52\xb7\xe4\xc4\x84\x21\x57\x85\x3a\xca\x0f\xef\xec\xd5\xdc\xea\xb8\xaa\x0f\x9a\x74\x9c\xa8\x1b\x7a\x69\xd3\x7d\xae\xe5\xde\xb3\xb6\xae\x62\x1d\xb5\x02\x3f\x68\xf5\xb1\x7a\x5b\xe0\xa2\x43\x43\x13\xd1\xb8\xa6\x56\x70\xa9\x94\x63\x94\x78\x1d\x97\x61\x83\x60\x07\x20\x82\x8a\x1b\x3b\x17\xb0\x1d\x3b\xf1\x3f\xde\x71\x9a\xf1\x38\x07\x11\xbd\x8b\x15\x67\x74\x99\xf1\x0f\x2e\x39\xb7\x64\x5b\x6a\xec\x47\x34\xbc\x09\xd7\x82\x5e\x66\xa9\xe0\x59\x02\x32\xf1\xff\x27\xf0\x87\x96\xb9\x7b\x1c\x73\xbc\x0a\xa1\x45\xb6\x1b\x40\x7f\x64\xf8\x59\x0f\xe3\x6b\xa7\x92\x1e\xbf\x8a\xde\xce\xc6\x3e\x17\x43\x34\x8d\x6c\x4d\xe0\xd7\xee\xf1\xf7\x0c\xfc\x34\x40\xcf\xd1\xef\x5e\x61\xf8\x55\xc7\xbd\xd3\x96\x75\xc4\xdb\x45\xfb\x55\x87\x77\x20\xf1\x3a\x86\xe3\xdb\x64\x1d\xa8\x56\x62\xbf\xea\x10\x8d\xae\x5a\x75\x70\x56\x4f\x45\x87\x47\xea\x7a\x65\xc7\x7e\xd5\x71\x1a\xd3\x4a\xed\x91\xfa\x6b\x4c\x23\xf8\x90\xa9\x24\x4f\xc0\x69\x93\xf7\xe8\xdc\x01\xdb\x2e\x57\x58\x26\x00\x51\x57\x3b\x54\xf3\x2f\x7c\x4b\xec\xe9\x4a\xef\xf1\x4a\x88\x76\x0b\xb7\x28\xbe\x6b\xbe\x2f\x78\x78\x46\x7a\xf1\xbb\x35\xb2\xe1\x0f\xa6\xa6\xc1\xa8\xa2\x10\xc3\xc3\x63\x10\xef\xe3\x0d\xe1\x94\x04\xf1\xee\x5d\xf0\x19\xcb\x6f\xc5\x5d\x20\xe8\x6a\x02\x9e\xc8\x6b\x2f\xe9\x30\x33\x4e\x87\x86\x64\xef\x71\xef\x4f\xa8\x63\x45\x03\x34\x16\x00\x00")
+
+func wasm_call_tracerJsBytes() ([]byte, error) {
+	return bindataRead(
+		_wasm_call_tracerJs,
+		"wasm_call_tracer.js",
+	)
+}
+
+func wasm_call_tracerJs() (*asset, error) {
+	bytes, err := wasm_call_tracerJsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "wasm_call_tracer.js", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x99, 0x33, 0x5d, 0x59, 0x52, 0x86, 0x92, 0x33, 0x7c, 0xc6, 0xec, 0x49, 0x32, 0x1c, 0x78, 0xa6, 0xe, 0xd3, 0x34, 0x11, 0x20, 0xc9, 0x5f, 0x6b, 0xa3, 0x20, 0x74, 0xa9, 0xa, 0xc, 0xa5, 0x82}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -365,6 +386,8 @@ var _bindata = map[string]func() (*asset, error){
 	"trigram_tracer.js": trigram_tracerJs,
 
 	"unigram_tracer.js": unigram_tracerJs,
+
+	"wasm_call_tracer.js": wasm_call_tracerJs,
 }
 
 // AssetDir returns the file names below a certain
@@ -408,15 +431,16 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"4byte_tracer.js":    {_4byte_tracerJs, map[string]*bintree{}},
-	"bigram_tracer.js":   {bigram_tracerJs, map[string]*bintree{}},
-	"call_tracer.js":     {call_tracerJs, map[string]*bintree{}},
-	"evmdis_tracer.js":   {evmdis_tracerJs, map[string]*bintree{}},
-	"noop_tracer.js":     {noop_tracerJs, map[string]*bintree{}},
-	"opcount_tracer.js":  {opcount_tracerJs, map[string]*bintree{}},
-	"prestate_tracer.js": {prestate_tracerJs, map[string]*bintree{}},
-	"trigram_tracer.js":  {trigram_tracerJs, map[string]*bintree{}},
-	"unigram_tracer.js":  {unigram_tracerJs, map[string]*bintree{}},
+	"4byte_tracer.js":     {_4byte_tracerJs, map[string]*bintree{}},
+	"bigram_tracer.js":    {bigram_tracerJs, map[string]*bintree{}},
+	"call_tracer.js":      {call_tracerJs, map[string]*bintree{}},
+	"evmdis_tracer.js":    {evmdis_tracerJs, map[string]*bintree{}},
+	"noop_tracer.js":      {noop_tracerJs, map[string]*bintree{}},
+	"opcount_tracer.js":   {opcount_tracerJs, map[string]*bintree{}},
+	"prestate_tracer.js":  {prestate_tracerJs, map[string]*bintree{}},
+	"trigram_tracer.js":   {trigram_tracerJs, map[string]*bintree{}},
+	"unigram_tracer.js":   {unigram_tracerJs, map[string]*bintree{}},
+	"wasm_call_tracer.js": {wasm_call_tracerJs, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
@@ -0,0 +1,175 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// wasmCallTracer is a transaction tracer for the WASM contracts that extracts and
+// reports the internal calls, transfers and events made by a transaction, along
+// with the invoked contract functions.
+{
+	// callstack is the current recursive call stack of the WASM execution.
+	callstack: [{}],
+
+	// step is invoked for every opcode that the EVM executes, the EVM contracts
+	// are not traced.
+	step: function(log, db) {},
+
+	// fault is invoked when the actual execution of an EVM opcode fails.
+	fault: function(log, db) {},
+
+	// wasmEnter is invoked when a function of a WASM contract is entered.
+	wasmEnter: function(wasm, db) {
+		// The first function entered in a call is the exported entry of the contract
+		var call = this.callstack[this.callstack.length - 1];
+		if (call.func === undefined && wasm.getDepth() == this.callstack.length) {
+			call.func = wasm.getFunction();
+			if (call.gasIn !== undefined) {
+				call.gas = wasm.getGas();
+			}
+		}
+	},
+
+	// wasmHostCall is invoked before a host function is invoked by a WASM contract.
+	wasmHostCall: function(wasm, db) {
+		var name = wasm.host.getName();
+		if (name.indexOf('platonCall') == 0 || name.indexOf('platonDelegateCall') == 0) {
+			this.callstack.push({
+				type:  name.indexOf('platonDelegateCall') == 0 ? 'DELEGATECALL' : 'CALL',
+				from:  toHex(wasm.contract.getAddress()),
+				to:    toHex(wasm.host.getTo()),
+				input: toHex(wasm.host.getInput()),
+				gasIn: wasm.getGas(),
+				host:  name
+			});
+		}
+	},
+
+	// wasmHostReturn is invoked after a host function invoked by a WASM contract returns.
+	wasmHostReturn: function(wasm, db) {
+		var name = wasm.host.getName();
+		if (name.indexOf('platonCall') == 0 || name.indexOf('platonDelegateCall') == 0) {
+			var call = this.callstack.pop();
+			call.gasUsed = '0x' + bigInt(call.gasIn - wasm.getGas()).toString(16);
+
+			// Only the bytes variants report the output and the failure of the call
+			if (name.slice(-5) == 'Bytes') {
+				if (wasm.host.getReturn() < 0) {
+					call.error = "internal failure";
+				} else {
+					call.output = toHex(wasm.host.getOutput());
+				}
+			}
+			this.inject(call);
+			return;
+		}
+		if (name == 'callTransfer') {
+			var transfer = {
+				type:  'TRANSFER',
+				from:  toHex(wasm.contract.getAddress()),
+				to:    toHex(wasm.host.getTo()),
+				value: '0x' + wasm.host.getAmount().toString(16)
+			};
+			if (wasm.host.getReturn() != 0) {
+				transfer.error = "transfer failed";
+			}
+			this.inject(transfer);
+			return;
+		}
+		if (name == 'emitEvent') {
+			var call = this.callstack[this.callstack.length - 1];
+			if (call.events === undefined) {
+				call.events = [];
+			}
+			call.events.push({topic: toHex(wasm.host.getTopic()), data: toHex(wasm.host.getData())});
+		}
+	},
+
+	// inject adds the finished call into the current one.
+	inject: function(call) {
+		if (call.gas !== undefined) {
+			call.gas = '0x' + bigInt(call.gas).toString(16);
+		}
+		delete call.gasIn; delete call.host;
+
+		var left = this.callstack.length;
+		if (this.callstack[left-1].calls === undefined) {
+			this.callstack[left-1].calls = [];
+		}
+		this.callstack[left-1].calls.push(call);
+	},
+
+	// result is invoked when the execution is over and returns the final result
+	// of the tracing.
+	result: function(ctx, db) {
+		// A failed contract aborts the whole execution, the calls in progress are
+		// failed as well
+		while (this.callstack.length > 1) {
+			var call = this.callstack.pop();
+			call.error = "execution failed";
+			delete call.events;
+			this.inject(call);
+		}
+		var result = {
+			type:    ctx.type,
+			from:    toHex(ctx.from),
+			to:      toHex(ctx.to),
+			value:   '0x' + ctx.value.toString(16),
+			gas:     '0x' + bigInt(ctx.gas).toString(16),
+			gasUsed: '0x' + bigInt(ctx.gasUsed).toString(16),
+			input:   toHex(ctx.input),
+			output:  toHex(ctx.output),
+			func:    this.callstack[0].func,
+			events:  this.callstack[0].events,
+			calls:   this.callstack[0].calls,
+			time:    ctx.time,
+		};
+		if (ctx.error !== undefined) {
+			result.error = ctx.error;
+			delete result.output;
+			delete result.events;
+		}
+		return this.finalize(result);
+	},
+
+	// finalize recreates a call object using the final desired field oder for json
+	// serialization, the events of the failed calls are reverted.
+	finalize: function(call) {
+		var sorted = {
+			type:    call.type,
+			from:    call.from,
+			to:      call.to,
+			value:   call.value,
+			gas:     call.gas,
+			gasUsed: call.gasUsed,
+			input:   call.input,
+			output:  call.output,
+			func:    call.func,
+			error:   call.error,
+			events:  call.error === undefined ? call.events : undefined,
+			time:    call.time,
+			calls:   call.calls,
+		}
+		for (var key in sorted) {
+			if (sorted[key] === undefined) {
+				delete sorted[key];
+			}
+		}
+		if (sorted.calls !== undefined) {
+			for (var i=0; i<sorted.calls.length; i++) {
+				sorted.calls[i] = this.finalize(sorted.calls[i]);
+			}
+		}
+		return sorted;
+	}
+}
@@ -274,6 +274,75 @@ func (cw *contractWrapper) pushObject(vm *duktape.Context) {
 	vm.PutPropString(obj, "getInput")
 }
 
+// wasmWrapper provides a JavaScript wrapper around the WASM tracing hooks.
+type wasmWrapper struct {
+	op       string           // The hook being invoked, ENTER, EXIT, HOST or GAS
+	function string           // The function entered or returned from
+	ret      int64            // The return value of the function or the host call
+	call     *vm.WasmHostCall // The host call being invoked or returned from
+}
+
+// pushBytes pushes the byte slice as a buffer, or undefined if it's nil.
+func pushBytes(ctx *duktape.Context, blob []byte) {
+	if blob == nil {
+		ctx.PushUndefined()
+		return
+	}
+	ptr := ctx.PushFixedBuffer(len(blob))
+	copy(makeSlice(ptr, uint(len(blob))), blob)
+}
+
+// pushHostObject assembles a JSVM object wrapping the swappable host call and
+// pushes it onto the VM stack. The accessors return undefined if the fields
+// don't apply to the host function.
+func (ww *wasmWrapper) pushHostObject(vm *duktape.Context) {
+	obj := vm.PushObject()
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushString(ww.call.Name); return 1 })
+	vm.PutPropString(obj, "getName")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int { pushBytes(ctx, ww.call.Key); return 1 })
+	vm.PutPropString(obj, "getKey")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int { pushBytes(ctx, ww.call.Value); return 1 })
+	vm.PutPropString(obj, "getValue")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int {
+		if ww.call.To == nil {
+			ctx.PushUndefined()
+			return 1
+		}
+		pushBytes(ctx, ww.call.To.Bytes())
+		return 1
+	})
+	vm.PutPropString(obj, "getTo")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int { pushBytes(ctx, ww.call.Input); return 1 })
+	vm.PutPropString(obj, "getInput")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int { pushBytes(ctx, ww.call.Output); return 1 })
+	vm.PutPropString(obj, "getOutput")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int {
+		if ww.call.Amount == nil {
+			ctx.PushUndefined()
+			return 1
+		}
+		pushBigInt(ww.call.Amount.ToInt(), ctx)
+		return 1
+	})
+	vm.PutPropString(obj, "getAmount")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int { pushBytes(ctx, ww.call.Topic); return 1 })
+	vm.PutPropString(obj, "getTopic")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int { pushBytes(ctx, ww.call.Data); return 1 })
+	vm.PutPropString(obj, "getData")
+
+	vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushNumber(float64(ww.call.Return)); return 1 })
+	vm.PutPropString(obj, "getReturn")
+}
+
 // Tracer provides an implementation of Tracer that evaluates a Javascript
 // function for each VM execution step.
 type Tracer struct {
@@ -289,6 +358,7 @@ type Tracer struct {
 	memoryWrapper   *memoryWrapper   // Wrapper around the VM memory
 	contractWrapper *contractWrapper // Wrapper around the contract object
 	dbWrapper       *dbWrapper       // Wrapper around the VM environment
+	wasmWrapper     *wasmWrapper     // Wrapper around the WASM hook arguments
 
 	pcValue    *uint   // Swappable pc value wrapped by a log accessor
 	gasValue   *uint   // Swappable gas value wrapped by a log accessor
@@ -296,6 +366,8 @@ type Tracer struct {
 	depthValue *uint   // Swappable depth value wrapped by a log accessor
 	errorValue *string // Swappable error value wrapped by a log accessor
 
+	wasmHooks map[string]bool // Optional WASM hooks exposed by the tracer object
+
 	ctx map[string]interface{} // Transaction context gathered throughout execution
 	err error                  // Error, if one has occurred
 
@@ -319,6 +391,8 @@ func New(code string) (*Tracer, error) {
 		memoryWrapper:   new(memoryWrapper),
 		contractWrapper: new(contractWrapper),
 		dbWrapper:       new(dbWrapper),
+		wasmWrapper:     new(wasmWrapper),
+		wasmHooks:       make(map[string]bool),
 		pcValue:         new(uint),
 		gasValue:        new(uint),
 		costValue:       new(uint),
@@ -409,6 +483,12 @@ func New(code string) (*Tracer, error) {
 	}
 	tracer.vm.Pop()
 
+	// The WASM hooks are optional, the tracer sees no WASM execution without them
+	for _, hook := range wasmHookNames {
+		tracer.wasmHooks[hook] = tracer.vm.GetPropString(tracer.tracerObject, hook)
+		tracer.vm.Pop()
+	}
+
 	// Tracer is valid, inject the big int library to access large numbers
 	tracer.vm.EvalString(bigIntegerJS)
 	tracer.vm.PutGlobalString("bigInt")
@@ -454,6 +534,34 @@ func New(code string) (*Tracer, error) {
 
 	tracer.vm.PutPropString(tracer.stateObject, "log")
 
+	wasmObject := tracer.vm.PushObject()
+
+	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushString(tracer.wasmWrapper.op); return 1 })
+	tracer.vm.PutPropString(wasmObject, "getOp")
+
+	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushString(tracer.wasmWrapper.function); return 1 })
+	tracer.vm.PutPropString(wasmObject, "getFunction")
+
+	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushNumber(float64(tracer.wasmWrapper.ret)); return 1 })
+	tracer.vm.PutPropString(wasmObject, "getReturn")
+
+	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushUint(*tracer.gasValue); return 1 })
+	tracer.vm.PutPropString(wasmObject, "getGas")
+
+	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushUint(*tracer.costValue); return 1 })
+	tracer.vm.PutPropString(wasmObject, "getCost")
+
+	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushUint(*tracer.depthValue); return 1 })
+	tracer.vm.PutPropString(wasmObject, "getDepth")
+
+	tracer.contractWrapper.pushObject(tracer.vm)
+	tracer.vm.PutPropString(wasmObject, "contract")
+
+	tracer.wasmWrapper.pushHostObject(tracer.vm)
+	tracer.vm.PutPropString(wasmObject, "host")
+
+	tracer.vm.PutPropString(tracer.stateObject, "wasm")
+
 	tracer.dbWrapper.pushObject(tracer.vm)
 	tracer.vm.PutPropString(tracer.stateObject, "db")
 
@@ -557,6 +665,76 @@ func (jst *Tracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 	return nil
 }
 
+// wasmHookNames are the optional methods of the tracer object called with the
+// WASM execution, in the order of the WasmTracer hooks.
+var wasmHookNames = []string{"wasmEnter", "wasmExit", "wasmHostCall", "wasmHostReturn", "wasmGas"}
+
+// captureWasm calls the WASM hook of the tracer object if it's exposed, the
+// arguments are set in the wasm wrapper by the caller.
+func (jst *Tracer) captureWasm(hook string, env *vm.EVM, contract *vm.Contract, gas, cost uint64, depth int) {
+	if jst.err != nil || !jst.wasmHooks[hook] {
+		return
+	}
+	// Initialize the context if it wasn't done yet
+	if !jst.inited {
+		jst.ctx["block"] = env.BlockNumber.Uint64()
+		jst.inited = true
+	}
+	// If tracing was interrupted, set the error and stop
+	if atomic.LoadUint32(&jst.interrupt) > 0 {
+		jst.err = jst.reason
+		return
+	}
+	jst.contractWrapper.contract = contract
+	jst.dbWrapper.db = env.StateDB
+
+	*jst.gasValue = uint(gas)
+	*jst.costValue = uint(cost)
+	*jst.depthValue = uint(depth)
+
+	if _, err := jst.call(hook, "wasm", "db"); err != nil {
+		jst.err = wrapError(hook, err)
+	}
+}
+
+// CaptureWasmEnter implements the WasmTracer interface to trace a function entry.
+func (jst *Tracer) CaptureWasmEnter(env *vm.EVM, contract *vm.Contract, function string, gas uint64, depth int) error {
+	jst.wasmWrapper.op, jst.wasmWrapper.function, jst.wasmWrapper.ret = vm.WasmEnter, function, 0
+	jst.captureWasm("wasmEnter", env, contract, gas, 0, depth)
+	return nil
+}
+
+// CaptureWasmExit implements the WasmTracer interface to trace a function exit.
+func (jst *Tracer) CaptureWasmExit(env *vm.EVM, contract *vm.Contract, function string, ret int64, gas uint64, depth int) error {
+	jst.wasmWrapper.op, jst.wasmWrapper.function, jst.wasmWrapper.ret = vm.WasmExit, function, ret
+	jst.captureWasm("wasmExit", env, contract, gas, 0, depth)
+	return nil
+}
+
+// CaptureWasmHostCall implements the WasmTracer interface to trace a host call
+// before the host function is invoked.
+func (jst *Tracer) CaptureWasmHostCall(env *vm.EVM, contract *vm.Contract, call *vm.WasmHostCall, gas uint64, depth int) error {
+	jst.wasmWrapper.op, jst.wasmWrapper.call, jst.wasmWrapper.ret = vm.WasmHost, call, 0
+	jst.captureWasm("wasmHostCall", env, contract, gas, 0, depth)
+	return nil
+}
+
+// CaptureWasmHostReturn implements the WasmTracer interface to trace the results
+// of a host call.
+func (jst *Tracer) CaptureWasmHostReturn(env *vm.EVM, contract *vm.Contract, call *vm.WasmHostCall, gas uint64, depth int) error {
+	jst.wasmWrapper.op, jst.wasmWrapper.call, jst.wasmWrapper.ret = vm.WasmHost, call, call.Return
+	jst.captureWasm("wasmHostReturn", env, contract, gas, 0, depth)
+	return nil
+}
+
+// CaptureWasmGas implements the WasmTracer interface to trace the gas of a WASM
+// instruction, the op of the wasm object is the instruction.
+func (jst *Tracer) CaptureWasmGas(env *vm.EVM, contract *vm.Contract, op string, gas, cost uint64, depth int) error {
+	jst.wasmWrapper.op = op
+	jst.captureWasm("wasmGas", env, contract, gas, cost, depth)
+	return nil
+}
+
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (jst *Tracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
 	jst.ctx["output"] = output
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/params"
 )
@@ -140,3 +141,46 @@ func TestHaltBetweenSteps(t *testing.T) {
 		t.Errorf("Expected timeout error, got %v", err)
 	}
 }
+
+func TestWasmCallTracer(t *testing.T) {
+	tracer, err := New("wasmCallTracer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1)}, nil, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	contract := vm.NewContract(account{}, account{}, big.NewInt(0), 10000)
+	callee := common.HexToAddress("0x0b")
+
+	tracer.CaptureStart(common.Address{}, common.Address{}, false, []byte{0x1}, 10000, big.NewInt(0))
+	tracer.CaptureWasmEnter(env, contract, "invoke", 10000, 1)
+	tracer.CaptureWasmGas(env, contract, "I32Const", 9999, 1, 1)
+
+	event := &vm.WasmHostCall{Name: "emitEvent", Topic: []byte("topic"), Data: []byte{0x2}}
+	tracer.CaptureWasmHostCall(env, contract, event, 9900, 1)
+	tracer.CaptureWasmHostReturn(env, contract, event, 9890, 1)
+
+	call := &vm.WasmHostCall{Name: "platonCallBytes", To: &callee, Input: []byte{0x3}}
+	tracer.CaptureWasmHostCall(env, contract, call, 9800, 1)
+	tracer.CaptureWasmEnter(env, contract, "invoke", 9000, 2)
+	tracer.CaptureWasmExit(env, contract, "invoke", 0, 8500, 2)
+	call.Output, call.Return = []byte{0x4}, 1
+	tracer.CaptureWasmHostReturn(env, contract, call, 8000, 1)
+
+	transfer := &vm.WasmHostCall{Name: "callTransfer", To: &callee, Amount: (*hexutil.Big)(big.NewInt(16)), Return: 1}
+	tracer.CaptureWasmHostCall(env, contract, transfer, 7900, 1)
+	tracer.CaptureWasmHostReturn(env, contract, transfer, 7800, 1)
+
+	tracer.CaptureWasmExit(env, contract, "invoke", 0, 7000, 1)
+	tracer.CaptureEnd([]byte{0x5}, 3000, time.Second, nil)
+
+	ret, err := tracer.GetResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"CALL","from":"0x0000000000000000000000000000000000000000","to":"0x0000000000000000000000000000000000000000","value":"0x0","gas":"0x2710","gasUsed":"0xbb8","input":"0x01","output":"0x05","func":"invoke","events":[{"topic":"0x746f706963","data":"0x02"}],"time":"1s","calls":[` +
+		`{"type":"CALL","from":"0x0000000000000000000000000000000000000000","to":"0x000000000000000000000000000000000000000b","gas":"0x2328","gasUsed":"0x708","input":"0x03","output":"0x04","func":"invoke"},` +
+		`{"type":"TRANSFER","from":"0x0000000000000000000000000000000000000000","to":"0x000000000000000000000000000000000000000b","value":"0x10","error":"transfer failed"}]}`
+	if string(ret) != want {
+		t.Errorf("trace mismatch:\nhave %s\nwant %s", ret, want)
+	}
+}
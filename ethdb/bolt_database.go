@@ -0,0 +1,646 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
+)
+
+const (
+	// boltFileName is the name of the bolt file in the database directory, the
+	// directory is kept so the other files of a database (e.g. the ancient store
+	// of the chain database) live next to it with both engines.
+	boltFileName = "bolt.db"
+
+	// boltIteratorChunk is the number of the items an iterator reads in one
+	// transaction.
+	boltIteratorChunk = 256
+)
+
+var (
+	// boltBucket is the bucket holding all the items of a database.
+	boltBucket = []byte("platon")
+
+	errBoltReleased = errors.New("bolt: snapshot released")
+)
+
+// BoltDatabase is a database stored by bbolt, a pure Go B+tree engine with a
+// single writer and lock-free readers.
+//
+// Bolt is the second engine because goleveldb is the only vendored LSM engine,
+// pebble and badger are not. It writes slower than leveldb (a Put is about ten
+// times slower, a batch about twice, see the benchmarks of the stores), and it
+// reads about as fast, but it keeps all the data in one file and never stalls
+// the writes for a compaction, so it suits the read-mostly databases.
+//
+// Every write is flushed to the disk when it's committed, since bolt has no
+// journal to recover a file from a crash in the middle of an unflushed commit.
+// So every Put and Delete pays a sync, the writers should use batches, and the
+// sync writes are the same as the other ones.
+//
+// Bolt can't compact the file in place, the freed pages are reused by the later
+// writes instead, so Compact returns ErrCompactionNotSupported.
+type BoltDatabase struct {
+	fn string   // filename for reporting
+	db *bolt.DB // Bolt instance
+
+	diskSizeGauge metrics.Gauge // Gauge for tracking the size of the bolt file
+	readTxMeter   metrics.Meter // Meter for measuring the read transactions
+	writeTxMeter  metrics.Meter // Meter for measuring the write transactions
+
+	quitLock sync.Mutex      // Mutex protecting the quit channel access
+	quitChan chan chan error // Quit channel to stop the metrics collection before closing the database
+
+	log log.Logger // Contextual logger tracking the database path
+}
+
+// NewBoltDatabase opens the bolt database in the directory, creating it if it
+// doesn't exist and the database isn't opened in read-only mode.
+func NewBoltDatabase(file string, readOnly bool) (*BoltDatabase, error) {
+	logger := log.New("database", file)
+
+	if !readOnly {
+		if err := os.MkdirAll(file, 0755); err != nil {
+			return nil, err
+		}
+	}
+	options := &bolt.Options{
+		Timeout:      time.Second,
+		ReadOnly:     readOnly,
+		FreelistType: bolt.FreelistMapType,
+	}
+	// The remapping of a growing file waits for the open read transactions, map
+	// enough space up front on 64 bit systems. Windows grows the file to the map
+	// size, so it's left to bolt there.
+	if strconv.IntSize == 64 && runtime.GOOS != "windows" {
+		options.InitialMmapSize = 1 << 30
+	}
+	db, err := bolt.Open(filepath.Join(file, boltFileName), 0644, options)
+	if err != nil {
+		return nil, err
+	}
+	if readOnly {
+		err = db.View(func(tx *bolt.Tx) error {
+			if tx.Bucket(boltBucket) == nil {
+				return fmt.Errorf("bolt: bucket %s is missing", boltBucket)
+			}
+			return nil
+		})
+	} else {
+		err = db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(boltBucket)
+			return err
+		})
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	logger.Info("Opened bolt database", "readonly", readOnly)
+	return &BoltDatabase{
+		fn:  file,
+		db:  db,
+		log: logger,
+	}, nil
+}
+
+// update commits the writes in one transaction, the commit flushes them to the disk.
+func (db *BoltDatabase) update(fn func(*bolt.Bucket) error) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(boltBucket))
+	})
+}
+
+// Engine returns the name of the engine of the database.
+func (db *BoltDatabase) Engine() string {
+	return EngineBolt
+}
+
+// Path returns the path to the database directory.
+func (db *BoltDatabase) Path() string {
+	return db.fn
+}
+
+// Put puts the given key / value into the database.
+func (db *BoltDatabase) Put(key []byte, value []byte) error {
+	return db.update(func(bucket *bolt.Bucket) error {
+		return bucket.Put(key, value)
+	})
+}
+
+// PutSync puts the given key / value into the database, it's the same as Put
+// since every commit is flushed to the disk.
+func (db *BoltDatabase) PutSync(key []byte, value []byte) error {
+	return db.Put(key, value)
+}
+
+// Has returns whether the key is present in the database.
+func (db *BoltDatabase) Has(key []byte) (bool, error) {
+	var has bool
+	err := db.db.View(func(tx *bolt.Tx) error {
+		_, has = boltGet(tx.Bucket(boltBucket), key)
+		return nil
+	})
+	return has, err
+}
+
+// Get returns the given key if it's present.
+func (db *BoltDatabase) Get(key []byte) ([]byte, error) {
+	var (
+		value []byte
+		has   bool
+	)
+	err := db.db.View(func(tx *bolt.Tx) error {
+		value, has = boltGet(tx.Bucket(boltBucket), key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// Delete deletes the key from the database.
+func (db *BoltDatabase) Delete(key []byte) error {
+	return db.update(func(bucket *bolt.Bucket) error {
+		return bucket.Delete(key)
+	})
+}
+
+func (db *BoltDatabase) NewIterator() iterator.Iterator {
+	return db.NewIteratorWithRange(nil)
+}
+
+// NewIteratorWithPrefix returns a iterator to iterate over subset of database content with a particular prefix.
+func (db *BoltDatabase) NewIteratorWithPrefix(prefix []byte) iterator.Iterator {
+	return db.NewIteratorWithRange(util.BytesPrefix(prefix))
+}
+
+// NewIteratorWithStart returns a iterator to iterate over subset of database content starting at a particular initial key.
+func (db *BoltDatabase) NewIteratorWithStart(start []byte) iterator.Iterator {
+	return db.NewIteratorWithRange(&util.Range{Start: start})
+}
+
+// NewIteratorWithRange returns a iterator to iterate over the key range of the
+// database content. The iterator reads the items in chunks of short transactions
+// to never hold back the writers, so the chunks may reflect the later writes.
+func (db *BoltDatabase) NewIteratorWithRange(slice *util.Range) iterator.Iterator {
+	return newBoltIterator(func(fn func(*bolt.Bucket) error) error {
+		return db.db.View(func(tx *bolt.Tx) error {
+			return fn(tx.Bucket(boltBucket))
+		})
+	}, slice)
+}
+
+// NewSnapshot returns a frozen view of the current state of the database, the
+// snapshot holds a read transaction until it's released. The open transaction
+// blocks the writers which grow the file and keeps the pages it reads from being
+// reused, so the snapshot must be short-lived.
+func (db *BoltDatabase) NewSnapshot() (Snapshot, error) {
+	tx, err := db.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &boltSnapshot{tx: tx}, nil
+}
+
+// Stat returns the statistics of bolt for the property bolt.stats.
+func (db *BoltDatabase) Stat(property string) (string, error) {
+	if property != "bolt.stats" {
+		return "", fmt.Errorf("bolt: unknown property %s", property)
+	}
+	stats := db.db.Stats()
+	return fmt.Sprintf("Read txs: %d (open %d)\nFree pages: %d, pending pages: %d, freelist: %d bytes\n"+
+		"Write txs: %d, pages written: %d, page allocs: %d bytes\nWrite time: %v, spill time: %v, rebalance time: %v\n",
+		stats.TxN, stats.OpenTxN, stats.FreePageN, stats.PendingPageN, stats.FreelistInuse,
+		stats.TxStats.Write, stats.TxStats.PageCount, stats.TxStats.PageAlloc,
+		stats.TxStats.WriteTime, stats.TxStats.SpillTime, stats.TxStats.RebalanceTime), nil
+}
+
+// Compact returns ErrCompactionNotSupported, bolt reuses the freed pages instead
+// of compacting them, so the file never shrinks. The file can be rewritten by
+// converting the database to leveldb and back.
+func (db *BoltDatabase) Compact(start []byte, limit []byte) error {
+	return ErrCompactionNotSupported
+}
+
+func (db *BoltDatabase) Close() {
+	// Stop the metrics collection to avoid internal database races
+	db.quitLock.Lock()
+	defer db.quitLock.Unlock()
+
+	if db.quitChan != nil {
+		errc := make(chan error)
+		db.quitChan <- errc
+		<-errc
+		db.quitChan = nil
+	}
+	err := db.db.Close()
+	if err == nil {
+		db.log.Info("Database closed")
+	} else {
+		db.log.Error("Failed to close database", "err", err)
+	}
+}
+
+// Meter configures the database metrics collectors.
+func (db *BoltDatabase) Meter(prefix string) {
+	db.diskSizeGauge = metrics.NewRegisteredGauge(prefix+"disk/size", nil)
+	db.readTxMeter = metrics.NewRegisteredMeter(prefix+"tx/read", nil)
+	db.writeTxMeter = metrics.NewRegisteredMeter(prefix+"tx/write", nil)
+
+	// Create a quit channel for the periodic collector and run it
+	db.quitLock.Lock()
+	db.quitChan = make(chan chan error)
+	db.quitLock.Unlock()
+
+	go db.meter(3 * time.Second)
+}
+
+// meter periodically retrieves the bolt statistics and reports them to the
+// metrics subsystem.
+func (db *BoltDatabase) meter(refresh time.Duration) {
+	prev := db.db.Stats()
+	for {
+		stats := db.db.Stats()
+		diff := stats.Sub(&prev)
+		db.readTxMeter.Mark(int64(diff.TxN))
+		db.writeTxMeter.Mark(int64(diff.TxStats.Write))
+		if info, err := os.Stat(filepath.Join(db.fn, boltFileName)); err == nil {
+			db.diskSizeGauge.Update(info.Size())
+		}
+		prev = stats
+
+		select {
+		case errc := <-db.quitChan:
+			errc <- nil
+			return
+		case <-time.After(refresh):
+		}
+	}
+}
+
+func (db *BoltDatabase) NewBatch() Batch {
+	return &boltBatch{db: db}
+}
+
+// NewSyncBatch creates a batch, it's the same as NewBatch since every commit is
+// flushed to the disk.
+func (db *BoltDatabase) NewSyncBatch() Batch {
+	return db.NewBatch()
+}
+
+type boltOp struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// boltBatch buffers the writes and commits them in one transaction.
+type boltBatch struct {
+	db   *BoltDatabase
+	ops  []boltOp
+	size int
+}
+
+func (b *boltBatch) Put(key, value []byte) error {
+	b.ops = append(b.ops, boltOp{key: common.CopyBytes(key), value: common.CopyBytes(value)})
+	b.size += len(value)
+	return nil
+}
+
+func (b *boltBatch) Delete(key []byte) error {
+	b.ops = append(b.ops, boltOp{key: common.CopyBytes(key), delete: true})
+	b.size += 1
+	return nil
+}
+
+func (b *boltBatch) Write() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	return b.db.update(func(bucket *bolt.Bucket) error {
+		for _, op := range b.ops {
+			var err error
+			if op.delete {
+				err = bucket.Delete(op.key)
+			} else {
+				err = bucket.Put(op.key, op.value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *boltBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *boltBatch) Reset() {
+	b.ops = b.ops[:0]
+	b.size = 0
+}
+
+// boltSnapshot is a read transaction, the transaction isn't safe for concurrent
+// use so the accesses are serialized.
+type boltSnapshot struct {
+	tx   *bolt.Tx
+	lock sync.Mutex
+}
+
+func (s *boltSnapshot) view(fn func(*bolt.Bucket) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.tx == nil {
+		return errBoltReleased
+	}
+	return fn(s.tx.Bucket(boltBucket))
+}
+
+func (s *boltSnapshot) Get(key []byte) ([]byte, error) {
+	var (
+		value []byte
+		has   bool
+	)
+	err := s.view(func(bucket *bolt.Bucket) error {
+		value, has = boltGet(bucket, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *boltSnapshot) Has(key []byte) (bool, error) {
+	var has bool
+	err := s.view(func(bucket *bolt.Bucket) error {
+		_, has = boltGet(bucket, key)
+		return nil
+	})
+	return has, err
+}
+
+func (s *boltSnapshot) NewIterator(slice *util.Range) iterator.Iterator {
+	return newBoltIterator(s.view, slice)
+}
+
+func (s *boltSnapshot) Release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.tx != nil {
+		s.tx.Rollback()
+		s.tx = nil
+	}
+}
+
+// boltGet returns a copy of the value of the key, the cursor is used instead of
+// Bucket.Get to tell an empty value from a missing key.
+func boltGet(bucket *bolt.Bucket, key []byte) ([]byte, bool) {
+	k, v := bucket.Cursor().Seek(key)
+	if k == nil || !bytes.Equal(k, key) {
+		return nil, false
+	}
+	return common.CopyBytes(v), true
+}
+
+const (
+	boltIterSOI = iota // before the first item
+	boltIterEOI        // after the last item
+	boltIterValid
+)
+
+// boltIterator iterates the items in the range in chunks, every chunk is read in
+// one call of the view function and kept in memory in ascending order.
+type boltIterator struct {
+	view  func(fn func(*bolt.Bucket) error) error
+	slice *util.Range
+
+	keys   [][]byte
+	values [][]byte
+	pos    int
+	state  int
+
+	err      error
+	released bool
+	releaser util.Releaser
+}
+
+func newBoltIterator(view func(fn func(*bolt.Bucket) error) error, slice *util.Range) *boltIterator {
+	if slice == nil {
+		slice = &util.Range{}
+	}
+	return &boltIterator{view: view, slice: slice, state: boltIterSOI}
+}
+
+func (it *boltIterator) inLimit(key []byte) bool {
+	return it.slice.Limit == nil || bytes.Compare(key, it.slice.Limit) < 0
+}
+
+func (it *boltIterator) inStart(key []byte) bool {
+	return it.slice.Start == nil || bytes.Compare(key, it.slice.Start) >= 0
+}
+
+// fillForward reads the chunk from the key, a nil key reads from the start of
+// the range.
+func (it *boltIterator) fillForward(from []byte, inclusive bool) bool {
+	it.keys, it.values = it.keys[:0], it.values[:0]
+	it.err = it.view(func(bucket *bolt.Bucket) error {
+		c := bucket.Cursor()
+		var k, v []byte
+		switch {
+		case from == nil && it.slice.Start == nil:
+			k, v = c.First()
+		case from == nil:
+			k, v = c.Seek(it.slice.Start)
+		default:
+			if k, v = c.Seek(from); !inclusive && k != nil && bytes.Equal(k, from) {
+				k, v = c.Next()
+			}
+		}
+		for ; k != nil && it.inLimit(k) && len(it.keys) < boltIteratorChunk; k, v = c.Next() {
+			it.keys, it.values = append(it.keys, common.CopyBytes(k)), append(it.values, common.CopyBytes(v))
+		}
+		return nil
+	})
+	if it.err != nil || len(it.keys) == 0 {
+		it.state = boltIterEOI
+		return false
+	}
+	it.pos, it.state = 0, boltIterValid
+	return true
+}
+
+// fillBackward reads the chunk before the key, a nil key reads from the end of
+// the range.
+func (it *boltIterator) fillBackward(before []byte) bool {
+	it.keys, it.values = it.keys[:0], it.values[:0]
+	it.err = it.view(func(bucket *bolt.Bucket) error {
+		c := bucket.Cursor()
+		if before == nil {
+			before = it.slice.Limit
+		}
+		var k, v []byte
+		if before == nil {
+			k, v = c.Last()
+		} else if k, v = c.Seek(before); k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		for ; k != nil && it.inStart(k) && len(it.keys) < boltIteratorChunk; k, v = c.Prev() {
+			it.keys, it.values = append(it.keys, common.CopyBytes(k)), append(it.values, common.CopyBytes(v))
+		}
+		return nil
+	})
+	if it.err != nil || len(it.keys) == 0 {
+		it.state = boltIterSOI
+		return false
+	}
+	for i, j := 0, len(it.keys)-1; i < j; i, j = i+1, j-1 {
+		it.keys[i], it.keys[j] = it.keys[j], it.keys[i]
+		it.values[i], it.values[j] = it.values[j], it.values[i]
+	}
+	it.pos, it.state = len(it.keys)-1, boltIterValid
+	return true
+}
+
+func (it *boltIterator) First() bool {
+	if it.released || it.err != nil {
+		return false
+	}
+	return it.fillForward(nil, true)
+}
+
+func (it *boltIterator) Last() bool {
+	if it.released || it.err != nil {
+		return false
+	}
+	return it.fillBackward(nil)
+}
+
+func (it *boltIterator) Seek(key []byte) bool {
+	if it.released || it.err != nil {
+		return false
+	}
+	if !it.inStart(key) {
+		key = it.slice.Start
+	}
+	return it.fillForward(key, true)
+}
+
+func (it *boltIterator) Next() bool {
+	if it.released || it.err != nil {
+		return false
+	}
+	switch it.state {
+	case boltIterSOI:
+		return it.First()
+	case boltIterEOI:
+		return false
+	}
+	if it.pos++; it.pos < len(it.keys) {
+		return true
+	}
+	return it.fillForward(it.keys[len(it.keys)-1], false)
+}
+
+func (it *boltIterator) Prev() bool {
+	if it.released || it.err != nil {
+		return false
+	}
+	switch it.state {
+	case boltIterSOI:
+		return false
+	case boltIterEOI:
+		return it.Last()
+	}
+	if it.pos--; it.pos >= 0 {
+		return true
+	}
+	return it.fillBackward(it.keys[0])
+}
+
+func (it *boltIterator) Valid() bool {
+	return !it.released && it.state == boltIterValid
+}
+
+func (it *boltIterator) Key() []byte {
+	if !it.Valid() {
+		return nil
+	}
+	return it.keys[it.pos]
+}
+
+func (it *boltIterator) Value() []byte {
+	if !it.Valid() {
+		return nil
+	}
+	return it.values[it.pos]
+}
+
+func (it *boltIterator) Error() error {
+	if it.released {
+		return iterator.ErrIterReleased
+	}
+	return it.err
+}
+
+func (it *boltIterator) SetReleaser(releaser util.Releaser) {
+	if !it.released {
+		it.releaser = releaser
+	}
+}
+
+func (it *boltIterator) Release() {
+	if it.released {
+		return
+	}
+	it.released = true
+	it.keys, it.values = nil, nil
+	if it.releaser != nil {
+		it.releaser.Release()
+		it.releaser = nil
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethdb_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+)
+
+func newTestStore(t testing.TB, engine string) (ethdb.Store, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "ethdb_test_")
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	db, err := ethdb.OpenStore(engine, dirname, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
+func fillStore(t testing.TB, db ethdb.Store, n int) {
+	batch := db.NewBatch()
+	for i := 0; i < n; i++ {
+		if err := batch.Put([]byte(fmt.Sprintf("key%04d", i)), []byte(fmt.Sprintf("value%d", i))); err != nil {
+			t.Fatalf("batch put failed: %v", err)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("batch write failed: %v", err)
+	}
+}
+
+func collect(it iterator.Iterator) []string {
+	defer it.Release()
+	var keys []string
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	return keys
+}
+
+// Tests that the stores of all the engines behave the same.
+func TestStoreEngines(t *testing.T) {
+	for _, engine := range ethdb.Engines {
+		t.Run(engine, func(t *testing.T) { testStore(t, engine) })
+	}
+}
+
+func testStore(t *testing.T, engine string) {
+	db, remove := newTestStore(t, engine)
+	defer remove()
+
+	if db.Engine() != engine {
+		t.Fatalf("engine mismatch: have %s, want %s", db.Engine(), engine)
+	}
+	// The chunked bolt iterator must cross the chunk boundaries
+	fillStore(t, db, 1000)
+
+	if value, err := db.Get([]byte("key0010")); err != nil || string(value) != "value10" {
+		t.Fatalf("get mismatch: have %q, %v", value, err)
+	}
+	if _, err := db.Get([]byte("key")); err != ethdb.ErrNotFound {
+		t.Fatalf("get error mismatch: have %v, want %v", err, ethdb.ErrNotFound)
+	}
+	if err := db.Delete([]byte("key0010")); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if has, _ := db.Has([]byte("key0010")); has {
+		t.Fatal("deleted key is found")
+	}
+	if err := db.PutSync([]byte("key0010"), []byte("sync")); err != nil {
+		t.Fatalf("sync put failed: %v", err)
+	}
+
+	if keys := collect(db.NewIterator()); len(keys) != 1000 {
+		t.Fatalf("iterated keys mismatch: have %d, want 1000", len(keys))
+	}
+	if keys := collect(db.NewIteratorWithPrefix([]byte("key012"))); len(keys) != 10 || keys[0] != "key0120" {
+		t.Fatalf("prefix keys mismatch: have %v", keys)
+	}
+	if keys := collect(db.NewIteratorWithStart([]byte("key0995"))); len(keys) != 5 {
+		t.Fatalf("start keys mismatch: have %v", keys)
+	}
+	it := db.NewIteratorWithRange(&util.Range{Start: []byte("key0100"), Limit: []byte("key0900")})
+	if !it.Last() || string(it.Key()) != "key0899" {
+		t.Fatalf("last key mismatch: have %s", it.Key())
+	}
+	for i := 898; i >= 100; i-- {
+		if !it.Prev() || string(it.Key()) != fmt.Sprintf("key%04d", i) {
+			t.Fatalf("prev key %d mismatch: have %s", i, it.Key())
+		}
+	}
+	if it.Prev() {
+		t.Fatalf("iterated before the start: %s", it.Key())
+	}
+	if !it.Seek([]byte("key0500x")) || string(it.Key()) != "key0501" || string(it.Value()) != "value501" {
+		t.Fatalf("seek mismatch: have %s=%s", it.Key(), it.Value())
+	}
+	it.Release()
+
+	// Bolt can't compact the file in place
+	want := error(nil)
+	if engine == ethdb.EngineBolt {
+		want = ethdb.ErrCompactionNotSupported
+	}
+	if err := db.Compact(nil, nil); err != want {
+		t.Fatalf("compact error mismatch: have %v, want %v", err, want)
+	}
+
+	// The snapshot must not see the later writes
+	snapshot, err := db.NewSnapshot()
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+	db.Put([]byte("key9999"), []byte("new"))
+	db.Delete([]byte("key0000"))
+	if has, _ := snapshot.Has([]byte("key9999")); has {
+		t.Fatal("snapshot sees the later insertion")
+	}
+	if value, err := snapshot.Get([]byte("key0000")); err != nil || !bytes.Equal(value, []byte("value0")) {
+		t.Fatalf("snapshot get mismatch: have %q, %v", value, err)
+	}
+	if keys := collect(snapshot.NewIterator(nil)); len(keys) != 1000 || keys[0] != "key0000" {
+		t.Fatalf("snapshot keys mismatch: have %d", len(keys))
+	}
+	snapshot.Release()
+}
+
+// Tests that a store is converted to all the other engines without losing data
+// and the subdirectories are kept.
+func TestConvertStore(t *testing.T) {
+	for _, from := range ethdb.Engines {
+		for _, to := range ethdb.Engines {
+			if from == to {
+				continue
+			}
+			db, remove := newTestStore(t, from)
+			fillStore(t, db, 500)
+			dir := db.Path()
+			db.Close()
+			os.Mkdir(filepath.Join(dir, "ancient"), 0755)
+
+			if _, err := ethdb.OpenStore(to, dir, 0, 0); err == nil {
+				t.Fatalf("%s database is opened by %s", from, to)
+			}
+			if copied, err := ethdb.ConvertStore(dir, to); err != nil || copied != 500 {
+				t.Fatalf("%s to %s: copied mismatch: have %d, %v, want 500", from, to, copied, err)
+			}
+			if engine := ethdb.DetectEngine(dir); engine != to {
+				t.Fatalf("%s to %s: detected engine mismatch: have %s", from, to, engine)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "ancient")); err != nil {
+				t.Fatalf("%s to %s: subdirectory is lost: %v", from, to, err)
+			}
+			converted, err := ethdb.OpenStore("", dir, 0, 0)
+			if err != nil {
+				t.Fatalf("%s to %s: failed to open converted database: %v", from, to, err)
+			}
+			if keys := collect(converted.NewIterator()); len(keys) != 500 {
+				t.Fatalf("%s to %s: keys mismatch: have %d, want 500", from, to, len(keys))
+			}
+			converted.Close()
+			remove()
+		}
+	}
+}
+
+func benchmarkStores(b *testing.B, fn func(b *testing.B, db ethdb.Store)) {
+	for _, engine := range ethdb.Engines {
+		b.Run(engine, func(b *testing.B) {
+			db, remove := newTestStore(b, engine)
+			defer remove()
+			fn(b, db)
+		})
+	}
+}
+
+func BenchmarkStorePut(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, db ethdb.Store) {
+		value := bytes.Repeat([]byte{0x01}, 128)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := db.Put([]byte(fmt.Sprintf("key%08d", i)), value); err != nil {
+				b.Fatalf("put failed: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkStoreBatchWrite(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, db ethdb.Store) {
+		value := bytes.Repeat([]byte{0x01}, 128)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			batch := db.NewBatch()
+			for j := 0; j < 100; j++ {
+				batch.Put([]byte(fmt.Sprintf("key%08d%03d", i, j)), value)
+			}
+			if err := batch.Write(); err != nil {
+				b.Fatalf("batch write failed: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkStoreGet(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, db ethdb.Store) {
+		fillStore(b, db, 10000)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := db.Get([]byte(fmt.Sprintf("key%04d", i%10000))); err != nil {
+				b.Fatalf("get failed: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkStoreIterate(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, db ethdb.Store) {
+		fillStore(b, db, 10000)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			it := db.NewIteratorWithRange(nil)
+			for it.Next() {
+			}
+			it.Release()
+		}
+	})
+}
@@ -95,6 +95,24 @@ func NewLDBDatabase(file string, cache int, handles int) (*LDBDatabase, error) {
 	}, nil
 }
 
+// NewReadOnlyLDBDatabase opens the existing LevelDB in read-only mode.
+func NewReadOnlyLDBDatabase(file string) (*LDBDatabase, error) {
+	db, err := leveldb.OpenFile(file, &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		return nil, err
+	}
+	return &LDBDatabase{
+		fn:  file,
+		db:  db,
+		log: log.New("database", file),
+	}, nil
+}
+
+// Engine returns the name of the engine of the database.
+func (db *LDBDatabase) Engine() string {
+	return EngineLevelDB
+}
+
 // Path returns the path to the database directory.
 func (db *LDBDatabase) Path() string {
 	return db.fn
@@ -105,6 +123,11 @@ func (db *LDBDatabase) Put(key []byte, value []byte) error {
 	return db.db.Put(key, value, nil)
 }
 
+// PutSync puts the given key / value and flushes the write to the disk.
+func (db *LDBDatabase) PutSync(key []byte, value []byte) error {
+	return db.db.Put(key, value, &opt.WriteOptions{Sync: true})
+}
+
 func (db *LDBDatabase) Has(key []byte) (bool, error) {
 	return db.db.Has(key, nil)
 }
@@ -132,6 +155,36 @@ func (db *LDBDatabase) NewIteratorWithPrefix(prefix []byte) iterator.Iterator {
 	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
 }
 
+// NewIteratorWithStart returns a iterator to iterate over subset of database content starting at a particular initial key.
+func (db *LDBDatabase) NewIteratorWithStart(start []byte) iterator.Iterator {
+	return db.db.NewIterator(&util.Range{Start: start}, nil)
+}
+
+// NewIteratorWithRange returns a iterator to iterate over the key range of the database content.
+func (db *LDBDatabase) NewIteratorWithRange(slice *util.Range) iterator.Iterator {
+	return db.db.NewIterator(slice, nil)
+}
+
+// NewSnapshot returns a frozen view of the current state of the database.
+func (db *LDBDatabase) NewSnapshot() (Snapshot, error) {
+	snapshot, err := db.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &ldbSnapshot{snapshot: snapshot}, nil
+}
+
+// Stat returns the leveldb property, e.g. leveldb.stats or leveldb.iostats.
+func (db *LDBDatabase) Stat(property string) (string, error) {
+	return db.db.GetProperty(property)
+}
+
+// Compact flattens the underlying data store for the given key range, a nil start
+// is treated as a key before all keys and a nil limit as a key after all keys.
+func (db *LDBDatabase) Compact(start []byte, limit []byte) error {
+	return db.db.CompactRange(util.Range{Start: start, Limit: limit})
+}
+
 func (db *LDBDatabase) Close() {
 	// Stop the metrics collection to avoid internal database races
 	db.quitLock.Lock()
@@ -379,9 +432,15 @@ func (db *LDBDatabase) NewBatch() Batch {
 	return &ldbBatch{db: db.db, b: new(leveldb.Batch)}
 }
 
+// NewSyncBatch returns a batch which is flushed to the disk when written.
+func (db *LDBDatabase) NewSyncBatch() Batch {
+	return &ldbBatch{db: db.db, b: new(leveldb.Batch), wo: &opt.WriteOptions{Sync: true}}
+}
+
 type ldbBatch struct {
 	db   *leveldb.DB
 	b    *leveldb.Batch
+	wo   *opt.WriteOptions
 	size int
 }
 
@@ -398,7 +457,7 @@ func (b *ldbBatch) Delete(key []byte) error {
 }
 
 func (b *ldbBatch) Write() error {
-	return b.db.Write(b.b, nil)
+	return b.db.Write(b.b, b.wo)
 }
 
 func (b *ldbBatch) ValueSize() int {
@@ -410,6 +469,26 @@ func (b *ldbBatch) Reset() {
 	b.size = 0
 }
 
+type ldbSnapshot struct {
+	snapshot *leveldb.Snapshot
+}
+
+func (s *ldbSnapshot) Get(key []byte) ([]byte, error) {
+	return s.snapshot.Get(key, nil)
+}
+
+func (s *ldbSnapshot) Has(key []byte) (bool, error) {
+	return s.snapshot.Has(key, nil)
+}
+
+func (s *ldbSnapshot) NewIterator(slice *util.Range) iterator.Iterator {
+	return s.snapshot.NewIterator(slice, nil)
+}
+
+func (s *ldbSnapshot) Release() {
+	s.snapshot.Release()
+}
+
 type table struct {
 	db     Database
 	prefix string
@@ -0,0 +1,315 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// The on-disk database engines.
+const (
+	EngineLevelDB = "leveldb"
+	EngineBolt    = "bolt"
+)
+
+// DefaultEngine is the engine of the new databases if no engine is configured.
+const DefaultEngine = EngineLevelDB
+
+// Engines are the names of all the supported on-disk database engines.
+var Engines = []string{EngineLevelDB, EngineBolt}
+
+// ErrNotFound is returned by all the engines if the key is not in the database.
+var ErrNotFound = errors.ErrNotFound
+
+// ErrCompactionNotSupported is returned by Compact of the engines which can't
+// compact the database in place, the callers may go on without the compaction.
+var ErrCompactionNotSupported = errors.New("ethdb: compaction is not supported by the engine")
+
+// Store is a database persisted in a directory by one of the engines. The chain
+// database, the snapshotdb baseDB, the evidence pool and the cbft wal are all
+// opened as a store, so they are not tied to leveldb.
+type Store interface {
+	Database
+	Stater
+	Compacter
+
+	// Engine returns the name of the engine of the store.
+	Engine() string
+
+	// Path returns the path to the database directory.
+	Path() string
+
+	// PutSync inserts the value and flushes it to the disk before returning.
+	PutSync(key []byte, value []byte) error
+
+	// NewSyncBatch creates a batch which is flushed to the disk when written.
+	NewSyncBatch() Batch
+
+	// NewIterator creates an iterator over the entire keyspace of the store.
+	NewIterator() iterator.Iterator
+
+	// NewIteratorWithPrefix creates an iterator over the keys with the prefix.
+	NewIteratorWithPrefix(prefix []byte) iterator.Iterator
+
+	// NewIteratorWithStart creates an iterator over the keys from the start.
+	NewIteratorWithStart(start []byte) iterator.Iterator
+
+	// NewIteratorWithRange creates an iterator over the key range, a nil range
+	// iterates the entire keyspace.
+	NewIteratorWithRange(slice *util.Range) iterator.Iterator
+
+	// NewSnapshot returns a frozen view of the current state of the store, the
+	// snapshot must be released after use.
+	NewSnapshot() (Snapshot, error)
+
+	// Meter configures the metrics collectors of the store at the prefix.
+	Meter(prefix string)
+}
+
+// Snapshot is a frozen view of a store at a point in time, the content of the
+// snapshot is consistent regardless of the later writes to the store.
+type Snapshot interface {
+	Get(key []byte) ([]byte, error)
+	Has(key []byte) (bool, error)
+
+	// NewIterator creates an iterator over the key range of the snapshot, a nil
+	// range iterates the entire keyspace.
+	NewIterator(slice *util.Range) iterator.Iterator
+
+	// Release releases the snapshot, the iterators of the snapshot must be
+	// released before.
+	Release()
+}
+
+// CheckEngine returns an error if the engine is not supported, an empty engine
+// is valid and means the engine of the existing database.
+func CheckEngine(engine string) error {
+	if engine == "" {
+		return nil
+	}
+	for _, e := range Engines {
+		if e == engine {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown database engine %q, supported engines: %s", engine, strings.Join(Engines, ", "))
+}
+
+// DetectEngine returns the engine of the database in the directory, or an empty
+// string if there is no database in it.
+func DetectEngine(file string) string {
+	if _, err := os.Stat(filepath.Join(file, boltFileName)); err == nil {
+		return EngineBolt
+	}
+	if _, err := os.Stat(filepath.Join(file, "CURRENT")); err == nil {
+		return EngineLevelDB
+	}
+	return ""
+}
+
+// resolveEngine returns the engine to open the database in the directory with.
+// An existing database is always opened with its own engine, the configured one
+// must match it.
+func resolveEngine(engine string, file string) (string, error) {
+	if err := CheckEngine(engine); err != nil {
+		return "", err
+	}
+	existing := DetectEngine(file)
+	switch {
+	case existing == "" && engine == "":
+		return DefaultEngine, nil
+	case existing == "":
+		return engine, nil
+	case engine == "" || engine == existing:
+		return existing, nil
+	}
+	return "", fmt.Errorf("database %s is stored by the %s engine instead of %s, convert it with `platon convertdb %s`", file, existing, engine, engine)
+}
+
+// OpenStore opens the database in the directory, creating it if it doesn't exist.
+// An empty engine opens the existing database with its own engine, or creates a
+// new one with the default engine.
+func OpenStore(engine string, file string, cache int, handles int) (Store, error) {
+	engine, err := resolveEngine(engine, file)
+	if err != nil {
+		return nil, err
+	}
+	if engine == EngineBolt {
+		return openBolt(file, false)
+	}
+	db, err := NewLDBDatabase(file, cache, handles)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// OpenStoreReadOnly opens the existing database in the directory in read-only
+// mode with its own engine.
+func OpenStoreReadOnly(file string) (Store, error) {
+	switch DetectEngine(file) {
+	case EngineBolt:
+		return openBolt(file, true)
+	case EngineLevelDB:
+		db, err := NewReadOnlyLDBDatabase(file)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	return nil, fmt.Errorf("no database in %s", file)
+}
+
+func openBolt(file string, readOnly bool) (Store, error) {
+	db, err := NewBoltDatabase(file, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// ConvertStore rewrites the database in the directory with the engine, it returns
+// the number of the copied items. The data is copied into a temporary database
+// first, the files of the old database are replaced only after the copy is done.
+// The subdirectories of the directory, e.g. the ancient store of the chain
+// database, are kept as they are.
+func ConvertStore(file string, engine string) (int, error) {
+	if err := CheckEngine(engine); err != nil || engine == "" {
+		return 0, fmt.Errorf("invalid target engine %q", engine)
+	}
+	existing := DetectEngine(file)
+	if existing == "" {
+		return 0, fmt.Errorf("no database in %s", file)
+	}
+	if existing == engine {
+		return 0, nil
+	}
+	tmp := file + ".convert"
+	if err := os.RemoveAll(tmp); err != nil {
+		return 0, err
+	}
+	src, err := OpenStore(existing, file, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	dst, err := OpenStore(engine, tmp, 0, 0)
+	if err != nil {
+		src.Close()
+		return 0, err
+	}
+	copied, err := copyStore(src, dst)
+	dst.Close()
+	src.Close()
+	if err != nil {
+		os.RemoveAll(tmp)
+		return 0, err
+	}
+	// Swap the database files. The files of the two engines never have the same
+	// names and a directory with a bolt file is detected as bolt, so there is one
+	// complete database in the directory even if the swap is interrupted.
+	if err := moveFiles(tmp, file); err != nil {
+		return 0, err
+	}
+	if err := removeFiles(file, func(name string) bool { return isEngineFile(engine, name) }); err != nil {
+		return 0, err
+	}
+	return copied, os.RemoveAll(tmp)
+}
+
+// copyStore copies all the items of the source store into the destination.
+func copyStore(src Store, dst Store) (int, error) {
+	it := src.NewIterator()
+	defer it.Release()
+
+	var (
+		copied int
+		batch  = dst.NewBatch()
+		logged = time.Now()
+	)
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return copied, err
+		}
+		copied++
+		if batch.ValueSize() >= IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return copied, err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Converting database", "path", src.Path(), "copied", copied)
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return copied, err
+	}
+	return copied, batch.Write()
+}
+
+// isEngineFile reports whether the file belongs to a database of the engine.
+func isEngineFile(engine string, name string) bool {
+	if engine == EngineBolt {
+		return name == boltFileName
+	}
+	return name != boltFileName
+}
+
+// moveFiles moves the regular files from one directory to the other.
+func moveFiles(from string, to string) error {
+	entries, err := ioutil.ReadDir(from)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := os.Rename(filepath.Join(from, entry.Name()), filepath.Join(to, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeFiles deletes the regular files in the directory which are not excluded.
+func removeFiles(dir string, exclude func(name string) bool) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || exclude(entry.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -36,14 +36,13 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p"
 	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"github.com/PlatONnetwork/PlatON-Go/rpc"
 	"github.com/davecgh/go-spew/spew"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 const (
@@ -571,6 +570,15 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	return res[:], state.Error()
 }
 
+// GetPposProof returns the merkle proof of the ppos key against the state root at the given block number.
+func (s *PublicBlockChainAPI) GetPposProof(ctx context.Context, key hexutil.Bytes, blockNr rpc.BlockNumber) (*core.PPOSProof, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return core.GetPPOSProof(state, key)
+}
+
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
 	From     common.Address  `json:"from"`
@@ -721,6 +729,7 @@ type StructLogRes struct {
 	Stack   *[]string          `json:"stack,omitempty"`
 	Memory  *[]string          `json:"memory,omitempty"`
 	Storage *map[string]string `json:"storage,omitempty"`
+	Wasm    *vm.WasmLog        `json:"wasm,omitempty"`
 }
 
 // formatLogs formats EVM returned structured logs for json output
@@ -729,11 +738,12 @@ func FormatLogs(logs []vm.StructLog) []StructLogRes {
 	for index, trace := range logs {
 		formatted[index] = StructLogRes{
 			Pc:      trace.Pc,
-			Op:      trace.Op.String(),
+			Op:      trace.OpName(),
 			Gas:     trace.Gas,
 			GasCost: trace.GasCost,
 			Depth:   trace.Depth,
 			Error:   trace.Err,
+			Wasm:    trace.Wasm,
 		}
 		if trace.Stack != nil {
 			stack := make([]string, len(trace.Stack))
@@ -1367,32 +1377,30 @@ func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
 	return &PrivateDebugAPI{b: b}
 }
 
-// ChaindbProperty returns leveldb properties of the chain database.
+// ChaindbProperty returns the engine properties of the chain database, e.g.
+// leveldb.stats of leveldb or bolt.stats of bolt.
 func (api *PrivateDebugAPI) ChaindbProperty(property string) (string, error) {
-	ldb, ok := api.b.ChainDb().(interface {
-		LDB() *leveldb.DB
-	})
+	db, ok := api.b.ChainDb().(ethdb.Store)
 	if !ok {
 		return "", fmt.Errorf("chaindbProperty does not work for memory databases")
 	}
+	prefix := db.Engine() + "."
 	if property == "" {
-		property = "leveldb.stats"
-	} else if !strings.HasPrefix(property, "leveldb.") {
-		property = "leveldb." + property
+		property = prefix + "stats"
+	} else if !strings.HasPrefix(property, prefix) {
+		property = prefix + property
 	}
-	return ldb.LDB().GetProperty(property)
+	return db.Stat(property)
 }
 
 func (api *PrivateDebugAPI) ChaindbCompact() error {
-	ldb, ok := api.b.ChainDb().(interface {
-		LDB() *leveldb.DB
-	})
+	db, ok := api.b.ChainDb().(ethdb.Store)
 	if !ok {
 		return fmt.Errorf("chaindbCompact does not work for memory databases")
 	}
 	for b := byte(0); b < 255; b++ {
 		log.Info("Compacting chain database", "range", fmt.Sprintf("0x%0.2X-0x%0.2X", b, b+1))
-		err := ldb.LDB().CompactRange(util.Range{Start: []byte{b}, Limit: []byte{b + 1}})
+		err := db.Compact([]byte{b}, []byte{b + 1})
 		if err != nil {
 			log.Error("Database compaction failed", "err", err)
 			return err
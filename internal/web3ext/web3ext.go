@@ -22,6 +22,7 @@ var Modules = map[string]string{
 	"chequebook": Chequebook_JS,
 	"clique":     Clique_JS,
 	"debug":      Debug_JS,
+	"liveness":   Liveness_JS,
 	"platon":     Platon_JS,
 	"miner":      Miner_JS,
 	"net":        Net_JS,
@@ -476,6 +477,27 @@ web3._extend({
 			call: 'platon_getPrepareQC',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getQuorumCerts',
+			call: 'platon_getQuorumCerts',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getViewChangeQCs',
+			call: 'platon_getViewChangeQCs',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'getViewSummaries',
+			call: 'platon_getViewSummaries',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'getPposProof',
+			call: 'platon_getPposProof',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -651,3 +673,27 @@ web3._extend({
 	]
 });
 `
+
+const Liveness_JS = `
+web3._extend({
+	property: 'liveness',
+	methods: [
+		new web3._extend.Method({
+			name: 'getEpochLiveness',
+			call: 'liveness_getEpochLiveness',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getLiveness',
+			call: 'liveness_getLiveness',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorLiveness',
+			call: 'liveness_getValidatorLiveness',
+			params: 3
+		}),
+	],
+	properties: []
+});
+`
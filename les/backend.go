@@ -123,6 +123,13 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 	if leth.blockchain, err = light.NewLightChain(leth.odr, leth.chainConfig, leth.engine); err != nil {
 		return nil, err
 	}
+	if config.LightQC {
+		verifier, err := light.NewQCVerifier(leth.odr, leth.chainConfig)
+		if err != nil {
+			return nil, err
+		}
+		leth.blockchain.SetQCVerifier(verifier)
+	}
 	// Note: AddChildIndexer starts the update process for the child
 	leth.bloomIndexer.AddChildIndexer(leth.bloomTrieIndexer)
 	leth.chtIndexer.Start(leth.blockchain)
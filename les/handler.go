@@ -61,6 +61,8 @@ const (
 	MaxHelperTrieProofsFetch = 64  // Amount of merkle proofs to be fetched per retrieval request
 	MaxTxSend                = 64  // Amount of transactions to be send per request
 	MaxTxStatus              = 256 // Amount of transactions to queried per request
+	MaxQuorumCertsFetch      = 192 // Amount of QuorumCerts to be fetched per retrieval request
+	MaxPposProofsFetch       = 64  // Amount of ppos proofs to be fetched per retrieval request
 
 	disableClientRemovePeer = false
 )
@@ -325,7 +327,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	}
 }
 
-var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg}
+var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg, GetQuorumCertsMsg, GetPposProofsMsg}
 
 // handleMsg is invoked whenever an inbound message is received from a remote
 // peer. The remote connection is torn down upon returning any error.
@@ -1101,6 +1103,119 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		p.fcServer.GotReply(resp.ReqID, resp.BV)
 
+	case GetQuorumCertsMsg:
+		p.Log().Trace("Received QuorumCerts request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID  uint64
+			Hashes []common.Hash
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Hashes)
+		if reject(uint64(reqCnt), MaxQuorumCertsFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		// The extra data of the block body carries the cbft version and QuorumCert,
+		// an empty one is returned for the unknown block.
+		extras := make([][]byte, 0, reqCnt)
+		for _, hash := range req.Hashes {
+			var extra []byte
+			if number := rawdb.ReadHeaderNumber(pm.chainDb, hash); number != nil {
+				if body := rawdb.ReadBody(pm.chainDb, hash, *number); body != nil {
+					extra = body.ExtraData
+				}
+			}
+			extras = append(extras, extra)
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		return p.SendQuorumCerts(req.ReqID, bv, extras)
+
+	case QuorumCertsMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received QuorumCerts response")
+		var resp struct {
+			ReqID, BV uint64
+			Extras    [][]byte
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgQuorumCerts,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Extras,
+		}
+
+	case GetPposProofsMsg:
+		p.Log().Trace("Received ppos proofs request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID uint64
+			Reqs  []PposProofReq
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Reqs)
+		if reject(uint64(reqCnt), MaxPposProofsFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		var (
+			lastBHash common.Hash
+			statedb   *state.StateDB
+			proofs    = make([]*core.PPOSProof, 0, reqCnt)
+		)
+		for _, req := range req.Reqs {
+			// Open the state of the requested block, the empty proof fails the verification of the client
+			if statedb == nil || req.BHash != lastBHash {
+				statedb, lastBHash = nil, req.BHash
+				if number := rawdb.ReadHeaderNumber(pm.chainDb, req.BHash); number != nil {
+					if header := rawdb.ReadHeader(pm.chainDb, req.BHash, *number); header != nil {
+						if current, err := pm.blockchain.State(); err == nil {
+							statedb, _ = state.New(header.Root, current.Database())
+						}
+					}
+				}
+			}
+			proof := new(core.PPOSProof)
+			if statedb != nil {
+				if pp, err := core.GetPPOSProof(statedb, req.Key); err == nil {
+					proof = pp
+				}
+			}
+			proofs = append(proofs, proof)
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		return p.SendPposProofs(req.ReqID, bv, proofs)
+
+	case PposProofsMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received ppos proofs response")
+		var resp struct {
+			ReqID, BV uint64
+			Proofs    []*core.PPOSProof
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgPposProofs,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Proofs,
+		}
+
 	default:
 		p.Log().Trace("Received unknown message", "code", msg.Code)
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
@@ -88,6 +88,8 @@ const (
 	MsgProofsV2
 	MsgHeaderProofs
 	MsgHelperTrieProofs
+	MsgQuorumCerts
+	MsgPposProofs
 )
 
 // Msg encodes a LES message that delivers reply data for a request
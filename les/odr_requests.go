@@ -19,11 +19,14 @@
 package les
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
+	"github.com/PlatONnetwork/PlatON-Go/core"
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
@@ -44,6 +47,8 @@ var (
 	errCHTHashMismatch     = errors.New("cht hash mismatch")
 	errCHTNumberMismatch   = errors.New("cht number mismatch")
 	errUselessNodes        = errors.New("useless nodes in merkle proof nodeset")
+	errQCMismatch          = errors.New("quorum cert mismatch")
+	errPposKeyMismatch     = errors.New("ppos key mismatch")
 )
 
 type LesOdrRequest interface {
@@ -67,6 +72,10 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*ChtRequest)(r)
 	case *light.BloomRequest:
 		return (*BloomRequest)(r)
+	case *light.QuorumCertsRequest:
+		return (*QuorumCertsRequest)(r)
+	case *light.PposProofsRequest:
+		return (*PposProofsRequest)(r)
 	default:
 		return nil
 	}
@@ -189,7 +198,7 @@ func (r *TrieRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetProofsV1Msg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetProofsV2Msg, 1)
 	default:
 		panic(nil)
@@ -349,7 +358,7 @@ func (r *ChtRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetHeaderProofsMsg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetHelperTrieProofsMsg, 1)
 	default:
 		panic(nil)
@@ -537,6 +546,122 @@ func (r *BloomRequest) Validate(db ethdb.Database, msg *Msg) error {
 	return nil
 }
 
+// ODR request type for the QuorumCerts of the headers, see LesOdrRequest interface
+type QuorumCertsRequest light.QuorumCertsRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *QuorumCertsRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetQuorumCertsMsg, len(r.Headers))
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *QuorumCertsRequest) CanSend(peer *peer) bool {
+	if peer.version < lpv3 {
+		return false
+	}
+	last := r.Headers[len(r.Headers)-1]
+	return peer.HasBlock(last.Hash(), last.Number.Uint64())
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *QuorumCertsRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting QuorumCerts", "count", len(r.Headers), "first", r.Headers[0].Number)
+	hashes := make([]common.Hash, len(r.Headers))
+	for i, header := range r.Headers {
+		hashes[i] = header.Hash()
+	}
+	return peer.RequestQuorumCerts(reqID, r.GetCost(peer), hashes)
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *QuorumCertsRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating QuorumCerts", "count", len(r.Headers))
+
+	if msg.MsgType != MsgQuorumCerts {
+		return errInvalidMessageType
+	}
+	extras := msg.Obj.([][]byte)
+	if len(extras) != len(r.Headers) {
+		return errInvalidEntryCount
+	}
+	// Only the QuorumCert is checked against the header here,
+	// the signature is verified by the QCVerifier.
+	qcs := make([]*ctypes.QuorumCert, len(extras))
+	for i, extra := range extras {
+		_, qc, err := ctypes.DecodeExtra(extra)
+		if err != nil {
+			return err
+		}
+		header := r.Headers[i]
+		if qc.BlockHash != header.Hash() || qc.BlockNumber != header.Number.Uint64() || qc.ValidatorSet == nil {
+			return errQCMismatch
+		}
+		qcs[i] = qc
+	}
+	r.QCs = qcs
+	return nil
+}
+
+type PposProofReq struct {
+	BHash common.Hash
+	Key   []byte
+}
+
+// ODR request type for the proofs of the ppos keys, see LesOdrRequest interface
+type PposProofsRequest light.PposProofsRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *PposProofsRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetPposProofsMsg, len(r.Keys))
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *PposProofsRequest) CanSend(peer *peer) bool {
+	if peer.version < lpv3 {
+		return false
+	}
+	return peer.HasBlock(r.Header.Hash(), r.Header.Number.Uint64())
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *PposProofsRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting ppos proofs", "number", r.Header.Number, "count", len(r.Keys))
+	reqs := make([]PposProofReq, len(r.Keys))
+	for i, key := range r.Keys {
+		reqs[i] = PposProofReq{BHash: r.Header.Hash(), Key: key}
+	}
+	return peer.RequestPposProofs(reqID, r.GetCost(peer), reqs)
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *PposProofsRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating ppos proofs", "number", r.Header.Number, "count", len(r.Keys))
+
+	if msg.MsgType != MsgPposProofs {
+		return errInvalidMessageType
+	}
+	proofs := msg.Obj.([]*core.PPOSProof)
+	if len(proofs) != len(r.Keys) {
+		return errInvalidEntryCount
+	}
+	for i, proof := range proofs {
+		if !bytes.Equal(proof.Key, r.Keys[i]) {
+			return errPposKeyMismatch
+		}
+		if err := proof.Verify(r.Header.Root); err != nil {
+			return err
+		}
+	}
+	r.Proofs = proofs
+	return nil
+}
+
 // readTraceDB stores the keys of database reads. We use this to check that received node
 // sets contain only the trie nodes necessary to make proofs pass.
 type readTraceDB struct {
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/eth"
 	"github.com/PlatONnetwork/PlatON-Go/les/flowcontrol"
@@ -236,6 +237,16 @@ func (p *peer) SendTxStatus(reqID, bv uint64, stats []txStatus) error {
 	return sendResponse(p.rw, TxStatusMsg, reqID, bv, stats)
 }
 
+// SendQuorumCerts sends a batch of cbft extra data, corresponding to the blocks requested.
+func (p *peer) SendQuorumCerts(reqID, bv uint64, extras [][]byte) error {
+	return sendResponse(p.rw, QuorumCertsMsg, reqID, bv, extras)
+}
+
+// SendPposProofs sends a batch of ppos proofs, corresponding to the keys requested.
+func (p *peer) SendPposProofs(reqID, bv uint64, proofs []*core.PPOSProof) error {
+	return sendResponse(p.rw, PposProofsMsg, reqID, bv, proofs)
+}
+
 // RequestHeadersByHash fetches a batch of blocks' headers corresponding to the
 // specified header query, based on the hash of an origin block.
 func (p *peer) RequestHeadersByHash(reqID, cost uint64, origin common.Hash, amount int, skip int, reverse bool) error {
@@ -276,7 +287,7 @@ func (p *peer) RequestProofs(reqID, cost uint64, reqs []ProofReq) error {
 	switch p.version {
 	case lpv1:
 		return sendRequest(p.rw, GetProofsV1Msg, reqID, cost, reqs)
-	case lpv2:
+	case lpv2, lpv3:
 		return sendRequest(p.rw, GetProofsV2Msg, reqID, cost, reqs)
 	default:
 		panic(nil)
@@ -293,7 +304,7 @@ func (p *peer) RequestHelperTrieProofs(reqID, cost uint64, data interface{}) err
 		}
 		p.Log().Debug("Fetching batch of header proofs", "count", len(reqs))
 		return sendRequest(p.rw, GetHeaderProofsMsg, reqID, cost, reqs)
-	case lpv2:
+	case lpv2, lpv3:
 		reqs, ok := data.([]HelperTrieReq)
 		if !ok {
 			return errInvalidHelpTrieReq
@@ -305,6 +316,18 @@ func (p *peer) RequestHelperTrieProofs(reqID, cost uint64, data interface{}) err
 	}
 }
 
+// RequestQuorumCerts fetches the cbft extra data carrying the QuorumCerts of the blocks.
+func (p *peer) RequestQuorumCerts(reqID, cost uint64, hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of QuorumCerts", "count", len(hashes))
+	return sendRequest(p.rw, GetQuorumCertsMsg, reqID, cost, hashes)
+}
+
+// RequestPposProofs fetches a batch of ppos proofs from a remote node.
+func (p *peer) RequestPposProofs(reqID, cost uint64, reqs []PposProofReq) error {
+	p.Log().Debug("Fetching batch of ppos proofs", "count", len(reqs))
+	return sendRequest(p.rw, GetPposProofsMsg, reqID, cost, reqs)
+}
+
 // RequestTxStatus fetches a batch of transaction status records from a remote node.
 func (p *peer) RequestTxStatus(reqID, cost uint64, txHashes []common.Hash) error {
 	p.Log().Debug("Requesting transaction status", "count", len(txHashes))
@@ -317,7 +340,7 @@ func (p *peer) SendTxs(reqID, cost uint64, txs types.Transactions) error {
 	switch p.version {
 	case lpv1:
 		return p2p.Send(p.rw, SendTxMsg, txs) // old message format does not include reqID
-	case lpv2:
+	case lpv2, lpv3:
 		return sendRequest(p.rw, SendTxV2Msg, reqID, cost, txs)
 	default:
 		panic(nil)
@@ -0,0 +1,61 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler/opcodes"
+	"github.com/go-interpreter/wagon/wasm"
+)
+
+// Tracer is notified of the execution of the virtual machine. It's set on the
+// VMContext, the hooks are skipped if it's nil.
+type Tracer interface {
+	// CaptureEnter is called when a function of the module is entered, the
+	// parameters are the locals of the current frame.
+	CaptureEnter(vm *VirtualMachine, functionID int)
+
+	// CaptureExit is called when a function of the module returns.
+	CaptureExit(vm *VirtualMachine, functionID int, ret int64)
+
+	// CaptureHostCall is called before the imported host function is invoked,
+	// the parameters are the locals of the current frame.
+	CaptureHostCall(vm *VirtualMachine, name string)
+
+	// CaptureHostReturn is called after the imported host function returns, it's
+	// also called with a zero ret if the host function panics.
+	CaptureHostReturn(vm *VirtualMachine, name string, ret int64)
+
+	// CaptureGas is called with the gas cost of each instruction before it's executed.
+	CaptureGas(vm *VirtualMachine, op opcodes.Opcode, cost uint64)
+}
+
+// FunctionName returns the name of the function in the name section of the
+// module or its export name, or its index if the module has no name for it.
+func (vm *VirtualMachine) FunctionName(functionID int) string {
+	if name, ok := vm.Module.FunctionNames[functionID]; ok {
+		return name
+	}
+	if exports := vm.Module.Base.Export; exports != nil {
+		for name, entry := range exports.Entries {
+			if entry.Kind == wasm.ExternalFunction && int(entry.Index) == functionID {
+				return name
+			}
+		}
+	}
+	return fmt.Sprintf("$%d", functionID)
+}
+
+// ImportName returns the field name of the function import, the import IDs only
+// count the function imports like FunctionImports.
+func (vm *VirtualMachine) ImportName(importID int) string {
+	if importID < len(vm.FunctionImportNames) {
+		return vm.FunctionImportNames[importID]
+	}
+	return fmt.Sprintf("$import%d", importID)
+}
+
+// isImportStub reports whether the function is the stub invoking a host function,
+// the stubs are placed before the functions of the module.
+func (vm *VirtualMachine) isImportStub(functionID int) bool {
+	return functionID < len(vm.FunctionImports)
+}
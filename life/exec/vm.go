@@ -57,11 +57,13 @@ type VirtualMachine struct {
 	Module          *compiler.Module
 	FunctionCode    []compiler.InterpreterCode
 	FunctionImports []*FunctionImport
-	JumpTable       [256]Instruction
-	CallStack       []Frame
-	CurrentFrame    int
-	Table           []uint32
-	Globals         []int64
+	// FunctionImportNames are the field names of the function imports, by import ID.
+	FunctionImportNames []string
+	JumpTable           [256]Instruction
+	CallStack           []Frame
+	CurrentFrame        int
+	Table               []uint32
+	Globals             []int64
 	//Memory          *VMMemory
 	Memory         *Memory
 	NumValueSlots  int
@@ -97,6 +99,12 @@ type VMContext struct {
 
 	StateDB StateDB
 	Log     log.Logger
+	Tracer  Tracer
+
+	// Output is the data returned to the caller by platonReturn
+	Output []byte
+	// CallOutput is the data returned by the last call to the other contract
+	CallOutput []byte
 }
 
 type VMMemory struct {
@@ -144,12 +152,14 @@ func NewVirtualMachineWithModule(m *compiler.Module, functionCode []compiler.Int
 	table := make([]uint32, 0)
 	globals := make([]int64, 0)
 	funcImports := make([]*FunctionImport, 0)
+	funcImportNames := make([]string, 0)
 
 	if m.Base.Import != nil && impResolver != nil {
 		for _, imp := range m.Base.Import.Entries {
 			switch imp.Type.Kind() {
 			case wasm.ExternalFunction:
 				funcImports = append(funcImports, impResolver.ResolveFunc(imp.ModuleName, imp.FieldName))
+				funcImportNames = append(funcImportNames, imp.FieldName)
 			case wasm.ExternalGlobal:
 				globals = append(globals, impResolver.ResolveGlobal(imp.ModuleName, imp.FieldName))
 			case wasm.ExternalMemory:
@@ -266,18 +276,19 @@ func NewVirtualMachineWithModule(m *compiler.Module, functionCode []compiler.Int
 	}
 
 	return &VirtualMachine{
-		Module:          m,
-		Context:         context,
-		FunctionCode:    functionCode,
-		FunctionImports: funcImports,
-		JumpTable:       GasTable,
-		CallStack:       make([]Frame, DefaultCallStackSize),
-		CurrentFrame:    -1,
-		Table:           table,
-		Globals:         globals,
-		Memory:          memory,
-		Exited:          true,
-		ExternalParams:  make([]int64, 0),
+		Module:              m,
+		Context:             context,
+		FunctionCode:        functionCode,
+		FunctionImports:     funcImports,
+		FunctionImportNames: funcImportNames,
+		JumpTable:           GasTable,
+		CallStack:           make([]Frame, DefaultCallStackSize),
+		CurrentFrame:        -1,
+		Table:               table,
+		Globals:             globals,
+		Memory:              memory,
+		Exited:              true,
+		ExternalParams:      make([]int64, 0),
 	}, nil
 }
 
@@ -420,6 +431,10 @@ func (vm *VirtualMachine) Ignite(functionID int, params ...int64) {
 		code,
 	)
 	copy(frame.Locals, params)
+
+	if tracer := vm.Context.Tracer; tracer != nil && !vm.isImportStub(functionID) {
+		tracer.CaptureEnter(vm, functionID)
+	}
 }
 
 func (vm *VirtualMachine) AddAndCheckGas(delta uint64) {
@@ -460,6 +475,7 @@ func (vm *VirtualMachine) Execute() {
 	}()
 
 	frame := vm.GetCurrentFrame()
+	tracer := vm.Context.Tracer
 
 	for {
 		if frame.JITInfo != nil {
@@ -483,6 +499,9 @@ func (vm *VirtualMachine) Execute() {
 			panic(fmt.Sprintf("out of gas  cost:%d GasUsed:%d GasLimit:%d", cost, vm.Context.GasUsed, vm.Context.GasLimit))
 		}
 		vm.Context.GasUsed += cost
+		if tracer != nil {
+			tracer.CaptureGas(vm, ins, cost)
+		}
 
 		//fmt.Printf("INS: [%d] %s\n", valueID, ins.String())
 
@@ -1428,6 +1447,9 @@ func (vm *VirtualMachine) Execute() {
 			}
 		case opcodes.ReturnValue:
 			val := frame.Regs[int(LE.Uint32(frame.Code[frame.IP:frame.IP+4]))]
+			if tracer != nil && !vm.isImportStub(frame.FunctionID) {
+				tracer.CaptureExit(vm, frame.FunctionID, val)
+			}
 			frame.Destroy(vm)
 			vm.CurrentFrame--
 			if vm.CurrentFrame == -1 {
@@ -1440,6 +1462,9 @@ func (vm *VirtualMachine) Execute() {
 				//fmt.Printf("Return value %d\n", val)
 			}
 		case opcodes.ReturnVoid:
+			if tracer != nil && !vm.isImportStub(frame.FunctionID) {
+				tracer.CaptureExit(vm, frame.FunctionID, 0)
+			}
 			frame.Destroy(vm)
 			vm.CurrentFrame--
 			if vm.CurrentFrame == -1 {
@@ -1487,6 +1512,9 @@ func (vm *VirtualMachine) Execute() {
 			for i := 0; i < argCount; i++ {
 				frame.Locals[i] = oldRegs[int(LE.Uint32(argsRaw[i*4:i*4+4]))]
 			}
+			if tracer != nil && !vm.isImportStub(functionID) {
+				tracer.CaptureEnter(vm, functionID)
+			}
 			//fmt.Println("Call params =", frame.Locals[:argCount])
 
 		case opcodes.CallIndirect:
@@ -1518,10 +1546,26 @@ func (vm *VirtualMachine) Execute() {
 			for i := 0; i < argCount; i++ {
 				frame.Locals[i] = oldRegs[int(LE.Uint32(argsRaw[i*4:i*4+4]))]
 			}
+			if tracer != nil && !vm.isImportStub(functionID) {
+				tracer.CaptureEnter(vm, functionID)
+			}
 
 		case opcodes.InvokeImport:
 			importID := int(LE.Uint32(frame.Code[frame.IP : frame.IP+4]))
 			frame.IP += 4
+			if tracer != nil {
+				// The host function is invoked by the caller of Execute, the
+				// hooks are run around it in the delegate.
+				name := vm.ImportName(importID)
+				vm.Delegate = func() {
+					var ret int64
+					tracer.CaptureHostCall(vm, name)
+					defer func() { tracer.CaptureHostReturn(vm, name, ret) }()
+					ret = vm.FunctionImports[importID].Execute(vm)
+					frame.Regs[valueID] = ret
+				}
+				return
+			}
 			vm.Delegate = func() {
 				frame.Regs[valueID] = vm.FunctionImports[importID].Execute(vm)
 			}
@@ -25,9 +25,14 @@ import (
 var (
 	cfc  = newCfcSet()
 	cgbl = newGlobalSet()
+	// the host functions added by RegisterFunc
+	hfc = make(map[string]map[string]*exec.FunctionImport)
 )
 
-type CResolver struct{}
+type CResolver struct {
+	// the host functions added by RegisterFunc are not resolved
+	legacy bool
+}
 
 func (r *CResolver) ResolveFunc(module, field string) *exec.FunctionImport {
 	df := &exec.FunctionImport{
@@ -42,12 +47,14 @@ func (r *CResolver) ResolveFunc(module, field string) *exec.FunctionImport {
 	if m, exist := cfc[module]; exist == true {
 		if f, exist := m[field]; exist == true {
 			return f
-		} else {
-			return df
 		}
-	} else {
-		return df
 	}
+	if !r.legacy {
+		if f, exist := hfc[module][field]; exist {
+			return f
+		}
+	}
+	return df
 }
 
 func (r *CResolver) ResolveGlobal(module, field string) int64 {
@@ -0,0 +1,183 @@
+package resolver
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/ripemd160"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+// errMemoryOutOfBounds fails the execution of the contract accessing the memory out of its bounds
+// by a host function.
+var errMemoryOutOfBounds = errors.New("host function: memory access out of bounds")
+
+func init() {
+	// crypto
+	RegisterFunc("env", "ecrecover", &exec.FunctionImport{Execute: envEcrecover, GasCost: constGasFunc(params.EcrecoverGas)})
+	RegisterFunc("env", "sha256", &exec.FunctionImport{Execute: envSha256, GasCost: envSha256GasCost})
+	RegisterFunc("env", "ripemd160", &exec.FunctionImport{Execute: envRipemd160, GasCost: envRipemd160GasCost})
+	RegisterFunc("env", "blsVerify", &exec.FunctionImport{Execute: envBlsVerify, GasCost: constGasFunc(params.BlsVerifyGas)})
+
+	// return data
+	RegisterFunc("env", "platonReturn", &exec.FunctionImport{Execute: envPlatonReturn, GasCost: envPlatonReturnGasCost})
+	RegisterFunc("env", "platonCallBytes", &exec.FunctionImport{Execute: envPlatonCallBytes, GasCost: envPlatonCallGasCost})
+	RegisterFunc("env", "platonDelegateCallBytes", &exec.FunctionImport{Execute: envPlatonDelegateCallBytes, GasCost: envPlatonCallGasCost})
+	RegisterFunc("env", "platonGetCallOutput", &exec.FunctionImport{Execute: envPlatonGetCallOutput, GasCost: envPlatonGetCallOutputGasCost})
+}
+
+// define: int ecrecover(const uint8_t hash[32], const uint8_t sig[65], uint8_t addr[20]);
+// sig is [R || S || V] with V in 0, 1 or 27, 28, returns 0 if the address is recovered.
+func envEcrecover(vm *exec.VirtualMachine) int64 {
+	hash := int(int32(vm.GetCurrentFrame().Locals[0]))
+	sig := int(int32(vm.GetCurrentFrame().Locals[1]))
+	addr := int(int32(vm.GetCurrentFrame().Locals[2]))
+
+	s := make([]byte, 65)
+	copy(s, memorySlice(vm, sig, 65))
+	if s[64] >= 27 {
+		s[64] -= 27
+	}
+	r, ss := new(big.Int).SetBytes(s[:32]), new(big.Int).SetBytes(s[32:64])
+	if !crypto.ValidateSignatureValues(s[64], r, ss, false) {
+		return 1
+	}
+	pubKey, err := crypto.Ecrecover(memorySlice(vm, hash, 32), s)
+	if err != nil {
+		return 1
+	}
+	copy(memorySlice(vm, addr, 20), crypto.Keccak256(pubKey[1:])[12:])
+	return 0
+}
+
+// define: void sha256(const uint8_t *src, size_t srcLen, uint8_t dest[32]);
+func envSha256(vm *exec.VirtualMachine) int64 {
+	offset := int(int32(vm.GetCurrentFrame().Locals[0]))
+	size := int(int32(vm.GetCurrentFrame().Locals[1]))
+	destOffset := int(int32(vm.GetCurrentFrame().Locals[2]))
+
+	hash := sha256.Sum256(memorySlice(vm, offset, size))
+	copy(memorySlice(vm, destOffset, len(hash)), hash[:])
+	return 0
+}
+
+func envSha256GasCost(vm *exec.VirtualMachine) (uint64, error) {
+	size := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	return (size+31)/32*params.Sha256PerWordGas + params.Sha256BaseGas, nil
+}
+
+// define: void ripemd160(const uint8_t *src, size_t srcLen, uint8_t dest[20]);
+func envRipemd160(vm *exec.VirtualMachine) int64 {
+	offset := int(int32(vm.GetCurrentFrame().Locals[0]))
+	size := int(int32(vm.GetCurrentFrame().Locals[1]))
+	destOffset := int(int32(vm.GetCurrentFrame().Locals[2]))
+
+	ripemd := ripemd160.New()
+	ripemd.Write(memorySlice(vm, offset, size))
+	hash := ripemd.Sum(nil)
+	copy(memorySlice(vm, destOffset, len(hash)), hash)
+	return 0
+}
+
+func envRipemd160GasCost(vm *exec.VirtualMachine) (uint64, error) {
+	size := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	return (size+31)/32*params.Ripemd160PerWordGas + params.Ripemd160BaseGas, nil
+}
+
+// define: int blsVerify(const uint8_t *msg, size_t msgLen, const uint8_t *sig, size_t sigLen, const uint8_t *pubKey, size_t pubKeyLen);
+// returns 1 if the signature of the message is valid, otherwise 0.
+func envBlsVerify(vm *exec.VirtualMachine) int64 {
+	msg := int(int32(vm.GetCurrentFrame().Locals[0]))
+	msgLen := int(int32(vm.GetCurrentFrame().Locals[1]))
+	sig := int(int32(vm.GetCurrentFrame().Locals[2]))
+	sigLen := int(int32(vm.GetCurrentFrame().Locals[3]))
+	pubKey := int(int32(vm.GetCurrentFrame().Locals[4]))
+	pubKeyLen := int(int32(vm.GetCurrentFrame().Locals[5]))
+
+	var (
+		sign bls.Sign
+		pub  bls.PublicKey
+	)
+	if err := sign.Deserialize(memorySlice(vm, sig, sigLen)); err != nil {
+		return 0
+	}
+	if err := pub.Deserialize(memorySlice(vm, pubKey, pubKeyLen)); err != nil {
+		return 0
+	}
+	if !sign.Verify(&pub, string(memorySlice(vm, msg, msgLen))) {
+		return 0
+	}
+	return 1
+}
+
+// define: void platonReturn(const uint8_t *data, size_t len);
+// the data is returned to the caller when the contract exits, the last one wins.
+func envPlatonReturn(vm *exec.VirtualMachine) int64 {
+	data := int(int32(vm.GetCurrentFrame().Locals[0]))
+	dataLen := int(int32(vm.GetCurrentFrame().Locals[1]))
+
+	vm.Context.Output = common.CopyBytes(memorySlice(vm, data, dataLen))
+	return 0
+}
+
+func envPlatonReturnGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	return uint64(uint32(vm.GetCurrentFrame().Locals[1])) + compiler.GasQuickStep, nil
+}
+
+// define: int64_t platonCallBytes(const uint8_t to[20], const uint8_t *params, size_t paramsLen);
+// returns the size of the call output which can be copied by platonGetCallOutput, -1 if the call fails.
+func envPlatonCallBytes(vm *exec.VirtualMachine) int64 {
+	addr := int(int32(vm.GetCurrentFrame().Locals[0]))
+	input := int(int32(vm.GetCurrentFrame().Locals[1]))
+	inputLen := int(int32(vm.GetCurrentFrame().Locals[2]))
+
+	ret, err := vm.Context.StateDB.Call(memorySlice(vm, addr, 20), memorySlice(vm, input, inputLen))
+	if err != nil {
+		vm.Context.CallOutput = nil
+		return -1
+	}
+	vm.Context.CallOutput = ret
+	return int64(len(ret))
+}
+
+// define: int64_t platonDelegateCallBytes(const uint8_t to[20], const uint8_t *params, size_t paramsLen);
+func envPlatonDelegateCallBytes(vm *exec.VirtualMachine) int64 {
+	addr := int(int32(vm.GetCurrentFrame().Locals[0]))
+	input := int(int32(vm.GetCurrentFrame().Locals[1]))
+	inputLen := int(int32(vm.GetCurrentFrame().Locals[2]))
+
+	ret, err := vm.Context.StateDB.DelegateCall(memorySlice(vm, addr, 20), memorySlice(vm, input, inputLen))
+	if err != nil {
+		vm.Context.CallOutput = nil
+		return -1
+	}
+	vm.Context.CallOutput = ret
+	return int64(len(ret))
+}
+
+// define: void platonGetCallOutput(uint8_t *dest);
+func envPlatonGetCallOutput(vm *exec.VirtualMachine) int64 {
+	dest := int(int32(vm.GetCurrentFrame().Locals[0]))
+	copy(memorySlice(vm, dest, len(vm.Context.CallOutput)), vm.Context.CallOutput)
+	return 0
+}
+
+// memorySlice returns the memory of the vm in [offset, offset+size), it panics with errMemoryOutOfBounds
+// if the range is not in the memory, the panic is recovered by the vm and fails the execution.
+func memorySlice(vm *exec.VirtualMachine, offset, size int) []byte {
+	if offset < 0 || size < 0 || offset > len(vm.Memory.Memory)-size {
+		panic(errMemoryOutOfBounds)
+	}
+	return vm.Memory.Memory[offset : offset+size]
+}
+
+func envPlatonGetCallOutputGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	return uint64(len(vm.Context.CallOutput)) + compiler.GasQuickStep, nil
+}
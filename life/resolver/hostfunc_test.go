@@ -0,0 +1,37 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+)
+
+func TestMemorySlice(t *testing.T) {
+	vm := &exec.VirtualMachine{Memory: &exec.Memory{Memory: make([]byte, 16)}}
+
+	if got := memorySlice(vm, 8, 8); len(got) != 8 {
+		t.Fatalf("slice length mismatch, have %d, want 8", len(got))
+	}
+	for _, c := range []struct{ offset, size int }{{8, 9}, {17, 0}, {-1, 1}, {0, -1}, {1 << 31, 1 << 31}} {
+		func() {
+			defer func() {
+				if r := recover(); r != errMemoryOutOfBounds {
+					t.Errorf("offset %d size %d: have %v, want %v", c.offset, c.size, r, errMemoryOutOfBounds)
+				}
+			}()
+			memorySlice(vm, c.offset, c.size)
+		}()
+	}
+}
+
+func TestLegacyResolver(t *testing.T) {
+	if f := NewResolver(clang).ResolveFunc("env", "sha256"); f.Execute == nil || f != hfc["env"]["sha256"] {
+		t.Fatal("sha256 should be resolved")
+	}
+	if f := NewLegacyResolver(clang).ResolveFunc("env", "sha256"); f == hfc["env"]["sha256"] {
+		t.Fatal("sha256 should be unknown to the legacy resolver")
+	}
+	if f := NewLegacyResolver(clang).ResolveFunc("env", "memcpy"); f != cfc["env"]["memcpy"] {
+		t.Fatal("memcpy should be resolved by the legacy resolver")
+	}
+}
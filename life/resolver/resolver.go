@@ -1,6 +1,8 @@
 package resolver
 
 import (
+	"fmt"
+
 	"github.com/PlatONnetwork/PlatON-Go/life/exec"
 )
 
@@ -20,6 +22,35 @@ func NewResolver(lang int) exec.ImportResolver {
 	return nil
 }
 
+// NewLegacyResolver returns the import resolver without the host functions added by RegisterFunc,
+// they are resolved like the unknown functions, as the nodes before them do.
+func NewLegacyResolver(lang int) exec.ImportResolver {
+	switch lang {
+	case clang:
+		return &CResolver{legacy: true}
+	case golang:
+	default:
+	}
+	return nil
+}
+
+// RegisterFunc adds the host function to the imports of the module,
+// it must be called before any contract is executed, e.g. in init.
+func RegisterFunc(module, field string, fn *exec.FunctionImport) {
+	if _, exist := cfc[module][field]; exist {
+		panic(fmt.Sprintf("host function %s.%s is already registered", module, field))
+	}
+	m, exist := hfc[module]
+	if !exist {
+		m = make(map[string]*exec.FunctionImport)
+		hfc[module] = m
+	}
+	if _, exist := m[field]; exist {
+		panic(fmt.Sprintf("host function %s.%s is already registered", module, field))
+	}
+	m[field] = fn
+}
+
 func MallocString(vm *exec.VirtualMachine, str string) int64 {
 	mem := vm.Memory
 	size := len([]byte(str)) + 1
@@ -29,3 +60,13 @@ func MallocString(vm *exec.VirtualMachine, str string) int64 {
 	vm.ExternalParams = append(vm.ExternalParams, int64(pos))
 	return int64(pos)
 }
+
+func MallocBytes(vm *exec.VirtualMachine, data []byte) int64 {
+	mem := vm.Memory
+	size := len(data)
+
+	pos := mem.Malloc(size)
+	copy(mem.Memory[pos:pos+size], data)
+	vm.ExternalParams = append(vm.ExternalParams, int64(pos))
+	return int64(pos)
+}
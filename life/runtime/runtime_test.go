@@ -13,6 +13,7 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 )
 
@@ -97,6 +98,52 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestTraceWasm(t *testing.T) {
+	state, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	address := common.HexToAddress("0x0a")
+	state.SetCode(address, genCodeInput())
+
+	trace := func(input []byte) []vm.StructLog {
+		tracer := vm.NewStructLogger(nil)
+		cfg := &Config{State: state, EVMConfig: vm.Config{Debug: true, Tracer: tracer}}
+		if _, _, err := Call(address, input, cfg); err != nil {
+			t.Fatal("didn't expect error", err)
+		}
+		return tracer.StructLogs()
+	}
+	hostCalls := func(logs []vm.StructLog) map[string]*vm.WasmHostCall {
+		calls := make(map[string]*vm.WasmHostCall)
+		for _, log := range logs {
+			if log.Wasm == nil {
+				t.Fatalf("non wasm log: %v", log.OpName())
+			}
+			if log.Wasm.HostCall != nil {
+				calls[log.Wasm.HostCall.Name] = log.Wasm.HostCall
+			}
+		}
+		return calls
+	}
+
+	setLogs := trace(genSetInput(10))
+	if len(setLogs) == 0 || setLogs[0].OpName() != vm.WasmEnter || setLogs[0].Depth != 1 {
+		t.Fatalf("first log mismatch: %+v", setLogs)
+	}
+	set := hostCalls(setLogs)["setState"]
+	if set == nil || len(set.Key) == 0 || len(set.Value) == 0 {
+		t.Fatalf("setState is not traced: %+v", set)
+	}
+
+	getLogs := trace(genInput())
+	get := hostCalls(getLogs)["getState"]
+	if get == nil || !bytes.Equal(get.Key, set.Key) || !bytes.Equal(get.Value, set.Value) {
+		t.Fatalf("getState mismatch: have %+v, want %+v", get, set)
+	}
+	last := getLogs[len(getLogs)-1]
+	if last.OpName() != vm.WasmExit || last.Wasm.Function != getLogs[0].Wasm.Function {
+		t.Fatalf("last log mismatch: %+v", last.Wasm)
+	}
+}
+
 func TestCallCode(t *testing.T){
 	code := genInput()
 	hexcode := common.Bytes2Hex(code)
@@ -167,6 +214,16 @@ func genInput() []byte {
 	return buffer.Bytes()
 }
 
+func genSetInput(value int64) []byte {
+	input := [][]byte{utils.Int64ToBytes(1), []byte("set"), utils.Int64ToBytes(value)}
+
+	buffer := new(bytes.Buffer)
+	if err := rlp.Encode(buffer, input); err != nil {
+		fmt.Println("genSetInput fail.", err)
+	}
+	return buffer.Bytes()
+}
+
 func genCallInput() []byte {
 	var input [][]byte
 	input = make([][]byte, 0)
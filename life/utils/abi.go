@@ -5,6 +5,15 @@ import (
 	"fmt"
 )
 
+const (
+	// AbiVersion1 passes the integer and string arguments as int64 params
+	// and returns a single value, it's the version of the contracts without ABI version.
+	AbiVersion1 = 1
+	// AbiVersion2 passes the arguments as a rlp list to the function,
+	// the function returns the rlp list of the outputs by platonReturn.
+	AbiVersion2 = 2
+)
+
 type WasmAbi struct {
 	AbiArr []AbiStruct	`json:"abiArr"`
 }
@@ -20,12 +29,11 @@ type AbiStruct struct {
 type InputParam struct {
 	Name string		`json:"name"`
 	Type string		`json:"type"`
+	// Components are the fields of the struct type, ABI v2 only
+	Components []InputParam `json:"components,omitempty"`
 }
 
-type OutputsParam struct {
-	Name string 	`json:"name"`
-	Type string 	`json:"type"`
-}
+type OutputsParam = InputParam
 
 func (abi *WasmAbi) FromJson(body []byte) error {
 	if body == nil {
@@ -33,4 +41,4 @@ func (abi *WasmAbi) FromJson(body []byte) error {
 	}
 	err := json.Unmarshal(body, &abi.AbiArr)
 	return err
-}
\ No newline at end of file
+}
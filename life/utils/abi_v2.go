@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The values of ABI v2 are rlp encoded as below:
+//   bool                   empty for false, 0x01 for true
+//   uint8 ~ uint256        big endian, no more bytes than the width, u128 and u256 are aliases
+//   int8 ~ int256          big endian two's complement, no more bytes than the width
+//   address                20 bytes
+//   bytes1 ~ bytes32       exactly N bytes
+//   bytes, string          byte string, the string must be valid utf-8
+//   T[N]                   list of N elements
+//   T[]                    list of elements
+//   struct, tuple          list of the components in order
+
+// CheckValues checks the rlp decoded values against the params of ABI v2.
+func CheckValues(params []InputParam, values []interface{}) error {
+	if len(params) != len(values) {
+		return fmt.Errorf("want %d values, have %d", len(params), len(values))
+	}
+	for i, p := range params {
+		if err := checkValue(p.Type, p.Components, values[i]); err != nil {
+			return fmt.Errorf("invalid value %d(%s): %v", i, p.Name, err)
+		}
+	}
+	return nil
+}
+
+func checkValue(typ string, components []InputParam, value interface{}) error {
+	if strings.HasSuffix(typ, "]") {
+		i := strings.LastIndex(typ, "[")
+		if i <= 0 {
+			return fmt.Errorf("invalid type %s", typ)
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s want a list", typ)
+		}
+		if size := typ[i+1 : len(typ)-1]; size != "" {
+			n, err := strconv.Atoi(size)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid type %s", typ)
+			}
+			if len(list) != n {
+				return fmt.Errorf("%s want %d elements, have %d", typ, n, len(list))
+			}
+		}
+		for _, v := range list {
+			if err := checkValue(typ[:i], components, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if typ == "struct" || typ == "tuple" {
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s want a list", typ)
+		}
+		return CheckValues(components, list)
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("%s want a byte string", typ)
+	}
+	switch {
+	case typ == "bool":
+		if len(b) > 1 || (len(b) == 1 && b[0] != 1) {
+			return fmt.Errorf("invalid bool %x", b)
+		}
+	case typ == "address":
+		if len(b) != 20 {
+			return fmt.Errorf("address want 20 bytes, have %d", len(b))
+		}
+	case typ == "bytes":
+	case typ == "string":
+		if !utf8.Valid(b) {
+			return fmt.Errorf("invalid utf-8 string")
+		}
+	case strings.HasPrefix(typ, "bytes"):
+		n, err := strconv.Atoi(typ[len("bytes"):])
+		if err != nil || n < 1 || n > 32 {
+			return fmt.Errorf("invalid type %s", typ)
+		}
+		if len(b) != n {
+			return fmt.Errorf("%s want %d bytes, have %d", typ, n, len(b))
+		}
+	case typ == "u128":
+		return checkInteger(typ, "128", b)
+	case typ == "u256":
+		return checkInteger(typ, "256", b)
+	case strings.HasPrefix(typ, "uint"):
+		return checkInteger(typ, typ[len("uint"):], b)
+	case strings.HasPrefix(typ, "int"):
+		return checkInteger(typ, typ[len("int"):], b)
+	default:
+		return fmt.Errorf("unsupported type %s", typ)
+	}
+	return nil
+}
+
+// checkInteger checks the width of the integer, int and uint are 32 bits same as ABI v1.
+func checkInteger(typ, bits string, b []byte) error {
+	width := 32
+	if bits != "" {
+		n, err := strconv.Atoi(bits)
+		if err != nil || n < 8 || n > 256 || n%8 != 0 {
+			return fmt.Errorf("invalid type %s", typ)
+		}
+		width = n
+	}
+	if len(b) > width/8 {
+		return fmt.Errorf("%s overflow, have %d bytes", typ, len(b))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+func decodeValues(t *testing.T, values ...interface{}) []interface{} {
+	data, err := rlp.EncodeToBytes(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []interface{}
+	if err := rlp.DecodeBytes(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestCheckValues(t *testing.T) {
+	type point struct {
+		X uint64
+		Y []byte
+	}
+	params := []InputParam{
+		{Name: "flag", Type: "bool"},
+		{Name: "amount", Type: "u256"},
+		{Name: "small", Type: "uint8"},
+		{Name: "to", Type: "address"},
+		{Name: "hash", Type: "bytes32"},
+		{Name: "memo", Type: "string"},
+		{Name: "ids", Type: "uint64[3]"},
+		{Name: "points", Type: "struct[]", Components: []InputParam{
+			{Name: "x", Type: "uint64"},
+			{Name: "y", Type: "bytes"},
+		}},
+	}
+	amount, _ := new(big.Int).SetString("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 16)
+	valid := func() []interface{} {
+		return []interface{}{
+			true, amount, uint8(7), make([]byte, 20), make([]byte, 32), "memo",
+			[]uint64{1, 2, 3}, []point{{1, []byte{1}}, {2, nil}},
+		}
+	}
+	if err := CheckValues(params, decodeValues(t, valid()...)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []struct {
+		index int
+		value interface{}
+	}{
+		{0, uint8(2)},
+		{1, append([]byte{1}, make([]byte, 32)...)},
+		{2, uint16(256)},
+		{3, make([]byte, 19)},
+		{4, make([]byte, 31)},
+		{5, []byte{0xff}},
+		{6, []uint64{1, 2}},
+		{7, []interface{}{[]interface{}{uint64(1)}}},
+	}
+	for _, c := range invalid {
+		values := valid()
+		values[c.index] = c.value
+		if err := CheckValues(params, decodeValues(t, values...)); err == nil {
+			t.Errorf("value %d: want error", c.index)
+		}
+	}
+	if err := CheckValues(params[:1], decodeValues(t, true, true)); err == nil {
+		t.Error("want error for the extra value")
+	}
+	if err := CheckValues([]InputParam{{Type: "float"}}, decodeValues(t, uint8(1))); err == nil {
+		t.Error("want error for the unsupported type")
+	}
+}
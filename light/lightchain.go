@@ -53,6 +53,7 @@ type LightChain struct {
 	chainDb       ethdb.Database
 	engine        consensus.Engine
 	odr           OdrBackend
+	qcVerifier    *QCVerifier // Verifies the QuorumCerts of the inserted headers if set
 	chainFeed     event.Feed
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
@@ -142,6 +143,12 @@ func (lc *LightChain) Odr() OdrBackend {
 	return lc.odr
 }
 
+// SetQCVerifier sets the verifier of the QuorumCerts, the headers are inserted
+// only if their QuorumCerts are signed by the validator set in force.
+func (lc *LightChain) SetQCVerifier(verifier *QCVerifier) {
+	lc.qcVerifier = verifier
+}
+
 // loadLastState loads the last known chain state from the database. This method
 // assumes that the chain manager mutex is held.
 func (lc *LightChain) loadLastState() error {
@@ -360,6 +367,11 @@ func (lc *LightChain) InsertHeaderChain(chain []*types.Header, checkFreq int) (i
 	if i, err := lc.hc.ValidateHeaderChain(chain, checkFreq); err != nil {
 		return i, err
 	}
+	if lc.qcVerifier != nil {
+		if i, err := lc.qcVerifier.VerifyHeaders(chain); err != nil {
+			return i, err
+		}
+	}
 
 	// Make sure only one thread manipulates the chain at once
 	lc.chainmu.Lock()
@@ -22,6 +22,7 @@ import (
 	"context"
 	"errors"
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
 	"github.com/PlatONnetwork/PlatON-Go/core"
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
@@ -173,6 +174,30 @@ func (req *BloomRequest) StoreResult(db ethdb.Database) {
 		rawdb.WriteBloomBits(db, req.BitIdx, sectionIdx, sectionHead, req.BloomBits[i])
 	}
 }
+
+// QuorumCertsRequest is the ODR request type for retrieving the cbft extra data
+// which carries the QuorumCert of the blocks
+type QuorumCertsRequest struct {
+	OdrRequest
+	Headers []*types.Header
+	QCs     []*ctypes.QuorumCert
+}
+
+// StoreResult is a no-op, the QuorumCerts are verified and dropped by the QCVerifier
+func (req *QuorumCertsRequest) StoreResult(db ethdb.Database) {}
+
+// PposProofsRequest is the ODR request type for retrieving the proofs of ppos
+// keys against the state root of a header
+type PposProofsRequest struct {
+	OdrRequest
+	Header *types.Header
+	Keys   [][]byte
+	Proofs []*core.PPOSProof
+}
+
+// StoreResult is a no-op, the proven values are kept in the request
+func (req *PposProofsRequest) StoreResult(db ethdb.Database) {}
+
 /*
 // TxStatus describes the status of a transaction
 type TxStatus struct {
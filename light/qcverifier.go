@@ -0,0 +1,298 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+const (
+	// maxValidatorSets is the number of the recent validator sets kept by the QCVerifier
+	maxValidatorSets = 8
+	// maxQuorumCertsRequest is the number of the QuorumCerts retrieved per request
+	maxQuorumCertsRequest = 192
+	// qcRetrieveTimeout is the timeout of retrieving a batch of QuorumCerts or a transition proof
+	qcRetrieveTimeout = 30 * time.Second
+)
+
+var (
+	errUnknownValidatorSet = errors.New("unknown validator set")
+	errEmptyValidatorSet   = errors.New("empty validator set")
+	errInvalidTransition   = errors.New("invalid validator set transition")
+	errInvalidQCSignature  = errors.New("invalid aggregated signature of the QuorumCert")
+)
+
+var qcValidatorSetsKey = []byte("LightQCValidatorSets")
+
+// ValidatorNode is a validator at its index of the QuorumCert.
+type ValidatorNode struct {
+	NodeID    discover.NodeID
+	BlsPubKey *bls.PublicKey
+}
+
+// ValidatorSet is a validator set trusted by the light client, it signs the
+// QuorumCerts of the blocks in [Start, End]. End is zero if the set never switches.
+type ValidatorSet struct {
+	Start, End uint64
+	Nodes      []*ValidatorNode
+}
+
+func (s *ValidatorSet) contains(number uint64) bool {
+	return number >= s.Start && (s.End == 0 || number <= s.End)
+}
+
+// VerifyQC verifies the aggregated signature of the QuorumCert,
+// it must be signed by more than 2/3 of the validators.
+func (s *ValidatorSet) VerifyQC(qc *ctypes.QuorumCert) error {
+	if len(s.Nodes) == 0 {
+		return errEmptyValidatorSet
+	}
+	if qc.ValidatorSet == nil || qc.ValidatorSet.Size() != uint32(len(s.Nodes)) {
+		return fmt.Errorf("validator set size of the QuorumCert mismatch, want %d", len(s.Nodes))
+	}
+	var (
+		pub     bls.PublicKey
+		signers int
+	)
+	for i, node := range s.Nodes {
+		if !qc.ValidatorSet.GetIndex(uint32(i)) {
+			continue
+		}
+		if signers == 0 {
+			if err := pub.Deserialize(node.BlsPubKey.Serialize()); err != nil {
+				return err
+			}
+		} else {
+			pub.Add(node.BlsPubKey)
+		}
+		signers++
+	}
+	if threshold := len(s.Nodes) - (len(s.Nodes)-1)/3; signers < threshold {
+		return fmt.Errorf("QuorumCert has small number of signers:%d, threshold:%d", signers, threshold)
+	}
+
+	msg, err := qc.CannibalizeBytes()
+	if err != nil {
+		return err
+	}
+	var sig bls.Sign
+	if err := sig.Deserialize(qc.Signature.Bytes()); err != nil {
+		return err
+	}
+	if !sig.Verify(&pub, string(msg)) {
+		return errInvalidQCSignature
+	}
+	return nil
+}
+
+// QCVerifier verifies the headers synced by the light client against the QuorumCerts
+// of the blocks, the QuorumCert of a block must be signed by the validator set in force
+// at its height. The validator set of the next round is proven against the state root
+// of the last block of the current round, which is already verified by the current set,
+// it requires the ppos state to be committed to the state trie.
+type QCVerifier struct {
+	odr OdrBackend
+
+	lock sync.Mutex
+	sets []*ValidatorSet // Recent trusted validator sets, in ascending order
+}
+
+// NewQCVerifier creates a QCVerifier, the validator sets are restored from the database,
+// or start with the initial nodes of the genesis. On the ppos chain the transitions are
+// proven from the first round, so the ppos commit fork must be in force since then.
+func NewQCVerifier(odr OdrBackend, config *params.ChainConfig) (*QCVerifier, error) {
+	if config.Cbft == nil {
+		return nil, errors.New("the QuorumCert is only verified on the cbft chain")
+	}
+	if config.Cbft.ValidatorMode == common.PPOS_VALIDATOR_MODE {
+		if first := xutil.ConsensusSize(); !config.IsPPOSCommit(new(big.Int).SetUint64(first)) {
+			return nil, fmt.Errorf("the QuorumCert can't be verified before the ppos commit fork: the validator sets are proven since block %d, but the fork block is %v",
+				first, config.PPOSCommitBlock)
+		}
+	}
+	v := &QCVerifier{odr: odr}
+	if data, err := odr.Database().Get(qcValidatorSetsKey); err == nil {
+		if err := rlp.DecodeBytes(data, &v.sets); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+
+	nodes := config.Cbft.InitialNodes
+	genesis := &ValidatorSet{Start: 1}
+	switch config.Cbft.ValidatorMode {
+	case "", common.STATIC_VALIDATOR_MODE:
+	case common.PPOS_VALIDATOR_MODE:
+		// same as the validators of the first round stored by the genesis
+		if max := int(xcom.MaxConsensusVals()); len(nodes) > max {
+			nodes = nodes[:max]
+		}
+		genesis.End = xutil.ConsensusSize()
+	default:
+		return nil, fmt.Errorf("the QuorumCert can't be verified in the validator mode: %s", config.Cbft.ValidatorMode)
+	}
+	for _, node := range nodes {
+		pub := node.BlsPubKey
+		genesis.Nodes = append(genesis.Nodes, &ValidatorNode{NodeID: node.Node.ID, BlsPubKey: &pub})
+	}
+	v.sets = []*ValidatorSet{genesis}
+	return v, nil
+}
+
+// ValidatorSet returns the trusted validator set in force at the height.
+func (v *QCVerifier) ValidatorSet(number uint64) *ValidatorSet {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.validatorSet(number)
+}
+
+func (v *QCVerifier) validatorSet(number uint64) *ValidatorSet {
+	for i := len(v.sets) - 1; i >= 0; i-- {
+		if v.sets[i].contains(number) {
+			return v.sets[i]
+		}
+	}
+	return nil
+}
+
+// VerifyHeaders retrieves the QuorumCerts of the headers and verifies them in order,
+// the index of the first invalid header is returned with the error.
+func (v *QCVerifier) VerifyHeaders(headers []*types.Header) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	for start := 0; start < len(headers); start += maxQuorumCertsRequest {
+		end := start + maxQuorumCertsRequest
+		if end > len(headers) {
+			end = len(headers)
+		}
+		req := &QuorumCertsRequest{Headers: headers[start:end]}
+		ctx, cancel := context.WithTimeout(context.Background(), qcRetrieveTimeout)
+		err := v.odr.Retrieve(ctx, req)
+		cancel()
+		if err != nil {
+			return start, err
+		}
+
+		for i, qc := range req.QCs {
+			header := headers[start+i]
+			number := header.Number.Uint64()
+			set := v.validatorSet(number)
+			if set == nil {
+				return start + i, errUnknownValidatorSet
+			}
+			if err := set.VerifyQC(qc); err != nil {
+				log.Debug("Failed to verify QuorumCert", "number", number, "hash", header.Hash(), "qc", qc.String(), "err", err)
+				return start + i, err
+			}
+			if number == set.End {
+				if err := v.transit(header); err != nil {
+					log.Debug("Failed to prove validator set transition", "number", number, "hash", header.Hash(), "err", err)
+					return start + i, err
+				}
+			}
+		}
+	}
+	return 0, nil
+}
+
+// transit proves the validator set of the next round against the state root of the
+// verified header at the end of the current round.
+func (v *QCVerifier) transit(header *types.Header) error {
+	next := header.Number.Uint64() + 1
+	if v.validatorSet(next) != nil {
+		return nil
+	}
+
+	value, err := v.retrievePposValue(header, staking.GetRoundIndexKey())
+	if err != nil {
+		return err
+	}
+	var indexes staking.ValArrIndexQueue
+	if err := rlp.DecodeBytes(value, &indexes); err != nil {
+		return err
+	}
+	var index *staking.ValArrIndex
+	for _, i := range indexes {
+		if i.Start == next && i.End >= i.Start {
+			index = i
+			break
+		}
+	}
+	if index == nil {
+		return errInvalidTransition
+	}
+
+	value, err = v.retrievePposValue(header, staking.GetRoundValArrKey(index.Start, index.End))
+	if err != nil {
+		return err
+	}
+	var queue staking.ValidatorQueue
+	if err := rlp.DecodeBytes(value, &queue); err != nil {
+		return err
+	}
+	if queue.IsEmpty() {
+		return errInvalidTransition
+	}
+	set := &ValidatorSet{Start: index.Start, End: index.End, Nodes: make([]*ValidatorNode, 0, len(queue))}
+	for _, val := range queue {
+		pub, err := val.BlsPubKey.ParseBlsPubKey()
+		if err != nil {
+			return err
+		}
+		set.Nodes = append(set.Nodes, &ValidatorNode{NodeID: val.NodeId, BlsPubKey: pub})
+	}
+
+	v.sets = append(v.sets, set)
+	if len(v.sets) > maxValidatorSets {
+		v.sets = v.sets[len(v.sets)-maxValidatorSets:]
+	}
+	if data, err := rlp.EncodeToBytes(v.sets); err != nil {
+		log.Error("Failed to encode validator sets", "err", err)
+	} else if err := v.odr.Database().Put(qcValidatorSetsKey, data); err != nil {
+		log.Error("Failed to store validator sets", "err", err)
+	}
+	log.Info("Proven validator set transition", "number", header.Number, "hash", header.Hash(), "start", set.Start, "end", set.End, "validators", len(set.Nodes))
+	return nil
+}
+
+func (v *QCVerifier) retrievePposValue(header *types.Header, key []byte) ([]byte, error) {
+	req := &PposProofsRequest{Header: header, Keys: [][]byte{key}}
+	ctx, cancel := context.WithTimeout(context.Background(), qcRetrieveTimeout)
+	defer cancel()
+	if err := v.odr.Retrieve(ctx, req); err != nil {
+		return nil, err
+	}
+	return req.Proofs[0].Value, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/utils"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+func newTestValidatorSet(n int) (*ValidatorSet, []*bls.SecretKey) {
+	set := &ValidatorSet{Start: 1, End: 10}
+	keys := make([]*bls.SecretKey, n)
+	for i := 0; i < n; i++ {
+		var sk bls.SecretKey
+		sk.SetByCSPRNG()
+		keys[i] = &sk
+		set.Nodes = append(set.Nodes, &ValidatorNode{BlsPubKey: sk.GetPublicKey()})
+	}
+	return set, keys
+}
+
+func newTestQC(t *testing.T, keys []*bls.SecretKey, signers []int) *ctypes.QuorumCert {
+	qc := &ctypes.QuorumCert{
+		Epoch:        1,
+		ViewNumber:   2,
+		BlockHash:    common.BytesToHash([]byte("block")),
+		BlockNumber:  3,
+		ValidatorSet: utils.NewBitArray(uint32(len(keys))),
+	}
+	msg, err := qc.CannibalizeBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var agg *bls.Sign
+	for _, i := range signers {
+		sig := keys[i].Sign(string(msg))
+		if agg == nil {
+			agg = sig
+		} else {
+			agg.Add(sig)
+		}
+		qc.ValidatorSet.SetIndex(uint32(i), true)
+	}
+	qc.Signature.SetBytes(agg.Serialize())
+	return qc
+}
+
+func TestValidatorSet_VerifyQC(t *testing.T) {
+	set, keys := newTestValidatorSet(4)
+
+	if err := set.VerifyQC(newTestQC(t, keys, []int{0, 1, 3})); err != nil {
+		t.Errorf("the QuorumCert signed by 3 of 4 validators must be valid: %v", err)
+	}
+	if err := set.VerifyQC(newTestQC(t, keys, []int{0, 1})); err == nil {
+		t.Error("the QuorumCert signed by 2 of 4 validators must be invalid")
+	}
+
+	// claims a signer which doesn't sign
+	qc := newTestQC(t, keys, []int{0, 1, 2})
+	qc.ValidatorSet.SetIndex(3, true)
+	if err := set.VerifyQC(qc); err != errInvalidQCSignature {
+		t.Errorf("want %v, have %v", errInvalidQCSignature, err)
+	}
+
+	// signed by the validators of another set
+	other, otherKeys := newTestValidatorSet(4)
+	if err := other.VerifyQC(newTestQC(t, keys, []int{0, 1, 2})); err != errInvalidQCSignature {
+		t.Errorf("want %v, have %v", errInvalidQCSignature, err)
+	}
+	if err := set.VerifyQC(newTestQC(t, otherKeys[:3], []int{0, 1, 2})); err == nil {
+		t.Error("the QuorumCert with mismatched validator set size must be invalid")
+	}
+	if err := (&ValidatorSet{}).VerifyQC(qc); err != errEmptyValidatorSet {
+		t.Errorf("want %v, have %v", errEmptyValidatorSet, err)
+	}
+}
+
+func TestValidatorSet_RLP(t *testing.T) {
+	set, keys := newTestValidatorSet(4)
+	data, err := rlp.EncodeToBytes([]*ValidatorSet{set, {Start: 11}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sets []*ValidatorSet
+	if err := rlp.DecodeBytes(data, &sets); err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 2 || !sets[0].contains(10) || sets[0].contains(11) || !sets[1].contains(1<<40) {
+		t.Fatalf("invalid decoded validator sets")
+	}
+	if err := sets[0].VerifyQC(newTestQC(t, keys, []int{0, 1, 2})); err != nil {
+		t.Errorf("the QuorumCert must be valid by the decoded validator set: %v", err)
+	}
+}
+
+func TestNewQCVerifier_PPOSCommitFork(t *testing.T) {
+	for _, fork := range []*big.Int{nil, new(big.Int).SetUint64(xutil.ConsensusSize() + 1)} {
+		config := &params.ChainConfig{
+			Cbft:            &params.CbftConfig{ValidatorMode: common.PPOS_VALIDATOR_MODE},
+			PPOSCommitBlock: fork,
+		}
+		if _, err := NewQCVerifier(nil, config); err == nil {
+			t.Errorf("the QuorumCert is verified with the ppos commit fork at %v", fork)
+		}
+	}
+}
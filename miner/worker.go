@@ -1110,6 +1110,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 
 	// Fill the block with all available pending transactions.
 	startTime := time.Now()
+	priority := w.eth.TxPool().PendingPriority()
 	pending, err := w.eth.TxPool().PendingLimited()
 
 	if err != nil {
@@ -1117,10 +1118,10 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 		return
 	}
 
-	log.Debug("Fetch pending transactions success", "pendingLength", len(pending), "time", common.PrettyDuration(time.Since(startTime)))
+	log.Debug("Fetch pending transactions success", "pendingLength", len(pending), "priorityLength", len(priority), "time", common.PrettyDuration(time.Since(startTime)))
 
 	// Short circuit if there is no available pending transactions
-	if len(pending) == 0 {
+	if len(pending) == 0 && len(priority) == 0 {
 		//// No empty block
 		//if "off" == w.EmptyBlock {
 		//	return
@@ -1135,7 +1136,20 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 		return
 	}
 
-	txsCount := 0
+	// The priority transactions are packed ahead, leave the rest of their accounts
+	// to the normal packing.
+	priorityTxsCount := 0
+	for addr, txs := range priority {
+		priorityTxsCount = priorityTxsCount + len(txs)
+		last := txs[len(txs)-1].Nonce()
+		for len(pending[addr]) > 0 && pending[addr][0].Nonce() <= last {
+			pending[addr] = pending[addr][1:]
+		}
+		if len(pending[addr]) == 0 {
+			delete(pending, addr)
+		}
+	}
+	txsCount := priorityTxsCount
 	for _, accTxs := range pending {
 		txsCount = txsCount + len(accTxs)
 	}
@@ -1157,9 +1171,24 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 	}
 	log.Debug("Execute pending transactions", "number", header.Number, "localTxCount", localTxsCount, "remoteTxCount", remoteTxsCount, "txsCount", txsCount)
 
+	// The priority transactions are committed first, so that the PPOS duties of the
+	// verifiers get the block space before the deadline of the block is reached.
+	startTime = time.Now()
+	var priorityTimeout = false
+	if len(priority) > 0 {
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, priority)
+		if ok, timeout := w.commitTransactionsWithHeader(header, txs, interrupt, timestamp, blockDeadline); ok {
+			return
+		} else {
+			priorityTimeout = timeout
+		}
+	}
+	commitPriorityTxCount := w.current.tcount
+	log.Debug("Priority transactions executing stat", "number", header.Number, "priorityTxCount", priorityTxsCount, "involvedTxCount", commitPriorityTxCount, "time", common.PrettyDuration(time.Since(startTime)))
+
 	startTime = time.Now()
-	var localTimeout = false
-	if len(localTxs) > 0 {
+	var localTimeout = priorityTimeout
+	if !priorityTimeout && len(localTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
 		if ok, timeout := w.commitTransactionsWithHeader(header, txs, interrupt, timestamp, blockDeadline); ok {
 			return
@@ -1168,7 +1197,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 		}
 	}
 
-	commitLocalTxCount := w.current.tcount
+	commitLocalTxCount := w.current.tcount - commitPriorityTxCount
 	log.Debug("Local transactions executing stat", "number", header.Number, "involvedTxCount", commitLocalTxCount, "time", common.PrettyDuration(time.Since(startTime)))
 
 	startTime = time.Now()
@@ -1178,7 +1207,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 			return
 		}
 	}
-	commitRemoteTxCount := w.current.tcount - commitLocalTxCount
+	commitRemoteTxCount := w.current.tcount - commitPriorityTxCount - commitLocalTxCount
 	log.Debug("Remote transactions executing stat", "number", header.Number, "involvedTxCount", commitRemoteTxCount, "time", common.PrettyDuration(time.Since(startTime)))
 
 	if err := w.commit(w.fullTaskHook, true, tstart); nil != err {
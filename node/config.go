@@ -70,6 +70,11 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DBEngine is the engine of the new databases in the data directory, e.g. the
+	// chain database, the snapshotdb and the cbft wal. The existing databases are
+	// opened with their own engine, they must match it if it's set.
+	DBEngine string `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
@@ -158,9 +158,9 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), "", big.NewInt(0), big.NewInt(0), nil, nil, ""}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), "", big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, ""}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1), "", big.NewInt(0), big.NewInt(0), nil, new(CbftConfig), ""}
+	TestChainConfig = &ChainConfig{big.NewInt(1), "", big.NewInt(0), big.NewInt(0), nil, nil, nil, new(CbftConfig), ""}
 )
 
 // TrustedCheckpoint represents a set of post-processed trie roots (CHT and
@@ -185,6 +185,10 @@ type ChainConfig struct {
 	EmptyBlock  string   `json:"emptyBlock"`
 	EIP155Block *big.Int `json:"eip155Block,omitempty"` // EIP155 HF block
 	EWASMBlock  *big.Int `json:"ewasmBlock,omitempty"`  // EWASM switch block (nil = no fork, 0 = already activated)
+	// PPOS state commitment switch block (nil = no fork, 0 = already activated)
+	PPOSCommitBlock *big.Int `json:"pposCommitBlock,omitempty"`
+	// Switch block of the highest QC carried by the header extra (nil = no fork, 0 = already activated)
+	HeaderQCBlock *big.Int `json:"headerQCBlock,omitempty"`
 	// Various consensus engines
 	Clique *CliqueConfig `json:"clique,omitempty"`
 	Cbft   *CbftConfig   `json:"cbft,omitempty"`
@@ -250,6 +254,16 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsPPOSCommit returns whether num represents a block number after the PPOS state commitment fork
+func (c *ChainConfig) IsPPOSCommit(num *big.Int) bool {
+	return isForked(c.PPOSCommitBlock, num)
+}
+
+// IsHeaderQC returns whether num represents a block number after the fork of the QC carried by the header
+func (c *ChainConfig) IsHeaderQC(num *big.Int) bool {
+	return isForked(c.HeaderQCBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -282,6 +296,12 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.HeaderQCBlock, newcfg.HeaderQCBlock, head) {
+		return newCompatError("header QC fork block", c.HeaderQCBlock, newcfg.HeaderQCBlock)
+	}
+	if isForkIncompatible(c.PPOSCommitBlock, newcfg.PPOSCommitBlock, head) {
+		return newCompatError("ppos commit fork block", c.PPOSCommitBlock, newcfg.PPOSCommitBlock)
+	}
 	return nil
 }
 
@@ -59,6 +59,23 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     0,
 			},
 		},
+		{
+			stored:  &ChainConfig{PPOSCommitBlock: big.NewInt(10)},
+			new:     &ChainConfig{PPOSCommitBlock: big.NewInt(20)},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{PPOSCommitBlock: big.NewInt(10)},
+			new:    &ChainConfig{PPOSCommitBlock: big.NewInt(20)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "ppos commit fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
 	}
 
 	for _, test := range tests {
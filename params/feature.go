@@ -0,0 +1,106 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Feature names a behavior change of a subsystem (vm, ppos plugins, consensus, tx validation ...),
+// it's switched on once the program version it is declared with is activated by a version proposal,
+// so the upgrades are coordinated by the votes on chain instead of the fork blocks of the ChainConfig.
+type Feature string
+
+var (
+	featureLock     sync.RWMutex
+	featureVersions = make(map[Feature]uint32)
+)
+
+// RegisterFeature declares the feature with the program version activating it, it's supposed
+// to be called at the initialization of the subsystem, e.g.
+//
+//	var FeatureFoo = params.RegisterFeature("vm.foo", 0<<16|8<<8|0)
+//
+// It panics if the feature is declared twice.
+func RegisterFeature(feature Feature, version uint32) Feature {
+	featureLock.Lock()
+	defer featureLock.Unlock()
+
+	if _, ok := featureVersions[feature]; ok {
+		panic(fmt.Sprintf("feature %s is already registered", feature))
+	}
+	featureVersions[feature] = version
+	return feature
+}
+
+// FeatureVersion returns the program version activating the feature.
+func FeatureVersion(feature Feature) (uint32, bool) {
+	featureLock.RLock()
+	defer featureLock.RUnlock()
+
+	version, ok := featureVersions[feature]
+	return version, ok
+}
+
+// IsFeatureActive reports whether the feature is switched on by the active version.
+// Like the version proposal, only the major and minor version are compared,
+// and the unregistered feature is never active.
+func IsFeatureActive(feature Feature, activeVersion uint32) bool {
+	version, ok := FeatureVersion(feature)
+	if !ok {
+		return false
+	}
+	return activeVersion>>8 >= version>>8
+}
+
+// FeaturesOf returns the features activated by the version, sorted by the name.
+func FeaturesOf(version uint32) []Feature {
+	featureLock.RLock()
+	defer featureLock.RUnlock()
+
+	features := make([]Feature, 0)
+	for feature, v := range featureVersions {
+		if v>>8 == version>>8 {
+			features = append(features, feature)
+		}
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i] < features[j]
+	})
+	return features
+}
+
+// ActivatedFeatures returns the features switched on when the active version moves from
+// prevVersion to newVersion, sorted by the name. The versions skipped by the upgrade are
+// included, e.g. the features of 0.8 are activated by an upgrade from 0.7 to 0.9.
+func ActivatedFeatures(prevVersion, newVersion uint32) []Feature {
+	featureLock.RLock()
+	defer featureLock.RUnlock()
+
+	features := make([]Feature, 0)
+	for feature, v := range featureVersions {
+		if prevVersion>>8 < v>>8 && v>>8 <= newVersion>>8 {
+			features = append(features, feature)
+		}
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i] < features[j]
+	})
+	return features
+}
@@ -0,0 +1,72 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeature(t *testing.T) {
+	v080 := uint32(0<<16 | 8<<8 | 0)
+	v081 := uint32(0<<16 | 8<<8 | 1)
+	v090 := uint32(0<<16 | 9<<8 | 0)
+
+	foo := RegisterFeature("test.foo", v080)
+	bar := RegisterFeature("test.bar", v081)
+	baz := RegisterFeature("test.baz", v090)
+
+	if version, ok := FeatureVersion(foo); !ok || version != v080 {
+		t.Fatalf("feature version mismatch, have %d, want %d", version, v080)
+	}
+
+	tests := []struct {
+		feature       Feature
+		activeVersion uint32
+		want          bool
+	}{
+		{foo, GenesisVersion, false},
+		{foo, v080, true},
+		{foo, v090, true},
+		{bar, v080, true},
+		{baz, v081, false},
+		{baz, v090, true},
+		{"test.unknown", v090, false},
+	}
+	for i, test := range tests {
+		if have := IsFeatureActive(test.feature, test.activeVersion); have != test.want {
+			t.Errorf("test %d: feature %s at version %d, have %v, want %v", i, test.feature, test.activeVersion, have, test.want)
+		}
+	}
+
+	if have, want := FeaturesOf(v080), []Feature{bar, foo}; !reflect.DeepEqual(have, want) {
+		t.Errorf("features of version mismatch, have %v, want %v", have, want)
+	}
+	if have, want := ActivatedFeatures(GenesisVersion, v090), []Feature{bar, baz, foo}; !reflect.DeepEqual(have, want) {
+		t.Errorf("activated features mismatch, have %v, want %v", have, want)
+	}
+	if have, want := ActivatedFeatures(v081, v090), []Feature{baz}; !reflect.DeepEqual(have, want) {
+		t.Errorf("activated features mismatch, have %v, want %v", have, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering the feature twice should panic")
+		}
+	}()
+	RegisterFeature(foo, v090)
+}
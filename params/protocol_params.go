@@ -89,31 +89,45 @@ const (
 	Bn256ScalarMulGas       uint64 = 40000  // Gas needed for an elliptic curve scalar multiplication
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
+	BlsVerifyGas            uint64 = 260000 // Price for a BLS signature verification, same as a pairing check of two points
 
 	// PlatONPrecompiled contract gas prices
 
-	StakingGas          uint64 = 6000  // Gas needed for precompiled contract: stakingContract
-	CreateStakeGas      uint64 = 32000 // Gas needed for createStaking
-	EditCandidatGas     uint64 = 12000 // Gas needed for editCandidate
-	IncStakeGas         uint64 = 20000 // Gas needed for increaseStaking
-	WithdrewStakeGas    uint64 = 20000 // Gas needed for withdrewStaking
-	DelegateGas         uint64 = 16000 // Gas needed for delegate
-	WithdrewDelegateGas uint64 = 8000  // Gas needed for withdrewDelegate
+	StakingGas               uint64 = 6000  // Gas needed for precompiled contract: stakingContract
+	CreateStakeGas           uint64 = 32000 // Gas needed for createStaking
+	EditCandidatGas          uint64 = 12000 // Gas needed for editCandidate
+	IncStakeGas              uint64 = 20000 // Gas needed for increaseStaking
+	WithdrewStakeGas         uint64 = 20000 // Gas needed for withdrewStaking
+	DelegateGas              uint64 = 16000 // Gas needed for delegate
+	WithdrewDelegateGas      uint64 = 8000  // Gas needed for withdrewDelegate
+	WithdrewDelRewardGas     uint64 = 8000  // Gas needed for withdrewDelegateReward
+	WithdrewDelRewardItemGas uint64 = 3000  // Gas needed for every delegation settled by withdrewDelegateReward
+	RedelegateGas            uint64 = 20000 // Gas needed for redelegate
+	UnjailGas                uint64 = 20000 // Gas needed for unjail
+	SetControllerGas         uint64 = 20000 // Gas needed for setStakingController
+	ApproveActionGas         uint64 = 8000  // Gas needed for approveStakingAction
+	CancelActionGas          uint64 = 6000  // Gas needed for cancelStakingAction
 
 	GovGas                   uint64 = 9000   // Gas needed for precompiled contract: govContract
 	SubmitTextProposalGas    uint64 = 320000 // Gas needed for submitText
 	SubmitVersionProposalGas uint64 = 450000 // Gas needed for submitVersion
 	SubmitCancelProposalGas  uint64 = 500000 // Gas needed for submitCancel
 	SubmitParamProposalGas   uint64 = 500000 // Gas needed for submitParam
+	SubmitSpendProposalGas   uint64 = 500000 // Gas needed for submitSpend
 	VoteGas                  uint64 = 2000   // Gas needed for vote
+	DelegatorVoteGas         uint64 = 6000   // Gas needed for delegatorVote
+	DelegatorVoteByteGas     uint64 = 68     // Gas needed for every byte of the vote written by delegatorVote
 	DeclareVersionGas        uint64 = 3000   // Gas needed for declareVersion
 
-	SlashingGas              uint64 = 21000 // Gas needed for precompiled contract: slashingContract
-	ReportDuplicateSignGas   uint64 = 21000 // Gas needed for reportDuplicateSign
-	DuplicateEvidencesGas    uint64 = 21000 // When reporting, each duplicate sign of evidence requires gas to be consumed
-	RestrictingPlanGas       uint64 = 18000 // Gas needed for precompiled contract: restrictingPlanContract
-	CreateRestrictingPlanGas uint64 = 8000  // Gas needed for createRestrictingPlan
-	ReleasePlanGas           uint64 = 21000 // Gas consumed every time the von of the restrictPlan is released
+	SlashingGas                uint64 = 21000 // Gas needed for precompiled contract: slashingContract
+	ReportDuplicateSignGas     uint64 = 21000 // Gas needed for reportDuplicateSign
+	DuplicateEvidencesGas      uint64 = 21000 // When reporting, each duplicate sign of evidence requires gas to be consumed
+	RestrictingPlanGas         uint64 = 18000 // Gas needed for precompiled contract: restrictingPlanContract
+	CreateRestrictingPlanGas   uint64 = 8000  // Gas needed for createRestrictingPlan
+	ReleasePlanGas             uint64 = 21000 // Gas consumed every time the von of the restrictPlan is released
+	RevokeRestrictingPlanGas   uint64 = 8000  // Gas needed for revokeRestrictingPlan
+	TransferRestrictingPlanGas uint64 = 8000  // Gas needed for transferRestrictingPlan
+	RestrictingFunderGas       uint64 = 6000  // Gas consumed for each funder of the restricting plans by revokeRestrictingPlan and transferRestrictingPlan
 )
 
 var (
@@ -126,4 +140,5 @@ var (
 	SubmitVersionProposalGasPrice = big.NewInt(2100000 * 1000000000) // Min gas price for submit a version proposal in Von
 	SubmitCancelProposalGasPrice  = big.NewInt(3000000 * 1000000000) // Min gas price for submit a cancel proposal in Von
 	SubmitParamProposalGasPrice   = big.NewInt(2000000 * 1000000000) // Min gas price for submit a cancel proposal in Von
+	SubmitSpendProposalGasPrice   = big.NewInt(2000000 * 1000000000) // Min gas price for submit a spend proposal in Von
 )
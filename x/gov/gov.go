@@ -20,12 +20,14 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"sync"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/byteutil"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/node"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/x/staking"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
@@ -38,6 +40,7 @@ type Staking interface {
 	GetCandidateInfo(blockHash common.Hash, addr common.Address) (*staking.Candidate, error)
 	GetCanBase(blockHash common.Hash, addr common.Address) (*staking.CandidateBase, error)
 	GetCanMutable(blockHash common.Hash, addr common.Address) (*staking.CandidateMutable, error)
+	GetDelegateInfo(blockHash common.Hash, delAddr common.Address, nodeId discover.NodeID, stakeBlockNumber uint64) (*staking.Delegation, error)
 	DeclarePromoteNotify(blockHash common.Hash, blockNumber uint64, nodeId discover.NodeID, programVersion uint32) error
 }
 
@@ -46,27 +49,93 @@ const (
 	ModuleSlashing = "slashing"
 	ModuleBlock    = "block"
 	ModuleTxPool   = "txPool"
+	ModuleGov      = "gov"
+	ModuleReward   = "reward"
 )
 
 const (
-	KeyStakeThreshold             = "stakeThreshold"
-	KeyOperatingThreshold         = "operatingThreshold"
-	KeyMaxValidators              = "maxValidators"
-	KeyUnStakeFreezeDuration      = "unStakeFreezeDuration"
-	KeySlashFractionDuplicateSign = "slashFractionDuplicateSign"
-	KeyDuplicateSignReportReward  = "duplicateSignReportReward"
-	KeyMaxEvidenceAge             = "maxEvidenceAge"
-	KeySlashBlocksReward          = "slashBlocksReward"
-	KeyMaxBlockGasLimit           = "maxBlockGasLimit"
-	KeyMaxTxDataLimit             = "maxTxDataLimit"
+	KeyStakeThreshold              = "stakeThreshold"
+	KeyOperatingThreshold          = "operatingThreshold"
+	KeyMaxValidators               = "maxValidators"
+	KeyUnStakeFreezeDuration       = "unStakeFreezeDuration"
+	KeyHesitateRatio               = "hesitateRatio"
+	KeySlashFractionDuplicateSign  = "slashFractionDuplicateSign"
+	KeyDuplicateSignReportReward   = "duplicateSignReportReward"
+	KeyMaxEvidenceAge              = "maxEvidenceAge"
+	KeySlashBlocksReward           = "slashBlocksReward"
+	KeyVoteSignedWindow            = "voteSignedWindow"
+	KeyMinVoteSignedRatio          = "minVoteSignedRatio"
+	KeySlashFractionLowVote        = "slashFractionLowVote"
+	KeyJailDuration                = "jailDuration"
+	KeyTextProposalTallyMode       = "textProposalTallyMode"
+	KeyParamProposalTallyMode      = "paramProposalTallyMode"
+	KeyVersionProposalVoteDuration = "versionProposalVoteDurationSeconds"
+	KeyVersionProposalSupportRate  = "versionProposalSupportRate"
+	KeyTextProposalVoteDuration    = "textProposalVoteDurationSeconds"
+	KeyTextProposalVoteRate        = "textProposalVoteRate"
+	KeyTextProposalSupportRate     = "textProposalSupportRate"
+	KeyCancelProposalVoteRate      = "cancelProposalVoteRate"
+	KeyCancelProposalSupportRate   = "cancelProposalSupportRate"
+	KeyParamProposalVoteDuration   = "paramProposalVoteDurationSeconds"
+	KeyParamProposalVoteRate       = "paramProposalVoteRate"
+	KeyParamProposalSupportRate    = "paramProposalSupportRate"
+	KeySpendProposalVoteDuration   = "spendProposalVoteDurationSeconds"
+	KeySpendProposalVoteRate       = "spendProposalVoteRate"
+	KeySpendProposalSupportRate    = "spendProposalSupportRate"
+	KeyNewBlockRate                = "newBlockRate"
+	KeyPlatONFoundationYear        = "platonFoundationYear"
+	KeyMaxBlockGasLimit            = "maxBlockGasLimit"
+	KeyMaxTxDataLimit              = "maxTxDataLimit"
 )
 
 const (
-//GenesisTxSize = 1024 * 1024        //  1 MB
-//CeilTxSize    = GenesisTxSize * 10 // 10 MB
+	//GenesisTxSize = 1024 * 1024        //  1 MB
+	//CeilTxSize    = GenesisTxSize * 10 // 10 MB
 
+	// MaxDelegatorVotesPerNode bounds the delegators' votes for the delegations to a verifier, all of them
+	// are read when the proposal is tallied, so the votes of a proposal are bounded by its verifiers
+	MaxDelegatorVotesPerNode = 100
 )
 
+// FeatureDelegatorVote switches on the votes of the delegators, they are counted by the stake tally mode.
+var FeatureDelegatorVote = params.RegisterFeature("gov.delegatorVote", 0<<16|8<<8|0)
+
+// FeatureSpendProposal switches on the spend proposal, its params are added once it's activated.
+var FeatureSpendProposal = params.RegisterFeature("gov.spendProposal", 0<<16|8<<8|0)
+
+// FeatureGovernEconomicModel switches on the govern params of the economic model added with it,
+// they are added once it's activated and the param values are cached since then.
+var FeatureGovernEconomicModel = params.RegisterFeature("gov.economicModel", 0<<16|8<<8|0)
+
+func init() {
+	RegisterFeatureActivator(FeatureDelegatorVote, func(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+		return SeedGovernParams(blockHash, ModuleGov, KeyTextProposalTallyMode, KeyParamProposalTallyMode)
+	})
+	RegisterFeatureActivator(FeatureSpendProposal, func(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+		return SeedGovernParams(blockHash, ModuleGov, KeySpendProposalVoteDuration, KeySpendProposalVoteRate, KeySpendProposalSupportRate)
+	})
+	RegisterFeatureActivator(FeatureGovernEconomicModel, func(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+		if err := SeedGovernParams(blockHash, ModuleStaking, KeyHesitateRatio); err != nil {
+			return err
+		}
+		if err := SeedGovernParams(blockHash, ModuleGov, KeyVersionProposalVoteDuration, KeyVersionProposalSupportRate,
+			KeyTextProposalVoteDuration, KeyTextProposalVoteRate, KeyTextProposalSupportRate,
+			KeyCancelProposalVoteRate, KeyCancelProposalSupportRate,
+			KeyParamProposalVoteDuration, KeyParamProposalVoteRate, KeyParamProposalSupportRate,
+			KeySpendProposalVoteRate, KeySpendProposalSupportRate); err != nil {
+			return err
+		}
+		if err := SeedGovernParams(blockHash, ModuleReward, KeyNewBlockRate, KeyPlatONFoundationYear); err != nil {
+			return err
+		}
+		values, err := readGovernParamValues(blockHash)
+		if err != nil {
+			return err
+		}
+		return initGovernParamDigest(values, blockHash)
+	})
+}
+
 func GetVersionForStaking(state xcom.StateDB) uint32 {
 	preActiveVersion := GetPreActiveVersion(state)
 	if preActiveVersion > 0 {
@@ -94,6 +163,85 @@ func GetCurrentActiveVersion(state xcom.StateDB) uint32 {
 	return version
 }
 
+// GetActiveVersion returns the version active at the block, the active versions are recorded
+// from the latest one, so the first one activated not after the block is picked.
+func GetActiveVersion(blockNumber uint64, state xcom.StateDB) uint32 {
+	avList, err := ListActiveVersion(state)
+	if err != nil {
+		log.Error("Cannot find active version list", "blockNumber", blockNumber)
+		return 0
+	}
+	for _, av := range avList {
+		if av.ActiveBlock <= blockNumber {
+			return av.ActiveVersion
+		}
+	}
+	return 0
+}
+
+// IsFeatureActive reports whether the feature is switched on by the version active at the block.
+func IsFeatureActive(feature params.Feature, blockNumber uint64, state xcom.StateDB) bool {
+	return params.IsFeatureActive(feature, GetActiveVersion(blockNumber, state))
+}
+
+// FeatureActivator is run at the block the feature is activated, it migrates the data kept
+// before the feature, e.g. adds the govern params introduced by the feature to the chains
+// initialized without them.
+type FeatureActivator func(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error
+
+var featureActivators = make(map[params.Feature][]FeatureActivator)
+
+// RegisterFeatureActivator adds the activator of the feature, it's supposed to be called at
+// the initialization of the subsystem declaring the feature. The activators of a feature
+// are run in the order they are registered.
+func RegisterFeatureActivator(feature params.Feature, activator FeatureActivator) {
+	featureActivators[feature] = append(featureActivators[feature], activator)
+}
+
+// ActivateFeatures runs the activators of the features switched on by the upgrade of the
+// active version from prevVersion to newVersion.
+func ActivateFeatures(prevVersion, newVersion uint32, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+	for _, feature := range params.ActivatedFeatures(prevVersion, newVersion) {
+		for _, activator := range featureActivators[feature] {
+			if err := activator(blockHash, blockNumber, state); err != nil {
+				log.Error("activate feature failed", "feature", feature, "blockNumber", blockNumber, "blockHash", blockHash, "err", err)
+				return err
+			}
+		}
+		log.Info("feature is active.", "feature", feature, "newVersionString", xutil.ProgramVersion2Str(newVersion), "blockNumber", blockNumber)
+	}
+	return nil
+}
+
+// SeedGovernParams adds the listed govern params of the module with their initial values if the
+// chain is initialized without them, so the params introduced by a feature become governable once
+// the feature is activated. The params already added, e.g. by the genesis, are kept.
+func SeedGovernParams(blockHash common.Hash, module string, names ...string) error {
+	for _, name := range names {
+		value, err := findGovernParamValue(module, name, blockHash)
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			continue
+		}
+		var param *GovernParam
+		for _, p := range queryInitParam() {
+			if p.ParamItem.Module == module && p.ParamItem.Name == name {
+				param = p
+				break
+			}
+		}
+		if param == nil {
+			return fmt.Errorf("unknown govern parameter %s.%s", module, name)
+		}
+		if err := addGovernParam(module, name, param.ParamItem.Desc, &ParamValue{"", param.ParamValue.Value, 0}, blockHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // submit a proposal
 func Submit(from common.Address, proposal Proposal, blockHash common.Hash, blockNumber uint64, stk Staking, state xcom.StateDB) error {
 	log.Debug("call Submit", "from", from, "blockHash", blockHash, "blockNumber", blockNumber, "proposal", proposal)
@@ -113,6 +261,11 @@ func Submit(from common.Address, proposal Proposal, blockHash common.Hash, block
 		return err
 	}
 
+	// the proposal is tallied by the mode at the submission, the later changes of the mode don't affect it
+	if err := setProposalTallyMode(proposal, blockHash, blockNumber); err != nil {
+		return err
+	}
+
 	//handle storage
 	if err := SetProposal(proposal, state); err != nil {
 		log.Error("save proposal failed", "proposalID", proposal.GetProposalID())
@@ -317,6 +470,92 @@ func DeclareVersion(from common.Address, declaredNodeID discover.NodeID, declare
 	return nil
 }
 
+// DelegatorVote votes for a proposal with the von delegated to the node,
+// the delegator's vote overrides the node's vote for the delegation when the proposal is tallied by stake.
+func DelegatorVote(from common.Address, vote VoteInfo, stakingBlockNum uint64, blockHash common.Hash, blockNumber uint64, stk Staking, state xcom.StateDB) error {
+	log.Debug("call DelegatorVote", "from", from, "proposalID", vote.ProposalID, "voteNodeID", vote.VoteNodeID, "stakingBlockNum", stakingBlockNum, "voteOption", vote.VoteOption, "blockHash", blockHash, "blockNumber", blockNumber)
+	if vote.ProposalID == common.ZeroHash {
+		return ProposalIDEmpty
+	}
+
+	if vote.VoteOption != Yes && vote.VoteOption != No && vote.VoteOption != Abstention {
+		return VoteOptionError
+	}
+
+	proposal, err := GetProposal(vote.ProposalID, state)
+	if err != nil {
+		log.Error("find proposal error", "proposalID", vote.ProposalID)
+		return err
+	} else if proposal == nil {
+		return ProposalNotFound
+	}
+
+	if ProposalTallyMode(proposal) != StakeWeightedTally {
+		return DelegatorVoteNotSupported
+	}
+
+	votingIDs, err := ListVotingProposalID(blockHash)
+	if err != nil {
+		log.Error("list voting proposal error", "blockHash", blockHash, "blockNumber", blockNumber, "err", err)
+		return err
+	} else if !xutil.InHashList(vote.ProposalID, votingIDs) {
+		return ProposalNotAtVoting
+	}
+
+	// only the delegations to the verifiers of the proposal are weighted
+	verifierList, err := ListAccuVerifier(blockHash, vote.ProposalID)
+	if err != nil {
+		log.Error("list accumulated verifiers error", "proposalID", vote.ProposalID, "blockHash", blockHash, "err", err)
+		return err
+	} else if !xutil.InNodeIDList(vote.VoteNodeID, verifierList) {
+		return VoteNodeNotVerifier
+	}
+
+	nodeAddress, err := xutil.NodeId2Addr(vote.VoteNodeID)
+	if err != nil {
+		return err
+	}
+	if canBase, err := stk.GetCanBase(blockHash, nodeAddress); err != nil || canBase == nil || canBase.StakingBlockNum != stakingBlockNum {
+		return DelegationNotFound
+	}
+	if del, err := stk.GetDelegateInfo(blockHash, from, vote.VoteNodeID, stakingBlockNum); err != nil || del == nil || DelegationAmount(del).Sign() == 0 {
+		return DelegationNotFound
+	}
+
+	if voted, err := GetDelegatorVoteValue(vote.ProposalID, from, vote.VoteNodeID, blockHash); err != nil {
+		log.Error("get delegator vote error", "proposalID", vote.ProposalID, "blockHash", blockHash, "blockNumber", blockNumber)
+		return err
+	} else if voted != nil {
+		return VoteDuplicated
+	}
+
+	if count, err := GetDelegatorVoteCount(vote.ProposalID, vote.VoteNodeID, blockHash); err != nil {
+		log.Error("get delegator vote count error", "proposalID", vote.ProposalID, "voteNodeID", vote.VoteNodeID, "blockHash", blockHash, "blockNumber", blockNumber)
+		return err
+	} else if count >= MaxDelegatorVotesPerNode {
+		return DelegatorVotesExceeded
+	}
+
+	voteValue := DelegatorVoteValue{
+		Delegator:       from,
+		VoteNodeID:      vote.VoteNodeID,
+		StakingBlockNum: stakingBlockNum,
+		VoteOption:      vote.VoteOption,
+	}
+	if err := AddDelegatorVoteValue(vote.ProposalID, voteValue, blockHash); err != nil {
+		log.Error("save delegator vote error", "proposalID", vote.ProposalID)
+		return err
+	}
+	return nil
+}
+
+// DelegationAmount returns the von of the delegation, in effect and in hesitation
+func DelegationAmount(del *staking.Delegation) *big.Int {
+	amount := new(big.Int).Add(del.Released, del.ReleasedHes)
+	amount.Add(amount, del.RestrictingPlan)
+	return amount.Add(amount, del.RestrictingPlanHes)
+}
+
 // check if the node a verifier, and the caller address is same as the staking address
 func checkVerifier(from common.Address, nodeID discover.NodeID, blockHash common.Hash, blockNumber uint64, stk Staking) error {
 	log.Debug("call checkVerifier", "from", from, "blockHash", blockHash, "blockNumber", blockNumber, "nodeID", nodeID)
@@ -531,7 +770,7 @@ func FindGovernParam(module, name string, blockHash common.Hash) (*GovernParam,
 			if value, err := findGovernParamValue(module, name, blockHash); err != nil {
 				return nil, err
 			} else if value != nil {
-				param := &GovernParam{item, value, nil}
+				param := &GovernParam{item, value, nil, nil}
 				return param, nil
 			}
 		}
@@ -568,6 +807,9 @@ func checkCandidate(from common.Address, nodeID discover.NodeID, blockHash commo
 
 type ParamVerifier func(blockNumber uint64, blockHash common.Hash, value string) error
 
+// ParamSetter sets the value of the govern parameter to the economic model
+type ParamSetter func(ec *xcom.EconomicModel, value string) error
+
 func GetGovernParamValue(module, name string, blockNumber uint64, blockHash common.Hash) (string, error) {
 	paramValue, err := findGovernParamValue(module, name, blockHash)
 	if err != nil {
@@ -586,6 +828,99 @@ func GetGovernParamValue(module, name string, blockNumber uint64, blockHash comm
 	}
 }
 
+// governParamCache caches the values of the params read by GovernEconomicModel by their digest,
+// they are reloaded once the digest is changed by a param value stored, e.g. by an executed param proposal.
+var governParamCache struct {
+	sync.Mutex
+	digest common.Hash
+	values []*ParamValue
+}
+
+// readGovernParamValues reads the values of the params with a ParamSetter, they're in the order of queryInitParam,
+// the value is nil if the param is missing.
+func readGovernParamValues(blockHash common.Hash) ([]*ParamValue, error) {
+	initParams := queryInitParam()
+	values := make([]*ParamValue, len(initParams))
+	for i, param := range initParams {
+		if nil == param.ParamSetter {
+			continue
+		}
+		paramValue, err := findGovernParamValue(param.ParamItem.Module, param.ParamItem.Name, blockHash)
+		if err != nil {
+			log.Error("get govern parameter value failed", "module", param.ParamItem.Module, "name", param.ParamItem.Name,
+				"blockHash", blockHash, "err", err)
+			return nil, err
+		}
+		values[i] = paramValue
+	}
+	return values, nil
+}
+
+// loadGovernParamValues returns the cached param values if the digest at the block matches,
+// the param values are read every time before FeatureGovernEconomicModel is activated.
+func loadGovernParamValues(blockHash common.Hash) ([]*ParamValue, error) {
+	digest, err := getGovernParamDigest(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if digest != (common.Hash{}) {
+		governParamCache.Lock()
+		values := governParamCache.values
+		cached := governParamCache.digest == digest
+		governParamCache.Unlock()
+		if cached {
+			return values, nil
+		}
+	}
+
+	values, err := readGovernParamValues(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if digest != (common.Hash{}) {
+		governParamCache.Lock()
+		governParamCache.digest, governParamCache.values = digest, values
+		governParamCache.Unlock()
+	}
+	return values, nil
+}
+
+// GovernEconomicModel returns the economic model in effect at the block,
+// the governable fields are overridden by the values of the govern parameters.
+func GovernEconomicModel(blockNumber uint64, blockHash common.Hash) (*xcom.EconomicModel, error) {
+	values, err := loadGovernParamValues(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	ec := xcom.CopyEconomicModel()
+	for i, param := range queryInitParam() {
+		paramValue := values[i]
+		// the chain is initialized without the param, keeps the value of the genesis
+		if nil == param.ParamSetter || paramValue == nil {
+			continue
+		}
+		value := paramValue.Value
+		if blockNumber < paramValue.ActiveBlock {
+			value = paramValue.StaleValue
+		}
+		if err := param.ParamSetter(ec, value); err != nil {
+			log.Error("set govern parameter value failed", "module", param.ParamItem.Module, "name", param.ParamItem.Name,
+				"value", value, "blockNumber", blockNumber, "blockHash", blockHash, "err", err)
+			return nil, err
+		}
+	}
+	return ec, nil
+}
+
+// GovernHesitateRatio returns the quantity of epoch the staking and delegation funds hesitate for at the block
+func GovernHesitateRatio(blockNumber uint64, blockHash common.Hash) (uint64, error) {
+	ec, err := GovernEconomicModel(blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+	return ec.Staking.HesitateRatio, nil
+}
+
 func GovernStakeThreshold(blockNumber uint64, blockHash common.Hash) (*big.Int, error) {
 	thresholdStr, err := GetGovernParamValue(ModuleStaking, KeyStakeThreshold, blockNumber, blockHash)
 	if nil != err {
@@ -698,6 +1033,62 @@ func GovernSlashBlocksReward(blockNumber uint64, blockHash common.Hash) (uint32,
 	return uint32(reward), nil
 }
 
+func GovernVoteSignedWindow(blockNumber uint64, blockHash common.Hash) (uint32, error) {
+	windowStr, err := GetGovernParamValue(ModuleSlashing, KeyVoteSignedWindow, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	window, err := strconv.Atoi(windowStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint32(window), nil
+}
+
+func GovernMinVoteSignedRatio(blockNumber uint64, blockHash common.Hash) (uint32, error) {
+	ratioStr, err := GetGovernParamValue(ModuleSlashing, KeyMinVoteSignedRatio, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	ratio, err := strconv.Atoi(ratioStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint32(ratio), nil
+}
+
+func GovernSlashFractionLowVote(blockNumber uint64, blockHash common.Hash) (uint32, error) {
+	fractionStr, err := GetGovernParamValue(ModuleSlashing, KeySlashFractionLowVote, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	fraction, err := strconv.Atoi(fractionStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint32(fraction), nil
+}
+
+func GovernJailDuration(blockNumber uint64, blockHash common.Hash) (uint32, error) {
+	durationStr, err := GetGovernParamValue(ModuleSlashing, KeyJailDuration, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	duration, err := strconv.Atoi(durationStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint32(duration), nil
+}
+
 func GovernMaxBlockGasLimit(blockNumber uint64, blockHash common.Hash) (int, error) {
 	gasLimitStr, err := GetGovernParamValue(ModuleBlock, KeyMaxBlockGasLimit, blockNumber, blockHash)
 	if nil != err {
@@ -725,3 +1116,59 @@ func GovernMaxBlockGasLimit(blockNumber uint64, blockHash common.Hash) (int, err
 //
 //	return size, nil
 //}
+
+// setProposalTallyMode records the governed tally mode on the proposals which can be tallied by stake.
+func setProposalTallyMode(proposal Proposal, blockHash common.Hash, blockNumber uint64) error {
+	mode, err := GovernTallyMode(proposal.GetProposalType(), blockNumber, blockHash)
+	if err != nil {
+		return err
+	}
+	switch p := proposal.(type) {
+	case *TextProposal:
+		p.TallyMode = mode
+	case *ParamProposal:
+		p.TallyMode = mode
+	}
+	return nil
+}
+
+// ProposalTallyMode returns the tally mode recorded on the proposal at the submission,
+// the proposals submitted before the stake tally are tallied by node count.
+func ProposalTallyMode(proposal Proposal) TallyMode {
+	switch p := proposal.(type) {
+	case *TextProposal:
+		return p.TallyMode
+	case *ParamProposal:
+		return p.TallyMode
+	}
+	return NodeCountTally
+}
+
+// GovernTallyMode returns the tally mode of the proposal type,
+// only the text and param proposals can be tallied by stake.
+func GovernTallyMode(proposalType ProposalType, blockNumber uint64, blockHash common.Hash) (TallyMode, error) {
+	var name string
+	switch proposalType {
+	case Text:
+		name = KeyTextProposalTallyMode
+	case Param:
+		name = KeyParamProposalTallyMode
+	default:
+		return NodeCountTally, nil
+	}
+
+	modeStr, err := GetGovernParamValue(ModuleGov, name, blockNumber, blockHash)
+	if err == UnsupportedGovernParam {
+		// the chain is initialized without the tally mode params
+		return NodeCountTally, nil
+	} else if nil != err {
+		return NodeCountTally, err
+	}
+
+	mode, err := strconv.Atoi(modeStr)
+	if nil != err {
+		return NodeCountTally, err
+	}
+
+	return TallyMode(mode), nil
+}
@@ -82,6 +82,13 @@ func GetProposal(proposalID common.Hash, state xcom.StateDB) (Proposal, error) {
 			return nil, e
 		}
 		return &proposal, nil
+	} else if pType == byte(Spend) {
+		var proposal SpendProposal
+		if e := json.Unmarshal(pData, &proposal); e != nil {
+			log.Error("cannot parse data to spend proposal")
+			return nil, e
+		}
+		return &proposal, nil
 	} else {
 		return nil, common.InternalError.Wrap("Incorrect proposal type.")
 	}
@@ -139,6 +146,78 @@ func UpdateVoteValue(proposalID common.Hash, voteValueList []VoteValue, blockHas
 	return nil
 }
 
+// list the delegators' vote detail, in the order of the delegator and the node
+func ListDelegatorVoteValue(proposalID common.Hash, blockHash common.Hash) ([]DelegatorVoteValue, error) {
+	itr := snapshotdb.Instance().Ranking(blockHash, KeyDelegatorVotePrefix(proposalID), 0)
+	defer itr.Release()
+
+	var voteList []DelegatorVoteValue
+	for itr.Next() {
+		if len(itr.Value()) == 0 {
+			continue
+		}
+		var vote DelegatorVoteValue
+		if err := rlp.DecodeBytes(itr.Value(), &vote); err != nil {
+			return nil, err
+		}
+		voteList = append(voteList, vote)
+	}
+	return voteList, itr.Error()
+}
+
+// GetDelegatorVoteValue returns the vote of the delegator for its delegation to the node, nil if it isn't voted.
+func GetDelegatorVoteValue(proposalID common.Hash, delegator common.Address, nodeID discover.NodeID, blockHash common.Hash) (*DelegatorVoteValue, error) {
+	voteBytes, err := get(blockHash, KeyDelegatorVote(proposalID, delegator, nodeID))
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	if len(voteBytes) == 0 {
+		return nil, nil
+	}
+	var vote DelegatorVoteValue
+	if err := rlp.DecodeBytes(voteBytes, &vote); err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+// AddDelegatorVoteValue saves the delegator's vote under its own key, so a vote writes the same bytes
+// however many delegators have voted, and counts the delegators' votes for the node.
+func AddDelegatorVoteValue(proposalID common.Hash, vote DelegatorVoteValue, blockHash common.Hash) error {
+	count, err := GetDelegatorVoteCount(proposalID, vote.VoteNodeID, blockHash)
+	if err != nil {
+		return err
+	}
+	if err := put(blockHash, KeyDelegatorVote(proposalID, vote.Delegator, vote.VoteNodeID), vote); err != nil {
+		return err
+	}
+	return put(blockHash, KeyDelegatorVoteCount(proposalID, vote.VoteNodeID), count+1)
+}
+
+// GetDelegatorVoteCount returns the number of the delegators' votes of the proposal for the delegations to the node
+func GetDelegatorVoteCount(proposalID common.Hash, nodeID discover.NodeID, blockHash common.Hash) (uint32, error) {
+	countBytes, err := get(blockHash, KeyDelegatorVoteCount(proposalID, nodeID))
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return 0, err
+	}
+	var count uint32
+	if len(countBytes) > 0 {
+		if err := rlp.DecodeBytes(countBytes, &count); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// DelegatorVoteSize returns the bytes written by the delegator's vote
+func DelegatorVoteSize(proposalID common.Hash, vote DelegatorVoteValue) (int, error) {
+	enc, err := rlp.EncodeToBytes(vote)
+	if err != nil {
+		return 0, err
+	}
+	return len(KeyDelegatorVote(proposalID, vote.Delegator, vote.VoteNodeID)) + len(enc), nil
+}
+
 // TallyVoteValue statistics vote option for a proposal
 func TallyVoteValue(proposalID common.Hash, blockHash common.Hash) (yeas, nays, abstentions uint16, e error) {
 	yes := uint16(0)
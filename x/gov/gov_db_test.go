@@ -19,6 +19,7 @@ package gov
 import (
 	"bytes"
 	"math/big"
+	"strconv"
 
 	"github.com/stretchr/testify/assert"
 
@@ -31,7 +32,9 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
 	"github.com/PlatONnetwork/PlatON-Go/crypto/sha3"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
 )
 
 var (
@@ -52,6 +55,7 @@ func TestGovDB_SetProposal_GetProposal_text(t *testing.T) {
 	defer snapshotdb.Instance().Clear()
 
 	proposal := getTxtProposal()
+	proposal.TallyMode = StakeWeightedTally
 	if e := SetProposal(proposal, statedb); e != nil {
 		t.Errorf("set proposal error,%s", e)
 	}
@@ -62,6 +66,8 @@ func TestGovDB_SetProposal_GetProposal_text(t *testing.T) {
 		if proposalGet.GetPIPID() != proposal.GetPIPID() {
 			t.Fatalf("get proposal error,expect %s,get %s", proposal.GetPIPID(), proposalGet.GetPIPID())
 		}
+		// the tally mode recorded at the submission is kept
+		assert.Equal(t, StakeWeightedTally, ProposalTallyMode(proposalGet))
 	}
 }
 
@@ -162,6 +168,38 @@ func TestGovDB_updateGovernParamValue(t *testing.T) {
 	}
 }
 
+func TestGovDB_GovernEconomicModel(t *testing.T) {
+	Init()
+	defer snapshotdb.Instance().Clear()
+
+	rate := xcom.NewBlockRewardRate()
+
+	//create block
+	blockHash, _ := newBlock(big.NewInt(1))
+
+	value := &ParamValue{"", "30", 0}
+	if err := addGovernParam(ModuleReward, KeyNewBlockRate, "for testing", value, blockHash); err != nil {
+		t.Fatalf("addGovernParam error, %s", err)
+	}
+	if err := updateGovernParamValue(ModuleReward, KeyNewBlockRate, "40", uint64(10000), blockHash); err != nil {
+		t.Fatalf("updateGovernParamValue error, %s", err)
+	}
+
+	if ec, err := GovernEconomicModel(1, blockHash); err != nil {
+		t.Fatalf("GovernEconomicModel error, %s", err)
+	} else {
+		assert.Equal(t, uint64(30), ec.Reward.NewBlockRate)
+		assert.Equal(t, xcom.MaxValidators(), ec.Staking.MaxValidators)
+	}
+	if ec, err := GovernEconomicModel(10000, blockHash); err != nil {
+		t.Fatalf("GovernEconomicModel error, %s", err)
+	} else {
+		assert.Equal(t, uint64(40), ec.Reward.NewBlockRate)
+	}
+	// the default economic model is kept
+	assert.Equal(t, rate, xcom.NewBlockRewardRate())
+}
+
 func TestGovDB_SetProposal_GetProposal_param(t *testing.T) {
 	Init()
 	defer snapshotdb.Instance().Clear()
@@ -191,6 +229,28 @@ func TestGovDB_SetProposal_GetProposal_param(t *testing.T) {
 	}
 }
 
+func TestGovDB_SetProposal_GetProposal_spend(t *testing.T) {
+	Init()
+	defer snapshotdb.Instance().Clear()
+
+	proposal := getSpendProposal()
+	if e := SetProposal(proposal, statedb); e != nil {
+		t.Errorf("set proposal error,%s", e)
+	}
+
+	if proposalGet, e := GetProposal(proposal.ProposalID, statedb); e != nil {
+		t.Errorf("get proposal error,%s", e)
+	} else {
+		spendGet, ok := proposalGet.(*SpendProposal)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, proposal.Recipient, spendGet.Recipient)
+		assert.Equal(t, 0, proposal.Amount.Cmp(spendGet.Amount))
+		assert.Equal(t, len(proposal.Plans), len(spendGet.Plans))
+	}
+}
+
 func TestGovDB_GetProposalList(t *testing.T) {
 	Init()
 	defer snapshotdb.Instance().Clear()
@@ -315,6 +375,70 @@ func TestGovDB_SetVote_ListVoteValue(t *testing.T) {
 	}
 }
 
+func TestGovDB_AddDelegatorVote_ListDelegatorVoteValue(t *testing.T) {
+	Init()
+	defer snapshotdb.Instance().Clear()
+
+	proposalID := common.Hash{0x03}
+	blockHash, _ := newBlock(big.NewInt(1))
+
+	var voteList []DelegatorVoteValue
+	var size int
+	for i, nodeId := range NodeIDList {
+		vote := DelegatorVoteValue{
+			Delegator:       common.BigToAddress(big.NewInt(int64(i + 1))),
+			VoteNodeID:      nodeId,
+			StakingBlockNum: uint64(i),
+			VoteOption:      No,
+		}
+		// every vote writes the same bytes
+		voteSize, err := DelegatorVoteSize(proposalID, vote)
+		if err != nil {
+			t.Fatalf("size delegator vote error,%s", err)
+		}
+		if i > 0 {
+			assert.Equal(t, size, voteSize)
+		}
+		size = voteSize
+
+		if err := AddDelegatorVoteValue(proposalID, vote, blockHash); err != nil {
+			t.Fatalf("add delegator vote error,%s", err)
+		}
+		voteList = append(voteList, vote)
+	}
+
+	if voteValueList, err := ListDelegatorVoteValue(proposalID, blockHash); err != nil {
+		t.Errorf("list proposal's delegator vote value error,%s", err)
+	} else {
+		assert.Equal(t, voteList, voteValueList)
+	}
+
+	// the votes are counted for each node
+	if count, err := GetDelegatorVoteCount(proposalID, voteList[0].VoteNodeID, blockHash); err != nil {
+		t.Errorf("get proposal's delegator vote count error,%s", err)
+	} else {
+		assert.Equal(t, uint32(1), count)
+	}
+
+	if vote, err := GetDelegatorVoteValue(proposalID, voteList[1].Delegator, voteList[1].VoteNodeID, blockHash); err != nil {
+		t.Errorf("get delegator vote value error,%s", err)
+	} else {
+		assert.Equal(t, voteList[1], *vote)
+	}
+	if vote, err := GetDelegatorVoteValue(proposalID, voteList[1].Delegator, voteList[0].VoteNodeID, blockHash); err != nil {
+		t.Errorf("get delegator vote value error,%s", err)
+	} else {
+		assert.Nil(t, vote)
+	}
+
+	// the delegator votes are not counted as the verifier votes
+	if voteValueList, err := ListVoteValue(proposalID, blockHash); err != nil {
+		t.Errorf("list proposal's vote value error,%s", err)
+	} else {
+		assert.Equal(t, 0, len(voteValueList))
+	}
+}
+
 /*
 func TestGovDB_ListVotedVerifier(t *testing.T) {
 	Init()
@@ -480,6 +604,25 @@ func TestGovDB_AddActiveVersion(t *testing.T) {
 	}
 }
 
+func TestGovDB_GetActiveVersion(t *testing.T) {
+	Init()
+	defer snapshotdb.Instance().Clear()
+
+	if err := AddActiveVersion(uint32(32), 0, statedb); err != nil {
+		t.Fatalf("add active version error...%s", err)
+	}
+	if err := AddActiveVersion(uint32(33<<8), 10000, statedb); err != nil {
+		t.Fatalf("add active version error...%s", err)
+	}
+
+	assert.Equal(t, uint32(32), GetActiveVersion(9999, statedb))
+	assert.Equal(t, uint32(33<<8), GetActiveVersion(10000, statedb))
+
+	feature := params.RegisterFeature("gov.test", uint32(33<<8))
+	assert.False(t, IsFeatureActive(feature, 9999, statedb))
+	assert.True(t, IsFeatureActive(feature, 10000, statedb))
+}
+
 func TestGovDB_TallyResult(t *testing.T) {
 	Init()
 	defer snapshotdb.Instance().Clear()
@@ -830,6 +973,96 @@ func TestGovDB_FindGovernParamValue(t *testing.T) {
 	}
 }
 
+func TestGovDB_ActivateFeatures(t *testing.T) {
+	Init()
+	defer snapshotdb.Instance().Clear()
+	blockHash, _ := newBlock(big.NewInt(1))
+
+	version := uint32(0<<16 | 99<<8 | 0)
+	feature := params.RegisterFeature("test.seedParams", version)
+	activated := 0
+	RegisterFeatureActivator(feature, func(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+		activated++
+		return SeedGovernParams(blockHash, ModuleSlashing, KeyJailDuration)
+	})
+
+	if _, err := GetGovernParamValue(ModuleSlashing, KeyJailDuration, 1, blockHash); err != UnsupportedGovernParam {
+		t.Fatalf("the param should be unsupported before the feature, err: %v", err)
+	}
+	if err := ActivateFeatures(params.GenesisVersion, params.GenesisVersion, blockHash, 1, statedb); err != nil || activated != 0 {
+		t.Fatalf("the feature should not be activated, activated: %d, err: %v", activated, err)
+	}
+	if err := ActivateFeatures(params.GenesisVersion, version, blockHash, 1, statedb); err != nil || activated != 1 {
+		t.Fatalf("the feature should be activated once, activated: %d, err: %v", activated, err)
+	}
+	param, err := FindGovernParam(ModuleSlashing, KeyJailDuration, blockHash)
+	if err != nil || param == nil {
+		t.Fatalf("the param is not seeded, err: %v", err)
+	}
+	assert.Equal(t, strconv.Itoa(int(xcom.JailDuration())), param.ParamValue.Value)
+
+	// the seeded param keeps its governed value
+	if err := UpdateGovernParamValue(ModuleSlashing, KeyJailDuration, "7", 1, blockHash); err != nil {
+		t.Fatal(err)
+	}
+	if err := SeedGovernParams(blockHash, ModuleSlashing, KeyJailDuration); err != nil {
+		t.Fatal(err)
+	}
+	items, err := listGovernParamItem(ModuleSlashing, blockHash)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("the param should be listed once, items: %d, err: %v", len(items), err)
+	}
+	if value, _ := GetGovernParamValue(ModuleSlashing, KeyJailDuration, 1, blockHash); value != "7" {
+		t.Fatalf("the governed value is overridden: %s", value)
+	}
+	if err := SeedGovernParams(blockHash, ModuleSlashing, "unknown"); err == nil {
+		t.Fatal("seeding an unknown param should fail")
+	}
+}
+
+func TestGovDB_GovernEconomicModelCache(t *testing.T) {
+	Init()
+	defer snapshotdb.Instance().Clear()
+	blockHash, _ := newBlock(big.NewInt(1))
+
+	if digest, err := getGovernParamDigest(blockHash); err != nil || digest != (common.Hash{}) {
+		t.Fatalf("the digest should not be kept before the feature, digest: %s, err: %v", digest.Hex(), err)
+	}
+	version, _ := params.FeatureVersion(FeatureGovernEconomicModel)
+	if err := ActivateFeatures(params.GenesisVersion, version, blockHash, 1, statedb); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := GetGovernParamValue(ModuleStaking, KeyHesitateRatio, 1, blockHash); err != nil || value != strconv.FormatUint(xcom.HesitateRatio(), 10) {
+		t.Fatalf("the param is not seeded, value: %s, err: %v", value, err)
+	}
+	digest, err := getGovernParamDigest(blockHash)
+	if err != nil || digest == (common.Hash{}) {
+		t.Fatalf("the digest should be kept since the feature, err: %v", err)
+	}
+	ec, err := GovernEconomicModel(1, blockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, xcom.HesitateRatio(), ec.Staking.HesitateRatio)
+	assert.Equal(t, digest, governParamCache.digest)
+
+	// the executed param proposal changes the digest, the values are reloaded
+	if err := UpdateGovernParamValue(ModuleStaking, KeyHesitateRatio, "3", 2, blockHash); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := getGovernParamDigest(blockHash)
+	if err != nil || updated == digest {
+		t.Fatalf("the digest should be changed by the param value, err: %v", err)
+	}
+	if ratio, err := GovernHesitateRatio(1, blockHash); err != nil || ratio != xcom.HesitateRatio() {
+		t.Fatalf("the stale value should be in effect, ratio: %d, err: %v", ratio, err)
+	}
+	if ratio, err := GovernHesitateRatio(2, blockHash); err != nil || ratio != 3 {
+		t.Fatalf("the new value should be in effect, ratio: %d, err: %v", ratio, err)
+	}
+	assert.Equal(t, updated, governParamCache.digest)
+}
+
 func newBlock(blockNumber *big.Int) (common.Hash, error) {
 
 	recognizedHash := generateHash("recognizedHash")
@@ -902,6 +1135,23 @@ func getParamProposal() *ParamProposal {
 	}
 }
 
+func getSpendProposal() *SpendProposal {
+	return &SpendProposal{
+		ProposalID:   common.Hash{0x06},
+		ProposalType: Spend,
+		PIPID:        "em6",
+		SubmitBlock:  uint64(1000),
+		Proposer:     discover.NodeID{},
+		Source:       common.HexToAddress("0x493301712671Ada506ba6Ca7891F436D29185821"),
+		Recipient:    common.HexToAddress("0x12c171900f010b17e969702efa044d077e868082"),
+		Amount:       big.NewInt(3e18),
+		Plans: []restricting.RestrictingPlan{
+			{Epoch: 1, Amount: big.NewInt(1e18)},
+			{Epoch: 2, Amount: big.NewInt(2e18)},
+		},
+	}
+}
+
 var voteValueList = []VoteValue{
 	{
 		VoteNodeID: discover.MustHexID("0x1dd9d65c4552b5eb43d5ad55a2ee3f56c6cbc1c64a5c8d659f51fcd51bace24351232b8d7821617d2b29b54b81cdefb9b3e9c37d7fd5f63270bcc9e1a6f6a439"),
@@ -944,3 +1194,13 @@ func rlpHash(x interface{}) (h common.Hash) {
 	hw.Sum(h[:0])
 	return h
 }
+
+func TestGovParams_rateVerifier(t *testing.T) {
+	assert.NotNil(t, rateVerifier(0, common.ZeroHash, "0"))
+	assert.Nil(t, rateVerifier(0, common.ZeroHash, "0.001"))
+	assert.Nil(t, rateVerifier(0, common.ZeroHash, "0.999"))
+	assert.NotNil(t, rateVerifier(0, common.ZeroHash, "1"))
+	assert.NotNil(t, rateVerifier(0, common.ZeroHash, "1.5"))
+	assert.NotNil(t, rateVerifier(0, common.ZeroHash, "-0.5"))
+	assert.NotNil(t, rateVerifier(0, common.ZeroHash, "rate"))
+}
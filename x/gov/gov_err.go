@@ -53,4 +53,14 @@ var (
 	VotingParamProposalExist          = common.NewBizError(302032, "another param proposal at voting stage")
 	GovernParamValueError             = common.NewBizError(302033, "govern parameter value error")
 	ParamProposalIsSameValue          = common.NewBizError(302034, "the new value of the parameter proposal is the same as the old value")
+	SpendProposalNotSupported         = common.NewBizError(302035, "spend proposal is not supported")
+	SpendSourceError                  = common.NewBizError(302036, "spend source is not a foundation account")
+	SpendRecipientEmpty               = common.NewBizError(302037, "spend recipient is empty")
+	SpendAmountError                  = common.NewBizError(302038, "spend amount error")
+	SpendPlansError                   = common.NewBizError(302039, "spend restricting plans error")
+	SpendBalanceNotEnough             = common.NewBizError(302040, "balance of the spend source is not enough")
+	DelegatorVoteNotSupported         = common.NewBizError(302041, "the proposal is not tallied by stake, delegator vote is not supported")
+	DelegationNotFound                = common.NewBizError(302042, "delegation not found")
+	DelegatorVotesExceeded            = common.NewBizError(302043, "the delegator votes for the node exceed the limit")
+	VoteNodeNotVerifier               = common.NewBizError(302044, "the node is not a verifier of the proposal")
 )
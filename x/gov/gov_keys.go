@@ -20,12 +20,15 @@ import (
 	"bytes"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 )
 
 var (
 	KeyDelimiter               = []byte(":")
 	keyPrefixProposal          = []byte("PID")
 	keyPrefixVote              = []byte("Vote")
+	keyPrefixDelegatorVote     = []byte("DelVote")
+	keyPrefixDelegatorVoteCnt  = []byte("DelVoteCnt")
 	keyPrefixTallyResult       = []byte("Result")
 	keyPrefixVotingProposals   = []byte("Votings")
 	keyPrefixEndProposals      = []byte("Ends")
@@ -37,6 +40,7 @@ var (
 	keyPrefixPIPIDs            = []byte("PIPIDs")
 	keyPrefixParamItems        = []byte("ParamItems")
 	keyPrefixParamValue        = []byte("ParamValue")
+	keyPrefixParamDigest       = []byte("ParamDigest")
 )
 
 func KeyProposal(proposalID common.Hash) []byte {
@@ -54,6 +58,34 @@ func KeyVote(proposalID common.Hash) []byte {
 	}, KeyDelimiter)
 }
 
+// KeyDelegatorVotePrefix is the prefix of the delegators' votes of the proposal
+func KeyDelegatorVotePrefix(proposalID common.Hash) []byte {
+	return bytes.Join([][]byte{
+		keyPrefixDelegatorVote,
+		proposalID.Bytes(),
+		{},
+	}, KeyDelimiter)
+}
+
+// KeyDelegatorVote is the key of the vote of the delegator for its delegation to the node
+func KeyDelegatorVote(proposalID common.Hash, delegator common.Address, nodeID discover.NodeID) []byte {
+	return bytes.Join([][]byte{
+		keyPrefixDelegatorVote,
+		proposalID.Bytes(),
+		delegator.Bytes(),
+		nodeID.Bytes(),
+	}, KeyDelimiter)
+}
+
+// KeyDelegatorVoteCount is the key of the number of the delegators' votes for the delegations to the node
+func KeyDelegatorVoteCount(proposalID common.Hash, nodeID discover.NodeID) []byte {
+	return bytes.Join([][]byte{
+		keyPrefixDelegatorVoteCnt,
+		proposalID.Bytes(),
+		nodeID.Bytes(),
+	}, KeyDelimiter)
+}
+
 func KeyTallyResult(proposalID common.Hash) []byte {
 	return bytes.Join([][]byte{
 		keyPrefixTallyResult,
@@ -102,6 +134,12 @@ func KeyPIPIDs() []byte {
 func KeyParamItems() []byte {
 	return keyPrefixParamItems
 }
+
+// KeyParamDigest is the key of the digest of the govern param values
+func KeyParamDigest() []byte {
+	return keyPrefixParamDigest
+}
+
 func KeyParamValue(module, name string) []byte {
 	return bytes.Join([][]byte{
 		keyPrefixParamValue,
@@ -29,6 +29,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
 )
 
 var (
@@ -45,6 +46,10 @@ func queryInitParam() []*GovernParam {
 	return governParam
 }
 
+// initParam lists the govern parameters, the economic model fields with a ParamSetter are
+// overridden by the effective values, see GovernEconomicModel.
+// The common config is not governable, it defines the length of the consensus round and the epoch,
+// all of the block number based calculations depend on it.
 func initParam() []*GovernParam {
 	return []*GovernParam{
 
@@ -56,6 +61,9 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyStakeThreshold,
 				fmt.Sprintf("minimum amount of stake, range：[%d, %d) ", xcom.MillionLAT, xcom.TenMillionLAT)},
 			ParamValue: &ParamValue{"", xcom.StakeThreshold().String(), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setBigInt(&ec.Staking.StakeThreshold, value)
+			},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
 				threshold, ok := new(big.Int).SetString(value, 10)
@@ -74,6 +82,9 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyOperatingThreshold,
 				fmt.Sprintf("minimum amount of stake increasing funds, delegation funds, or delegation withdrawing funds, range：[%d, %d) ", xcom.TenLAT, xcom.TenThousandLAT)},
 			ParamValue: &ParamValue{"", xcom.OperatingThreshold().String(), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setBigInt(&ec.Staking.OperatingThreshold, value)
+			},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
 				threshold, ok := new(big.Int).SetString(value, 10)
@@ -94,6 +105,9 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyMaxValidators,
 				fmt.Sprintf("maximum amount of validator, range：[%d, %d]", xcom.MaxConsensusVals(), xcom.CeilMaxValidators)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.MaxValidators())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint64(&ec.Staking.MaxValidators, value)
+			},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
 				num, err := strconv.Atoi(value)
@@ -114,6 +128,9 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyUnStakeFreezeDuration,
 				fmt.Sprintf("quantity of epoch for skake withdrawal, range：(MaxEvidenceAge, %d]", xcom.CeilUnStakeFreezeDuration)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.UnStakeFreezeDuration())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint64(&ec.Staking.UnStakeFreezeDuration, value)
+			},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
 				num, err := strconv.Atoi(value)
@@ -131,6 +148,28 @@ func initParam() []*GovernParam {
 
 			},
 		},
+		{
+			ParamItem: &ParamItem{ModuleStaking, KeyHesitateRatio,
+				fmt.Sprintf("quantity of epoch, the staking and delegation funds hesitate for it before they are effective, a change applies to the funds still hesitating, range：[%d, %d]", 1, xcom.CeilHesitateRatio)},
+			ParamValue: &ParamValue{"", strconv.FormatUint(xcom.HesitateRatio(), 10), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint64(&ec.Staking.HesitateRatio, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				ratio, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed HesitateRatio is failed: %v", err)
+				}
+
+				if err := xcom.CheckHesitateRatio(ratio); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
 
 		/**
 		About Slashing module
@@ -140,6 +179,9 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeySlashFractionDuplicateSign,
 				fmt.Sprintf("quantity of base point(1BP=1‱). Node's stake will be deducted(BPs*staking amount*1‱) it the node sign block duplicatlly, range：(%d, %d]", xcom.Zero, xcom.TenThousand)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.SlashFractionDuplicateSign())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Slashing.SlashFractionDuplicateSign, value)
+			},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
 				fraction, err := strconv.Atoi(value)
@@ -159,6 +201,9 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeyDuplicateSignReportReward,
 				fmt.Sprintf("quantity of base point(1bp=1%%). Bonus(BPs*deduction amount for sign block duplicatlly*%%) to the node who reported another's duplicated-signature, range：(%d, %d]", xcom.Zero, xcom.Eighty)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.DuplicateSignReportReward())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Slashing.DuplicateSignReportReward, value)
+			},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
 				fraction, err := strconv.Atoi(value)
@@ -178,6 +223,9 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeyMaxEvidenceAge,
 				fmt.Sprintf("quantity of epoch. During these epochs after a node duplicated-sign, others can report it, range：(%d, UnStakeFreezeDuration)", xcom.Zero)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.MaxEvidenceAge())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Slashing.MaxEvidenceAge, value)
+			},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
 				age, err := strconv.Atoi(value)
@@ -199,6 +247,9 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeySlashBlocksReward,
 				fmt.Sprintf("quantity of block, the total bonus amount for these blocks will be deducted from a inefficient node's stake, range：[%d, %d)", xcom.Zero, xcom.CeilBlocksReward)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.SlashBlocksReward())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Slashing.SlashBlocksReward, value)
+			},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
 				rewards, err := strconv.Atoi(value)
@@ -214,6 +265,360 @@ func initParam() []*GovernParam {
 
 			},
 		},
+		{
+			ParamItem: &ParamItem{ModuleSlashing, KeyVoteSignedWindow,
+				fmt.Sprintf("quantity of recent QCs, the vote participation of a validator is counted within them, zero disables the slashing of low vote participation, range：[%d, %d]", xcom.Zero, xcom.CeilVoteSignedWindow)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.VoteSignedWindow())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Slashing.VoteSignedWindow, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				window, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed VoteSignedWindow is failed: %v", err)
+				}
+
+				if err := xcom.CheckVoteSignedWindow(window); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleSlashing, KeyMinVoteSignedRatio,
+				fmt.Sprintf("minimum percentage of the QCs signed by a validator within the window, below it the validator will be slashed and jailed, range：[%d, %d]", xcom.Zero, xcom.Hundred)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.MinVoteSignedRatio())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Slashing.MinVoteSignedRatio, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				ratio, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed MinVoteSignedRatio is failed: %v", err)
+				}
+
+				if err := xcom.CheckMinVoteSignedRatio(ratio); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleSlashing, KeySlashFractionLowVote,
+				fmt.Sprintf("the ten thousandth of the stake will be deducted from a validator with low vote participation, range：[%d, %d]", xcom.Zero, xcom.TenThousand)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.SlashFractionLowVote())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Slashing.SlashFractionLowVote, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				fraction, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed SlashFractionLowVote is failed: %v", err)
+				}
+
+				if err := xcom.CheckSlashFractionLowVote(fraction); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleSlashing, KeyJailDuration,
+				fmt.Sprintf("quantity of epoch, the jailed candidate can be unjailed after it, range：[%d, %d]", xcom.Zero, xcom.CeilJailDuration)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.JailDuration())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Slashing.JailDuration, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				duration, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed JailDuration is failed: %v", err)
+				}
+
+				if err := xcom.CheckJailDuration(duration); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+
+		/**
+		About Gov module
+		*/
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyTextProposalTallyMode,
+				fmt.Sprintf("tally mode of the text proposal, 0: one vote per verifier, 1: by the stake of verifiers and delegators, range：[%d, %d]", xcom.Zero, xcom.CeilTallyMode)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.TextProposal_TallyMode())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Gov.TextProposalTallyMode, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				mode, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed TextProposalTallyMode is failed: %v", err)
+				}
+
+				if err := xcom.CheckTallyMode(mode); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyParamProposalTallyMode,
+				fmt.Sprintf("tally mode of the param proposal, 0: one vote per verifier, 1: by the stake of verifiers and delegators, range：[%d, %d]", xcom.Zero, xcom.CeilTallyMode)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.ParamProposal_TallyMode())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Gov.ParamProposalTallyMode, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				mode, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed ParamProposalTallyMode is failed: %v", err)
+				}
+
+				if err := xcom.CheckTallyMode(mode); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyVersionProposalVoteDuration,
+				fmt.Sprintf("maximum voting duration of the version proposal, counted in consensus rounds, range：[%d, %d]", xutil.ConsensusSize()*xcom.Interval(), xcom.CeilProposalVoteDuration)},
+			ParamValue: &ParamValue{"", strconv.FormatUint(xcom.VersionProposalVote_DurationSeconds(), 10), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint64(&ec.Gov.VersionProposalVoteDurationSeconds, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				seconds, err := strconv.ParseUint(value, 10, 64)
+				if nil != err {
+					return fmt.Errorf("Parsed VersionProposalVoteDurationSeconds is failed: %v", err)
+				}
+
+				if err := xcom.CheckProposalVoteDuration(seconds, xutil.ConsensusSize()*xcom.Interval()); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyVersionProposalSupportRate,
+				fmt.Sprintf("the version proposal will pass if the support rate reaches this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.VersionProposal_SupportRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.VersionProposalSupportRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyTextProposalVoteDuration,
+				fmt.Sprintf("voting duration of the text proposal, counted in consensus rounds, range：[%d, %d]", xutil.ConsensusSize()*xcom.Interval(), xcom.CeilProposalVoteDuration)},
+			ParamValue: &ParamValue{"", strconv.FormatUint(xcom.TextProposalVote_DurationSeconds(), 10), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint64(&ec.Gov.TextProposalVoteDurationSeconds, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				seconds, err := strconv.ParseUint(value, 10, 64)
+				if nil != err {
+					return fmt.Errorf("Parsed TextProposalVoteDurationSeconds is failed: %v", err)
+				}
+
+				if err := xcom.CheckProposalVoteDuration(seconds, xutil.ConsensusSize()*xcom.Interval()); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyTextProposalVoteRate,
+				fmt.Sprintf("the text proposal will pass if the vote rate exceeds this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.TextProposal_VoteRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.TextProposalVoteRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyTextProposalSupportRate,
+				fmt.Sprintf("the text proposal will pass if the support rate reaches this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.TextProposal_SupportRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.TextProposalSupportRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyCancelProposalVoteRate,
+				fmt.Sprintf("the cancel proposal will pass if the vote rate exceeds this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.CancelProposal_VoteRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.CancelProposalVoteRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyCancelProposalSupportRate,
+				fmt.Sprintf("the cancel proposal will pass if the support rate reaches this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.CancelProposal_SupportRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.CancelProposalSupportRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyParamProposalVoteDuration,
+				fmt.Sprintf("voting duration of the param proposal, counted in epochs, range：[%d, %d]", xutil.CalcEpochDuration(), xcom.CeilProposalVoteDuration)},
+			ParamValue: &ParamValue{"", strconv.FormatUint(xcom.ParamProposalVote_DurationSeconds(), 10), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint64(&ec.Gov.ParamProposalVoteDurationSeconds, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				seconds, err := strconv.ParseUint(value, 10, 64)
+				if nil != err {
+					return fmt.Errorf("Parsed ParamProposalVoteDurationSeconds is failed: %v", err)
+				}
+
+				if err := xcom.CheckProposalVoteDuration(seconds, xutil.CalcEpochDuration()); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyParamProposalVoteRate,
+				fmt.Sprintf("the param proposal will pass if the vote rate exceeds this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.ParamProposal_VoteRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.ParamProposalVoteRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeyParamProposalSupportRate,
+				fmt.Sprintf("the param proposal will pass if the support rate reaches this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.ParamProposal_SupportRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.ParamProposalSupportRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeySpendProposalVoteDuration,
+				fmt.Sprintf("voting duration of the spend proposal, counted in epochs, range：[%d, %d]", xutil.CalcEpochDuration(), xcom.CeilProposalVoteDuration)},
+			ParamValue: &ParamValue{"", strconv.FormatUint(xcom.SpendProposalVote_DurationSeconds(), 10), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint64(&ec.Gov.SpendProposalVoteDurationSeconds, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				seconds, err := strconv.ParseUint(value, 10, 64)
+				if nil != err {
+					return fmt.Errorf("Parsed SpendProposalVoteDurationSeconds is failed: %v", err)
+				}
+
+				if err := xcom.CheckProposalVoteDuration(seconds, xutil.CalcEpochDuration()); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeySpendProposalVoteRate,
+				fmt.Sprintf("the spend proposal will pass if the vote rate exceeds this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.SpendProposal_VoteRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.SpendProposalVoteRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleGov, KeySpendProposalSupportRate,
+				fmt.Sprintf("the spend proposal will pass if the support rate reaches this value, range：(%d, %d)", xcom.Zero, 1)},
+			ParamValue: &ParamValue{"", formatRate(xcom.SpendProposal_SupportRate()), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setFloat64(&ec.Gov.SpendProposalSupportRate, value)
+			},
+			ParamVerifier: rateVerifier,
+		},
+
+		/**
+		About Reward module
+		*/
+		{
+			ParamItem: &ParamItem{ModuleReward, KeyNewBlockRate,
+				fmt.Sprintf("percentage of the annual issuance rewarded to the block producers, the rest is rewarded by staking, range：[%d, %d]", xcom.Zero, xcom.Hundred)},
+			ParamValue: &ParamValue{"", strconv.FormatUint(xcom.NewBlockRewardRate(), 10), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint64(&ec.Reward.NewBlockRate, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				rate, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed NewBlockRate is failed: %v", err)
+				}
+
+				if err := xcom.CheckNewBlockRate(rate); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleReward, KeyPlatONFoundationYear,
+				fmt.Sprintf("quantity of year, the PlatON foundation is allotted from the annual issuance after it, range：[%d, %s)", 1, xcom.PositiveInfinity)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.PlatONFoundationYear())), 0},
+			ParamSetter: func(ec *xcom.EconomicModel, value string) error {
+				return setUint32(&ec.Reward.PlatONFoundationYear, value)
+			},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				year, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed PlatONFoundationYear is failed: %v", err)
+				}
+
+				if err := xcom.CheckPlatONFoundationYear(year); nil != err {
+					return err
+				}
+
+				return nil
+
+			},
+		},
 
 		/**
 		About Block module
@@ -261,6 +666,54 @@ func initParam() []*GovernParam {
 	}
 }
 
+func setBigInt(dst **big.Int, value string) error {
+	num, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return fmt.Errorf("Parsed big integer is failed: %s", value)
+	}
+	*dst = num
+	return nil
+}
+
+func setUint64(dst *uint64, value string) error {
+	num, err := strconv.ParseUint(value, 10, 64)
+	if nil != err {
+		return err
+	}
+	*dst = num
+	return nil
+}
+
+func setUint32(dst *uint32, value string) error {
+	num, err := strconv.ParseUint(value, 10, 32)
+	if nil != err {
+		return err
+	}
+	*dst = uint32(num)
+	return nil
+}
+
+func setFloat64(dst *float64, value string) error {
+	num, err := strconv.ParseFloat(value, 64)
+	if nil != err {
+		return err
+	}
+	*dst = num
+	return nil
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+func rateVerifier(blockNumber uint64, blockHash common.Hash, value string) error {
+	rate, err := strconv.ParseFloat(value, 64)
+	if nil != err {
+		return fmt.Errorf("Parsed proposal rate is failed: %v", err)
+	}
+	return xcom.CheckProposalRate(rate)
+}
+
 var ParamVerifierMap = make(map[string]ParamVerifier)
 
 func InitGenesisGovernParam(snapDB snapshotdb.DB) error {
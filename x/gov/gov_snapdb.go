@@ -21,6 +21,7 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
@@ -212,17 +213,57 @@ func getAccuVerifiers(blockHash common.Hash, proposalId common.Hash) ([]discover
 func addGovernParam(module, name, desc string, paramValue *ParamValue, blockHash common.Hash) error {
 	itemList, err := listGovernParamItem("", blockHash)
 	if err != nil {
-		return nil
+		return err
 	}
 	itemList = append(itemList, &ParamItem{module, name, desc})
 	if err := put(blockHash, keyPrefixParamItems, itemList); err != nil {
 		return err
 	}
 
+	return putGovernParamValue(module, name, paramValue, blockHash)
+}
+
+// putGovernParamValue stores the param value, and chains it into the digest of the param values if the digest is kept.
+func putGovernParamValue(module, name string, paramValue *ParamValue, blockHash common.Hash) error {
 	if err := put(blockHash, KeyParamValue(module, name), paramValue); err != nil {
 		return err
 	}
-	return nil
+	digest, err := getGovernParamDigest(blockHash)
+	if err != nil {
+		return err
+	}
+	if digest == (common.Hash{}) {
+		return nil
+	}
+	digest = crypto.Keccak256Hash(digest.Bytes(), []byte(module+"/"+name), common.MustRlpEncode(paramValue))
+	return put(blockHash, KeyParamDigest(), digest)
+}
+
+// initGovernParamDigest starts to keep the digest of the param values, from the digest of the values read at the block.
+func initGovernParamDigest(values []*ParamValue, blockHash common.Hash) error {
+	var items []ParamValue
+	for _, value := range values {
+		if value == nil {
+			value = &ParamValue{}
+		}
+		items = append(items, *value)
+	}
+	return put(blockHash, KeyParamDigest(), crypto.Keccak256Hash(common.MustRlpEncode(items)))
+}
+
+// getGovernParamDigest returns the digest of the param values, it's empty if the digest isn't kept yet.
+func getGovernParamDigest(blockHash common.Hash) (common.Hash, error) {
+	value, err := get(blockHash, KeyParamDigest())
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return common.Hash{}, err
+	}
+	var digest common.Hash
+	if len(value) > 0 {
+		if err := rlp.DecodeBytes(value, &digest); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return digest, nil
 }
 
 func findGovernParamValue(module, name string, blockHash common.Hash) (*ParamValue, error) {
@@ -256,10 +297,7 @@ func updateGovernParamValue(module, name, newValue string, activeBlock uint64, b
 		paramValue.Value = newValue
 		paramValue.ActiveBlock = activeBlock
 
-		if err := put(blockHash, KeyParamValue(module, name), paramValue); err != nil {
-			return err
-		}
-		return nil
+		return putGovernParamValue(module, name, &paramValue, blockHash)
 	}
 	return fmt.Errorf("Not found the %s.%s Govern value", module, name)
 }
@@ -274,7 +312,7 @@ func listGovernParam(module string, blockHash common.Hash) ([]*GovernParam, erro
 		if value, err := findGovernParamValue(item.Module, item.Name, blockHash); err != nil {
 			return nil, err
 		} else {
-			param := &GovernParam{item, value, nil}
+			param := &GovernParam{item, value, nil, nil}
 			paraList = append(paraList, param)
 		}
 	}
@@ -17,6 +17,8 @@
 package gov
 
 import (
+	"math/big"
+
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 )
@@ -29,8 +31,23 @@ type TallyResult struct {
 	AccuVerifiers uint16         `json:"accuVerifiers"`
 	Status        ProposalStatus `json:"status"`
 	CanceledBy    common.Hash    `json:"canceledBy"`
+	// Executed is set if the passed spend proposal is executed, it keeps false if the transfer is failed
+	Executed bool `json:"executed,omitempty"`
+	// the breakdown of the stake weighted tally, they are empty if the proposal is tallied by node count
+	TallyMode        TallyMode `json:"tallyMode,omitempty"`
+	YeaWeight        *big.Int  `json:"yeaWeight,omitempty"`
+	NayWeight        *big.Int  `json:"nayWeight,omitempty"`
+	AbstentionWeight *big.Int  `json:"abstentionWeight,omitempty"`
+	TotalWeight      *big.Int  `json:"totalWeight,omitempty"`
 }
 
+type TallyMode uint8
+
+const (
+	NodeCountTally     TallyMode = 0x00
+	StakeWeightedTally TallyMode = 0x01
+)
+
 type VoteInfo struct {
 	ProposalID common.Hash     `json:"proposalID"`
 	VoteNodeID discover.NodeID `json:"voteNodeID"`
@@ -42,6 +59,13 @@ type VoteValue struct {
 	VoteOption VoteOption      `json:"voteOption"`
 }
 
+type DelegatorVoteValue struct {
+	Delegator       common.Address  `json:"delegator"`
+	VoteNodeID      discover.NodeID `json:"voteNodeID"`
+	StakingBlockNum uint64          `json:"stakingBlockNum"`
+	VoteOption      VoteOption      `json:"voteOption"`
+}
+
 type ActiveVersionValue struct {
 	ActiveVersion uint32 `json:"ActiveVersion"`
 	ActiveBlock   uint64 `json:"ActiveBlock"`
@@ -62,4 +86,5 @@ type GovernParam struct {
 	ParamItem     *ParamItem
 	ParamValue    *ParamValue
 	ParamVerifier ParamVerifier `json:"-"`
+	ParamSetter   ParamSetter   `json:"-"`
 }
@@ -18,10 +18,12 @@ package gov
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
 )
@@ -33,6 +35,7 @@ const (
 	Version ProposalType = 0x02
 	Param   ProposalType = 0x03
 	Cancel  ProposalType = 0x04
+	Spend   ProposalType = 0x05
 )
 
 type ProposalStatus uint8
@@ -105,6 +108,7 @@ type TextProposal struct {
 	EndVotingBlock uint64
 	Proposer       discover.NodeID
 	Result         TallyResult `json:"-"`
+	TallyMode      TallyMode   `json:",omitempty"` // recorded at the submission
 }
 
 func (tp *TextProposal) GetProposalID() common.Hash {
@@ -144,7 +148,11 @@ func (tp *TextProposal) Verify(submitBlock uint64, blockHash common.Hash, state
 		return err
 	}
 
-	endVotingBlock := xutil.CalEndVotingBlock(submitBlock, xutil.CalcConsensusRounds(xcom.TextProposalVote_DurationSeconds()))
+	ec, err := GovernEconomicModel(submitBlock, blockHash)
+	if err != nil {
+		return err
+	}
+	endVotingBlock := xutil.CalEndVotingBlock(submitBlock, xutil.CalcConsensusRounds(ec.Gov.TextProposalVoteDurationSeconds))
 	tp.EndVotingBlock = endVotingBlock
 
 	log.Debug("text proposal", "endVotingBlock", tp.EndVotingBlock, "consensusSize", xutil.ConsensusSize(), "xcom.ElectionDistance()", xcom.ElectionDistance())
@@ -219,7 +227,11 @@ func (vp *VersionProposal) Verify(submitBlock uint64, blockHash common.Hash, sta
 		return EndVotingRoundsTooSmall
 	}
 
-	if vp.EndVotingRounds > xutil.CalcConsensusRounds(xcom.VersionProposalVote_DurationSeconds()) {
+	ec, err := GovernEconomicModel(submitBlock, blockHash)
+	if err != nil {
+		return err
+	}
+	if vp.EndVotingRounds > xutil.CalcConsensusRounds(ec.Gov.VersionProposalVoteDurationSeconds) {
 		return EndVotingRoundsTooLarge
 	}
 
@@ -341,7 +353,7 @@ func (cp *CancelProposal) Verify(submitBlock uint64, blockHash common.Hash, stat
 		return err
 	} else if tobeCanceled == nil {
 		return TobeCanceledProposalNotFound
-	} else if tobeCanceled.GetProposalType() != Version && tobeCanceled.GetProposalType() != Param {
+	} else if tobeCanceled.GetProposalType() != Version && tobeCanceled.GetProposalType() != Param && tobeCanceled.GetProposalType() != Spend {
 		return TobeCanceledProposalTypeError
 	} else if votingList, err := ListVotingProposal(blockHash); err != nil {
 		log.Error("list voting proposal error", "err", err)
@@ -376,6 +388,7 @@ type ParamProposal struct {
 	Module         string
 	Name           string
 	NewValue       string
+	TallyMode      TallyMode `json:",omitempty"` // recorded at the submission
 }
 
 func (pp *ParamProposal) GetProposalID() common.Hash {
@@ -453,7 +466,11 @@ func (pp *ParamProposal) Verify(submitBlock uint64, blockHash common.Hash, state
 		return PreActiveVersionProposalExist
 	}
 
-	epochRounds := xutil.CalcEpochRounds(xcom.ParamProposalVote_DurationSeconds())
+	ec, err := GovernEconomicModel(submitBlock, blockHash)
+	if err != nil {
+		return err
+	}
+	epochRounds := xutil.CalcEpochRounds(ec.Gov.ParamProposalVoteDurationSeconds)
 	endVotingBlock := xutil.CalEndVotingBlockForParamProposal(submitBlock, epochRounds)
 	pp.EndVotingBlock = endVotingBlock
 
@@ -473,6 +490,119 @@ func (pp *ParamProposal) String() string {
 		pp.ProposalID, pp.ProposalType, pp.PIPID, pp.Proposer, pp.SubmitBlock, pp.EndVotingBlock, pp.Module, pp.Name, pp.NewValue)
 }
 
+// SpendProposal spends the funds of the foundation account to the recipient,
+// the funds are locked by the restricting plans if there are.
+type SpendProposal struct {
+	ProposalID     common.Hash
+	ProposalType   ProposalType
+	PIPID          string
+	SubmitBlock    uint64
+	EndVotingBlock uint64
+	Proposer       discover.NodeID
+	Result         TallyResult `json:"-"`
+	Source         common.Address
+	Recipient      common.Address
+	Amount         *big.Int
+	Plans          []restricting.RestrictingPlan
+}
+
+func (sp *SpendProposal) GetProposalID() common.Hash {
+	return sp.ProposalID
+}
+
+func (sp *SpendProposal) GetProposalType() ProposalType {
+	return sp.ProposalType
+}
+
+func (sp *SpendProposal) GetPIPID() string {
+	return sp.PIPID
+}
+
+func (sp *SpendProposal) GetSubmitBlock() uint64 {
+	return sp.SubmitBlock
+}
+
+func (sp *SpendProposal) GetEndVotingBlock() uint64 {
+	return sp.EndVotingBlock
+}
+
+func (sp *SpendProposal) GetProposer() discover.NodeID {
+	return sp.Proposer
+}
+
+func (sp *SpendProposal) GetTallyResult() TallyResult {
+	return sp.Result
+}
+
+func (sp *SpendProposal) Verify(submitBlock uint64, blockHash common.Hash, state xcom.StateDB) error {
+	if sp.ProposalType != Spend {
+		return ProposalTypeError
+	}
+
+	if !IsFeatureActive(FeatureSpendProposal, submitBlock, state) {
+		return SpendProposalNotSupported
+	}
+	ec, err := GovernEconomicModel(submitBlock, blockHash)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyBasic(sp, blockHash, state); err != nil {
+		return err
+	}
+
+	if sp.Source != xcom.PlatONFundAccount() && sp.Source != xcom.CDFAccount() {
+		return SpendSourceError
+	}
+	if sp.Recipient == (common.Address{}) {
+		return SpendRecipientEmpty
+	}
+	if sp.Amount == nil || sp.Amount.Sign() <= 0 {
+		return SpendAmountError
+	}
+
+	if len(sp.Plans) > 0 {
+		if len(sp.Plans) > restricting.RestrictTxPlanSize {
+			return SpendPlansError
+		}
+		total := new(big.Int)
+		for _, plan := range sp.Plans {
+			if plan.Epoch == 0 || plan.Amount == nil || plan.Amount.Sign() <= 0 {
+				return SpendPlansError
+			}
+			total.Add(total, plan.Amount)
+		}
+		// the amount is all locked by the plans
+		if total.Cmp(sp.Amount) != 0 {
+			return SpendPlansError
+		}
+	}
+
+	if state.GetBalance(sp.Source).Cmp(sp.Amount) < 0 {
+		return SpendBalanceNotEnough
+	}
+
+	epochRounds := xutil.CalcEpochRounds(ec.Gov.SpendProposalVoteDurationSeconds)
+	endVotingBlock := xutil.CalEndVotingBlockForParamProposal(submitBlock, epochRounds)
+	sp.EndVotingBlock = endVotingBlock
+
+	return nil
+}
+
+func (sp *SpendProposal) String() string {
+	return fmt.Sprintf(`Proposal %x: 
+  Type:               	%x
+  PIPID:			    %s
+  Proposer:            	%x
+  SubmitBlock:        	%d
+  EndVotingBlock:   	%d
+  Source:   			%s
+  Recipient:   			%s
+  Amount:   			%s
+  Plans:   				%d`,
+		sp.ProposalID, sp.ProposalType, sp.PIPID, sp.Proposer, sp.SubmitBlock, sp.EndVotingBlock, sp.Source.Hex(), sp.Recipient.Hex(), sp.Amount, len(sp.Plans))
+}
+
 func verifyBasic(p Proposal, blockHash common.Hash, state xcom.StateDB) error {
 	log.Debug("verify proposal basic parameters", "proposalID", p.GetProposalID(), "proposer", p.GetProposer(), "pipID", p.GetPIPID(), "endVotingBlock", p.GetEndVotingBlock(), "submitBlock", p.GetSubmitBlock())
 
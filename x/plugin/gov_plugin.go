@@ -18,11 +18,13 @@ package plugin
 
 import (
 	"math"
+	"math/big"
 	"sync"
 
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/x/gov"
@@ -111,11 +113,15 @@ func (govPlugin *GovPlugin) BeginBlock(blockHash common.Hash, header *types.Head
 				return err
 			}
 
+			prevVersion := gov.GetCurrentActiveVersion(state)
 			if err = gov.AddActiveVersion(versionProposal.NewVersion, blockNumber, state); err != nil {
 				log.Error("save active version to stateDB failed.", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID)
 				return err
 			}
 			log.Info("version proposal is active.", "proposalID", versionProposal.ProposalID, "newVersion", versionProposal.NewVersion, "newVersionString", xutil.ProgramVersion2Str(versionProposal.NewVersion))
+			if err = gov.ActivateFeatures(prevVersion, versionProposal.NewVersion, blockHash, blockNumber, state); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -127,7 +133,7 @@ func (govPlugin *GovPlugin) EndBlock(blockHash common.Hash, header *types.Header
 	//log.Debug("call EndBlock()", "blockNumber", blockNumber, "blockHash", blockHash)
 
 	//text/version/cancel proposal's end voting block is ElectionBlock
-	//param proposal's end voting block is end of Epoch
+	//param/spend proposal's end voting block is end of Epoch
 	isEndOfEpoch := false
 	isElection := false
 	if xutil.IsElection(blockNumber) {
@@ -178,6 +184,11 @@ func (govPlugin *GovPlugin) EndBlock(blockHash common.Hash, header *types.Header
 				if err != nil {
 					return err
 				}
+			} else if votingProposal.GetProposalType() == gov.Spend && isEndOfEpoch {
+				_, err := tallySpend(votingProposal.(*gov.SpendProposal), blockHash, blockNumber, state)
+				if err != nil {
+					return err
+				}
 			} else {
 				log.Error("invalid proposal type", "type", votingProposal.GetProposalType())
 				return gov.ProposalTypeError
@@ -247,7 +258,11 @@ func tallyVersion(proposal *gov.VersionProposal, blockHash common.Hash, blockNum
 
 	//log.Debug("version proposal", "supportRate", supportRate, "required", Decimal(xcom.VersionProposalSupportRate()))
 
-	if Decimal(supportRate) >= Decimal(xcom.VersionProposal_SupportRate()) {
+	ec, err := gov.GovernEconomicModel(blockNumber, blockHash)
+	if err != nil {
+		return err
+	}
+	if Decimal(supportRate) >= Decimal(ec.Gov.VersionProposalSupportRate) {
 		status = gov.PreActive
 
 		if err := gov.AddPIPID(proposal.GetPIPID(), state); err != nil {
@@ -312,17 +327,17 @@ func tallyVersion(proposal *gov.VersionProposal, blockHash common.Hash, blockNum
 }
 
 func tallyText(tp *gov.TextProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
-	return tally(gov.Text, tp.ProposalID, tp.PIPID, blockHash, blockNumber, state)
+	return tally(gov.Text, tp.TallyMode, tp.ProposalID, tp.PIPID, blockHash, blockNumber, state)
 }
 
 func tallyCancel(cp *gov.CancelProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
-	if pass, err := tally(gov.Cancel, cp.ProposalID, cp.PIPID, blockHash, blockNumber, state); err != nil {
+	if pass, err := tally(gov.Cancel, gov.NodeCountTally, cp.ProposalID, cp.PIPID, blockHash, blockNumber, state); err != nil {
 		log.Info("canceled a proposal failed", "proposalID", cp.TobeCanceled, "tobeCanceledProposalID", cp.TobeCanceled)
 		return false, err
 	} else if pass {
 		if proposal, err := gov.GetExistProposal(cp.TobeCanceled, state); err != nil {
 			return false, err
-		} else if proposal.GetProposalType() != gov.Version && proposal.GetProposalType() != gov.Param {
+		} else if proposal.GetProposalType() != gov.Version && proposal.GetProposalType() != gov.Param && proposal.GetProposalType() != gov.Spend {
 			return false, gov.TobeCanceledProposalTypeError
 		}
 		if votingProposalIDList, err := gov.ListVotingProposalID(blockHash); err != nil {
@@ -387,7 +402,7 @@ func tallyCancel(cp *gov.CancelProposal, blockHash common.Hash, blockNumber uint
 }
 
 func tallyParam(pp *gov.ParamProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
-	if pass, err := tally(gov.Param, pp.ProposalID, pp.PIPID, blockHash, blockNumber, state); err != nil {
+	if pass, err := tally(gov.Param, pp.TallyMode, pp.ProposalID, pp.PIPID, blockHash, blockNumber, state); err != nil {
 		return false, err
 	} else if pass {
 		if err := gov.UpdateGovernParamValue(pp.Module, pp.Name, pp.NewValue, blockNumber+1, blockHash); err != nil {
@@ -397,7 +412,53 @@ func tallyParam(pp *gov.ParamProposal, blockHash common.Hash, blockNumber uint64
 	return true, nil
 }
 
-func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
+// tallySpend executes the transfer of the spend proposal if it is passed,
+// the proposal is still passed if the transfer is failed, but the tally result is not marked as executed.
+func tallySpend(sp *gov.SpendProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
+	if pass, err := tally(gov.Spend, gov.NodeCountTally, sp.ProposalID, sp.PIPID, blockHash, blockNumber, state); err != nil {
+		return false, err
+	} else if !pass {
+		return false, nil
+	}
+
+	if err := executeSpend(sp, blockNumber, state); err != nil {
+		if _, ok := err.(*common.BizError); !ok {
+			return false, err
+		}
+		log.Warn("execute spend proposal failed", "proposalID", sp.ProposalID, "source", sp.Source, "recipient", sp.Recipient,
+			"amount", sp.Amount, "blockNumber", blockNumber, "blockHash", blockHash, "err", err)
+		return true, nil
+	}
+
+	tallyResult, err := gov.GetTallyResult(sp.ProposalID, state)
+	if err != nil || tallyResult == nil {
+		log.Error("find spend proposal tally result failed", "proposalID", sp.ProposalID, "blockNumber", blockNumber, "blockHash", blockHash)
+		return false, err
+	}
+	tallyResult.Executed = true
+	if err := gov.SetTallyResult(*tallyResult, state); err != nil {
+		log.Error("save spend proposal tally result failed", "tallyResult", tallyResult)
+		return false, err
+	}
+	log.Info("spend proposal is executed", "proposalID", sp.ProposalID, "source", sp.Source, "recipient", sp.Recipient,
+		"amount", sp.Amount, "plans", len(sp.Plans))
+	return true, nil
+}
+
+func executeSpend(sp *gov.SpendProposal, blockNumber uint64, state xcom.StateDB) error {
+	if state.GetBalance(sp.Source).Cmp(sp.Amount) < 0 {
+		return gov.SpendBalanceNotEnough
+	}
+	if len(sp.Plans) > 0 {
+		return RestrictingInstance().AddRestrictingRecord(sp.Source, sp.Recipient, blockNumber, sp.Plans, state)
+	}
+	state.SubBalance(sp.Source, sp.Amount)
+	state.AddBalance(sp.Recipient, sp.Amount)
+	return nil
+}
+
+// tally counts the votes of the proposal by the tally mode recorded on it at the submission.
+func tally(proposalType gov.ProposalType, tallyMode gov.TallyMode, proposalID common.Hash, pipID string, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
 	//log.Debug("proposal tally", "proposalID", proposalID, "blockHash", blockHash, "blockNumber", blockNumber, "proposalID", proposalID)
 
 	verifierList, err := gov.ListAccuVerifier(blockHash, proposalID)
@@ -417,24 +478,46 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 	voteRate := Decimal(float64(yeas+nays+abstentions) / float64(verifiersCnt))
 	supportRate := Decimal(float64(yeas) / float64(yeas+nays+abstentions))
 
+	var yeaWeight, nayWeight, abstentionWeight, totalWeight *big.Int
+	if tallyMode == gov.StakeWeightedTally {
+		yeaWeight, nayWeight, abstentionWeight, totalWeight, err = tallyStake(proposalID, verifierList, blockHash)
+		if err != nil {
+			return false, err
+		}
+		votedWeight := new(big.Int).Add(yeaWeight, nayWeight)
+		votedWeight.Add(votedWeight, abstentionWeight)
+		voteRate = decimalRatio(votedWeight, totalWeight)
+		supportRate = decimalRatio(yeaWeight, votedWeight)
+	}
+
+	ec, err := gov.GovernEconomicModel(blockNumber, blockHash)
+	if err != nil {
+		return false, err
+	}
 	switch proposalType {
 	case gov.Text:
 		//log.Debug("text proposal", "voteRate", voteRate, "required", xcom.TextProposalVoteRate(), "supportRate", supportRate, "required", Decimal(xcom.TextProposalSupportRate()))
-		if voteRate > Decimal(xcom.TextProposal_VoteRate()) && supportRate >= Decimal(xcom.TextProposal_SupportRate()) {
+		if voteRate > Decimal(ec.Gov.TextProposalVoteRate) && supportRate >= Decimal(ec.Gov.TextProposalSupportRate) {
 			status = gov.Pass
 		} else {
 			status = gov.Failed
 		}
 	case gov.Cancel:
 		//log.Debug("cancel proposal", "voteRate", voteRate, "required", xcom.CancelProposalVoteRate(), "supportRate", supportRate, "required", Decimal(xcom.CancelProposalSupportRate()))
-		if voteRate > Decimal(xcom.CancelProposal_VoteRate()) && supportRate >= Decimal(xcom.CancelProposal_SupportRate()) {
+		if voteRate > Decimal(ec.Gov.CancelProposalVoteRate) && supportRate >= Decimal(ec.Gov.CancelProposalSupportRate) {
 			status = gov.Pass
 		} else {
 			status = gov.Failed
 		}
 	case gov.Param:
 		//log.Debug("param proposal", "voteRate", voteRate, "required", xcom.ParamProposalVoteRate(), "supportRate", supportRate, "required", Decimal(xcom.ParamProposalSupportRate()))
-		if voteRate > Decimal(xcom.ParamProposal_VoteRate()) && supportRate >= Decimal(xcom.ParamProposal_SupportRate()) {
+		if voteRate > Decimal(ec.Gov.ParamProposalVoteRate) && supportRate >= Decimal(ec.Gov.ParamProposalSupportRate) {
+			status = gov.Pass
+		} else {
+			status = gov.Failed
+		}
+	case gov.Spend:
+		if voteRate > Decimal(ec.Gov.SpendProposalVoteRate) && supportRate >= Decimal(ec.Gov.SpendProposalSupportRate) {
 			status = gov.Pass
 		} else {
 			status = gov.Failed
@@ -448,6 +531,13 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 		AccuVerifiers: verifiersCnt,
 		Status:        status,
 	}
+	if tallyMode == gov.StakeWeightedTally {
+		tallyResult.TallyMode = tallyMode
+		tallyResult.YeaWeight = yeaWeight
+		tallyResult.NayWeight = nayWeight
+		tallyResult.AbstentionWeight = abstentionWeight
+		tallyResult.TotalWeight = totalWeight
+	}
 	if err := gov.SetTallyResult(*tallyResult, state); err != nil {
 		log.Error("save tally result failed", "tallyResult", tallyResult)
 		return false, err
@@ -475,6 +565,88 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 	return status == gov.Pass, nil
 }
 
+// tallyStake weights the votes by the shares of the verifiers,
+// the von delegated by the delegators who vote by themselves is moved from the node's vote to the delegator's vote,
+// at most gov.MaxDelegatorVotesPerNode delegations are read for each verifier.
+func tallyStake(proposalID common.Hash, verifierList []discover.NodeID, blockHash common.Hash) (yeas, nays, abstentions, total *big.Int, err error) {
+	yeas, nays, abstentions, total = new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+
+	weights := make(map[discover.NodeID]*big.Int, len(verifierList))
+	stakingBlockNums := make(map[discover.NodeID]uint64, len(verifierList))
+	for _, nodeID := range verifierList {
+		nodeAddress, err := xutil.NodeId2Addr(nodeID)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		can, err := stk.GetCandidateInfo(blockHash, nodeAddress)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			return nil, nil, nil, nil, err
+		}
+		// the withdrew or invalid verifier has no weight
+		if can.IsEmpty() || can.IsInvalid() || nil == can.Shares {
+			continue
+		}
+		weights[nodeID] = new(big.Int).Set(can.Shares)
+		stakingBlockNums[nodeID] = can.StakingBlockNum
+		total.Add(total, can.Shares)
+	}
+
+	addWeight := func(option gov.VoteOption, weight *big.Int) {
+		switch option {
+		case gov.Yes:
+			yeas.Add(yeas, weight)
+		case gov.No:
+			nays.Add(nays, weight)
+		case gov.Abstention:
+			abstentions.Add(abstentions, weight)
+		}
+	}
+
+	delegatorVotes, err := gov.ListDelegatorVoteValue(proposalID, blockHash)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	for _, vote := range delegatorVotes {
+		weight, ok := weights[vote.VoteNodeID]
+		if !ok || stakingBlockNums[vote.VoteNodeID] != vote.StakingBlockNum {
+			continue
+		}
+		del, err := stk.GetDelegateInfo(blockHash, vote.Delegator, vote.VoteNodeID, vote.StakingBlockNum)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			return nil, nil, nil, nil, err
+		}
+		if nil == del {
+			continue
+		}
+		amount := gov.DelegationAmount(del)
+		if amount.Cmp(weight) > 0 {
+			amount.Set(weight)
+		}
+		weight.Sub(weight, amount)
+		addWeight(vote.VoteOption, amount)
+	}
+
+	voteList, err := gov.ListVoteValue(proposalID, blockHash)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	for _, vote := range voteList {
+		if weight, ok := weights[vote.VoteNodeID]; ok {
+			addWeight(vote.VoteOption, weight)
+		}
+	}
+	return yeas, nays, abstentions, total, nil
+}
+
+// decimalRatio is the same as Decimal for the ratio of big integers
+func decimalRatio(x, y *big.Int) int {
+	if y.Sign() == 0 {
+		return 0
+	}
+	ratio := new(big.Int).Mul(x, big.NewInt(1000))
+	return int(ratio.Div(ratio, y).Int64())
+}
+
 func Decimal(value float64) int {
 	return int(math.Floor(value * 1000))
 }
@@ -40,6 +40,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	//	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	//	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 
 	"math/big"
@@ -1003,6 +1004,86 @@ func TestGovPlugin_textProposalPassed(t *testing.T) {
 	}
 }
 
+func TestGovPlugin_spendProposalPassed(t *testing.T) {
+
+	defer setup(t)()
+
+	amount := big.NewInt(1e18)
+	recipient := common.HexToAddress("0x12c171900f010b17e969702efa044d077e868082")
+	stateDB.AddBalance(xcom.CDFAccount(), amount)
+
+	sp := &gov.SpendProposal{
+		ProposalID:   txHashArr[0],
+		ProposalType: gov.Spend,
+		PIPID:        "spendPIPID",
+		SubmitBlock:  1,
+		Proposer:     nodeIdArr[0],
+		Source:       xcom.CDFAccount(),
+		Recipient:    recipient,
+		Amount:       amount,
+	}
+	if err := gov.Submit(sender, sp, lastBlockHash, lastBlockNumber, stk, stateDB); err != nil {
+		t.Fatalf("submit spend proposal err: %s", err)
+	}
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+
+	buildBlockNoCommit(2)
+
+	allVote(t, txHashArr[0])
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+
+	lastBlockNumber = sp.GetEndVotingBlock()
+	lastHeader = types.Header{
+		Number: big.NewInt(int64(lastBlockNumber)),
+	}
+	lastBlockHash = lastHeader.Hash()
+	sndb.SetCurrent(lastBlockHash, *big.NewInt(int64(lastBlockNumber)), *big.NewInt(int64(lastBlockNumber)))
+
+	build_staking_data_more(sp.GetEndVotingBlock())
+
+	balance := new(big.Int).Set(stateDB.GetBalance(recipient))
+	endBlock(t)
+	sndb.Commit(lastBlockHash)
+
+	result, err := gov.GetTallyResult(txHashArr[0], stateDB)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if result == nil {
+		t.Fatal("cannot find the tally result")
+	}
+	assert.Equal(t, gov.Pass, result.Status)
+	assert.True(t, result.Executed)
+	assert.Equal(t, new(big.Int).Add(balance, amount), stateDB.GetBalance(recipient))
+}
+
+func TestGovPlugin_spendProposalBeforeFeature(t *testing.T) {
+
+	defer setup(t)()
+
+	// the chain is still at the genesis version
+	if err := gov.AddActiveVersion(params.GenesisVersion, 0, stateDB); err != nil {
+		t.Fatal(err)
+	}
+	amount := big.NewInt(1e18)
+	stateDB.AddBalance(xcom.CDFAccount(), amount)
+
+	sp := &gov.SpendProposal{
+		ProposalID:   txHashArr[0],
+		ProposalType: gov.Spend,
+		PIPID:        "spendPIPID",
+		SubmitBlock:  1,
+		Proposer:     nodeIdArr[0],
+		Source:       xcom.CDFAccount(),
+		Recipient:    common.HexToAddress("0x12c171900f010b17e969702efa044d077e868082"),
+		Amount:       amount,
+	}
+	err := gov.Submit(sender, sp, lastBlockHash, lastBlockNumber, stk, stateDB)
+	assert.Equal(t, gov.SpendProposalNotSupported, err)
+}
+
 func TestGovPlugin_textProposalFailed(t *testing.T) {
 
 	defer setup(t)()
@@ -1295,3 +1376,9 @@ func initChandlerHandler() {
 	chandler = node.GetCryptoHandler()
 	chandler.SetPrivateKey(priKey)
 }
+
+func TestGovPlugin_decimalRatio(t *testing.T) {
+	assert.Equal(t, Decimal(float64(2)/float64(3)), decimalRatio(big.NewInt(2), big.NewInt(3)))
+	assert.Equal(t, 500, decimalRatio(big.NewInt(5e17), big.NewInt(1e18)))
+	assert.Equal(t, 0, decimalRatio(big.NewInt(1), big.NewInt(0)))
+}
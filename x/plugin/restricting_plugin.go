@@ -30,7 +30,9 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common/vm"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
@@ -45,6 +47,12 @@ var (
 	rt              *RestrictingPlugin
 )
 
+// FeatureRestrictingRevoke switches on the revocation of the restricting plans by their funders.
+var FeatureRestrictingRevoke = params.RegisterFeature("restricting.revoke", 0<<16|8<<8|0)
+
+// FeatureRestrictingTransfer switches on the transfer of the restricting plans by their beneficiaries.
+var FeatureRestrictingTransfer = params.RegisterFeature("restricting.transfer", 0<<16|8<<8|0)
+
 func RestrictingInstance() *RestrictingPlugin {
 	restrictingOnce.Do(func() {
 		restrictLog := log.Root().New("package", "RestrictingPlugin")
@@ -159,6 +167,15 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 				"total", totalAmount, "balance", state.GetBalance(from))
 			return restricting.ErrBalanceNotEnough
 		}
+
+		// the revocable plans are bounded by their funders, which are iterated by the release and the transfer
+		if blockNum > 0 && gov.IsFeatureActive(FeatureRestrictingRevoke, blockNum, state) {
+			if funders := rp.getFunders(state, account); len(funders) >= restricting.MaxRestrictingFunders && !containsFunder(funders, from) {
+				rp.log.Error("Failed to AddRestrictingRecord: too many funders of the account",
+					"account", account, "funders", len(funders))
+				return restricting.ErrTooManyFunders
+			}
+		}
 	}
 
 	var (
@@ -214,6 +231,11 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 		}
 	}
 
+	// the plans created by the genesis are not revocable
+	if blockNum > 0 {
+		rp.recordFunder(state, account, from, totalPlans)
+	}
+
 	// sort release list
 	sort.Slice(restrictInfo.ReleaseList, func(i, j int) bool {
 		return restrictInfo.ReleaseList[i] < restrictInfo.ReleaseList[j]
@@ -224,6 +246,161 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 	return nil
 }
 
+// RevokeRestrictingRecord revokes the unreleased restricting plans funded by the sender on the account,
+// and returns the funds of them to the sender. The funds pledged by the account via PledgeLockFunds
+// can't be revoked, so the plans are revoked from the last epoch until the unpledged funds run out.
+func (rp *RestrictingPlugin) RevokeRestrictingRecord(from, account common.Address, state xcom.StateDB) (*big.Int, error) {
+
+	restrictingKey, restrictInfo, err := rp.mustGetRestrictingInfoByDecode(state, account)
+	if err != nil {
+		return nil, err
+	}
+	rp.log.Debug("Call RevokeRestrictingRecord begin", "sender", from, "account", account, "info", restrictInfo)
+
+	funded := new(big.Int)
+	for _, epoch := range restrictInfo.ReleaseList {
+		_, amount := rp.getFunderAmount(state, epoch, account, from)
+		funded.Add(funded, amount)
+	}
+	if funded.Cmp(common.Big0) == 0 {
+		return nil, restricting.ErrRestrictingPlanNotFunded
+	}
+	canRevoke := new(big.Int).Sub(restrictInfo.CachePlanAmount, restrictInfo.StakingAmount)
+	if canRevoke.Cmp(common.Big0) <= 0 {
+		rp.log.Warn("Restricting funds of the account are all pledged", "account", account,
+			"totalAmount", restrictInfo.CachePlanAmount, "stakingAmount", restrictInfo.StakingAmount)
+		return nil, restricting.ErrRevokeAmountPledged
+	}
+	if canRevoke.Cmp(funded) > 0 {
+		canRevoke.Set(funded)
+	}
+
+	revoked := new(big.Int)
+	for i := len(restrictInfo.ReleaseList) - 1; i >= 0 && revoked.Cmp(canRevoke) < 0; i-- {
+		epoch := restrictInfo.ReleaseList[i]
+		_, fundedAmount := rp.getFunderAmount(state, epoch, account, from)
+		if fundedAmount.Cmp(common.Big0) == 0 {
+			continue
+		}
+		amount := new(big.Int).Sub(canRevoke, revoked)
+		if amount.Cmp(fundedAmount) > 0 {
+			amount.Set(fundedAmount)
+		}
+		revoked.Add(revoked, amount)
+		rp.storeFunderAmount(state, epoch, account, from, fundedAmount.Sub(fundedAmount, amount))
+
+		releaseAmountKey, releaseAmount := rp.getReleaseAmount(state, epoch, account)
+		if releaseAmount.Sub(releaseAmount, amount).Cmp(common.Big0) == 0 {
+			state.SetState(vm.RestrictingContractAddr, releaseAmountKey, []byte{})
+			rp.removeReleaseAccount(state, epoch, account)
+			restrictInfo.RemoveEpoch(epoch)
+		} else {
+			rp.storeAmount2ReleaseAmount(state, epoch, account, releaseAmount)
+		}
+	}
+	if funded.Cmp(revoked) == 0 {
+		rp.removeFunder(state, account, from)
+	}
+
+	restrictInfo.CachePlanAmount.Sub(restrictInfo.CachePlanAmount, revoked)
+	rp.transferAmount(state, vm.RestrictingContractAddr, from, revoked)
+
+	if restrictInfo.StakingAmount.Cmp(common.Big0) == 0 &&
+		len(restrictInfo.ReleaseList) == 0 && restrictInfo.CachePlanAmount.Cmp(common.Big0) == 0 {
+		rp.deleteRestrictingInfo(state, restrictingKey, account)
+		rp.log.Debug("Call RevokeRestrictingRecord finished,set info empty", "sender", from, "account", account, "revoked", revoked)
+	} else {
+		rp.storeRestrictingInfo(state, restrictingKey, restrictInfo)
+		rp.log.Debug("Call RevokeRestrictingRecord finished", "sender", from, "account", account, "revoked", revoked, "info", restrictInfo)
+	}
+	return revoked, nil
+}
+
+// TransferRestrictingRecord transfers the unreleased restricting plans of the account to the new
+// beneficiary, the funders of the plans are kept so they can still revoke them on the new one.
+// The account must have no pledged funds, they are bound to the staking of the account.
+func (rp *RestrictingPlugin) TransferRestrictingRecord(account, to common.Address, state xcom.StateDB) (*big.Int, error) {
+	if account == to {
+		return nil, restricting.ErrTransferToSelf
+	}
+	restrictingKey, restrictInfo, err := rp.mustGetRestrictingInfoByDecode(state, account)
+	if err != nil {
+		return nil, err
+	}
+	rp.log.Debug("Call TransferRestrictingRecord begin", "account", account, "to", to, "info", restrictInfo)
+
+	if restrictInfo.StakingAmount.Cmp(common.Big0) > 0 || restrictInfo.NeedRelease.Cmp(common.Big0) > 0 {
+		rp.log.Warn("Failed to TransferRestrictingRecord: the account has pledged funds", "account", account,
+			"stakingAmount", restrictInfo.StakingAmount, "needRelease", restrictInfo.NeedRelease)
+		return nil, restricting.ErrTransferPledgedPlan
+	}
+
+	funders := rp.getFunders(state, account)
+	toFunders := rp.getFunders(state, to)
+	merged := len(toFunders)
+	for _, funder := range funders {
+		if !containsFunder(toFunders, funder) {
+			merged++
+		}
+	}
+	if merged > restricting.MaxRestrictingFunders {
+		rp.log.Warn("Failed to TransferRestrictingRecord: too many funders of the beneficiary", "account", account,
+			"to", to, "funders", merged)
+		return nil, restricting.ErrTooManyFunders
+	}
+
+	toKey, toInfoByte := rp.getRestrictingInfo(state, to)
+	var toInfo restricting.RestrictingInfo
+	if len(toInfoByte) == 0 {
+		toInfo.CachePlanAmount = big.NewInt(0)
+		toInfo.NeedRelease = big.NewInt(0)
+		toInfo.StakingAmount = big.NewInt(0)
+	} else if err := rlp.DecodeBytes(toInfoByte, &toInfo); err != nil {
+		rp.log.Error("failed to rlp decode the restricting account", "err", err.Error())
+		return nil, common.InternalError.Wrap(err.Error())
+	}
+
+	for _, epoch := range restrictInfo.ReleaseList {
+		releaseAmountKey, amount := rp.getReleaseAmount(state, epoch, account)
+		state.SetState(vm.RestrictingContractAddr, releaseAmountKey, []byte{})
+
+		_, toAmount := rp.getReleaseAmount(state, epoch, to)
+		if toAmount.Cmp(common.Big0) == 0 {
+			// take the place of the account at target epoch
+			index := rp.getReleaseAccountIndex(state, epoch, account)
+			rp.storeAccount2ReleaseAccount(state, epoch, index, to)
+			toInfo.ReleaseList = append(toInfo.ReleaseList, epoch)
+		} else {
+			rp.removeReleaseAccount(state, epoch, account)
+		}
+		rp.storeAmount2ReleaseAmount(state, epoch, to, toAmount.Add(toAmount, amount))
+
+		for _, funder := range funders {
+			_, fundedAmount := rp.getFunderAmount(state, epoch, account, funder)
+			if fundedAmount.Cmp(common.Big0) == 0 {
+				continue
+			}
+			rp.storeFunderAmount(state, epoch, account, funder, common.Big0)
+			_, toFundedAmount := rp.getFunderAmount(state, epoch, to, funder)
+			rp.storeFunderAmount(state, epoch, to, funder, toFundedAmount.Add(toFundedAmount, fundedAmount))
+		}
+	}
+	for _, funder := range funders {
+		rp.addFunder(state, to, funder)
+	}
+
+	transferred := new(big.Int).Set(restrictInfo.CachePlanAmount)
+	toInfo.CachePlanAmount.Add(toInfo.CachePlanAmount, transferred)
+	sort.Slice(toInfo.ReleaseList, func(i, j int) bool {
+		return toInfo.ReleaseList[i] < toInfo.ReleaseList[j]
+	})
+	rp.storeRestrictingInfo(state, toKey, toInfo)
+	rp.deleteRestrictingInfo(state, restrictingKey, account)
+
+	rp.log.Debug("Call TransferRestrictingRecord finished", "account", account, "to", to, "amount", transferred, "info", toInfo)
+	return transferred, nil
+}
+
 // PledgeLockFunds transfer the money from the restricting contract account to the staking contract account
 func (rp *RestrictingPlugin) PledgeLockFunds(account common.Address, amount *big.Int, state xcom.StateDB) error {
 
@@ -292,7 +469,7 @@ func (rp *RestrictingPlugin) ReturnLockFunds(account common.Address, amount *big
 	// save restricting account info
 	if restrictInfo.StakingAmount.Cmp(common.Big0) == 0 &&
 		len(restrictInfo.ReleaseList) == 0 && restrictInfo.CachePlanAmount.Cmp(common.Big0) == 0 {
-		state.SetState(vm.RestrictingContractAddr, restrictingKey, []byte{})
+		rp.deleteRestrictingInfo(state, restrictingKey, account)
 		rp.log.Debug("Call ReturnLockFunds finished,set info empty", "RCContractBalance", state.GetBalance(vm.RestrictingContractAddr))
 	} else {
 		rp.storeRestrictingInfo(state, restrictingKey, restrictInfo)
@@ -327,7 +504,7 @@ func (rp *RestrictingPlugin) SlashingNotify(account common.Address, amount *big.
 
 	if restrictInfo.StakingAmount.Cmp(common.Big0) == 0 &&
 		len(restrictInfo.ReleaseList) == 0 && restrictInfo.CachePlanAmount.Cmp(common.Big0) == 0 {
-		state.SetState(vm.RestrictingContractAddr, restrictingKey, []byte{})
+		rp.deleteRestrictingInfo(state, restrictingKey, account)
 		// save restricting account info
 		rp.log.Debug("Call SlashingNotify finished,set empty info", "account", account, "amount", amount)
 	} else {
@@ -413,6 +590,142 @@ func (rp *RestrictingPlugin) storeAmount2ReleaseAmount(state xcom.StateDB, epoch
 	state.SetState(vm.RestrictingContractAddr, releaseAmountKey, amount.Bytes())
 }
 
+func (rp *RestrictingPlugin) deleteRestrictingInfo(state xcom.StateDB, restrictingKey []byte, account common.Address) {
+	state.SetState(vm.RestrictingContractAddr, restrictingKey, []byte{})
+	funderKey := restricting.GetRestrictingFunderKey(account)
+	if len(state.GetState(vm.RestrictingContractAddr, funderKey)) != 0 {
+		state.SetState(vm.RestrictingContractAddr, funderKey, []byte{})
+	}
+}
+
+// getReleaseAccountIndex returns the index of the account in the released account list at target epoch,
+// it returns 0 if the account is not found.
+func (rp *RestrictingPlugin) getReleaseAccountIndex(state xcom.StateDB, epoch uint64, account common.Address) uint32 {
+	_, numbers := rp.getReleaseEpochNumber(state, epoch)
+	for index := numbers; index > 0; index-- {
+		if _, target := rp.getReleaseAccount(state, epoch, index); target == account {
+			return index
+		}
+	}
+	return 0
+}
+
+// removeReleaseAccount removes the account from the released account list at target epoch,
+// the last account in the list is moved to the index of the removed one.
+func (rp *RestrictingPlugin) removeReleaseAccount(state xcom.StateDB, epoch uint64, account common.Address) {
+	index := rp.getReleaseAccountIndex(state, epoch, account)
+	if index == 0 {
+		return
+	}
+	releaseEpochKey, numbers := rp.getReleaseEpochNumber(state, epoch)
+	lastAccountKey, lastAccount := rp.getReleaseAccount(state, epoch, numbers)
+	if index != numbers {
+		rp.storeAccount2ReleaseAccount(state, epoch, index, lastAccount)
+	}
+	state.SetState(vm.RestrictingContractAddr, lastAccountKey, []byte{})
+	if numbers == 1 {
+		state.SetState(vm.RestrictingContractAddr, releaseEpochKey, []byte{})
+	} else {
+		rp.storeNumber2ReleaseEpoch(state, releaseEpochKey, numbers-1)
+	}
+}
+
+func (rp *RestrictingPlugin) getFunders(state xcom.StateDB, account common.Address) []common.Address {
+	var funders []common.Address
+	bFunders := state.GetState(vm.RestrictingContractAddr, restricting.GetRestrictingFunderKey(account))
+	if len(bFunders) == 0 {
+		return funders
+	}
+	if err := rlp.DecodeBytes(bFunders, &funders); err != nil {
+		rp.log.Error("Failed to rlp decode restricting funders", "account", account, "error", err)
+		panic(err)
+	}
+	return funders
+}
+
+func (rp *RestrictingPlugin) storeFunders(state xcom.StateDB, account common.Address, funders []common.Address) {
+	funderKey := restricting.GetRestrictingFunderKey(account)
+	if len(funders) == 0 {
+		state.SetState(vm.RestrictingContractAddr, funderKey, []byte{})
+		return
+	}
+	bFunders, err := rlp.EncodeToBytes(funders)
+	if err != nil {
+		rp.log.Error("Failed to rlp encode restricting funders", "account", account, "error", err)
+		panic(err)
+	}
+	state.SetState(vm.RestrictingContractAddr, funderKey, bFunders)
+}
+
+func (rp *RestrictingPlugin) addFunder(state xcom.StateDB, account, funder common.Address) {
+	funders := rp.getFunders(state, account)
+	if containsFunder(funders, funder) {
+		return
+	}
+	rp.storeFunders(state, account, append(funders, funder))
+}
+
+func containsFunder(funders []common.Address, funder common.Address) bool {
+	for _, target := range funders {
+		if target == funder {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRestrictingPlanSize returns the number of the release epochs and the number of the funders
+// of the restricting plans of the account, they are used to charge the gas of the revocation and the transfer.
+func (rp *RestrictingPlugin) GetRestrictingPlanSize(account common.Address, state xcom.StateDB) (int, int) {
+	_, bInfo := rp.getRestrictingInfo(state, account)
+	if len(bInfo) == 0 {
+		return 0, 0
+	}
+	var info restricting.RestrictingInfo
+	if err := rlp.DecodeBytes(bInfo, &info); err != nil {
+		rp.log.Error("failed to rlp decode the restricting account", "account", account, "err", err)
+		return 0, 0
+	}
+	return len(info.ReleaseList), len(rp.getFunders(state, account))
+}
+
+func (rp *RestrictingPlugin) removeFunder(state xcom.StateDB, account, funder common.Address) {
+	funders := rp.getFunders(state, account)
+	for i, target := range funders {
+		if target == funder {
+			rp.storeFunders(state, account, append(funders[:i], funders[i+1:]...))
+			return
+		}
+	}
+}
+
+// recordFunder records the amounts funded by the sender at each epoch, they can be revoked by the sender
+// before released.
+func (rp *RestrictingPlugin) recordFunder(state xcom.StateDB, account, funder common.Address, plans map[uint64]*big.Int) {
+	for epoch, amount := range plans {
+		_, fundedAmount := rp.getFunderAmount(state, epoch, account, funder)
+		rp.storeFunderAmount(state, epoch, account, funder, fundedAmount.Add(fundedAmount, amount))
+	}
+	rp.addFunder(state, account, funder)
+}
+
+func (rp *RestrictingPlugin) getFunderAmount(state xcom.StateDB, epoch uint64, account, funder common.Address) ([]byte, *big.Int) {
+	funderAmountKey := restricting.GetReleaseFunderAmountKey(epoch, account, funder)
+	bAmount := state.GetState(vm.RestrictingContractAddr, funderAmountKey)
+	return funderAmountKey, new(big.Int).SetBytes(bAmount)
+}
+
+func (rp *RestrictingPlugin) storeFunderAmount(state xcom.StateDB, epoch uint64, account, funder common.Address, amount *big.Int) {
+	funderAmountKey := restricting.GetReleaseFunderAmountKey(epoch, account, funder)
+	if amount.Cmp(common.Big0) == 0 {
+		if len(state.GetState(vm.RestrictingContractAddr, funderAmountKey)) != 0 {
+			state.SetState(vm.RestrictingContractAddr, funderAmountKey, []byte{})
+		}
+		return
+	}
+	state.SetState(vm.RestrictingContractAddr, funderAmountKey, amount.Bytes())
+}
+
 // releaseRestricting will release restricting plans on target epoch
 func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB) error {
 
@@ -461,6 +774,10 @@ func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB
 		state.SetState(vm.RestrictingContractAddr, releaseAmountKey, []byte{})
 		// delete ReleaseAccount
 		state.SetState(vm.RestrictingContractAddr, releaseAccountKey, []byte{})
+		// delete the amounts funded by the senders
+		for _, funder := range rp.getFunders(state, account) {
+			rp.storeFunderAmount(state, epoch, account, funder, common.Big0)
+		}
 
 		// delete epoch in ReleaseList
 		// In general, the first epoch is released first.
@@ -470,7 +787,7 @@ func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB
 		if restrictInfo.CachePlanAmount.Cmp(common.Big0) == 0 {
 			if restrictInfo.NeedRelease.Cmp(common.Big0) == 0 || len(restrictInfo.ReleaseList) == 0 {
 				//if all is release,remove info
-				state.SetState(vm.RestrictingContractAddr, restrictingKey, []byte{})
+				rp.deleteRestrictingInfo(state, restrictingKey, account)
 			} else {
 				rp.storeRestrictingInfo(state, restrictingKey, restrictInfo)
 			}
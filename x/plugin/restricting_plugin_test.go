@@ -30,6 +30,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/vm"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
 	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
 )
@@ -390,6 +391,158 @@ func TestRestrictingPlugin_Compose(t *testing.T) {
 	infoAssertF(big.NewInt(2e18), []uint64{2}, big.NewInt(2e18), big.NewInt(1e18))
 }
 
+func TestRestrictingPlugin_RevokeRestrictingRecord(t *testing.T) {
+	plugin := NewTestRestrictingPlugin()
+	plans := make([]restricting.RestrictingPlan, 0)
+	plans = append(plans, restricting.RestrictingPlan{Epoch: 1, Amount: big.NewInt(1e18)})
+	plans = append(plans, restricting.RestrictingPlan{Epoch: 2, Amount: big.NewInt(1e18)})
+	plans = append(plans, restricting.RestrictingPlan{Epoch: 3, Amount: big.NewInt(1e18)})
+	if err := plugin.AddRestrictingRecord(plugin.from, plugin.to, xutil.CalcBlocksEachEpoch()-10, plans, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	if err := plugin.PledgeLockFunds(plugin.to, big.NewInt(1e18), plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := plugin.RevokeRestrictingRecord(addrArr[2], plugin.to, plugin.mockDB); err != restricting.ErrRestrictingPlanNotFunded {
+		t.Errorf("want err %v, have err %v", restricting.ErrRestrictingPlanNotFunded, err)
+	}
+
+	// the pledged funds are kept in the plan of the first epoch
+	revoked, err := plugin.RevokeRestrictingRecord(plugin.from, plugin.to, plugin.mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(2e18), revoked)
+	assert.Equal(t, big.NewInt(8e18), plugin.mockDB.GetBalance(plugin.from))
+	assert.Equal(t, uint64(0), plugin.mockDB.GetBalance(vm.RestrictingContractAddr).Uint64())
+	_, info, bizErr := plugin.mustGetRestrictingInfoByDecode(plugin.mockDB, plugin.to)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, big.NewInt(1e18), info.CachePlanAmount)
+	assert.Equal(t, big.NewInt(1e18), info.StakingAmount)
+	assert.Equal(t, []uint64{1}, info.ReleaseList)
+	_, numbers := plugin.getReleaseEpochNumber(plugin.mockDB, 3)
+	assert.Equal(t, uint32(0), numbers)
+
+	if _, err := plugin.RevokeRestrictingRecord(plugin.from, plugin.to, plugin.mockDB); err != restricting.ErrRevokeAmountPledged {
+		t.Errorf("want err %v, have err %v", restricting.ErrRevokeAmountPledged, err)
+	}
+
+	if err := plugin.releaseRestricting(1, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	_, funded := plugin.getFunderAmount(plugin.mockDB, 1, plugin.to, plugin.from)
+	assert.Equal(t, uint64(0), funded.Uint64())
+	if _, err := plugin.RevokeRestrictingRecord(plugin.from, plugin.to, plugin.mockDB); err != restricting.ErrRestrictingPlanNotFunded {
+		t.Errorf("want err %v, have err %v", restricting.ErrRestrictingPlanNotFunded, err)
+	}
+}
+
+func TestRestrictingPlugin_TransferRestrictingRecord(t *testing.T) {
+	plugin := NewTestRestrictingPlugin()
+	beneficiary := addrArr[2]
+	plans := make([]restricting.RestrictingPlan, 0)
+	plans = append(plans, restricting.RestrictingPlan{Epoch: 1, Amount: big.NewInt(1e18)})
+	plans = append(plans, restricting.RestrictingPlan{Epoch: 2, Amount: big.NewInt(1e18)})
+	if err := plugin.AddRestrictingRecord(plugin.from, plugin.to, xutil.CalcBlocksEachEpoch()-10, plans, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	plans2 := []restricting.RestrictingPlan{{Epoch: 2, Amount: big.NewInt(1e18)}}
+	if err := plugin.AddRestrictingRecord(plugin.from, beneficiary, xutil.CalcBlocksEachEpoch()-10, plans2, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := plugin.TransferRestrictingRecord(plugin.to, plugin.to, plugin.mockDB); err != restricting.ErrTransferToSelf {
+		t.Errorf("want err %v, have err %v", restricting.ErrTransferToSelf, err)
+	}
+	if err := plugin.PledgeLockFunds(plugin.to, big.NewInt(1e18), plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plugin.TransferRestrictingRecord(plugin.to, beneficiary, plugin.mockDB); err != restricting.ErrTransferPledgedPlan {
+		t.Errorf("want err %v, have err %v", restricting.ErrTransferPledgedPlan, err)
+	}
+	if err := plugin.ReturnLockFunds(plugin.to, big.NewInt(1e18), plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+
+	transferred, err := plugin.TransferRestrictingRecord(plugin.to, beneficiary, plugin.mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(2e18), transferred)
+	if _, info := plugin.getRestrictingInfo(plugin.mockDB, plugin.to); len(info) != 0 {
+		t.Error("info must del")
+	}
+	_, info, bizErr := plugin.mustGetRestrictingInfoByDecode(plugin.mockDB, beneficiary)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, big.NewInt(3e18), info.CachePlanAmount)
+	assert.Equal(t, []uint64{1, 2}, info.ReleaseList)
+	_, amount := plugin.getReleaseAmount(plugin.mockDB, 2, beneficiary)
+	assert.Equal(t, big.NewInt(2e18), amount)
+	_, numbers := plugin.getReleaseEpochNumber(plugin.mockDB, 2)
+	assert.Equal(t, uint32(1), numbers)
+
+	if err := plugin.releaseRestricting(1, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(1e18), plugin.mockDB.GetBalance(beneficiary))
+
+	// the funder can revoke the transferred plans on the new beneficiary
+	revoked, err := plugin.RevokeRestrictingRecord(plugin.from, beneficiary, plugin.mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(2e18), revoked)
+	assert.Equal(t, big.NewInt(8e18), plugin.mockDB.GetBalance(plugin.from))
+	assert.Equal(t, uint64(0), plugin.mockDB.GetBalance(vm.RestrictingContractAddr).Uint64())
+	if _, info := plugin.getRestrictingInfo(plugin.mockDB, beneficiary); len(info) != 0 {
+		t.Error("info must del")
+	}
+}
+
+func TestRestrictingPlugin_MaxRestrictingFunders(t *testing.T) {
+	plugin := NewTestRestrictingPlugin()
+	if err := gov.AddActiveVersion(uint32(0<<16|8<<8|0), 0, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	beneficiary := addrArr[2]
+	blockNumber := xutil.CalcBlocksEachEpoch() - 10
+	plans := []restricting.RestrictingPlan{{Epoch: 1, Amount: big.NewInt(1e18)}}
+	for i := 0; i < restricting.MaxRestrictingFunders; i++ {
+		funder := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		plugin.mockDB.AddBalance(funder, big.NewInt(2e18))
+		if err := plugin.AddRestrictingRecord(funder, plugin.to, blockNumber, plans, plugin.mockDB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	epochs, funders := plugin.GetRestrictingPlanSize(plugin.to, plugin.mockDB)
+	assert.Equal(t, 1, epochs)
+	assert.Equal(t, restricting.MaxRestrictingFunders, funders)
+
+	if err := plugin.AddRestrictingRecord(plugin.from, plugin.to, blockNumber, plans, plugin.mockDB); err != restricting.ErrTooManyFunders {
+		t.Errorf("want err %v, have err %v", restricting.ErrTooManyFunders, err)
+	}
+	// the recorded funders can still fund the account
+	if err := plugin.AddRestrictingRecord(common.BigToAddress(big.NewInt(0x1000)), plugin.to, blockNumber, plans, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+
+	// the funders are merged by the transfer
+	if err := plugin.AddRestrictingRecord(plugin.from, beneficiary, blockNumber, plans, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plugin.TransferRestrictingRecord(plugin.to, beneficiary, plugin.mockDB); err != restricting.ErrTooManyFunders {
+		t.Errorf("want err %v, have err %v", restricting.ErrTooManyFunders, err)
+	}
+	if _, err := plugin.TransferRestrictingRecord(beneficiary, plugin.to, plugin.mockDB); err != restricting.ErrTooManyFunders {
+		t.Errorf("want err %v, have err %v", restricting.ErrTooManyFunders, err)
+	}
+}
+
 func TestRestrictingPlugin_GetRestrictingInfo(t *testing.T) {
 
 	t.Run("restricting account not exist", func(t *testing.T) {
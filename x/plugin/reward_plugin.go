@@ -26,8 +26,11 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 	"github.com/PlatONnetwork/PlatON-Go/x/reward"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
@@ -35,6 +38,7 @@ import (
 
 type RewardMgrPlugin struct {
 	currentYear    uint32
+	newBlockRate   uint64
 	stakingReward  *big.Int
 	newBlockReward *big.Int
 }
@@ -47,6 +51,13 @@ const (
 	RewardPoolIncreaseRate                 = 80 // 80% of fixed-issued tokens are allocated to reward pool each year
 )
 
+// The precision of the cumulative delegate reward per unit of delegated von
+var DelegateRewardPerUnitPrecision = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// FeatureDelegateReward shares the rewards of the candidates with their delegators,
+// until the feature is active the candidates keep the whole rewards.
+var FeatureDelegateReward = params.RegisterFeature("reward.delegateReward", 0<<16|8<<8|0)
+
 var (
 	rewardOnce sync.Once
 	rm         *RewardMgrPlugin = nil
@@ -78,9 +89,18 @@ func (rmp *RewardMgrPlugin) EndBlock(blockHash common.Hash, head *types.Header,
 		lastYear = thisYear - 1
 	}
 
-	if thisYear != rmp.currentYear {
-		rmp.stakingReward, rmp.newBlockReward = rmp.calculateExpectReward(thisYear, lastYear, state)
+	ec, err := gov.GovernEconomicModel(blockNumber, blockHash)
+	if nil != err {
+		log.Error("Failed to EndBlock on reward_plugin: query the economic model is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
+		return err
+	}
+
+	// the expected rewards are recalculated once the NewBlockRate is changed by the governance
+	if thisYear != rmp.currentYear || ec.Reward.NewBlockRate != rmp.newBlockRate {
+		rmp.stakingReward, rmp.newBlockReward = rmp.calculateExpectReward(thisYear, lastYear, ec.Reward.NewBlockRate, state)
 		rmp.currentYear = thisYear
+		rmp.newBlockRate = ec.Reward.NewBlockRate
 	}
 	stakingReward := new(big.Int).Set(rmp.stakingReward)
 	packageReward := new(big.Int).Set(rmp.newBlockReward)
@@ -91,11 +111,21 @@ func (rmp *RewardMgrPlugin) EndBlock(blockHash common.Hash, head *types.Header,
 		}
 	}
 
-	rmp.allocatePackageBlock(blockNumber, blockHash, head.Coinbase, packageReward, state)
+	if head.Coinbase != vm.RewardManagerPoolAddr {
+		nodeId, err := parseNodeId(head)
+		if nil != err {
+			log.Error("Failed to EndBlock on reward_plugin: parse the nodeId of producer is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
+			return err
+		}
+		if err := rmp.allocatePackageBlock(blockNumber, blockHash, nodeId, head.Coinbase, packageReward, state); nil != err {
+			return err
+		}
+	}
 
 	// the block at the end of each year, additional issuance
 	if xutil.IsYearEnd(blockNumber) {
-		rmp.increaseIssuance(thisYear, lastYear, state)
+		rmp.increaseIssuance(thisYear, lastYear, ec.Reward.PlatONFoundationYear, state)
 	}
 
 	return nil
@@ -106,8 +136,8 @@ func (rmp *RewardMgrPlugin) Confirmed(nodeId discover.NodeID, block *types.Block
 	return nil
 }
 
-func (rmp *RewardMgrPlugin) isLessThanFoundationYear(thisYear uint32) bool {
-	if thisYear < xcom.PlatONFoundationYear()-1 {
+func (rmp *RewardMgrPlugin) isLessThanFoundationYear(thisYear, foundationYear uint32) bool {
+	if thisYear < foundationYear-1 {
 		return true
 	}
 	return false
@@ -128,7 +158,7 @@ func (rmp *RewardMgrPlugin) addRewardPoolIncreaseIssuance(state xcom.StateDB, cu
 }
 
 // increaseIssuance used for increase issuance at the end of each year
-func (rmp *RewardMgrPlugin) increaseIssuance(thisYear, lastYear uint32, state xcom.StateDB) {
+func (rmp *RewardMgrPlugin) increaseIssuance(thisYear, lastYear, foundationYear uint32, state xcom.StateDB) {
 	var currIssuance *big.Int
 	//issuance increase
 	{
@@ -143,7 +173,7 @@ func (rmp *RewardMgrPlugin) increaseIssuance(thisYear, lastYear uint32, state xc
 	rewardpoolIncr := percentageCalculation(currIssuance, uint64(RewardPoolIncreaseRate))
 	state.AddBalance(vm.RewardManagerPoolAddr, rewardpoolIncr)
 	lessBalance := new(big.Int).Sub(currIssuance, rewardpoolIncr)
-	if rmp.isLessThanFoundationYear(thisYear) {
+	if rmp.isLessThanFoundationYear(thisYear, foundationYear) {
 		log.Debug("Call EndBlock on reward_plugin: increase issuance to developer", "thisYear", thisYear, "developBalance", lessBalance)
 		rmp.addCommunityDeveloperFoundation(state, lessBalance, LessThanFoundationYearDeveloperRate)
 	} else {
@@ -167,11 +197,11 @@ func (rmp *RewardMgrPlugin) allocateStakingReward(blockNumber uint64, blockHash
 		log.Error("Failed to allocateStakingReward: call GetVerifierList is failed", "blockNumber", blockNumber, "hash", blockHash, "err", err)
 		return err
 	}
-	rmp.rewardStakingByValidatorList(state, verifierList, reward)
-	return nil
+	return rmp.rewardStakingByValidatorList(state, blockNumber, blockHash, verifierList, reward)
 }
 
-func (rmp *RewardMgrPlugin) rewardStakingByValidatorList(state xcom.StateDB, list staking.ValidatorExQueue, reward *big.Int) {
+func (rmp *RewardMgrPlugin) rewardStakingByValidatorList(state xcom.StateDB, blockNumber uint64, blockHash common.Hash,
+	list staking.ValidatorExQueue, reward *big.Int) error {
 	validatorNum := int64(len(list))
 	everyValidatorReward := new(big.Int).Div(reward, big.NewInt(validatorNum))
 
@@ -181,27 +211,113 @@ func (rmp *RewardMgrPlugin) rewardStakingByValidatorList(state xcom.StateDB, lis
 		addr := value.BenefitAddress
 		if addr != vm.RewardManagerPoolAddr {
 
+			delegateReward, err := rmp.allocateDelegateReward(blockNumber, blockHash, value.NodeId, everyValidatorReward, state)
+			if nil != err {
+				log.Error("Failed to allocate delegate reward of staking reward", "blockNumber", blockNumber,
+					"blockHash", blockHash.Hex(), "nodeId", value.NodeId.String(), "err", err)
+				return err
+			}
+			stakingReward := new(big.Int).Sub(everyValidatorReward, delegateReward)
+
 			log.Debug("allocate staking reward one-by-one", "nodeId", value.NodeId.String(),
-				"benefitAddress", addr.String(), "staking reward", everyValidatorReward)
+				"benefitAddress", addr.String(), "staking reward", stakingReward, "delegate reward", delegateReward)
 
-			state.AddBalance(addr, everyValidatorReward)
+			state.AddBalance(addr, stakingReward)
 			totalValidatorReward.Add(totalValidatorReward, everyValidatorReward)
 		}
 	}
 	state.SubBalance(vm.RewardManagerPoolAddr, totalValidatorReward)
+	return nil
 }
 
-// allocatePackageBlock used for reward new block. it returns coinbase and error
-func (rmp *RewardMgrPlugin) allocatePackageBlock(blockNumber uint64, blockHash common.Hash, coinBase common.Address, reward *big.Int, state xcom.StateDB) {
+// allocatePackageBlock used for reward new block. the delegators of the block producer share the reward
+// according to the commission rate of the producer.
+func (rmp *RewardMgrPlugin) allocatePackageBlock(blockNumber uint64, blockHash common.Hash, nodeId discover.NodeID,
+	coinBase common.Address, reward *big.Int, state xcom.StateDB) error {
 
 	if coinBase != vm.RewardManagerPoolAddr {
 
+		delegateReward, err := rmp.allocateDelegateReward(blockNumber, blockHash, nodeId, reward, state)
+		if nil != err {
+			log.Error("Failed to allocate delegate reward of package reward", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+			return err
+		}
+		packageReward := new(big.Int).Sub(reward, delegateReward)
+
 		log.Debug("allocate package reward", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
-			"coinBase", coinBase.String(), "reward", reward)
+			"coinBase", coinBase.String(), "reward", packageReward, "delegate reward", delegateReward)
 
 		state.SubBalance(vm.RewardManagerPoolAddr, reward)
-		state.AddBalance(coinBase, reward)
+		state.AddBalance(coinBase, packageReward)
+	}
+	return nil
+}
+
+// allocateDelegateReward takes the delegators' share out of the reward of the candidate, and moves it into
+// the delegate reward pool. The share is proportional to the effective delegated von of the candidate, and
+// the candidate keeps its commission (RewardPer in effect on the epoch) of that share. It returns the amount that moved into the pool.
+func (rmp *RewardMgrPlugin) allocateDelegateReward(blockNumber uint64, blockHash common.Hash, nodeId discover.NodeID,
+	reward *big.Int, state xcom.StateDB) (*big.Int, error) {
+
+	delegateReward := new(big.Int)
+	if !gov.IsFeatureActive(FeatureDelegateReward, blockNumber, state) {
+		return delegateReward, nil
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return nil, err
+	}
+	can, err := StakingInstance().GetCandidateInfo(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	if can.IsEmpty() || can.CandidateBase.IsEmpty() || can.CandidateMutable.IsEmpty() {
+		return delegateReward, nil
+	}
+
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+
+	epoch := xutil.CalculateEpoch(blockNumber)
+	canMutable := *can.CandidateMutable
+	lazyCalcStakeAmount(epoch, hesitateRatio, &canMutable)
+	lazyCalcDelegateTotal(epoch, hesitateRatio, &canMutable)
+	canBase := *can.CandidateBase
+	canBase.CalcRewardPer(epoch)
+
+	if canMutable.DelegateTotal.Cmp(common.Big0) <= 0 {
+		return delegateReward, nil
+	}
+
+	total := new(big.Int).Add(canMutable.Released, canMutable.RestrictingPlan)
+	total.Add(total, canMutable.DelegateTotal)
+
+	delegateReward.Mul(reward, canMutable.DelegateTotal)
+	delegateReward.Div(delegateReward, total)
+	commission := calcAmountByRate(delegateReward, uint64(canBase.RewardPer), TenThousandDenominator)
+	delegateReward.Sub(delegateReward, commission)
+
+	if delegateReward.Cmp(common.Big0) <= 0 {
+		return new(big.Int), nil
 	}
+
+	perUnit := new(big.Int).Mul(delegateReward, DelegateRewardPerUnitPrecision)
+	perUnit.Div(perUnit, canMutable.DelegateTotal)
+	perUnit.Add(perUnit, GetDelegateRewardPerUnit(state, canAddr, can.StakingBlockNum))
+	SetDelegateRewardPerUnit(state, canAddr, can.StakingBlockNum, epoch, perUnit)
+
+	state.AddBalance(vm.DelegateRewardPoolAddr, delegateReward)
+
+	log.Debug("allocate delegate reward", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
+		"nodeId", nodeId.String(), "stakingNum", can.StakingBlockNum, "reward", reward,
+		"delegateTotal", canMutable.DelegateTotal, "rewardPer", canBase.RewardPer, "commission", commission,
+		"delegateReward", delegateReward, "perUnit", perUnit)
+
+	return delegateReward, nil
 }
 
 //  Calculation percentage ,  input 100,10    cal:  100*10/100 = 10
@@ -211,13 +327,13 @@ func percentageCalculation(mount *big.Int, rate uint64) *big.Int {
 }
 
 // calculateExpectReward used for calculate the stakingReward and newBlockReward that should be send in each corresponding period
-func (rmp *RewardMgrPlugin) calculateExpectReward(thisYear, lastYear uint32, state xcom.StateDB) (*big.Int, *big.Int) {
+func (rmp *RewardMgrPlugin) calculateExpectReward(thisYear, lastYear uint32, newBlockRate uint64, state xcom.StateDB) (*big.Int, *big.Int) {
 	// get expected settlement epochs and new blocks per year first
 	epochs := xutil.EpochsPerYear()
 	blocks := xutil.CalcBlocksEachYear()
 	lastYearBalance := GetYearEndBalance(state, lastYear)
 
-	totalNewBlockReward := percentageCalculation(lastYearBalance, xcom.NewBlockRewardRate())
+	totalNewBlockReward := percentageCalculation(lastYearBalance, newBlockRate)
 	totalStakingReward := new(big.Int).Sub(lastYearBalance, totalNewBlockReward)
 
 	newBlockReward := new(big.Int).Div(totalNewBlockReward, big.NewInt(int64(blocks)))
@@ -225,7 +341,7 @@ func (rmp *RewardMgrPlugin) calculateExpectReward(thisYear, lastYear uint32, sta
 
 	log.Debug("Call calculateExpectReward", "thisYear", thisYear, "lastYear", lastYear,
 		"lastYearBalance", lastYearBalance, "totalNewBlockReward", totalNewBlockReward,
-		"totalStakingReward", totalStakingReward, "epochs of this year", epochs,
+		"newBlockRate", newBlockRate, "totalStakingReward", totalStakingReward, "epochs of this year", epochs,
 		"blocks of this year", blocks, "newBlockReward", newBlockReward, "stakingReward", stakingReward)
 
 	return stakingReward, newBlockReward
@@ -258,3 +374,65 @@ func GetYearEndBalance(state xcom.StateDB, year uint32) *big.Int {
 	log.Trace("show balance of reward pool at last year end", "lastYear", year, "amount", balance.SetBytes(bBalance))
 	return balance.SetBytes(bBalance)
 }
+
+// SetDelegateRewardPerUnit used for set the cumulative delegate reward per unit of the candidate,
+// and keep it as the value of the epoch
+func SetDelegateRewardPerUnit(state xcom.StateDB, nodeAddr common.Address, stakingNum, epoch uint64, perUnit *big.Int) {
+	state.SetState(vm.DelegateRewardPoolAddr, reward.DelegateRewardPerUnitKey(nodeAddr, stakingNum), perUnit.Bytes())
+	state.SetState(vm.DelegateRewardPoolAddr, reward.DelegateRewardHistoryKey(nodeAddr, stakingNum, epoch), perUnit.Bytes())
+}
+
+// GetDelegateRewardPerUnit used for get the current cumulative delegate reward per unit of the candidate
+func GetDelegateRewardPerUnit(state xcom.StateDB, nodeAddr common.Address, stakingNum uint64) *big.Int {
+	bPerUnit := state.GetState(vm.DelegateRewardPoolAddr, reward.DelegateRewardPerUnitKey(nodeAddr, stakingNum))
+	return new(big.Int).SetBytes(bPerUnit)
+}
+
+// getDelegateRewardPerUnitAt used for get the cumulative delegate reward per unit of the candidate at the end of
+// the epoch `to`. Only the epochs in [from, to] are looked up, if no reward was allocated during them, returns def.
+func getDelegateRewardPerUnitAt(state xcom.StateDB, nodeAddr common.Address, stakingNum, from, to uint64, def *big.Int) *big.Int {
+	for epoch := to; epoch >= from && epoch > 0; epoch-- {
+		bPerUnit := state.GetState(vm.DelegateRewardPoolAddr, reward.DelegateRewardHistoryKey(nodeAddr, stakingNum, epoch))
+		if len(bPerUnit) != 0 {
+			return new(big.Int).SetBytes(bPerUnit)
+		}
+	}
+	return def
+}
+
+// settleDelegateReward accrues the delegate reward of del since its last settlement into del.CumulativeIncome.
+// It must be called before the hesitating von of del is turned into effective (lazyCalcDelegateAmount),
+// because the hesitating von only shares the reward allocated after its hesitation period.
+func settleDelegateReward(state xcom.StateDB, nodeAddr common.Address, stakingNum, epoch, hesitateRatio uint64, del *staking.Delegation) {
+
+	if nil == del.CumulativeIncome {
+		del.CumulativeIncome = new(big.Int)
+	}
+	if nil == del.RewardPerUnit {
+		del.RewardPerUnit = new(big.Int)
+	}
+
+	current := GetDelegateRewardPerUnit(state, nodeAddr, stakingNum)
+	income := new(big.Int)
+
+	effective := new(big.Int).Add(del.Released, del.RestrictingPlan)
+	if effective.Cmp(common.Big0) > 0 {
+		income.Mul(effective, new(big.Int).Sub(current, del.RewardPerUnit))
+	}
+
+	hesitate := new(big.Int).Add(del.ReleasedHes, del.RestrictingPlanHes)
+	if hesitate.Cmp(common.Big0) > 0 && del.DelegateEpoch != 0 && epoch-uint64(del.DelegateEpoch) >= hesitateRatio {
+		// the hesitating von became effective since the epoch after `hesEnd`
+		hesEnd := uint64(del.DelegateEpoch) + hesitateRatio - 1
+		startPerUnit := getDelegateRewardPerUnitAt(state, nodeAddr, stakingNum, uint64(del.DelegateEpoch), hesEnd, del.RewardPerUnit)
+		// the reward before the last settlement has been accrued already
+		if startPerUnit.Cmp(del.RewardPerUnit) < 0 {
+			startPerUnit = del.RewardPerUnit
+		}
+		income.Add(income, new(big.Int).Mul(hesitate, new(big.Int).Sub(current, startPerUnit)))
+	}
+
+	income.Div(income, DelegateRewardPerUnitPrecision)
+	del.CumulativeIncome = new(big.Int).Add(del.CumulativeIncome, income)
+	del.RewardPerUnit = current
+}
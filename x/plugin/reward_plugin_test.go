@@ -17,14 +17,19 @@
 package plugin
 
 import (
+	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+
 	"github.com/PlatONnetwork/PlatON-Go/x/reward"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 
 	"github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 
 	"github.com/PlatONnetwork/PlatON-Go/x/staking"
 
@@ -50,7 +55,7 @@ func TestRewardPlugin(t *testing.T) {
 		SetYearEndBalance(mockDB, lastYear, yearBalance)
 		mockDB.AddBalance(vm.RewardManagerPoolAddr, yearBalance)
 
-		plugin.stakingReward, plugin.newBlockReward = plugin.calculateExpectReward(thisYear, lastYear, mockDB)
+		plugin.stakingReward, plugin.newBlockReward = plugin.calculateExpectReward(thisYear, lastYear, rate, mockDB)
 		stakingReward := plugin.stakingReward
 		newBlockReward := plugin.newBlockReward
 		expectStakingReward := new(big.Int).Sub(yearBalance, expectNewBlockReward)
@@ -59,26 +64,27 @@ func TestRewardPlugin(t *testing.T) {
 		assert.Equal(t, expectStakingReward.Div(expectStakingReward, big.NewInt(int64(epochs))), stakingReward)
 
 		list := make(staking.ValidatorExQueue, 0)
-		for _, value := range addrArr {
+		for i, value := range addrArr {
 			list = append(list, &staking.ValidatorEx{
+				NodeId:         nodeIdArr[i],
 				BenefitAddress: value,
 			})
 		}
 
-		plugin.rewardStakingByValidatorList(mockDB, list, stakingReward)
+		assert.Nil(t, plugin.rewardStakingByValidatorList(mockDB, 10, common.ZeroHash, list, stakingReward))
 		everyValidatorReward := new(big.Int).Div(stakingReward, big.NewInt(int64(len(list))))
 		for _, value := range list {
 			assert.Equal(t, everyValidatorReward, mockDB.GetBalance(value.BenefitAddress))
 		}
 
 		account := common.HexToAddress("0xeef233120ce31b3fac20dac379db243021a5234")
-		plugin.allocatePackageBlock(10, common.ZeroHash, account, newBlockReward, mockDB)
+		assert.Nil(t, plugin.allocatePackageBlock(10, common.ZeroHash, nodeIdArr[0], account, newBlockReward, mockDB))
 
 		assert.Equal(t, newBlockReward, mockDB.GetBalance(account))
 
 		lastIssue := GetHistoryCumulativeIssue(mockDB, lastYear)
 
-		plugin.increaseIssuance(thisYear, lastYear, mockDB)
+		plugin.increaseIssuance(thisYear, lastYear, xcom.PlatONFoundationYear(), mockDB)
 
 		newIssue := GetHistoryCumulativeIssue(mockDB, thisYear)
 
@@ -87,7 +93,7 @@ func TestRewardPlugin(t *testing.T) {
 
 		lastYearIssue := new(big.Int).SetBytes(mockDB.GetState(vm.RewardManagerPoolAddr, reward.GetHistoryIncreaseKey(lastYear)))
 
-		if plugin.isLessThanFoundationYear(thisYear) {
+		if plugin.isLessThanFoundationYear(thisYear, xcom.PlatONFoundationYear()) {
 			mockDB.GetBalance(xcom.CDFAccount())
 
 		} else {
@@ -103,3 +109,252 @@ func TestRewardPlugin(t *testing.T) {
 	})
 
 }
+
+func TestRewardMgrPlugin_AllocateDelegateReward(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	index := 1
+
+	if err := create_staking(state, blockNumber, blockHash, index, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+
+	can, err := getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+
+	// the candidate keeps 10% of the delegate reward
+	can.RewardPer = 1000
+	if err := StakingInstance().EditCandidate(blockHash, blockNumber, canAddr, can); nil != err {
+		t.Error("Failed to EditCandidate", err)
+		return
+	}
+
+	del, err := delegate(state, blockHash, blockNumber, can, 0, index, t)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to delegate: %v", err)) {
+		return
+	}
+
+	rmp := RewardMgrInstance()
+	reward := new(big.Int).Mul(big.NewInt(1e18), big.NewInt(100))
+
+	// the delegation is in hesitation, so it shares nothing
+	delegateReward, err := rmp.allocateDelegateReward(blockNumber.Uint64(), blockHash, can.NodeId, reward, state)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, delegateReward.Cmp(common.Big0))
+
+	// the delegation is in effect on the next epoch
+	nextNumber := xutil.CalcBlocksEachEpoch() + 1
+	can, err = getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+
+	delegated := calcDelegateTotalAmount(del)
+	total := new(big.Int).Add(calcCandidateTotalAmount(can), delegated)
+	expect := new(big.Int).Div(new(big.Int).Mul(reward, delegated), total)
+	expect.Sub(expect, calcAmountByRate(expect, 1000, TenThousandDenominator))
+
+	delegateReward, err = rmp.allocateDelegateReward(nextNumber, blockHash, can.NodeId, reward, state)
+	assert.Nil(t, err)
+	assert.Equal(t, expect, delegateReward)
+	assert.Equal(t, expect, state.GetBalance(vm.DelegateRewardPoolAddr))
+
+	// settle the delegation, the loss of precision is less than 1 von for every DelegateRewardPerUnitPrecision von
+	settleDelegateReward(state, canAddr, can.StakingBlockNum, xutil.CalculateEpoch(nextNumber), xcom.HesitateRatio(), del)
+	assert.True(t, del.CumulativeIncome.Cmp(expect) <= 0)
+	assert.True(t, new(big.Int).Sub(expect, del.CumulativeIncome).Cmp(maxPrecisionLoss(delegated)) <= 0)
+
+	// settle again, nothing is accrued
+	income := new(big.Int).Set(del.CumulativeIncome)
+	settleDelegateReward(state, canAddr, can.StakingBlockNum, xutil.CalculateEpoch(nextNumber), xcom.HesitateRatio(), del)
+	assert.Equal(t, income, del.CumulativeIncome)
+}
+
+func TestRewardMgrPlugin_AllocateDelegateRewardBeforeActive(t *testing.T) {
+
+	state, _, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+
+	// the delegate reward is not active with the genesis version
+	if err := gov.AddActiveVersion(params.GenesisVersion, 0, state); nil != err {
+		t.Error("Failed to AddActiveVersion", err)
+		return
+	}
+
+	reward := new(big.Int).Mul(big.NewInt(1e18), big.NewInt(100))
+	delegateReward, err := RewardMgrInstance().allocateDelegateReward(blockNumber.Uint64(), blockHash, nodeIdArr[0], reward, state)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, delegateReward.Cmp(common.Big0))
+	assert.Equal(t, 0, state.GetBalance(vm.DelegateRewardPoolAddr).Cmp(common.Big0))
+}
+
+func TestRewardMgrPlugin_WithdrewDelegateRewardTwice(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	index := 1
+
+	if err := create_staking(state, blockNumber, blockHash, index, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+
+	can, err := getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+
+	del, err := delegate(state, blockHash, blockNumber, can, 0, index, t)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to delegate: %v", err)) {
+		return
+	}
+	delAddr := addrArr[index+1]
+	delegated := calcDelegateTotalAmount(del)
+
+	// the reward of the 1st epoch is shared by the other delegations in effect
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+	SetDelegateRewardPerUnit(state, canAddr, can.StakingBlockNum, xutil.CalculateEpoch(blockNumber.Uint64()), DelegateRewardPerUnitPrecision)
+
+	rmp := RewardMgrInstance()
+	reward := new(big.Int).Mul(big.NewInt(1e18), big.NewInt(100))
+
+	withdrew := func(number uint64) *big.Int {
+		before := new(big.Int).Set(state.GetBalance(delAddr))
+		if _, err := StakingInstance().WithdrewDelegateReward(state, blockHash, new(big.Int).SetUint64(number), delAddr); nil != err {
+			t.Error("Failed to WithdrewDelegateReward", err)
+		}
+		return new(big.Int).Sub(state.GetBalance(delAddr), before)
+	}
+
+	// the delegation is in effect on the 2nd epoch, and shares the reward of it
+	secondNumber := xutil.CalcBlocksEachEpoch() + 1
+	first, err := rmp.allocateDelegateReward(secondNumber, blockHash, can.NodeId, reward, state)
+	assert.Nil(t, err)
+	first = new(big.Int).Set(first)
+	paid := withdrew(secondNumber)
+	assert.True(t, paid.Cmp(first) <= 0)
+	assert.True(t, new(big.Int).Sub(first, paid).Cmp(maxPrecisionLoss(delegated)) <= 0)
+
+	// the second withdrawal only pays the reward of the 3rd epoch
+	thirdNumber := 2*xutil.CalcBlocksEachEpoch() + 1
+	second, err := rmp.allocateDelegateReward(thirdNumber, blockHash, can.NodeId, reward, state)
+	assert.Nil(t, err)
+	second = new(big.Int).Set(second)
+	paid = withdrew(thirdNumber)
+	assert.True(t, paid.Cmp(second) <= 0)
+	assert.True(t, new(big.Int).Sub(second, paid).Cmp(maxPrecisionLoss(delegated)) <= 0)
+
+	// nothing is left to withdraw in the same epoch
+	_, err = StakingInstance().WithdrewDelegateReward(state, blockHash, new(big.Int).SetUint64(thirdNumber), delAddr)
+	assert.Equal(t, staking.ErrDelegateRewardNoExist, err)
+}
+
+func TestRewardMgrPlugin_AllocateDelegateRewardAfterEdit(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	index := 1
+
+	if err := create_staking(state, blockNumber, blockHash, index, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+
+	can, err := getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+
+	if _, err := delegate(state, blockHash, blockNumber, can, 0, index, t); !assert.Nil(t, err, fmt.Sprintf("Failed to delegate: %v", err)) {
+		return
+	}
+
+	rmp := RewardMgrInstance()
+	reward := new(big.Int).Mul(big.NewInt(1e18), big.NewInt(100))
+
+	// the candidate raises the commission rate on the 2nd epoch, it keeps nothing of the 2nd epoch
+	secondNumber := xutil.CalcBlocksEachEpoch() + 1
+	can.SetNextRewardPer(5000, xutil.CalculateEpoch(secondNumber))
+	if err := StakingInstance().EditCandidate(blockHash, new(big.Int).SetUint64(secondNumber), canAddr, can); nil != err {
+		t.Error("Failed to EditCandidate", err)
+		return
+	}
+	assert.Equal(t, uint16(0), can.RewardPer)
+
+	full, err := rmp.allocateDelegateReward(secondNumber, blockHash, can.NodeId, reward, state)
+	assert.Nil(t, err)
+	full = new(big.Int).Set(full)
+
+	// the new rate is in effect on the 3rd epoch
+	thirdNumber := 2*xutil.CalcBlocksEachEpoch() + 1
+	half, err := rmp.allocateDelegateReward(thirdNumber, blockHash, can.NodeId, reward, state)
+	assert.Nil(t, err)
+	assert.Equal(t, new(big.Int).Sub(full, calcAmountByRate(full, 5000, TenThousandDenominator)), half)
+
+	canHex, err := StakingInstance().GetCandidateCompactInfo(blockHash, thirdNumber, canAddr)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(5000), canHex.RewardPer)
+}
+
+// maxPrecisionLoss returns the max loss of the delegate reward settled for the delegated von
+func maxPrecisionLoss(delegated *big.Int) *big.Int {
+	loss := new(big.Int).Div(delegated, DelegateRewardPerUnitPrecision)
+	return loss.Add(loss, common.Big1)
+}
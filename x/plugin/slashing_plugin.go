@@ -32,12 +32,16 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/common/vm"
+	ctypes "github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
 	"github.com/PlatONnetwork/PlatON-Go/life/utils"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"github.com/PlatONnetwork/PlatON-Go/x/staking"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
@@ -51,10 +55,62 @@ const (
 var (
 	// The prefix key of the number of blocks packed in the recording node
 	packAmountPrefix = []byte("nodePackAmount")
-	once             sync.Once
-	slash            *SlashingPlugin
+	// The prefix key of the vote signing info of the validators
+	voteSigningInfoPrefix = []byte("nodeVoteSigningInfo")
+	// The key of the block number of the last QC counted for the vote signing info
+	lastVoteQCKey = []byte("lastVoteQCNumber")
+	once          sync.Once
+	slash         *SlashingPlugin
 )
 
+// FeatureLowVoteSlashing slashes the validators missing too many votes of the QCs carried by the headers.
+var FeatureLowVoteSlashing = params.RegisterFeature("slashing.lowVote", 0<<16|8<<8|0)
+
+func init() {
+	// the vote signing is counted once the chain has the params
+	gov.RegisterFeatureActivator(FeatureLowVoteSlashing, func(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+		return gov.SeedGovernParams(blockHash, gov.ModuleSlashing, gov.KeyVoteSignedWindow, gov.KeyMinVoteSignedRatio,
+			gov.KeySlashFractionLowVote, gov.KeyJailDuration)
+	})
+}
+
+// voteSigningInfo records whether the validator signed the recent QCs in a sliding window,
+// the bit of the slot is set if the QC is missed.
+type voteSigningInfo struct {
+	Window uint32
+	Index  uint64 // The count of the QCs recorded
+	Missed uint32 // The count of the QCs missed within the window
+	Bitmap []byte
+}
+
+func newVoteSigningInfo(window uint32) *voteSigningInfo {
+	return &voteSigningInfo{Window: window, Bitmap: make([]byte, (window+7)/8)}
+}
+
+func (info *voteSigningInfo) record(signed bool) {
+	slot := uint32(info.Index % uint64(info.Window))
+	pos, bit := slot/8, byte(1)<<(slot%8)
+	if info.full() && info.Bitmap[pos]&bit != 0 {
+		info.Missed--
+	}
+	if signed {
+		info.Bitmap[pos] &^= bit
+	} else {
+		info.Bitmap[pos] |= bit
+		info.Missed++
+	}
+	info.Index++
+}
+
+func (info *voteSigningInfo) full() bool {
+	return info.Index >= uint64(info.Window)
+}
+
+// isLowRatio returns true if the window is full and the percentage of the signed QCs is less than minRatio.
+func (info *voteSigningInfo) isLowRatio(minRatio uint32) bool {
+	return info.full() && uint64(info.Window-info.Missed)*HundredDenominator < uint64(minRatio)*uint64(info.Window)
+}
+
 type SlashingPlugin struct {
 	db             snapshotdb.DB
 	decodeEvidence func(dupType consensus.EvidenceType, data string) (consensus.Evidence, error)
@@ -92,6 +148,10 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 		log.Error("Failed to BeginBlock, call setPackAmount is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
 		return err
 	}
+	if err := sp.countVotes(blockHash, header, state); nil != err {
+		log.Error("Failed to BeginBlock, call countVotes is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
+		return err
+	}
 	// If it is the 230th block of each round,
 	// it will punish the node with abnormal block rate.
 	// Do this from the second consensus round
@@ -114,11 +174,19 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 
 			slashQueue := make(staking.SlashQueue, 0)
 
+			// the offence is in the previous consensus round, so it starts from the first block of that round
+			offenceBlockNum := (xutil.CalculateRound(header.Number.Uint64())-2)*xutil.ConsensusSize() + 1
+
 			blockReward, err := gov.GovernSlashBlocksReward(header.Number.Uint64(), blockHash)
 			if nil != err {
 				log.Error("Failed to BeginBlock, query GovernSlashBlocksReward is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
 				return err
 			}
+			hesitateRatio, err := gov.GovernHesitateRatio(header.Number.Uint64(), blockHash)
+			if nil != err {
+				log.Error("Failed to BeginBlock, query GovernHesitateRatio is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
+				return err
+			}
 
 			for _, validator := range preRoundVal.Arr {
 				nodeId := validator.NodeId
@@ -134,9 +202,13 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 					log.Error("Failed to BeginBlock, call candidate mutable info is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
 					return err
 				}
-				totalBalance := calcCanTotalBalance(header.Number.Uint64(), canMutable)
+				totalBalance := calcCanTotalBalance(header.Number.Uint64(), hesitateRatio, canMutable)
 				if blockReward > 0 {
-					slashAmount = calcSlashBlockRewards(header.Number.Uint64(), uint64(blockReward), state)
+					slashAmount, err = calcSlashBlockRewards(header.Number.Uint64(), blockHash, uint64(blockReward), state)
+					if nil != err {
+						log.Error("Failed to BeginBlock, calculate the slashing amount of block rewards is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
+						return err
+					}
 					if slashAmount.Cmp(totalBalance) > 0 {
 						slashAmount = totalBalance
 					}
@@ -145,10 +217,11 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 					"packBlockCount", count, "slashType", slashType, "totalBalance", totalBalance, "slashAmount", slashAmount, "SlashBlocksReward", blockReward)
 
 				slashItem := &staking.SlashNodeItem{
-					NodeId:      nodeId,
-					Amount:      slashAmount,
-					SlashType:   slashType,
-					BenefitAddr: vm.RewardManagerPoolAddr,
+					NodeId:          nodeId,
+					Amount:          slashAmount,
+					SlashType:       slashType,
+					BenefitAddr:     vm.RewardManagerPoolAddr,
+					OffenceBlockNum: offenceBlockNum,
 				}
 
 				slashQueue = append(slashQueue, slashItem)
@@ -253,6 +326,204 @@ func (sp *SlashingPlugin) GetPrePackAmount(blockNumber uint64, parentHash common
 	return result, nil
 }
 
+// countVotes records the signers of the QC carried by the header into the vote signing info
+// of the validators, and slashes the validators whose vote participation is too low.
+// The QC is counted only if it is higher than the last counted one, and it must be signed
+// by the validators of its round.
+func (sp *SlashingPlugin) countVotes(blockHash common.Hash, header *types.Header, state xcom.StateDB) error {
+	blockNumber := header.Number.Uint64()
+	if !gov.IsFeatureActive(FeatureLowVoteSlashing, blockNumber, state) {
+		return nil
+	}
+	qc, err := ctypes.DecodeHeaderQC(header.Extra)
+	if nil != err {
+		log.Warn("Failed to countVotes, decode the QC of the header is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+		return nil
+	}
+	if nil == qc {
+		return nil
+	}
+
+	window, err := gov.GovernVoteSignedWindow(blockNumber, blockHash)
+	if err == gov.UnsupportedGovernParam {
+		// the chain is initialized without the vote signing params
+		return nil
+	} else if nil != err {
+		return err
+	}
+	if window == 0 {
+		return nil
+	}
+
+	last, err := sp.getLastVoteQC(blockHash)
+	if nil != err {
+		return err
+	}
+	if qc.BlockNumber <= last || qc.BlockNumber >= blockNumber {
+		return nil
+	}
+
+	valArr, err := stk.getCurrValList(blockHash, qc.BlockNumber, QueryStartNotIrr)
+	if err == staking.ErrValidatorNoExist {
+		log.Warn("Failed to countVotes, the validators of the QC not found", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "qcNumber", qc.BlockNumber)
+		return nil
+	} else if nil != err {
+		return err
+	}
+	if err := verifyVoteQC(valArr.Arr, qc); nil != err {
+		log.Warn("Failed to countVotes, the QC is invalid", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "qc", qc.String(), "err", err)
+		return nil
+	}
+	if err := sp.db.Put(blockHash, lastVoteQCKey, common.Uint64ToBytes(qc.BlockNumber)); nil != err {
+		return err
+	}
+
+	minRatio, err := gov.GovernMinVoteSignedRatio(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+	fraction, err := gov.GovernSlashFractionLowVote(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+
+	slashQueue := make(staking.SlashQueue, 0)
+	for i, validator := range valArr.Arr {
+		info, err := sp.getVoteSigningInfo(blockHash, validator.NodeId)
+		if nil != err {
+			return err
+		}
+		// restart the counting if the window is changed by the governance
+		if nil == info || info.Window != window {
+			info = newVoteSigningInfo(window)
+		}
+		info.record(qc.ValidatorSet.GetIndex(uint32(i)))
+
+		if !info.isLowRatio(minRatio) {
+			if err := sp.setVoteSigningInfo(blockHash, validator.NodeId, info); nil != err {
+				return err
+			}
+			continue
+		}
+
+		// the validator is counted from scratch after it is slashed
+		if err := sp.db.Del(blockHash, voteSigningInfoKey(validator.NodeId)); nil != err {
+			return err
+		}
+		canMutable, err := stk.GetCanMutable(blockHash, validator.NodeAddress)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			return err
+		}
+		if nil == canMutable || canMutable.IsInvalid() {
+			continue
+		}
+		totalBalance := calcCanTotalBalance(blockNumber, hesitateRatio, canMutable)
+		slashAmount := calcAmountByRate(totalBalance, uint64(fraction), TenThousandDenominator)
+
+		log.Info("Need to call SlashCandidates low vote ratio nodes", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "nodeId", validator.NodeId.TerminalString(),
+			"window", info.Window, "missed", info.Missed, "minRatio", minRatio, "totalBalance", totalBalance, "slashAmount", slashAmount)
+
+		slashQueue = append(slashQueue, &staking.SlashNodeItem{
+			NodeId:          validator.NodeId,
+			Amount:          slashAmount,
+			SlashType:       staking.LowVoteRatio,
+			BenefitAddr:     vm.RewardManagerPoolAddr,
+			OffenceBlockNum: qc.BlockNumber,
+		})
+	}
+
+	if len(slashQueue) != 0 {
+		if err := stk.SlashCandidates(state, blockHash, blockNumber, slashQueue...); nil != err {
+			log.Error("Failed to countVotes, call SlashCandidates is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (sp *SlashingPlugin) getLastVoteQC(blockHash common.Hash) (uint64, error) {
+	value, err := sp.db.Get(blockHash, lastVoteQCKey)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return 0, err
+	}
+	if err == snapshotdb.ErrNotFound {
+		return 0, nil
+	}
+	return common.BytesToUint64(value), nil
+}
+
+func (sp *SlashingPlugin) getVoteSigningInfo(blockHash common.Hash, nodeId discover.NodeID) (*voteSigningInfo, error) {
+	value, err := sp.db.Get(blockHash, voteSigningInfoKey(nodeId))
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	if err == snapshotdb.ErrNotFound {
+		return nil, nil
+	}
+	var info voteSigningInfo
+	if err := rlp.DecodeBytes(value, &info); nil != err {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (sp *SlashingPlugin) setVoteSigningInfo(blockHash common.Hash, nodeId discover.NodeID, info *voteSigningInfo) error {
+	value, err := rlp.EncodeToBytes(info)
+	if nil != err {
+		return err
+	}
+	return sp.db.Put(blockHash, voteSigningInfoKey(nodeId), value)
+}
+
+// verifyVoteQC verifies the aggregated signature of the QC against the validators of its round,
+// the index of the validator in the QC is the same as the index in the validator list.
+func verifyVoteQC(validators staking.ValidatorQueue, qc *ctypes.QuorumCert) error {
+	if nil == qc.ValidatorSet || qc.ValidatorSet.Size() != uint32(len(validators)) {
+		return errors.New("the validator set size of the QC mismatch")
+	}
+	var (
+		pub     bls.PublicKey
+		signers int
+	)
+	for i, validator := range validators {
+		if !qc.ValidatorSet.GetIndex(uint32(i)) {
+			continue
+		}
+		blsKey, err := validator.BlsPubKey.ParseBlsPubKey()
+		if nil != err {
+			return err
+		}
+		if signers == 0 {
+			if err := pub.Deserialize(blsKey.Serialize()); nil != err {
+				return err
+			}
+		} else {
+			pub.Add(blsKey)
+		}
+		signers++
+	}
+	if threshold := len(validators) - (len(validators)-1)/3; signers < threshold {
+		return errors.Errorf("the QC has small number of signers: %d, threshold: %d", signers, threshold)
+	}
+
+	msg, err := qc.CannibalizeBytes()
+	if nil != err {
+		return err
+	}
+	var sig bls.Sign
+	if err := sig.Deserialize(qc.Signature.Bytes()); nil != err {
+		return err
+	}
+	if !sig.Verify(&pub, string(msg)) {
+		return errors.New("the aggregated signature of the QC is invalid")
+	}
+	return nil
+}
+
 func (sp *SlashingPlugin) DecodeEvidence(dupType consensus.EvidenceType, data string) (consensus.Evidence, error) {
 	if sp.decodeEvidence == nil {
 		return nil, common.InternalError.Wrap("decodeEvidence function is nil")
@@ -374,7 +645,14 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 		return err
 	}
 
-	totalBalance := calcCanTotalBalance(blockNumber, canMutable)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		log.Error("Failed to Slash, query Gov HesitateRatio is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
+			"err", err)
+		return err
+	}
+
+	totalBalance := calcCanTotalBalance(blockNumber, hesitateRatio, canMutable)
 	slashAmount := calcAmountByRate(totalBalance, uint64(fraction), TenThousandDenominator)
 
 	log.Info("Call SlashCandidates on executeSlash", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
@@ -383,18 +661,20 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 
 	toCallerAmount := calcAmountByRate(slashAmount, uint64(rewardFraction), HundredDenominator)
 	toCallerItem := &staking.SlashNodeItem{
-		NodeId:      canBase.NodeId,
-		Amount:      toCallerAmount,
-		SlashType:   staking.DuplicateSign,
-		BenefitAddr: caller,
+		NodeId:          canBase.NodeId,
+		Amount:          toCallerAmount,
+		SlashType:       staking.DuplicateSign,
+		BenefitAddr:     caller,
+		OffenceBlockNum: evidence.BlockNumber(),
 	}
 
 	toRewardPoolAmount := new(big.Int).Sub(slashAmount, toCallerAmount)
 	toRewardPoolItem := &staking.SlashNodeItem{
-		NodeId:      canBase.NodeId,
-		Amount:      toRewardPoolAmount,
-		SlashType:   staking.DuplicateSign,
-		BenefitAddr: vm.RewardManagerPoolAddr,
+		NodeId:          canBase.NodeId,
+		Amount:          toRewardPoolAmount,
+		SlashType:       staking.DuplicateSign,
+		BenefitAddr:     vm.RewardManagerPoolAddr,
+		OffenceBlockNum: evidence.BlockNumber(),
 	}
 
 	if err := stk.SlashCandidates(stateDB, blockHash, blockNumber, toCallerItem, toRewardPoolItem); nil != err {
@@ -430,6 +710,10 @@ func duplicateSignKey(addr common.Address, blockNumber uint64, dupType consensus
 	return append(append(addr.Bytes(), utils.Uint64ToBytes(blockNumber)...), common.Uint16ToBytes(uint16(dupType))...)
 }
 
+func voteSigningInfoKey(nodeId discover.NodeID) []byte {
+	return append(voteSigningInfoPrefix, nodeId.Bytes()...)
+}
+
 func buildKey(blockNumber uint64, key []byte) []byte {
 	return append(buildPrefix(blockNumber), key...)
 }
@@ -464,9 +748,9 @@ func parseNodeId(header *types.Header) (discover.NodeID, error) {
 	}
 }
 
-func calcCanTotalBalance(blockNumber uint64, candidate *staking.CandidateMutable) *big.Int {
+func calcCanTotalBalance(blockNumber, hesitateRatio uint64, candidate *staking.CandidateMutable) *big.Int {
 	// Recalculate the quality deposit
-	lazyCalcStakeAmount(xutil.CalculateEpoch(blockNumber), candidate)
+	lazyCalcStakeAmount(xutil.CalculateEpoch(blockNumber), hesitateRatio, candidate)
 	return new(big.Int).Add(candidate.Released, candidate.RestrictingPlan)
 }
 
@@ -478,13 +762,17 @@ func calcAmountByRate(balance *big.Int, numerator, denominator uint64) *big.Int
 	return new(big.Int).SetInt64(0)
 }
 
-func calcSlashBlockRewards(blockNumber, blockReward uint64, state xcom.StateDB) *big.Int {
+func calcSlashBlockRewards(blockNumber uint64, blockHash common.Hash, blockReward uint64, state xcom.StateDB) (*big.Int, error) {
 	thisYear := xutil.CalculateYear(blockNumber)
 	var lastYear uint32
 	if thisYear != 0 {
 		lastYear = thisYear - 1
 	}
-	_, newBlockReward := RewardMgrInstance().calculateExpectReward(thisYear, lastYear, state)
+	ec, err := gov.GovernEconomicModel(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	_, newBlockReward := RewardMgrInstance().calculateExpectReward(thisYear, lastYear, ec.Reward.NewBlockRate, state)
 
-	return new(big.Int).Mul(newBlockReward, new(big.Int).SetUint64(blockReward))
+	return new(big.Int).Mul(newBlockReward, new(big.Int).SetUint64(blockReward)), nil
 }
@@ -23,6 +23,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 
 	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
@@ -303,7 +304,7 @@ func TestSlashingPlugin_BeginBlock(t *testing.T) {
 	if nil != err {
 		t.Fatal(err)
 	}
-	copy(header.Extra[len(header.Extra)-common.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+common.ExtraSeal], sign[:])
 	if err := snapshotdb.Instance().NewBlock(header.Number, phash, common.ZeroHash); nil != err {
 		t.Fatal(err)
 	}
@@ -338,7 +339,7 @@ func buildBlock(t *testing.T, maxNumber int, stateDb xcom.StateDB) (*ecdsa.Priva
 		if nil != err {
 			t.Fatal(err)
 		}
-		copy(header.Extra[len(header.Extra)-common.ExtraSeal:], sign[:])
+		copy(header.Extra[32:32+common.ExtraSeal], sign[:])
 		if err := db.NewBlock(blockNum, parentHash, common.ZeroHash); err != nil {
 			t.Fatal(err)
 		}
@@ -573,3 +574,64 @@ func TestSlashingPlugin_CheckMutiSign(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSlashingPlugin_VoteSigningInfo(t *testing.T) {
+	info := newVoteSigningInfo(10)
+	for i := 0; i < 9; i++ {
+		info.record(i%2 == 0)
+	}
+	assert.Equal(t, uint32(4), info.Missed)
+	assert.False(t, info.full())
+	// the window is not full
+	assert.False(t, info.isLowRatio(100))
+
+	info.record(false)
+	assert.Equal(t, uint32(5), info.Missed)
+	assert.True(t, info.isLowRatio(60))
+	assert.False(t, info.isLowRatio(50))
+
+	// the slots out of the window are overwritten
+	for i := 0; i < 10; i++ {
+		info.record(true)
+	}
+	assert.Equal(t, uint32(0), info.Missed)
+	assert.False(t, info.isLowRatio(100))
+	for i := 0; i < 3; i++ {
+		info.record(false)
+	}
+	assert.Equal(t, uint32(3), info.Missed)
+	assert.True(t, info.isLowRatio(80))
+	assert.False(t, info.isLowRatio(70))
+}
+
+func TestSlashingPlugin_lowVoteParamsSeeded(t *testing.T) {
+	stateDB, _, _ := newChainState()
+	defer func() {
+		snapshotdb.Instance().Clear()
+	}()
+	blockHash := common.HexToHash("0x0a0409021f020b080a16070609071c141f19011d090b091303121e1802130406")
+	if err := snapshotdb.Instance().NewBlock(common.Big1, common.ZeroHash, blockHash); err != nil {
+		t.Fatal(err)
+	}
+
+	// the chain is initialized without the params
+	if _, err := gov.GovernVoteSignedWindow(1, blockHash); err != gov.UnsupportedGovernParam {
+		t.Fatalf("the param should be unsupported before the feature, err: %v", err)
+	}
+	version, _ := params.FeatureVersion(FeatureLowVoteSlashing)
+	if err := gov.ActivateFeatures(params.GenesisVersion, version, blockHash, 1, stateDB); err != nil {
+		t.Fatal(err)
+	}
+	window, err := gov.GovernVoteSignedWindow(1, blockHash)
+	assert.Nil(t, err)
+	assert.Equal(t, xcom.VoteSignedWindow(), window)
+	ratio, err := gov.GovernMinVoteSignedRatio(1, blockHash)
+	assert.Nil(t, err)
+	assert.Equal(t, xcom.MinVoteSignedRatio(), ratio)
+	fraction, err := gov.GovernSlashFractionLowVote(1, blockHash)
+	assert.Nil(t, err)
+	assert.Equal(t, xcom.SlashFractionLowVote(), fraction)
+	duration, err := gov.GovernJailDuration(1, blockHash)
+	assert.Nil(t, err)
+	assert.Equal(t, xcom.JailDuration(), duration)
+}
@@ -38,10 +38,13 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/crypto/vrf"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/x/reward"
 	"github.com/PlatONnetwork/PlatON-Go/x/staking"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
@@ -57,6 +60,25 @@ var (
 	stk          *StakingPlugin
 )
 
+// FeatureJail jails the candidate slashed for the low ratio instead of invaliding it,
+// until the feature is active the candidate is invalided as before.
+var FeatureJail = params.RegisterFeature("staking.jail", 0<<16|8<<8|0)
+
+func init() {
+	gov.RegisterFeatureActivator(FeatureJail, func(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+		return gov.SeedGovernParams(blockHash, gov.ModuleSlashing, gov.KeyJailDuration)
+	})
+	gov.RegisterFeatureActivator(FeatureDelegateReward, func(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+		return StakingInstance().migrateDelegateReward(blockHash, blockNumber)
+	})
+}
+
+// FeatureRedelegate switches on the redelegate transaction moving the delegation to another candidate.
+var FeatureRedelegate = params.RegisterFeature("staking.redelegate", 0<<16|8<<8|0)
+
+// FeatureStakingController switches on the multisig controllers of the candidates and their pending actions.
+var FeatureStakingController = params.RegisterFeature("staking.controller", 0<<16|8<<8|0)
+
 const (
 	FreeVon     = uint16(0)
 	RestrictVon = uint16(1)
@@ -210,7 +232,13 @@ func (sk *StakingPlugin) GetCandidateCompactInfo(blockHash common.Hash, blockNum
 	}
 
 	epoch := xutil.CalculateEpoch(blockNumber)
-	lazyCalcStakeAmount(epoch, can.CandidateMutable)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	lazyCalcStakeAmount(epoch, hesitateRatio, can.CandidateMutable)
+	lazyCalcDelegateTotal(epoch, hesitateRatio, can.CandidateMutable)
+	can.CalcRewardPer(epoch)
 	canHex := buildCanHex(can)
 
 	return canHex, nil
@@ -363,8 +391,12 @@ func (sk *StakingPlugin) IncreaseStaking(state xcom.StateDB, blockHash common.Ha
 	amount *big.Int, typ uint16, canAddr common.Address, can *staking.Candidate) error {
 
 	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber.Uint64(), blockHash)
+	if nil != err {
+		return err
+	}
 
-	lazyCalcStakeAmount(epoch, can.CandidateMutable)
+	lazyCalcStakeAmount(epoch, hesitateRatio, can.CandidateMutable)
 
 	if typ == FreeVon {
 		origin := state.GetBalance(can.StakingAddress)
@@ -428,8 +460,12 @@ func (sk *StakingPlugin) WithdrewStaking(state xcom.StateDB, blockHash common.Ha
 	canAddr common.Address, can *staking.Candidate) error {
 
 	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber.Uint64(), blockHash)
+	if nil != err {
+		return err
+	}
 
-	lazyCalcStakeAmount(epoch, can.CandidateMutable)
+	lazyCalcStakeAmount(epoch, hesitateRatio, can.CandidateMutable)
 
 	if err := sk.db.DelCanPowerStore(blockHash, can); nil != err {
 		log.Error("Failed to WithdrewStaking on stakingPlugin: Delete Candidate old power is failed",
@@ -500,6 +536,16 @@ func (sk *StakingPlugin) withdrewStakeAmount(state xcom.StateDB, blockHash commo
 		}
 	}
 
+	// the jailed candidate gives up the unjailing
+	if can.IsJailed() {
+		if err := sk.db.DelCanJailStore(blockHash, canAddr); nil != err {
+			log.Error("Failed to WithdrewStaking on stakingPlugin: Delete the jail of candidate is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return err
+		}
+		can.Status &^= staking.Jailed
+	}
+
 	can.CleanShares()
 	can.Status |= staking.Invalided | staking.Withdrew
 
@@ -610,7 +656,11 @@ func (sk *StakingPlugin) handleUnStake(state xcom.StateDB, blockNumber uint64, b
 	log.Debug("Call handleUnStake", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
 		"epoch", epoch, "nodeId", can.NodeId.String())
 
-	lazyCalcStakeAmount(epoch, can.CandidateMutable)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+	lazyCalcStakeAmount(epoch, hesitateRatio, can.CandidateMutable)
 
 	refundReleaseFn := func(balance *big.Int) *big.Int {
 		if balance.Cmp(common.Big0) > 0 {
@@ -682,6 +732,7 @@ func (sk *StakingPlugin) GetDelegateExInfo(blockHash common.Hash, delAddr common
 			ReleasedHes:        (*hexutil.Big)(del.ReleasedHes),
 			RestrictingPlan:    (*hexutil.Big)(del.RestrictingPlan),
 			RestrictingPlanHes: (*hexutil.Big)(del.RestrictingPlanHes),
+			CumulativeIncome:   (*hexutil.Big)(del.CumulativeIncome),
 		},
 	}, nil
 }
@@ -695,7 +746,11 @@ func (sk *StakingPlugin) GetDelegateExCompactInfo(blockHash common.Hash, blockNu
 	}
 
 	epoch := xutil.CalculateEpoch(blockNumber)
-	lazyCalcDelegateAmount(epoch, del)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	lazyCalcDelegateAmount(epoch, hesitateRatio, del)
 
 	return &staking.DelegationEx{
 		Addr:            delAddr,
@@ -707,6 +762,7 @@ func (sk *StakingPlugin) GetDelegateExCompactInfo(blockHash common.Hash, blockNu
 			ReleasedHes:        (*hexutil.Big)(del.ReleasedHes),
 			RestrictingPlan:    (*hexutil.Big)(del.RestrictingPlan),
 			RestrictingPlanHes: (*hexutil.Big)(del.RestrictingPlanHes),
+			CumulativeIncome:   (*hexutil.Big)(del.CumulativeIncome),
 		},
 	}, nil
 }
@@ -734,6 +790,7 @@ func (sk *StakingPlugin) GetDelegateExInfoByIrr(delAddr common.Address,
 			ReleasedHes:        (*hexutil.Big)(del.ReleasedHes),
 			RestrictingPlan:    (*hexutil.Big)(del.RestrictingPlan),
 			RestrictingPlanHes: (*hexutil.Big)(del.RestrictingPlanHes),
+			CumulativeIncome:   (*hexutil.Big)(del.CumulativeIncome),
 		},
 	}, nil
 }
@@ -743,7 +800,15 @@ func (sk *StakingPlugin) Delegate(state xcom.StateDB, blockHash common.Hash, blo
 	typ uint16, amount *big.Int) error {
 
 	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
-	lazyCalcDelegateAmount(epoch, del)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber.Uint64(), blockHash)
+	if nil != err {
+		return err
+	}
+	delegateReward := gov.IsFeatureActive(FeatureDelegateReward, blockNumber.Uint64(), state)
+	if delegateReward {
+		settleDelegateReward(state, canAddr, can.StakingBlockNum, epoch, hesitateRatio, del)
+	}
+	lazyCalcDelegateAmount(epoch, hesitateRatio, del)
 
 	if typ == FreeVon { // from account free von
 		origin := state.GetBalance(delAddr)
@@ -794,6 +859,13 @@ func (sk *StakingPlugin) Delegate(state xcom.StateDB, blockHash common.Hash, blo
 	// add the candidate power
 	can.AddShares(amount)
 
+	// add the total delegated von of can
+	if delegateReward {
+		lazyCalcDelegateTotal(epoch, hesitateRatio, can.CandidateMutable)
+		can.DelegateTotalHes = new(big.Int).Add(can.DelegateTotalHes, amount)
+		can.DelegateEpoch = uint32(epoch)
+	}
+
 	// set new power of can
 	if err := sk.db.SetCanPowerStore(blockHash, canAddr, can); nil != err {
 		log.Error("Failed to Delegate on stakingPlugin: Store Candidate new power is failed",
@@ -810,15 +882,18 @@ func (sk *StakingPlugin) Delegate(state xcom.StateDB, blockHash common.Hash, blo
 	return nil
 }
 
+// WithdrewDelegate refunds the delegated von, the hesitating von first. The whole delegation is refunded
+// when the remain is less than the operating threshold. It returns the von really refunded to the balance
+// and to the restricting plan.
 func (sk *StakingPlugin) WithdrewDelegate(state xcom.StateDB, blockHash common.Hash, blockNumber, amount *big.Int,
-	delAddr common.Address, nodeId discover.NodeID, stakingBlockNum uint64, del *staking.Delegation) error {
+	delAddr common.Address, nodeId discover.NodeID, stakingBlockNum uint64, del *staking.Delegation) (*big.Int, *big.Int, error) {
 
 	canAddr, err := xutil.NodeId2Addr(nodeId)
 	if nil != err {
 		log.Error("Failed to WithdrewDelegate on stakingPlugin: nodeId parse addr failed",
 			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
 			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
-		return err
+		return nil, nil, err
 	}
 
 	can, err := sk.db.GetCandidateStore(blockHash, canAddr)
@@ -826,7 +901,7 @@ func (sk *StakingPlugin) WithdrewDelegate(state xcom.StateDB, blockHash common.H
 		log.Error("Failed to WithdrewDelegate on stakingPlugin: Query candidate info failed",
 			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
 			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
-		return err
+		return nil, nil, err
 	}
 
 	total := calcDelegateTotalAmount(del)
@@ -836,15 +911,30 @@ func (sk *StakingPlugin) WithdrewDelegate(state xcom.StateDB, blockHash common.H
 			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
 			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "delegate amount", total,
 			"withdrew amount", amount)
-		return staking.ErrDelegateVonNoEnough
+		return nil, nil, staking.ErrDelegateVonNoEnough
 	}
 
 	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
 	refundAmount := calcRealRefund(blockNumber.Uint64(), blockHash, total, amount)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber.Uint64(), blockHash)
+	if nil != err {
+		return nil, nil, err
+	}
 	realSub := refundAmount
-	lazyCalcDelegateAmount(epoch, del)
+	delegateReward := gov.IsFeatureActive(FeatureDelegateReward, blockNumber.Uint64(), state)
+	if delegateReward {
+		settleDelegateReward(state, canAddr, stakingBlockNum, epoch, hesitateRatio, del)
+	}
+	lazyCalcDelegateAmount(epoch, hesitateRatio, del)
 	del.DelegateEpoch = uint32(epoch)
 
+	// the von of delegation before withdrew, used to adjust the total delegated von of can
+	effectiveBefore := new(big.Int).Add(del.Released, del.RestrictingPlan)
+	hesitateBefore := new(big.Int).Add(del.ReleasedHes, del.RestrictingPlanHes)
+	// the von of delegation before withdrew, used to calculate the real refund
+	releasedBefore := new(big.Int).Add(del.Released, del.ReleasedHes)
+	restrictingBefore := new(big.Int).Add(del.RestrictingPlan, del.RestrictingPlanHes)
+
 	switch {
 	// Illegal parameter
 	case can.IsNotEmpty() && stakingBlockNum > can.StakingBlockNum:
@@ -852,7 +942,7 @@ func (sk *StakingPlugin) WithdrewDelegate(state xcom.StateDB, blockHash common.H
 			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
 			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "fn.stakeBlockNum", stakingBlockNum,
 			"can.stakeBlockNum", can.StakingBlockNum)
-		return staking.ErrBlockNumberDisordered
+		return nil, nil, staking.ErrBlockNumberDisordered
 	default:
 		log.Debug("Call WithdrewDelegate", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
 			"delAddr", delAddr.String(), "nodeId", nodeId.String(), "StakingNum", stakingBlockNum,
@@ -865,82 +955,795 @@ func (sk *StakingPlugin) WithdrewDelegate(state xcom.StateDB, blockHash common.H
 				log.Error("Failed  to WithdrewDelegate, refund the hesitate balance is failed", "blockNumber", blockNumber,
 					"blockHash", blockHash.Hex(), "delAddr", delAddr.String(), "nodeId", nodeId.String(), "StakingNum", stakingBlockNum,
 					"refund balance", refundAmount, "releaseHes", del.ReleasedHes, "restrictingPlanHes", del.RestrictingPlanHes, "err", err)
-				return err
+				return nil, nil, err
 			}
 			refundAmount, del.ReleasedHes, del.RestrictingPlanHes = rm, rbalance, lbalance
 		}
 
-		// handle delegate on Effective period
-		if refundAmount.Cmp(common.Big0) > 0 {
-			rm, rbalance, lbalance, err := rufundDelegateFn(refundAmount, del.Released, del.RestrictingPlan, delAddr, state)
-			if nil != err {
-				log.Error("Failed  to WithdrewDelegate, refund the no hesitate balance is failed", "blockNumber", blockNumber,
-					"blockHash", blockHash.Hex(), "delAddr", delAddr.String(), "nodeId", nodeId.String(), "StakingNum", stakingBlockNum,
-					"refund balance", refundAmount, "release", del.Released, "restrictingPlan", del.RestrictingPlan, "err", err)
-				return err
-			}
-			refundAmount, del.Released, del.RestrictingPlan = rm, rbalance, lbalance
+		// handle delegate on Effective period
+		if refundAmount.Cmp(common.Big0) > 0 {
+			rm, rbalance, lbalance, err := rufundDelegateFn(refundAmount, del.Released, del.RestrictingPlan, delAddr, state)
+			if nil != err {
+				log.Error("Failed  to WithdrewDelegate, refund the no hesitate balance is failed", "blockNumber", blockNumber,
+					"blockHash", blockHash.Hex(), "delAddr", delAddr.String(), "nodeId", nodeId.String(), "StakingNum", stakingBlockNum,
+					"refund balance", refundAmount, "release", del.Released, "restrictingPlan", del.RestrictingPlan, "err", err)
+				return nil, nil, err
+			}
+			refundAmount, del.Released, del.RestrictingPlan = rm, rbalance, lbalance
+		}
+
+		if refundAmount.Cmp(common.Big0) != 0 {
+			log.Error("Failed to WithdrewDelegate on stakingPlugin: the withdrew ramain is not zero",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+				"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "del balance", total,
+				"withdrew balance", amount, "realSub amount", realSub, "withdrew remain", refundAmount)
+			return nil, nil, staking.ErrWrongWithdrewDelVonCalc
+		}
+
+		// If tatol had full sub,
+		// then clean the delegate info and pay out its delegate reward
+		if total.Cmp(realSub) == 0 {
+			if err := sk.db.DelDelegateStore(blockHash, delAddr, nodeId, stakingBlockNum); nil != err {
+				log.Error("Failed to WithdrewDelegate on stakingPlugin: Delete detegate is failed",
+					"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+					"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+				return nil, nil, err
+			}
+			if delegateReward && del.CumulativeIncome.Cmp(common.Big0) > 0 {
+				state.SubBalance(vm.DelegateRewardPoolAddr, del.CumulativeIncome)
+				state.AddBalance(delAddr, del.CumulativeIncome)
+			}
+		} else {
+			if err := sk.db.SetDelegateStore(blockHash, delAddr, nodeId, stakingBlockNum, del); nil != err {
+				log.Error("Failed to WithdrewDelegate on stakingPlugin: Store detegate is failed",
+					"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+					"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+				return nil, nil, err
+			}
+		}
+	}
+
+	// the total delegated von of can is kept whatever the can status since the delegate reward
+	if can.IsNotEmpty() && stakingBlockNum == can.StakingBlockNum && (delegateReward || can.IsValid()) {
+
+		// sub the total delegated von of can
+		if delegateReward {
+			lazyCalcDelegateTotal(epoch, hesitateRatio, can.CandidateMutable)
+			hesitateSub := new(big.Int).Sub(hesitateBefore, new(big.Int).Add(del.ReleasedHes, del.RestrictingPlanHes))
+			effectiveSub := new(big.Int).Sub(effectiveBefore, new(big.Int).Add(del.Released, del.RestrictingPlan))
+			can.DelegateTotalHes = subDelegateTotal(can.DelegateTotalHes, hesitateSub)
+			can.DelegateTotal = subDelegateTotal(can.DelegateTotal, effectiveSub)
+			can.DelegateEpoch = uint32(epoch)
+		}
+
+		if can.IsValid() {
+			if err := sk.db.DelCanPowerStore(blockHash, can); nil != err {
+				log.Error("Failed to WithdrewDelegate on stakingPlugin: Delete candidate old power is failed", "blockNumber",
+					blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "nodeId", nodeId.String(),
+					"stakingBlockNum", stakingBlockNum, "err", err)
+				return nil, nil, err
+			}
+
+			// change candidate shares
+			if can.Shares.Cmp(realSub) > 0 {
+				can.SubShares(realSub)
+			} else {
+				log.Error("Failed to WithdrewDelegate on stakingPlugin: the candidate shares is no enough", "blockNumber",
+					blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "nodeId", nodeId.String(), "stakingBlockNum",
+					stakingBlockNum, "can shares", can.Shares, "real withdrew delegate amount", realSub)
+				panic("the candidate shares is no enough")
+			}
+		}
+
+		if err := sk.db.SetCanMutableStore(blockHash, canAddr, can.CandidateMutable); nil != err {
+			log.Error("Failed to WithdrewDelegate on stakingPlugin: Store CandidateMutable info is failed", "blockNumber",
+				blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "nodeId", nodeId.String(),
+				"stakingBlockNum", stakingBlockNum, "err", err)
+			return nil, nil, err
+		}
+
+		if can.IsValid() {
+			if err := sk.db.SetCanPowerStore(blockHash, canAddr, can); nil != err {
+				log.Error("Failed to WithdrewDelegate on stakingPlugin: Store candidate old power is failed", "blockNumber",
+					blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "nodeId", nodeId.String(),
+					"stakingBlockNum", stakingBlockNum, "err", err)
+				return nil, nil, err
+			}
+		}
+	}
+	return releasedBefore.Sub(releasedBefore, new(big.Int).Add(del.Released, del.ReleasedHes)),
+		restrictingBefore.Sub(restrictingBefore, new(big.Int).Add(del.RestrictingPlan, del.RestrictingPlanHes)), nil
+}
+
+// Redelegate moves the effective von of the delegation on the source candidate to the target candidate.
+// The moved von has passed the hesitation on the source candidate, so it stays in effect on the target one.
+// A record of the moving is kept with the source candidate, so that the moved von can still be slashed
+// when the source candidate is slashed for an offence committed before the moving.
+// It returns the von actually moved, the amount may be rounded up to the whole delegation.
+func (sk *StakingPlugin) Redelegate(state xcom.StateDB, blockHash common.Hash, blockNumber, amount *big.Int,
+	delAddr common.Address, srcNodeId discover.NodeID, srcStakingBlockNum uint64, srcDel *staking.Delegation,
+	dstCanAddr common.Address, dstCan *staking.Candidate, dstDel *staking.Delegation) (*big.Int, error) {
+
+	if srcNodeId == dstCan.NodeId {
+		log.Error("Failed to Redelegate on stakingPlugin: the source and target candidate are the same",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+			"nodeId", srcNodeId.String())
+		return nil, staking.ErrRedelegateSameNode
+	}
+
+	srcCanAddr, err := xutil.NodeId2Addr(srcNodeId)
+	if nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: nodeId parse addr failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+			"srcNodeId", srcNodeId.String(), "srcStakingBlockNum", srcStakingBlockNum, "err", err)
+		return nil, err
+	}
+
+	srcCan, err := sk.db.GetCandidateStore(blockHash, srcCanAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to Redelegate on stakingPlugin: Query source candidate info failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+			"srcNodeId", srcNodeId.String(), "srcStakingBlockNum", srcStakingBlockNum, "err", err)
+		return nil, err
+	}
+
+	if srcCan.IsNotEmpty() && srcStakingBlockNum > srcCan.StakingBlockNum {
+		log.Error("Failed to Redelegate on stakingPlugin: the stakeBlockNum invalid",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+			"srcNodeId", srcNodeId.String(), "fn.stakeBlockNum", srcStakingBlockNum,
+			"can.stakeBlockNum", srcCan.StakingBlockNum)
+		return nil, staking.ErrBlockNumberDisordered
+	}
+
+	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber.Uint64(), blockHash)
+	if nil != err {
+		return nil, err
+	}
+
+	settleDelegateReward(state, srcCanAddr, srcStakingBlockNum, epoch, hesitateRatio, srcDel)
+	lazyCalcDelegateAmount(epoch, hesitateRatio, srcDel)
+	settleDelegateReward(state, dstCanAddr, dstCan.StakingBlockNum, epoch, hesitateRatio, dstDel)
+	lazyCalcDelegateAmount(epoch, hesitateRatio, dstDel)
+
+	// Only the von in effect can be moved, the hesitating von must be withdrew
+	effective := new(big.Int).Add(srcDel.Released, srcDel.RestrictingPlan)
+	if effective.Cmp(amount) < 0 {
+		log.Error("Failed to Redelegate on stakingPlugin: the amount of effective delegate is not enough",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+			"srcNodeId", srcNodeId.String(), "srcStakingBlockNum", srcStakingBlockNum,
+			"effective amount", effective, "redelegate amount", amount)
+		return nil, staking.ErrDelegateVonNoEnough
+	}
+
+	realMove := calcRealRefund(blockNumber.Uint64(), blockHash, effective, amount)
+
+	// move the circulating von first, then the RestrictingPlan von
+	moveReleased := new(big.Int).Set(realMove)
+	if moveReleased.Cmp(srcDel.Released) > 0 {
+		moveReleased = new(big.Int).Set(srcDel.Released)
+	}
+	moveRestrictingPlan := new(big.Int).Sub(realMove, moveReleased)
+	if moveRestrictingPlan.Cmp(srcDel.RestrictingPlan) > 0 {
+		log.Error("Failed to Redelegate on stakingPlugin: the RestrictingPlan of delegate is not enough",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+			"srcNodeId", srcNodeId.String(), "srcStakingBlockNum", srcStakingBlockNum,
+			"realMove", realMove, "moveReleased", moveReleased, "restrictingPlan", srcDel.RestrictingPlan)
+		return nil, staking.ErrWrongRedelegateVonCalc
+	}
+
+	log.Debug("Call Redelegate", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
+		"delAddr", delAddr.String(), "srcNodeId", srcNodeId.String(), "srcStakingBlockNum", srcStakingBlockNum,
+		"dstNodeId", dstCan.NodeId.String(), "dstStakingBlockNum", dstCan.StakingBlockNum,
+		"amount", amount, "realMove", realMove, "moveReleased", moveReleased, "moveRestrictingPlan", moveRestrictingPlan)
+
+	srcDel.Released = new(big.Int).Sub(srcDel.Released, moveReleased)
+	srcDel.RestrictingPlan = new(big.Int).Sub(srcDel.RestrictingPlan, moveRestrictingPlan)
+
+	dstDel.Released = new(big.Int).Add(dstDel.Released, moveReleased)
+	dstDel.RestrictingPlan = new(big.Int).Add(dstDel.RestrictingPlan, moveRestrictingPlan)
+
+	// If the source delegation had been moved all,
+	// then clean it and pay out its delegate reward
+	if calcDelegateTotalAmount(srcDel).Cmp(common.Big0) == 0 {
+		if err := sk.db.DelDelegateStore(blockHash, delAddr, srcNodeId, srcStakingBlockNum); nil != err {
+			log.Error("Failed to Redelegate on stakingPlugin: Delete source detegate is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+				"srcNodeId", srcNodeId.String(), "srcStakingBlockNum", srcStakingBlockNum, "err", err)
+			return nil, err
+		}
+		if srcDel.CumulativeIncome.Cmp(common.Big0) > 0 {
+			state.SubBalance(vm.DelegateRewardPoolAddr, srcDel.CumulativeIncome)
+			state.AddBalance(delAddr, srcDel.CumulativeIncome)
+		}
+	} else {
+		if err := sk.db.SetDelegateStore(blockHash, delAddr, srcNodeId, srcStakingBlockNum, srcDel); nil != err {
+			log.Error("Failed to Redelegate on stakingPlugin: Store source detegate is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+				"srcNodeId", srcNodeId.String(), "srcStakingBlockNum", srcStakingBlockNum, "err", err)
+			return nil, err
+		}
+	}
+
+	if err := sk.db.SetDelegateStore(blockHash, delAddr, dstCan.NodeId, dstCan.StakingBlockNum, dstDel); nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: Store target detegate is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+			"dstNodeId", dstCan.NodeId.String(), "dstStakingBlockNum", dstCan.StakingBlockNum, "err", err)
+		return nil, err
+	}
+
+	// sub the power and the total delegated von of the source candidate
+	if srcCan.IsNotEmpty() && srcStakingBlockNum == srcCan.StakingBlockNum {
+		if err := sk.changeDelegateShares(blockNumber.Uint64(), blockHash, epoch, srcCanAddr, srcCan, realMove, common.Big0, false); nil != err {
+			return nil, err
+		}
+	}
+
+	// add the power and the total delegated von of the target candidate
+	if err := sk.changeDelegateShares(blockNumber.Uint64(), blockHash, epoch, dstCanAddr, dstCan, realMove, common.Big0, true); nil != err {
+		return nil, err
+	}
+
+	// keep the record for slashing the moved von
+	if err := sk.addRedelegation(blockNumber.Uint64(), blockHash, epoch, srcCanAddr, srcStakingBlockNum, &staking.Redelegation{
+		DelAddr:            delAddr,
+		DstNodeId:          dstCan.NodeId,
+		DstStakingBlockNum: dstCan.StakingBlockNum,
+		BlockNumber:        blockNumber.Uint64(),
+		Released:           moveReleased,
+		RestrictingPlan:    moveRestrictingPlan,
+	}); nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: Store redelegation record is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+			"srcNodeId", srcNodeId.String(), "srcStakingBlockNum", srcStakingBlockNum, "err", err)
+		return nil, err
+	}
+	return realMove, nil
+}
+
+// changeDelegateShares adds or subs the effective and the hesitating delegated von of the candidate,
+// and updates the power of the candidate if it is still valid.
+func (sk *StakingPlugin) changeDelegateShares(blockNumber uint64, blockHash common.Hash, epoch uint64,
+	canAddr common.Address, can *staking.Candidate, effective, hesitate *big.Int, isAdd bool) error {
+
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+	lazyCalcDelegateTotal(epoch, hesitateRatio, can.CandidateMutable)
+	if isAdd {
+		can.DelegateTotal = new(big.Int).Add(can.DelegateTotal, effective)
+		if hesitate.Cmp(common.Big0) > 0 {
+			can.DelegateTotalHes = new(big.Int).Add(can.DelegateTotalHes, hesitate)
+			can.DelegateEpoch = uint32(epoch)
+		}
+	} else {
+		can.DelegateTotal = subDelegateTotal(can.DelegateTotal, effective)
+		can.DelegateTotalHes = subDelegateTotal(can.DelegateTotalHes, hesitate)
+	}
+	amount := new(big.Int).Add(effective, hesitate)
+
+	if can.IsValid() {
+		if err := sk.db.DelCanPowerStore(blockHash, can); nil != err {
+			log.Error("Failed to change delegate shares on stakingPlugin: Delete candidate old power is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return err
+		}
+
+		if isAdd {
+			can.AddShares(amount)
+		} else if can.Shares.Cmp(amount) > 0 {
+			can.SubShares(amount)
+		} else {
+			log.Error("Failed to change delegate shares on stakingPlugin: the candidate shares is no enough",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(),
+				"can shares", can.Shares, "sub amount", amount)
+			panic("the candidate shares is no enough")
+		}
+
+		if err := sk.db.SetCanPowerStore(blockHash, canAddr, can); nil != err {
+			log.Error("Failed to change delegate shares on stakingPlugin: Store candidate new power is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return err
+		}
+	}
+
+	if err := sk.db.SetCanMutableStore(blockHash, canAddr, can.CandidateMutable); nil != err {
+		log.Error("Failed to change delegate shares on stakingPlugin: Store CandidateMutable info is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+		return err
+	}
+	return nil
+}
+
+// addRedelegation appends the record to the redelegations of the source candidate,
+// and cleans the records which can't be slashed any more.
+func (sk *StakingPlugin) addRedelegation(blockNumber uint64, blockHash common.Hash, epoch uint64,
+	srcCanAddr common.Address, srcStakingBlockNum uint64, record *staking.Redelegation) error {
+
+	queue, err := sk.db.GetRedelegateStore(blockHash, srcCanAddr, srcStakingBlockNum)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return err
+	}
+
+	evidenceAge, err := gov.GovernMaxEvidenceAge(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+
+	remain := make(staking.RedelegationQueue, 0, len(queue)+1)
+	for _, r := range queue {
+		if !isRedelegationExpired(epoch, evidenceAge, r) {
+			remain = append(remain, r)
+		}
+	}
+	remain = append(remain, record)
+
+	return sk.db.SetRedelegateStore(blockHash, srcCanAddr, srcStakingBlockNum, remain)
+}
+
+// slashRedelegations slashes the von which had been moved away from the slashed candidate
+// since the offence, by the same ratio as the slashing of the candidate and of the delegations
+// stayed on it (slashDelegations). The von is slashed from the delegation on the target candidate,
+// and never more than it has.
+func (sk *StakingPlugin) slashRedelegations(state xcom.StateDB, blockNumber uint64, blockHash common.Hash, epoch uint64,
+	canAddr common.Address, stakingBlockNum uint64, slashItem *staking.SlashNodeItem, canTotal *big.Int) error {
+
+	if canTotal.Cmp(common.Big0) == 0 || slashItem.Amount.Cmp(common.Big0) == 0 {
+		return nil
+	}
+
+	queue, err := sk.db.GetRedelegateStore(blockHash, canAddr, stakingBlockNum)
+	if snapshotdb.IsDbNotFoundErr(err) {
+		return nil
+	} else if nil != err {
+		return err
+	}
+
+	evidenceAge, err := gov.GovernMaxEvidenceAge(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+
+	remain := make(staking.RedelegationQueue, 0, len(queue))
+
+	for _, r := range queue {
+
+		if isRedelegationExpired(epoch, evidenceAge, r) {
+			continue
+		}
+
+		// The von moved before the offence had not been at stake for it
+		if r.BlockNumber < slashItem.OffenceBlockNum {
+			remain = append(remain, r)
+			continue
+		}
+
+		slashReleased := new(big.Int).Div(new(big.Int).Mul(r.Released, slashItem.Amount), canTotal)
+		slashRestrictingPlan := new(big.Int).Div(new(big.Int).Mul(r.RestrictingPlan, slashItem.Amount), canTotal)
+
+		if err := sk.slashRedelegatedVon(state, blockNumber, blockHash, epoch, slashItem, r, slashReleased, slashRestrictingPlan); nil != err {
+			return err
+		}
+
+		r.Released = new(big.Int).Sub(r.Released, slashReleased)
+		r.RestrictingPlan = new(big.Int).Sub(r.RestrictingPlan, slashRestrictingPlan)
+		if r.Released.Cmp(common.Big0) > 0 || r.RestrictingPlan.Cmp(common.Big0) > 0 {
+			remain = append(remain, r)
+		}
+	}
+
+	if remain.IsEmpty() {
+		return sk.db.DelRedelegateStore(blockHash, canAddr, stakingBlockNum)
+	}
+	return sk.db.SetRedelegateStore(blockHash, canAddr, stakingBlockNum, remain)
+}
+
+func (sk *StakingPlugin) slashRedelegatedVon(state xcom.StateDB, blockNumber uint64, blockHash common.Hash, epoch uint64,
+	slashItem *staking.SlashNodeItem, r *staking.Redelegation, slashReleased, slashRestrictingPlan *big.Int) error {
+
+	dstCanAddr, err := xutil.NodeId2Addr(r.DstNodeId)
+	if nil != err {
+		return err
+	}
+
+	del, err := sk.db.GetDelegateStore(blockHash, r.DelAddr, r.DstNodeId, r.DstStakingBlockNum)
+	if snapshotdb.IsDbNotFoundErr(err) {
+		// the delegation had been withdrew already, nothing can be slashed
+		log.Warn("Warned to SlashCandidates: the delegation of redelegation is not exist", "blockNumber", blockNumber,
+			"blockHash", blockHash.Hex(), "delAddr", r.DelAddr.Hex(), "dstNodeId", r.DstNodeId.String(),
+			"dstStakingBlockNum", r.DstStakingBlockNum)
+		return nil
+	} else if nil != err {
+		return err
+	}
+
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+
+	settleDelegateReward(state, dstCanAddr, r.DstStakingBlockNum, epoch, hesitateRatio, del)
+	lazyCalcDelegateAmount(epoch, hesitateRatio, del)
+
+	effectiveBefore := new(big.Int).Add(del.Released, del.RestrictingPlan)
+	hesitateBefore := new(big.Int).Add(del.ReleasedHes, del.RestrictingPlanHes)
+
+	// The moved von is in effect on the target delegation, so the effective von is slashed first
+	if err := slashDelegatedVon(state, slashItem, r.DelAddr, slashReleased, false, &del.Released, &del.ReleasedHes); nil != err {
+		return err
+	}
+	if err := slashDelegatedVon(state, slashItem, r.DelAddr, slashRestrictingPlan, true, &del.RestrictingPlan, &del.RestrictingPlanHes); nil != err {
+		return err
+	}
+
+	slashedEffective := new(big.Int).Sub(effectiveBefore, new(big.Int).Add(del.Released, del.RestrictingPlan))
+	slashedHesitate := new(big.Int).Sub(hesitateBefore, new(big.Int).Add(del.ReleasedHes, del.RestrictingPlanHes))
+
+	log.Debug("Call SlashCandidates: slash the redelegated von", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
+		"delAddr", r.DelAddr.Hex(), "dstNodeId", r.DstNodeId.String(), "dstStakingBlockNum", r.DstStakingBlockNum,
+		"slashReleased", slashReleased, "slashRestrictingPlan", slashRestrictingPlan,
+		"slashedEffective", slashedEffective, "slashedHesitate", slashedHesitate)
+
+	if calcDelegateTotalAmount(del).Cmp(common.Big0) == 0 {
+		if err := sk.db.DelDelegateStore(blockHash, r.DelAddr, r.DstNodeId, r.DstStakingBlockNum); nil != err {
+			return err
+		}
+		if del.CumulativeIncome.Cmp(common.Big0) > 0 {
+			state.SubBalance(vm.DelegateRewardPoolAddr, del.CumulativeIncome)
+			state.AddBalance(r.DelAddr, del.CumulativeIncome)
+		}
+	} else {
+		if err := sk.db.SetDelegateStore(blockHash, r.DelAddr, r.DstNodeId, r.DstStakingBlockNum, del); nil != err {
+			return err
+		}
+	}
+
+	if slashedEffective.Cmp(common.Big0) == 0 && slashedHesitate.Cmp(common.Big0) == 0 {
+		return nil
+	}
+
+	dstCan, err := sk.db.GetCandidateStore(blockHash, dstCanAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return err
+	}
+	if dstCan.IsNotEmpty() && dstCan.StakingBlockNum == r.DstStakingBlockNum {
+		return sk.changeDelegateShares(blockNumber, blockHash, epoch, dstCanAddr, dstCan, slashedEffective, slashedHesitate, false)
+	}
+	return nil
+}
+
+// slashDelegations slashes the effective von of the delegations on the slashed candidate, by the same ratio
+// as the slashing of the candidate, like the von moved away from it since the offence (slashRedelegations).
+// The hesitating von is not slashed, since it had not been at stake. It returns the slashed von.
+func (sk *StakingPlugin) slashDelegations(state xcom.StateDB, blockNumber uint64, blockHash common.Hash, epoch uint64,
+	canAddr common.Address, can *staking.Candidate, slashItem *staking.SlashNodeItem, canTotal *big.Int) (*big.Int, error) {
+
+	slashed := new(big.Int)
+	if canTotal.Cmp(common.Big0) == 0 || slashItem.Amount.Cmp(common.Big0) == 0 {
+		return slashed, nil
+	}
+
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+
+	// the delegations are collected before they are stored, the keys are kept in the order of the iteration
+	delAddrs := make([]common.Address, 0)
+	dels := make([]*staking.Delegation, 0)
+
+	delKeyLen := len(staking.DelegateKeyPrefix) + common.AddressLength + discover.NodeIDBits/8 + 8
+	canSuffix := append(can.NodeId.Bytes(), common.Uint64ToBytes(can.StakingBlockNum)...)
+	iter := sk.db.IteratorDelegateByBlockHash(blockHash, 0)
+	if err := iter.Error(); nil != err {
+		return nil, err
+	}
+	for iter.Valid(); iter.Next(); {
+		key := iter.Key()
+		if len(key) != delKeyLen || !bytes.HasSuffix(key, canSuffix) {
+			continue
+		}
+		suffix := common.CopyBytes(key[len(staking.DelegateKeyPrefix):])
+		del, err := sk.db.GetDelegateStoreBySuffix(blockHash, suffix)
+		if nil != err {
+			iter.Release()
+			return nil, err
+		}
+		delAddrs = append(delAddrs, common.BytesToAddress(suffix[:common.AddressLength]))
+		dels = append(dels, del)
+	}
+	iter.Release()
+
+	for i, del := range dels {
+		delAddr := delAddrs[i]
+
+		settleDelegateReward(state, canAddr, can.StakingBlockNum, epoch, hesitateRatio, del)
+		lazyCalcDelegateAmount(epoch, hesitateRatio, del)
+
+		effectiveBefore := new(big.Int).Add(del.Released, del.RestrictingPlan)
+		slashReleased := new(big.Int).Div(new(big.Int).Mul(del.Released, slashItem.Amount), canTotal)
+		slashRestrictingPlan := new(big.Int).Div(new(big.Int).Mul(del.RestrictingPlan, slashItem.Amount), canTotal)
+
+		if err := slashDelegatedVon(state, slashItem, delAddr, slashReleased, false, &del.Released); nil != err {
+			return nil, err
+		}
+		if err := slashDelegatedVon(state, slashItem, delAddr, slashRestrictingPlan, true, &del.RestrictingPlan); nil != err {
+			return nil, err
+		}
+		slashed.Add(slashed, new(big.Int).Sub(effectiveBefore, new(big.Int).Add(del.Released, del.RestrictingPlan)))
+
+		if calcDelegateTotalAmount(del).Cmp(common.Big0) == 0 {
+			if err := sk.db.DelDelegateStore(blockHash, delAddr, can.NodeId, can.StakingBlockNum); nil != err {
+				return nil, err
+			}
+			if del.CumulativeIncome.Cmp(common.Big0) > 0 {
+				state.SubBalance(vm.DelegateRewardPoolAddr, del.CumulativeIncome)
+				state.AddBalance(delAddr, del.CumulativeIncome)
+			}
+		} else {
+			if err := sk.db.SetDelegateStore(blockHash, delAddr, can.NodeId, can.StakingBlockNum, del); nil != err {
+				return nil, err
+			}
+		}
+	}
+
+	log.Debug("Call SlashCandidates: slash the delegations", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
+		"nodeId", can.NodeId.String(), "delegations", len(dels), "slashed", slashed)
+	return slashed, nil
+}
+
+// slashDelegatedVon slashes the amount from the balances of the delegation in order, and never more than they have
+func slashDelegatedVon(state xcom.StateDB, slashItem *staking.SlashNodeItem, delAddr common.Address,
+	amount *big.Int, isNotify bool, balances ...**big.Int) error {
+
+	remain := amount
+	for _, balance := range balances {
+		if remain.Cmp(common.Big0) <= 0 {
+			break
+		}
+		if (*balance).Cmp(common.Big0) <= 0 {
+			continue
+		}
+		rAmount, rval, err := slashBalanceFn(remain, *balance, isNotify, slashItem.SlashType,
+			slashItem.BenefitAddr, delAddr, state)
+		if nil != err {
+			return err
+		}
+		remain, *balance = rAmount, rval
+	}
+	return nil
+}
+
+// The von moved before `MaxEvidenceAge` epochs can't be slashed any more
+func isRedelegationExpired(epoch uint64, evidenceAge uint32, r *staking.Redelegation) bool {
+	return epoch > xutil.CalculateEpoch(r.BlockNumber)+uint64(evidenceAge)
+}
+
+// WithdrewDelegateReward settles all the delegations of delAddr, and pays out the delegate reward
+// accrued by them from the delegate reward pool. It returns the reward of every delegation.
+func (sk *StakingPlugin) WithdrewDelegateReward(state xcom.StateDB, blockHash common.Hash, blockNumber *big.Int,
+	delAddr common.Address) (reward.DelegateRewardQueue, error) {
+
+	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber.Uint64(), blockHash)
+	if nil != err {
+		return nil, err
+	}
+
+	relatedList, err := sk.GetRelatedListByDelAddr(blockHash, delAddr)
+	if nil != err {
+		log.Error("Failed to WithdrewDelegateReward on stakingPlugin: Query the related list of delegate is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "err", err)
+		return nil, err
+	}
+
+	total := new(big.Int)
+	rewards := make(reward.DelegateRewardQueue, 0)
+
+	for _, related := range relatedList {
+
+		del, err := sk.db.GetDelegateStore(blockHash, delAddr, related.NodeId, related.StakingBlockNum)
+		if nil != err {
+			log.Error("Failed to WithdrewDelegateReward on stakingPlugin: Query delegate info is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+				"nodeId", related.NodeId.String(), "stakingBlockNum", related.StakingBlockNum, "err", err)
+			return nil, err
+		}
+
+		canAddr, err := xutil.NodeId2Addr(related.NodeId)
+		if nil != err {
+			return nil, err
+		}
+
+		settleDelegateReward(state, canAddr, related.StakingBlockNum, epoch, hesitateRatio, del)
+		lazyCalcDelegateAmount(epoch, hesitateRatio, del)
+		if del.CumulativeIncome.Cmp(common.Big0) == 0 {
+			continue
+		}
+
+		rewards = append(rewards, &reward.DelegateReward{
+			NodeId:          related.NodeId,
+			StakingBlockNum: related.StakingBlockNum,
+			Reward:          (*hexutil.Big)(del.CumulativeIncome),
+		})
+		total.Add(total, del.CumulativeIncome)
+		del.CumulativeIncome = new(big.Int).SetInt64(0)
+
+		if err := sk.db.SetDelegateStore(blockHash, delAddr, related.NodeId, related.StakingBlockNum, del); nil != err {
+			log.Error("Failed to WithdrewDelegateReward on stakingPlugin: Store delegate info is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
+				"nodeId", related.NodeId.String(), "stakingBlockNum", related.StakingBlockNum, "err", err)
+			return nil, err
+		}
+	}
+
+	if total.Cmp(common.Big0) == 0 {
+		return nil, staking.ErrDelegateRewardNoExist
+	}
+
+	state.SubBalance(vm.DelegateRewardPoolAddr, total)
+	state.AddBalance(delAddr, total)
+
+	log.Debug("Call WithdrewDelegateReward", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
+		"delAddr", delAddr.String(), "total", total, "rewards", rewards)
+	return rewards, nil
+}
+
+// GetDelegateRewardList returns the delegate reward that delAddr can withdraw now, for the delegations on nodeIds.
+// If nodeIds is empty, all the delegations of delAddr are returned.
+func (sk *StakingPlugin) GetDelegateRewardList(state xcom.StateDB, blockHash common.Hash, blockNumber uint64,
+	delAddr common.Address, nodeIds []discover.NodeID) (reward.DelegateRewardQueue, error) {
+
+	epoch := xutil.CalculateEpoch(blockNumber)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+
+	relatedList, err := sk.GetRelatedListByDelAddr(blockHash, delAddr)
+	if nil != err {
+		return nil, err
+	}
+
+	nodeIdMap := make(map[discover.NodeID]struct{}, len(nodeIds))
+	for _, nodeId := range nodeIds {
+		nodeIdMap[nodeId] = struct{}{}
+	}
+
+	rewards := make(reward.DelegateRewardQueue, 0)
+	for _, related := range relatedList {
+
+		if _, ok := nodeIdMap[related.NodeId]; len(nodeIdMap) != 0 && !ok {
+			continue
+		}
+
+		del, err := sk.db.GetDelegateStore(blockHash, delAddr, related.NodeId, related.StakingBlockNum)
+		if nil != err {
+			return nil, err
+		}
+
+		canAddr, err := xutil.NodeId2Addr(related.NodeId)
+		if nil != err {
+			return nil, err
 		}
 
-		if refundAmount.Cmp(common.Big0) != 0 {
-			log.Error("Failed to WithdrewDelegate on stakingPlugin: the withdrew ramain is not zero",
-				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
-				"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "del balance", total,
-				"withdrew balance", amount, "realSub amount", realSub, "withdrew remain", refundAmount)
-			return staking.ErrWrongWithdrewDelVonCalc
+		// settle on the query, the delegation isn't stored
+		settleDelegateReward(state, canAddr, related.StakingBlockNum, epoch, hesitateRatio, del)
+
+		rewards = append(rewards, &reward.DelegateReward{
+			NodeId:          related.NodeId,
+			StakingBlockNum: related.StakingBlockNum,
+			Reward:          (*hexutil.Big)(del.CumulativeIncome),
+		})
+	}
+	return rewards, nil
+}
+
+// migrateDelegateReward is run at the activation of the delegate reward. It stores the candidates and the
+// delegations kept before in the current layout, and counts the delegations of every candidate into its
+// delegate total. The delegations start sharing the rewards allocated since the activation.
+func (sk *StakingPlugin) migrateDelegateReward(blockHash common.Hash, blockNumber uint64) error {
+
+	epoch := xutil.CalculateEpoch(blockNumber)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+
+	// the records are collected before they are stored, the keys are kept in the order of the iteration
+	canSuffixes := make([][]byte, 0)
+	cans := make(map[common.Address]*staking.Candidate)
+
+	iter := sk.db.IteratorCanBaseByBlockHash(blockHash, 0)
+	if err := iter.Error(); nil != err {
+		return err
+	}
+	for iter.Valid(); iter.Next(); {
+		suffix := common.CopyBytes(iter.Key()[len(staking.CanBaseKeyPrefix):])
+		can, err := sk.db.GetCandidateStoreWithSuffix(blockHash, suffix)
+		if nil != err {
+			iter.Release()
+			return err
 		}
+		can.DelegateTotal = new(big.Int).SetInt64(0)
+		can.DelegateTotalHes = new(big.Int).SetInt64(0)
+		can.DelegateEpoch = 0
+		canSuffixes = append(canSuffixes, suffix)
+		cans[common.BytesToAddress(suffix)] = can
+	}
+	iter.Release()
 
-		// If tatol had full sub,
-		// then clean the delegate info
-		if total.Cmp(realSub) == 0 {
-			if err := sk.db.DelDelegateStore(blockHash, delAddr, nodeId, stakingBlockNum); nil != err {
-				log.Error("Failed to WithdrewDelegate on stakingPlugin: Delete detegate is failed",
-					"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
-					"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
-				return err
-			}
-		} else {
-			if err := sk.db.SetDelegateStore(blockHash, delAddr, nodeId, stakingBlockNum, del); nil != err {
-				log.Error("Failed to WithdrewDelegate on stakingPlugin: Store detegate is failed",
-					"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(),
-					"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
-				return err
-			}
+	delSuffixes := make([][]byte, 0)
+	dels := make([]*staking.Delegation, 0)
+
+	delKeyLen := len(staking.DelegateKeyPrefix) + common.AddressLength + discover.NodeIDBits/8 + 8
+	iter = sk.db.IteratorDelegateByBlockHash(blockHash, 0)
+	if err := iter.Error(); nil != err {
+		return err
+	}
+	for iter.Valid(); iter.Next(); {
+		key := iter.Key()
+		if len(key) != delKeyLen {
+			continue
+		}
+		suffix := common.CopyBytes(key[len(staking.DelegateKeyPrefix):])
+		del, err := sk.db.GetDelegateStoreBySuffix(blockHash, suffix)
+		if nil != err {
+			iter.Release()
+			return err
 		}
+		delSuffixes = append(delSuffixes, suffix)
+		dels = append(dels, del)
 	}
+	iter.Release()
 
-	if can.IsNotEmpty() && stakingBlockNum == can.StakingBlockNum && can.IsValid() {
-		if err := sk.db.DelCanPowerStore(blockHash, can); nil != err {
-			log.Error("Failed to WithdrewDelegate on stakingPlugin: Delete candidate old power is failed", "blockNumber",
-				blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "nodeId", nodeId.String(),
-				"stakingBlockNum", stakingBlockNum, "err", err)
+	for i, del := range dels {
+
+		suffix := delSuffixes[i]
+		nodeId := discover.MustBytesID(suffix[common.AddressLength : common.AddressLength+discover.NodeIDBits/8])
+		stakingBlockNum := common.BytesToUint64(suffix[common.AddressLength+discover.NodeIDBits/8:])
+
+		canAddr, err := xutil.NodeId2Addr(nodeId)
+		if nil != err {
 			return err
 		}
 
-		// change candidate shares
-		if can.Shares.Cmp(realSub) > 0 {
-			can.SubShares(realSub)
-		} else {
-			log.Error("Failed to WithdrewDelegate on stakingPlugin: the candidate shares is no enough", "blockNumber",
-				blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "nodeId", nodeId.String(), "stakingBlockNum",
-				stakingBlockNum, "can shares", can.Shares, "real withdrew delegate amount", realSub)
-			panic("the candidate shares is no enough")
+		// the delegations on the withdrawn stakings are not counted
+		if can, ok := cans[canAddr]; ok && can.StakingBlockNum == stakingBlockNum {
+			calc := *del
+			lazyCalcDelegateAmount(epoch, hesitateRatio, &calc)
+			can.DelegateTotal.Add(can.DelegateTotal, calc.Released)
+			can.DelegateTotal.Add(can.DelegateTotal, calc.RestrictingPlan)
+			hesitate := new(big.Int).Add(calc.ReleasedHes, calc.RestrictingPlanHes)
+			if hesitate.Sign() > 0 {
+				can.DelegateTotalHes.Add(can.DelegateTotalHes, hesitate)
+				if calc.DelegateEpoch > can.DelegateEpoch {
+					can.DelegateEpoch = calc.DelegateEpoch
+				}
+			}
 		}
 
-		if err := sk.db.SetCanMutableStore(blockHash, canAddr, can.CandidateMutable); nil != err {
-			log.Error("Failed to WithdrewDelegate on stakingPlugin: Store CandidateMutable info is failed", "blockNumber",
-				blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "nodeId", nodeId.String(),
-				"stakingBlockNum", stakingBlockNum, "err", err)
+		del.CumulativeIncome = new(big.Int).SetInt64(0)
+		del.RewardPerUnit = new(big.Int).SetInt64(0)
+		del.SetLegacy(false)
+		if err := sk.db.SetDelegateStoreBySuffix(blockHash, suffix, del); nil != err {
 			return err
 		}
+	}
 
-		if err := sk.db.SetCanPowerStore(blockHash, canAddr, can); nil != err {
-			log.Error("Failed to WithdrewDelegate on stakingPlugin: Store candidate old power is failed", "blockNumber",
-				blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr.Hex(), "nodeId", nodeId.String(),
-				"stakingBlockNum", stakingBlockNum, "err", err)
+	for _, suffix := range canSuffixes {
+		canAddr := common.BytesToAddress(suffix)
+		can := cans[canAddr]
+		can.CandidateBase.SetLegacy(false)
+		can.CandidateMutable.SetLegacy(false)
+		if err := sk.db.SetCandidateStore(blockHash, canAddr, can); nil != err {
 			return err
 		}
 	}
+
+	log.Info("Migrate the staking data for the delegate reward", "blockNumber", blockNumber,
+		"blockHash", blockHash.Hex(), "candidates", len(canSuffixes), "delegations", len(dels))
 	return nil
 }
 
@@ -1400,6 +2203,10 @@ func (sk *StakingPlugin) IsCurrValidator(blockHash common.Hash, blockNumber uint
 func (sk *StakingPlugin) GetCandidateList(blockHash common.Hash, blockNumber uint64) (staking.CandidateHexQueue, error) {
 
 	epoch := xutil.CalculateEpoch(blockNumber)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
 
 	iter := sk.db.IteratorCandidatePowerByBlockHash(blockHash, 0)
 	if err := iter.Error(); nil != err {
@@ -1417,7 +2224,9 @@ func (sk *StakingPlugin) GetCandidateList(blockHash common.Hash, blockNumber uin
 			return nil, err
 		}
 
-		lazyCalcStakeAmount(epoch, can.CandidateMutable)
+		lazyCalcStakeAmount(epoch, hesitateRatio, can.CandidateMutable)
+		lazyCalcDelegateTotal(epoch, hesitateRatio, can.CandidateMutable)
+		can.CalcRewardPer(epoch)
 		canHex := buildCanHex(can)
 		queue = append(queue, canHex)
 	}
@@ -1571,12 +2380,14 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 	// Collecting removed as a result of being slashed
 	// That is not withdrew to invalid
 	//
-	// eg. (lowRatio and must delete) OR (lowRatio and balance no enough) OR duplicateSign
+	// eg. (lowRatio and must delete) OR (lowRatio and balance no enough) OR duplicateSign OR lowVoteRatio OR jailed
 	//
 	checkHaveSlash := func(status staking.CandidateStatus) bool {
 		return status.IsInvalidLowRatioDel() ||
 			status.IsInvalidLowRatioNotEnough() ||
-			status.IsInvalidDuplicateSign()
+			status.IsInvalidDuplicateSign() ||
+			status.IsInvalidLowVoteRatio() ||
+			status.IsJailed()
 	}
 
 	currMap := make(map[discover.NodeID]*big.Int, len(curr.Arr))
@@ -1837,7 +2648,8 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 	slashTypeIsWrong := func() bool {
 		return !slashItem.SlashType.IsLowRatio() &&
 			!slashItem.SlashType.IsLowRatioDel() &&
-			!slashItem.SlashType.IsDuplicateSign()
+			!slashItem.SlashType.IsDuplicateSign() &&
+			!slashItem.SlashType.IsLowVoteRatio()
 	}
 	if slashTypeIsWrong() {
 		log.Error("Failed to SlashCandidates: the slashType is wrong", "blockNumber", blockNumber,
@@ -1860,7 +2672,11 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 	}
 
 	epoch := xutil.CalculateEpoch(blockNumber)
-	lazyCalcStakeAmount(epoch, can.CandidateMutable)
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber, blockHash)
+	if nil != err {
+		return needRemove, err
+	}
+	lazyCalcStakeAmount(epoch, hesitateRatio, can.CandidateMutable)
 
 	// Balance that can only be effective for Slash
 	total := new(big.Int).Add(can.Released, can.RestrictingPlan)
@@ -1911,10 +2727,32 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 		return needRemove, staking.ErrWrongSlashVonCalc
 	}
 
+	// slash the von which had been moved away from the can since the offence
+	if err := sk.slashRedelegations(state, blockNumber, blockHash, epoch, canAddr, can.StakingBlockNum, slashItem, total); nil != err {
+		log.Error("Failed to SlashCandidates: slash the redelegated von is failed", "slashType", slashItem.SlashType,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", slashItem.NodeId.String(), "err", err)
+		return needRemove, err
+	}
+
+	// the delegations stayed on the can are slashed by the same rule as the moved von
+	slashShares := slashItem.Amount
+	if gov.IsFeatureActive(FeatureRedelegate, blockNumber, state) {
+		delSlashed, err := sk.slashDelegations(state, blockNumber, blockHash, epoch, canAddr, can, slashItem, total)
+		if nil != err {
+			log.Error("Failed to SlashCandidates: slash the delegations is failed", "slashType", slashItem.SlashType,
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", slashItem.NodeId.String(), "err", err)
+			return needRemove, err
+		}
+		lazyCalcDelegateTotal(epoch, hesitateRatio, can.CandidateMutable)
+		can.DelegateTotal = subDelegateTotal(can.DelegateTotal, delSlashed)
+		slashShares = new(big.Int).Add(slashShares, delSlashed)
+	}
+
 	sharesHaveBeenClean := func() bool {
 		return (can.IsInvalidLowRatioNotEnough() ||
 			can.IsInvalidLowRatioDel() ||
 			can.IsInvalidDuplicateSign() ||
+			can.IsInvalidLowVoteRatio() ||
 			can.IsInvalidWithdrew())
 	}
 
@@ -1923,24 +2761,42 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 
 		// first slash and no withdrew
 		// sub Shares to effect power
-		if can.Shares.Cmp(slashItem.Amount) >= 0 {
-			can.SubShares(slashItem.Amount)
+		if can.Shares.Cmp(slashShares) >= 0 {
+			can.SubShares(slashShares)
 		} else {
 			log.Error("Failed to SlashCandidates: the candidate shares is no enough", "slashType", slashItem.SlashType,
 				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", slashItem.NodeId.String(), "candidate shares",
-				can.Shares, "slash amount", slashItem.Amount)
+				can.Shares, "slash amount", slashShares)
 			panic("the candidate shares is no enough")
 		}
 	}
 
 	// need invalid candidate status
 	// need remove from verifierList
-	needInvalid, needRemove, changeStatus := handleSlashTypeFn(blockNumber, blockHash, slashItem.SlashType, calcCandidateTotalAmount(can))
+	needInvalid, needRemove, changeStatus := handleSlashTypeFn(blockNumber, blockHash, slashItem.SlashType, calcCandidateTotalAmount(can), state)
 
 	log.Debug("Call SlashCandidates: the status", "needInvalid", needInvalid,
 		"needRemove", needRemove, "current can.Status", can.Status, "need to superpose status", changeStatus)
 
-	if needInvalid && can.IsValid() {
+	if needInvalid && changeStatus.IsJailed() && can.IsValid() {
+
+		if err := sk.jailCandidate(blockNumber, blockHash, epoch, canAddr, can, changeStatus); nil != err {
+			log.Error("Failed to SlashCandidates on stakingPlugin: jail the candidate is failed", "slashType", slashItem.SlashType,
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return needRemove, err
+		}
+
+	} else if needInvalid && (can.IsValid() || (can.IsJailed() && !changeStatus.IsJailed())) {
+
+		// the jailed candidate can't be unjailed any more
+		if can.IsJailed() {
+			if err := sk.db.DelCanJailStore(blockHash, canAddr); nil != err {
+				log.Error("Failed to SlashCandidates on stakingPlugin: Delete the jail of candidate is failed",
+					"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+				return needRemove, err
+			}
+			can.Status &^= staking.Jailed
+		}
 
 		if can.ReleasedHes.Cmp(common.Big0) > 0 {
 			state.AddBalance(can.StakingAddress, can.ReleasedHes)
@@ -2009,6 +2865,306 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 	return needRemove, nil
 }
 
+// jailCandidate invalids the candidate without withdrawing its staking, the delegations are kept as well,
+// the candidate can be unjailed by its staking address after the jail duration.
+func (sk *StakingPlugin) jailCandidate(blockNumber uint64, blockHash common.Hash, epoch uint64,
+	canAddr common.Address, can *staking.Candidate, changeStatus staking.CandidateStatus) error {
+
+	duration, err := gov.GovernJailDuration(blockNumber, blockHash)
+	if err == gov.UnsupportedGovernParam {
+		// the chain is initialized without the jail params
+		duration = xcom.JailDuration()
+	} else if nil != err {
+		return err
+	}
+
+	if err := sk.db.SetCanJailStore(blockHash, canAddr, epoch+uint64(duration)); nil != err {
+		return err
+	}
+
+	// the shares is recalculated when the candidate is unjailed
+	can.CleanShares()
+	can.Status |= changeStatus
+	return sk.db.SetCanMutableStore(blockHash, canAddr, can.CandidateMutable)
+}
+
+// Unjail returns the jailed candidate to the candidates with its staking and delegations,
+// it can be done from the epoch that the jail duration is expired.
+func (sk *StakingPlugin) Unjail(blockHash common.Hash, blockNumber *big.Int, canAddr common.Address, can *staking.Candidate) error {
+
+	if !can.IsJailed() {
+		return staking.ErrCanNotJailed
+	}
+
+	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
+
+	releaseEpoch, err := sk.db.GetCanJailStore(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to Unjail on stakingPlugin: Query the jail of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+		return err
+	}
+	if epoch < releaseEpoch {
+		log.Error("Failed to Unjail on stakingPlugin: the jail duration is not expired", "blockNumber", blockNumber.Uint64(),
+			"blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "epoch", epoch, "releaseEpoch", releaseEpoch)
+		return staking.ErrJailNotExpired
+	}
+
+	hesitateRatio, err := gov.GovernHesitateRatio(blockNumber.Uint64(), blockHash)
+	if nil != err {
+		return err
+	}
+	lazyCalcStakeAmount(epoch, hesitateRatio, can.CandidateMutable)
+	lazyCalcDelegateTotal(epoch, hesitateRatio, can.CandidateMutable)
+
+	stake := new(big.Int).Add(can.Released, can.ReleasedHes)
+	stake.Add(stake, can.RestrictingPlan)
+	stake.Add(stake, can.RestrictingPlanHes)
+	if ok, threshold := CheckStakeThreshold(blockNumber.Uint64(), blockHash, stake); !ok {
+		log.Error("Failed to Unjail on stakingPlugin: the staking von is not enough", "blockNumber", blockNumber.Uint64(),
+			"blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "stake", stake, "threshold", threshold)
+		return staking.ErrStakeVonTooLow
+	}
+
+	can.Shares = new(big.Int).Add(stake, can.DelegateTotal)
+	can.Shares.Add(can.Shares, can.DelegateTotalHes)
+	can.Status = staking.Valided
+
+	if err := sk.db.SetCanPowerStore(blockHash, canAddr, can); nil != err {
+		log.Error("Failed to Unjail on stakingPlugin: Store Candidate power is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+		return err
+	}
+
+	if err := sk.db.SetCanMutableStore(blockHash, canAddr, can.CandidateMutable); nil != err {
+		log.Error("Failed to Unjail on stakingPlugin: Store CandidateMutable info is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+		return err
+	}
+
+	if err := sk.db.DelCanJailStore(blockHash, canAddr); nil != err {
+		log.Error("Failed to Unjail on stakingPlugin: Delete the jail of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+		return err
+	}
+	return nil
+}
+
+// GetStakingController returns the controller of the candidate, it's nil if the candidate is not controlled.
+func (sk *StakingPlugin) GetStakingController(blockHash common.Hash, canAddr common.Address, stakingBlockNum uint64) (*staking.StakingController, error) {
+	controller, err := sk.db.GetCanControllerStore(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	// the controller registered by the previous staking is dropped
+	if nil == controller || controller.StakingBlockNum != stakingBlockNum {
+		return nil, nil
+	}
+	return controller, nil
+}
+
+// SetStakingController registers the controller of the candidate, the empty operators with the zero threshold unregister it.
+// The pending actions are dropped, because they were approved by the previous operators.
+func (sk *StakingPlugin) SetStakingController(blockHash common.Hash, blockNumber *big.Int, canAddr common.Address,
+	can *staking.Candidate, operators []common.Address, threshold uint32) error {
+
+	if len(operators) == 0 {
+		if threshold != 0 {
+			return staking.ErrWrongStakingController
+		}
+		if err := sk.db.DelCanControllerStore(blockHash, canAddr); nil != err {
+			log.Error("Failed to SetStakingController on stakingPlugin: Delete the controller of candidate is failed",
+				"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return err
+		}
+	} else {
+		if len(operators) > staking.MaxControllerOperators || threshold == 0 || int(threshold) > len(operators) {
+			return staking.ErrWrongStakingController
+		}
+		operatorMap := make(map[common.Address]struct{}, len(operators))
+		for _, operator := range operators {
+			if _, ok := operatorMap[operator]; ok || operator == common.ZeroAddr {
+				return staking.ErrWrongStakingController
+			}
+			operatorMap[operator] = struct{}{}
+		}
+
+		controller := &staking.StakingController{
+			StakingBlockNum: can.StakingBlockNum,
+			Operators:       operators,
+			Threshold:       threshold,
+		}
+		if err := sk.db.SetCanControllerStore(blockHash, canAddr, controller); nil != err {
+			log.Error("Failed to SetStakingController on stakingPlugin: Store the controller of candidate is failed",
+				"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return err
+		}
+	}
+
+	if err := sk.db.DelPendingActionStore(blockHash, canAddr); nil != err {
+		log.Error("Failed to SetStakingController on stakingPlugin: Delete the pending actions of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+		return err
+	}
+	return nil
+}
+
+// ListPendingActions returns the staking operations of the controlled candidate waiting for the approvals,
+// the expired ones are excluded.
+func (sk *StakingPlugin) ListPendingActions(blockHash common.Hash, blockNumber *big.Int, canAddr common.Address, stakingBlockNum uint64) ([]*staking.PendingAction, error) {
+	queue, err := sk.getPendingActionQueue(blockHash, blockNumber, canAddr, stakingBlockNum)
+	if nil != err {
+		return nil, err
+	}
+	return queue.Actions, nil
+}
+
+func (sk *StakingPlugin) getPendingActionQueue(blockHash common.Hash, blockNumber *big.Int, canAddr common.Address, stakingBlockNum uint64) (*staking.PendingActionQueue, error) {
+	queue, err := sk.db.GetPendingActionStore(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	// the pending actions of the previous staking are dropped
+	if nil == queue || queue.StakingBlockNum != stakingBlockNum {
+		queue = &staking.PendingActionQueue{StakingBlockNum: stakingBlockNum}
+	}
+	// the expired actions are dropped, they are removed from the db by the next storing of the queue
+	queue.DropExpired(blockNumber.Uint64())
+	return queue, nil
+}
+
+func (sk *StakingPlugin) setPendingActionQueue(blockHash common.Hash, canAddr common.Address, queue *staking.PendingActionQueue) error {
+	if len(queue.Actions) == 0 {
+		return sk.db.DelPendingActionStore(blockHash, canAddr)
+	}
+	return sk.db.SetPendingActionStore(blockHash, canAddr, queue)
+}
+
+// SubmitStakingAction approves the staking operation of the controlled candidate by the operator,
+// the operation is pending since its first approval, until it expires after PendingActionExpiredEpochs epochs.
+// It returns true if the operation is approved by enough operators, then it should be executed right now,
+// and be removed by RemoveStakingAction whether the execution is succeeded or not.
+func (sk *StakingPlugin) SubmitStakingAction(blockHash common.Hash, blockNumber *big.Int, canAddr common.Address,
+	controller *staking.StakingController, fnType uint16, input []byte, operator common.Address) (*staking.PendingAction, bool, error) {
+
+	queue, err := sk.getPendingActionQueue(blockHash, blockNumber, canAddr, controller.StakingBlockNum)
+	if nil != err {
+		log.Error("Failed to SubmitStakingAction on stakingPlugin: Query the pending actions of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return nil, false, err
+	}
+
+	actionId := crypto.Keccak256Hash(common.Uint64ToBytes(controller.StakingBlockNum), input)
+	_, action := queue.Find(actionId)
+	if nil == action {
+		if len(queue.Actions) >= staking.MaxPendingActions {
+			return nil, false, staking.ErrTooManyPendingActions
+		}
+		if queue.CountByProposer(operator) >= staking.MaxPendingActionsPerOperator {
+			return nil, false, staking.ErrTooManyOperatorActions
+		}
+		action = &staking.PendingAction{
+			ActionId:    actionId,
+			FuncType:    fnType,
+			Input:       input,
+			Proposer:    operator,
+			BlockNumber: blockNumber.Uint64(),
+		}
+		queue.Actions = append(queue.Actions, action)
+	}
+	return sk.approveStakingAction(blockHash, canAddr, controller, queue, action, operator)
+}
+
+// ApproveStakingAction approves the pending staking operation of the controlled candidate by the operator,
+// It returns true if the operation is approved by enough operators, like SubmitStakingAction.
+func (sk *StakingPlugin) ApproveStakingAction(blockHash common.Hash, blockNumber *big.Int, canAddr common.Address,
+	controller *staking.StakingController, actionId common.Hash, operator common.Address) (*staking.PendingAction, bool, error) {
+
+	queue, err := sk.getPendingActionQueue(blockHash, blockNumber, canAddr, controller.StakingBlockNum)
+	if nil != err {
+		log.Error("Failed to ApproveStakingAction on stakingPlugin: Query the pending actions of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return nil, false, err
+	}
+
+	_, action := queue.Find(actionId)
+	if nil == action {
+		return nil, false, staking.ErrPendingActionNoExist
+	}
+	return sk.approveStakingAction(blockHash, canAddr, controller, queue, action, operator)
+}
+
+func (sk *StakingPlugin) approveStakingAction(blockHash common.Hash, canAddr common.Address, controller *staking.StakingController,
+	queue *staking.PendingActionQueue, action *staking.PendingAction, operator common.Address) (*staking.PendingAction, bool, error) {
+
+	if action.IsApprovedBy(operator) {
+		return nil, false, staking.ErrPendingActionApproved
+	}
+	action.Approvals = append(action.Approvals, operator)
+
+	if err := sk.setPendingActionQueue(blockHash, canAddr, queue); nil != err {
+		log.Error("Failed to approve the staking action on stakingPlugin: Store the pending actions of candidate is failed",
+			"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return nil, false, err
+	}
+	return action, uint32(len(action.Approvals)) >= controller.Threshold, nil
+}
+
+// CancelStakingAction removes the pending staking operation of the controlled candidate by its proposer.
+func (sk *StakingPlugin) CancelStakingAction(blockHash common.Hash, blockNumber *big.Int, canAddr common.Address,
+	controller *staking.StakingController, actionId common.Hash, operator common.Address) error {
+
+	queue, err := sk.getPendingActionQueue(blockHash, blockNumber, canAddr, controller.StakingBlockNum)
+	if nil != err {
+		log.Error("Failed to CancelStakingAction on stakingPlugin: Query the pending actions of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return err
+	}
+
+	index, action := queue.Find(actionId)
+	if nil == action {
+		return staking.ErrPendingActionNoExist
+	}
+	if action.Proposer != operator {
+		return staking.ErrNotActionProposer
+	}
+	queue.Actions = append(queue.Actions[:index], queue.Actions[index+1:]...)
+
+	if err := sk.setPendingActionQueue(blockHash, canAddr, queue); nil != err {
+		log.Error("Failed to CancelStakingAction on stakingPlugin: Store the pending actions of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveStakingAction removes the pending staking operation of the controlled candidate,
+// it's called after the approved operation is executed, the failed operation is dropped as well,
+// so the operators have to submit it again.
+func (sk *StakingPlugin) RemoveStakingAction(blockHash common.Hash, blockNumber *big.Int, canAddr common.Address, stakingBlockNum uint64, actionId common.Hash) error {
+
+	queue, err := sk.getPendingActionQueue(blockHash, blockNumber, canAddr, stakingBlockNum)
+	if nil != err {
+		log.Error("Failed to RemoveStakingAction on stakingPlugin: Query the pending actions of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return err
+	}
+
+	// the pending actions may be dropped by the executed action
+	index, action := queue.Find(actionId)
+	if nil == action {
+		return nil
+	}
+	queue.Actions = append(queue.Actions[:index], queue.Actions[index+1:]...)
+
+	if err := sk.setPendingActionQueue(blockHash, canAddr, queue); nil != err {
+		log.Error("Failed to RemoveStakingAction on stakingPlugin: Store the pending actions of candidate is failed",
+			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return err
+	}
+	return nil
+}
+
 func (sk *StakingPlugin) removeFromVerifiers(blockNumber uint64, blockHash common.Hash, slashNodeIdMap map[discover.NodeID]struct{}) error {
 	verifier, err := sk.getVerifierList(blockHash, blockNumber, QueryStartNotIrr)
 	if nil != err {
@@ -2048,7 +3204,8 @@ func (sk *StakingPlugin) removeFromVerifiers(blockNumber uint64, blockHash commo
 	return nil
 }
 
-func handleSlashTypeFn(blockNumber uint64, blockHash common.Hash, slashType staking.CandidateStatus, remain *big.Int) (bool, bool, staking.CandidateStatus) {
+func handleSlashTypeFn(blockNumber uint64, blockHash common.Hash, slashType staking.CandidateStatus, remain *big.Int,
+	state xcom.StateDB) (bool, bool, staking.CandidateStatus) {
 
 	var needInvalid, needRemove bool         // need invalid candidate status And need remove from verifierList
 	var changeStatus staking.CandidateStatus // need to add this status
@@ -2062,7 +3219,11 @@ func handleSlashTypeFn(blockNumber uint64, blockHash common.Hash, slashType stak
 			needInvalid = true
 			needRemove = true
 		}
-	case staking.LowRatioDel:
+	case staking.LowRatioDel, staking.LowVoteRatio:
+		if gov.IsFeatureActive(FeatureJail, blockNumber, state) {
+			// the candidate is jailed, it keeps the staking and delegations until unjailed
+			changeStatus |= staking.Jailed
+		}
 		changeStatus |= staking.Invalided
 		needInvalid = true
 		needRemove = true
@@ -2308,7 +3469,7 @@ func buildCbftValidators(start uint64, arr staking.ValidatorQueue) *cbfttypes.Va
 	return res
 }
 
-func lazyCalcStakeAmount(epoch uint64, can *staking.CandidateMutable) {
+func lazyCalcStakeAmount(epoch, hesitateRatio uint64, can *staking.CandidateMutable) {
 
 	changeAmountEpoch := can.StakingEpoch
 
@@ -2317,7 +3478,7 @@ func lazyCalcStakeAmount(epoch uint64, can *staking.CandidateMutable) {
 	log.Debug("lazyCalcStakeAmount before", "current epoch", epoch, "canMutable", can)
 
 	// If it is during the same hesitation period, short circuit
-	if sub < xcom.HesitateRatio() {
+	if sub < hesitateRatio {
 		return
 	}
 
@@ -2335,7 +3496,7 @@ func lazyCalcStakeAmount(epoch uint64, can *staking.CandidateMutable) {
 
 }
 
-func lazyCalcDelegateAmount(epoch uint64, del *staking.Delegation) {
+func lazyCalcDelegateAmount(epoch, hesitateRatio uint64, del *staking.Delegation) {
 
 	// When the first time, there was no previous changeAmountEpoch
 	if del.DelegateEpoch == 0 {
@@ -2349,7 +3510,7 @@ func lazyCalcDelegateAmount(epoch uint64, del *staking.Delegation) {
 	log.Debug("lazyCalcDelegateAmount before", "epoch", epoch, "del", del)
 
 	// If it is during the same hesitation period, short circuit
-	if sub < xcom.HesitateRatio() {
+	if sub < hesitateRatio {
 		return
 	}
 
@@ -2366,6 +3527,37 @@ func lazyCalcDelegateAmount(epoch uint64, del *staking.Delegation) {
 	log.Debug("lazyCalcDelegateAmount end", "epoch", epoch, "del", del)
 }
 
+func lazyCalcDelegateTotal(epoch, hesitateRatio uint64, can *staking.CandidateMutable) {
+
+	// Prevent null pointer, the can may be stored before it has the delegate total
+	if nil == can.DelegateTotal {
+		can.DelegateTotal = new(big.Int).SetInt64(0)
+	}
+	if nil == can.DelegateTotalHes {
+		can.DelegateTotalHes = new(big.Int).SetInt64(0)
+	}
+
+	sub := epoch - uint64(can.DelegateEpoch)
+
+	// If it is during the same hesitation period, short circuit
+	if sub < hesitateRatio {
+		return
+	}
+
+	if can.DelegateTotalHes.Cmp(common.Big0) > 0 {
+		can.DelegateTotal = new(big.Int).Add(can.DelegateTotal, can.DelegateTotalHes)
+		can.DelegateTotalHes = new(big.Int).SetInt64(0)
+	}
+}
+
+// subDelegateTotal returns total - sub, but never less than zero
+func subDelegateTotal(total, sub *big.Int) *big.Int {
+	if total.Cmp(sub) <= 0 {
+		return new(big.Int).SetInt64(0)
+	}
+	return new(big.Int).Sub(total, sub)
+}
+
 type sortValidator struct {
 	v           *staking.Validator
 	x           int64
@@ -3154,21 +4346,27 @@ func calcRealRefund(blockNumber uint64, blockHash common.Hash, realtotal, amount
 
 func buildCanHex(can *staking.Candidate) *staking.CandidateHex {
 	return &staking.CandidateHex{
-		NodeId:             can.NodeId,
-		BlsPubKey:          can.BlsPubKey,
-		StakingAddress:     can.StakingAddress,
-		BenefitAddress:     can.BenefitAddress,
-		StakingTxIndex:     can.StakingTxIndex,
-		ProgramVersion:     can.ProgramVersion,
-		Status:             can.Status,
-		StakingEpoch:       can.StakingEpoch,
-		StakingBlockNum:    can.StakingBlockNum,
-		Shares:             (*hexutil.Big)(can.Shares),
-		Released:           (*hexutil.Big)(can.Released),
-		ReleasedHes:        (*hexutil.Big)(can.ReleasedHes),
-		RestrictingPlan:    (*hexutil.Big)(can.RestrictingPlan),
-		RestrictingPlanHes: (*hexutil.Big)(can.RestrictingPlanHes),
-		Description:        can.Description,
+		NodeId:               can.NodeId,
+		BlsPubKey:            can.BlsPubKey,
+		StakingAddress:       can.StakingAddress,
+		BenefitAddress:       can.BenefitAddress,
+		StakingTxIndex:       can.StakingTxIndex,
+		ProgramVersion:       can.ProgramVersion,
+		Status:               can.Status,
+		StakingEpoch:         can.StakingEpoch,
+		StakingBlockNum:      can.StakingBlockNum,
+		Shares:               (*hexutil.Big)(can.Shares),
+		Released:             (*hexutil.Big)(can.Released),
+		ReleasedHes:          (*hexutil.Big)(can.ReleasedHes),
+		RestrictingPlan:      (*hexutil.Big)(can.RestrictingPlan),
+		RestrictingPlanHes:   (*hexutil.Big)(can.RestrictingPlanHes),
+		RewardPer:            can.RewardPer,
+		NextRewardPer:        can.NextRewardPer,
+		RewardPerChangeEpoch: can.RewardPerChangeEpoch,
+		DelegateEpoch:        can.DelegateEpoch,
+		DelegateTotal:        (*hexutil.Big)(can.DelegateTotal),
+		DelegateTotalHes:     (*hexutil.Big)(can.DelegateTotalHes),
+		Description:          can.Description,
 	}
 }
 
@@ -31,6 +31,7 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common/vm"
 
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
 	"github.com/PlatONnetwork/PlatON-Go/x/handler"
 
 	"github.com/stretchr/testify/assert"
@@ -43,6 +44,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"github.com/PlatONnetwork/PlatON-Go/x/staking"
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
@@ -1645,12 +1647,14 @@ func TestStakingPlugin_WithdrewDelegate(t *testing.T) {
 	/**
 	Start Withdrew Delegate
 	*/
-	err = StakingInstance().WithdrewDelegate(state, blockHash2, blockNumber2, common.Big257, addrArr[index+1],
+	released, restrictingPlan, err := StakingInstance().WithdrewDelegate(state, blockHash2, blockNumber2, common.Big257, addrArr[index+1],
 		nodeIdArr[index], blockNumber.Uint64(), del)
 
 	if !assert.Nil(t, err, fmt.Sprintf("Failed to WithdrewDelegate: %v", err)) {
 		return
 	}
+	assert.Equal(t, common.Big257, released)
+	assert.Equal(t, common.Big0.Uint64(), restrictingPlan.Uint64())
 
 	if err := sndb.Commit(blockHash2); nil != err {
 		t.Error("Commit 2 err", err)
@@ -1663,6 +1667,202 @@ func TestStakingPlugin_WithdrewDelegate(t *testing.T) {
 	t.Log("Get Candidate Info is:", can)
 }
 
+func TestStakingPlugin_Redelegate(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	srcIndex, dstIndex := 1, 2
+
+	if err := create_staking(state, blockNumber, blockHash, srcIndex, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+	if err := create_staking(state, blockNumber, blockHash, dstIndex, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+
+	srcCan, err := getCandidate(blockHash, srcIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	srcCanAddr, _ := xutil.NodeId2Addr(srcCan.NodeId)
+
+	// make the staking von of source can in effect, so that it can be slashed
+	srcCan.Released, srcCan.ReleasedHes = srcCan.ReleasedHes, common.Big0
+	if err := StakingInstance().db.SetCanMutableStore(blockHash, srcCanAddr, srcCan.CandidateMutable); nil != err {
+		t.Error("Failed to SetCanMutableStore", err)
+		return
+	}
+
+	// build an effective delegation on source can
+	delAddr := addrArr[srcIndex+1]
+	amount, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	srcDel := &staking.Delegation{
+		DelegateEpoch:      1,
+		Released:           amount,
+		ReleasedHes:        common.Big0,
+		RestrictingPlan:    common.Big0,
+		RestrictingPlanHes: common.Big0,
+		CumulativeIncome:   common.Big0,
+		RewardPerUnit:      common.Big0,
+	}
+	state.AddBalance(vm.StakingContractAddr, amount)
+	if err := StakingInstance().db.SetDelegateStore(blockHash, delAddr, srcCan.NodeId, srcCan.StakingBlockNum, srcDel); nil != err {
+		t.Error("Failed to SetDelegateStore", err)
+		return
+	}
+
+	if err := sndb.Commit(blockHash); nil != err {
+		t.Error("Commit 1 err", err)
+		return
+	}
+
+	if err := sndb.NewBlock(blockNumber2, blockHash, blockHash2); nil != err {
+		t.Error("newBlock 2 err", err)
+		return
+	}
+
+	/**
+	Start Redelegate
+	*/
+	dstCan, err := getCandidate(blockHash2, dstIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	dstCanAddr, _ := xutil.NodeId2Addr(dstCan.NodeId)
+	dstShares := new(big.Int).Set(dstCan.Shares)
+
+	dstDel := &staking.Delegation{
+		Released:           common.Big0,
+		ReleasedHes:        common.Big0,
+		RestrictingPlan:    common.Big0,
+		RestrictingPlanHes: common.Big0,
+		CumulativeIncome:   common.Big0,
+		RewardPerUnit:      common.Big0,
+	}
+
+	// the source and target can not be the same
+	_, err = StakingInstance().Redelegate(state, blockHash2, blockNumber2, amount, delAddr, srcCan.NodeId,
+		srcCan.StakingBlockNum, srcDel, srcCanAddr, srcCan, dstDel)
+	assert.Equal(t, staking.ErrRedelegateSameNode, err)
+
+	move := new(big.Int).Div(amount, big.NewInt(2))
+	moved, err := StakingInstance().Redelegate(state, blockHash2, blockNumber2, move, delAddr, srcCan.NodeId,
+		srcCan.StakingBlockNum, srcDel, dstCanAddr, dstCan, dstDel)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to Redelegate: %v", err)) {
+		return
+	}
+	assert.Equal(t, move, moved)
+
+	srcDel, err = StakingInstance().GetDelegateInfo(blockHash2, delAddr, srcCan.NodeId, srcCan.StakingBlockNum)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to GetDelegateInfo of source: %v", err)) {
+		return
+	}
+	assert.Equal(t, new(big.Int).Sub(amount, move), srcDel.Released)
+
+	dstDel, err = StakingInstance().GetDelegateInfo(blockHash2, delAddr, dstCan.NodeId, dstCan.StakingBlockNum)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to GetDelegateInfo of target: %v", err)) {
+		return
+	}
+	// the moved von is in effect on the target at once
+	assert.Equal(t, move, dstDel.Released)
+	assert.Equal(t, common.Big0.Uint64(), dstDel.ReleasedHes.Uint64())
+	assert.Equal(t, uint32(0), dstDel.DelegateEpoch)
+
+	dstCan, err = getCandidate(blockHash2, dstIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	assert.Equal(t, new(big.Int).Add(dstShares, move), dstCan.Shares)
+	assert.Equal(t, move, dstCan.DelegateTotal)
+	assert.Equal(t, common.Big0.Uint64(), dstCan.DelegateTotalHes.Uint64())
+
+	queue, err := StakingInstance().db.GetRedelegateStore(blockHash2, srcCanAddr, srcCan.StakingBlockNum)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to GetRedelegateStore: %v", err)) {
+		return
+	}
+	assert.Equal(t, 1, len(queue))
+	t.Log("Get Redelegation record is:", queue)
+
+	if err := sndb.Commit(blockHash2); nil != err {
+		t.Error("Commit 2 err", err)
+		return
+	}
+
+	if err := sndb.NewBlock(blockNumber3, blockHash2, blockHash3); nil != err {
+		t.Error("newBlock 3 err", err)
+		return
+	}
+
+	/**
+	Slash the source can for the offence before the moving
+	*/
+	srcCan, err = getCandidate(blockHash3, srcIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+
+	slashItem := &staking.SlashNodeItem{
+		NodeId:          srcCan.NodeId,
+		Amount:          new(big.Int).Div(srcCan.Released, big.NewInt(10)),
+		SlashType:       staking.LowRatio,
+		BenefitAddr:     vm.RewardManagerPoolAddr,
+		OffenceBlockNum: blockNumber.Uint64(),
+	}
+
+	err = StakingInstance().SlashCandidates(state, blockHash3, blockNumber3.Uint64(), slashItem)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to SlashCandidates: %v", err)) {
+		return
+	}
+
+	// the moved von is slashed by the same ratio as the von stayed on the source can
+	remain := new(big.Int).Sub(move, new(big.Int).Div(move, big.NewInt(10)))
+	stay := new(big.Int).Sub(amount, move)
+	stayRemain := new(big.Int).Sub(stay, new(big.Int).Div(stay, big.NewInt(10)))
+
+	srcDel, err = StakingInstance().GetDelegateInfo(blockHash3, delAddr, srcCan.NodeId, srcCan.StakingBlockNum)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to GetDelegateInfo of source: %v", err)) {
+		return
+	}
+	assert.Equal(t, stayRemain, srcDel.Released)
+
+	dstDel, err = StakingInstance().GetDelegateInfo(blockHash3, delAddr, dstCan.NodeId, dstCan.StakingBlockNum)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to GetDelegateInfo of target: %v", err)) {
+		return
+	}
+	assert.Equal(t, remain, dstDel.Released)
+
+	dstCan, err = getCandidate(blockHash3, dstIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	assert.Equal(t, new(big.Int).Add(dstShares, remain), dstCan.Shares)
+	assert.Equal(t, remain, dstCan.DelegateTotal)
+
+	queue, err = StakingInstance().db.GetRedelegateStore(blockHash3, srcCanAddr, srcCan.StakingBlockNum)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to GetRedelegateStore: %v", err)) {
+		return
+	}
+	assert.Equal(t, remain, queue[0].Released)
+}
+
 func TestStakingPlugin_GetDelegateInfo(t *testing.T) {
 
 	state, genesis, err := newChainState()
@@ -2505,6 +2705,292 @@ func TestStakingPlugin_SlashCandidates(t *testing.T) {
 
 }
 
+func TestStakingPlugin_Unjail(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	index := 1
+
+	if err := create_staking(state, blockNumber, blockHash, index, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+
+	can, err := getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+
+	verifierArr := &staking.ValidatorArray{
+		Start: 1,
+		End:   xutil.CalcBlocksEachEpoch(),
+		Arr: staking.ValidatorQueue{
+			&staking.Validator{
+				NodeAddress:     canAddr,
+				NodeId:          can.NodeId,
+				BlsPubKey:       can.BlsPubKey,
+				ProgramVersion:  can.ProgramVersion,
+				Shares:          can.Shares,
+				StakingBlockNum: can.StakingBlockNum,
+				StakingTxIndex:  can.StakingTxIndex,
+			},
+		},
+	}
+	if err := setVerifierList(blockHash, verifierArr); nil != err {
+		t.Errorf("Failed to Set Genesis VerfierList, err: %v", err)
+		return
+	}
+
+	if err := sndb.Commit(blockHash); nil != err {
+		t.Error("Commit 1 err", err)
+		return
+	}
+
+	if err := sndb.NewBlock(blockNumber2, blockHash, blockHash2); nil != err {
+		t.Error("newBlock 2 err", err)
+		return
+	}
+
+	/**
+	Jail the candidate
+	*/
+	slashItem := &staking.SlashNodeItem{
+		NodeId:      can.NodeId,
+		Amount:      common.Big0,
+		SlashType:   staking.LowRatioDel,
+		BenefitAddr: vm.RewardManagerPoolAddr,
+	}
+	err = StakingInstance().SlashCandidates(state, blockHash2, blockNumber2.Uint64(), slashItem)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to SlashCandidates: %v", err)) {
+		return
+	}
+
+	can, err = getCandidate(blockHash2, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	assert.True(t, can.IsJailed())
+	assert.True(t, can.IsInvalid())
+
+	// the jail duration is not expired
+	err = StakingInstance().Unjail(blockHash2, blockNumber2, canAddr, can)
+	assert.Equal(t, staking.ErrJailNotExpired, err)
+
+	if err := sndb.Commit(blockHash2); nil != err {
+		t.Error("Commit 2 err", err)
+		return
+	}
+
+	/**
+	Unjail the candidate after the jail duration
+	*/
+	releaseNumber := new(big.Int).SetUint64(xutil.CalcBlocksEachEpoch()*uint64(xcom.JailDuration()) + 1)
+	if err := sndb.NewBlock(releaseNumber, blockHash2, blockHash3); nil != err {
+		t.Error("newBlock 3 err", err)
+		return
+	}
+
+	err = StakingInstance().Unjail(blockHash3, releaseNumber, canAddr, can)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to Unjail: %v", err)) {
+		return
+	}
+
+	can, err = getCandidate(blockHash3, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	assert.True(t, can.IsValid())
+	assert.False(t, can.IsJailed())
+
+	// the candidate is not jailed any more
+	err = StakingInstance().Unjail(blockHash3, releaseNumber, canAddr, can)
+	assert.Equal(t, staking.ErrCanNotJailed, err)
+}
+
+func TestStakingPlugin_StakingController(t *testing.T) {
+
+	_, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	can := &staking.Candidate{
+		CandidateBase: &staking.CandidateBase{
+			NodeId:          nodeIdArr[0],
+			StakingAddress:  sender,
+			StakingBlockNum: blockNumber.Uint64(),
+		},
+		CandidateMutable: &staking.CandidateMutable{},
+	}
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+	operators := []common.Address{addrArr[0], addrArr[1], addrArr[2]}
+
+	// wrong threshold and duplicated operators
+	assert.Equal(t, staking.ErrWrongStakingController, StakingInstance().SetStakingController(blockHash, blockNumber, canAddr, can, operators, 4))
+	assert.Equal(t, staking.ErrWrongStakingController, StakingInstance().SetStakingController(blockHash, blockNumber, canAddr, can,
+		[]common.Address{addrArr[0], addrArr[0]}, 1))
+
+	if err := StakingInstance().SetStakingController(blockHash, blockNumber, canAddr, can, operators, 2); nil != err {
+		t.Errorf("Failed to SetStakingController: %v", err)
+		return
+	}
+	controller, err := StakingInstance().GetStakingController(blockHash, canAddr, can.StakingBlockNum)
+	if !assert.Nil(t, err) || !assert.NotNil(t, controller) {
+		return
+	}
+	assert.Equal(t, uint32(2), controller.Threshold)
+	assert.True(t, controller.IsOperator(addrArr[1]))
+
+	// the controller of the previous staking is dropped
+	stale, err := StakingInstance().GetStakingController(blockHash, canAddr, can.StakingBlockNum+1)
+	assert.Nil(t, err)
+	assert.Nil(t, stale)
+
+	input := []byte("increaseStaking")
+	action, approved, err := StakingInstance().SubmitStakingAction(blockHash, blockNumber, canAddr, controller, 1002, input, addrArr[0])
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.False(t, approved)
+
+	_, _, err = StakingInstance().SubmitStakingAction(blockHash, blockNumber, canAddr, controller, 1002, input, addrArr[0])
+	assert.Equal(t, staking.ErrPendingActionApproved, err)
+
+	actions, err := StakingInstance().ListPendingActions(blockHash, blockNumber, canAddr, can.StakingBlockNum)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(actions))
+	assert.Equal(t, action.ActionId, actions[0].ActionId)
+
+	_, _, err = StakingInstance().ApproveStakingAction(blockHash, blockNumber, canAddr, controller, common.ZeroHash, addrArr[1])
+	assert.Equal(t, staking.ErrPendingActionNoExist, err)
+
+	action, approved, err = StakingInstance().ApproveStakingAction(blockHash, blockNumber, canAddr, controller, action.ActionId, addrArr[1])
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, approved)
+	assert.Equal(t, input, action.Input)
+
+	// the approved action can't be executed again by any approval
+	_, _, err = StakingInstance().ApproveStakingAction(blockHash, blockNumber, canAddr, controller, action.ActionId, addrArr[1])
+	assert.Equal(t, staking.ErrPendingActionApproved, err)
+
+	assert.Nil(t, StakingInstance().RemoveStakingAction(blockHash, blockNumber, canAddr, can.StakingBlockNum, action.ActionId))
+	actions, err = StakingInstance().ListPendingActions(blockHash, blockNumber, canAddr, can.StakingBlockNum)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actions))
+
+	// the pending actions submitted by an operator are limited
+	for i := 0; i < staking.MaxPendingActionsPerOperator; i++ {
+		_, _, err = StakingInstance().SubmitStakingAction(blockHash, blockNumber, canAddr, controller, 1002, []byte{byte(i)}, addrArr[0])
+		assert.Nil(t, err)
+	}
+	_, _, err = StakingInstance().SubmitStakingAction(blockHash, blockNumber, canAddr, controller, 1002, []byte("overflow"), addrArr[0])
+	assert.Equal(t, staking.ErrTooManyOperatorActions, err)
+
+	// only the proposer can cancel the pending action
+	actions, err = StakingInstance().ListPendingActions(blockHash, blockNumber, canAddr, can.StakingBlockNum)
+	if !assert.Nil(t, err) || !assert.Equal(t, staking.MaxPendingActionsPerOperator, len(actions)) {
+		return
+	}
+	assert.Equal(t, staking.ErrNotActionProposer,
+		StakingInstance().CancelStakingAction(blockHash, blockNumber, canAddr, controller, actions[0].ActionId, addrArr[1]))
+	assert.Equal(t, staking.ErrPendingActionNoExist,
+		StakingInstance().CancelStakingAction(blockHash, blockNumber, canAddr, controller, common.ZeroHash, addrArr[0]))
+	assert.Nil(t, StakingInstance().CancelStakingAction(blockHash, blockNumber, canAddr, controller, actions[0].ActionId, addrArr[0]))
+	actions, err = StakingInstance().ListPendingActions(blockHash, blockNumber, canAddr, can.StakingBlockNum)
+	assert.Nil(t, err)
+	if !assert.Equal(t, staking.MaxPendingActionsPerOperator-1, len(actions)) {
+		return
+	}
+
+	// the pending actions are expired after PendingActionExpiredEpochs epochs
+	expiredNumber := new(big.Int).SetUint64(blockNumber.Uint64() + staking.PendingActionExpiredEpochs*xutil.CalcBlocksEachEpoch())
+	_, _, err = StakingInstance().ApproveStakingAction(blockHash, expiredNumber, canAddr, controller, actions[0].ActionId, addrArr[1])
+	assert.Equal(t, staking.ErrPendingActionNoExist, err)
+	actions, err = StakingInstance().ListPendingActions(blockHash, expiredNumber, canAddr, can.StakingBlockNum)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actions))
+
+	// the pending actions are dropped along with the controller
+	_, _, err = StakingInstance().SubmitStakingAction(blockHash, blockNumber, canAddr, controller, 1003, []byte("withdrewStaking"), addrArr[2])
+	assert.Nil(t, err)
+	if err := StakingInstance().SetStakingController(blockHash, blockNumber, canAddr, can, nil, 0); nil != err {
+		t.Errorf("Failed to SetStakingController: %v", err)
+		return
+	}
+	controller, err = StakingInstance().GetStakingController(blockHash, canAddr, can.StakingBlockNum)
+	assert.Nil(t, err)
+	assert.Nil(t, controller)
+	actions, err = StakingInstance().ListPendingActions(blockHash, blockNumber, canAddr, can.StakingBlockNum)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actions))
+}
+
+func TestStakingPlugin_HandleSlashTypeBeforeJail(t *testing.T) {
+
+	state, _, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+
+	// the jailing is not active with the genesis version
+	if err := gov.AddActiveVersion(params.GenesisVersion, 0, state); nil != err {
+		t.Error("Failed to AddActiveVersion", err)
+		return
+	}
+	jailVersion, _ := params.FeatureVersion(FeatureJail)
+	if err := gov.AddActiveVersion(jailVersion, 100, state); nil != err {
+		t.Error("Failed to AddActiveVersion", err)
+		return
+	}
+
+	for _, slashType := range []staking.CandidateStatus{staking.LowRatioDel, staking.LowVoteRatio} {
+		needInvalid, needRemove, changeStatus := handleSlashTypeFn(99, blockHash, slashType, common.Big0, state)
+		assert.True(t, needInvalid)
+		assert.True(t, needRemove)
+		assert.True(t, changeStatus.IsInvalid())
+		assert.False(t, changeStatus.IsJailed())
+
+		needInvalid, needRemove, changeStatus = handleSlashTypeFn(100, blockHash, slashType, common.Big0, state)
+		assert.True(t, needInvalid)
+		assert.True(t, needRemove)
+		assert.True(t, changeStatus.IsInvalid())
+		assert.True(t, changeStatus.IsJailed())
+	}
+}
+
 func TestStakingPlugin_DeclarePromoteNotify(t *testing.T) {
 
 	state, genesis, err := newChainState()
@@ -3515,3 +4001,278 @@ func Test_IteratorCandidate(t *testing.T) {
 	t.Log("CandidateList:", string(arrJson))
 	t.Log("Candidate queue length:", len(queue))
 }
+
+func TestStakingPlugin_RedelegateToHesitating(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	srcIndex, dstIndex := 1, 2
+
+	if err := create_staking(state, blockNumber, blockHash, srcIndex, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+	if err := create_staking(state, blockNumber, blockHash, dstIndex, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+
+	srcCan, err := getCandidate(blockHash, srcIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	dstCan, err := getCandidate(blockHash, dstIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	dstCanAddr, _ := xutil.NodeId2Addr(dstCan.NodeId)
+
+	// the source delegation has been in effect since the 1st epoch,
+	// and the target delegation is hesitating since the 3rd epoch
+	number := new(big.Int).SetUint64(2*xutil.CalcBlocksEachEpoch() + 1)
+	epoch := uint32(xutil.CalculateEpoch(number.Uint64()))
+	delAddr := addrArr[srcIndex+1]
+	amount, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	srcDel := &staking.Delegation{
+		DelegateEpoch:      1,
+		Released:           new(big.Int).Set(amount),
+		ReleasedHes:        common.Big0,
+		RestrictingPlan:    common.Big0,
+		RestrictingPlanHes: common.Big0,
+		CumulativeIncome:   common.Big0,
+		RewardPerUnit:      common.Big0,
+	}
+	dstDel := &staking.Delegation{
+		DelegateEpoch:      epoch,
+		Released:           common.Big0,
+		ReleasedHes:        new(big.Int).Set(amount),
+		RestrictingPlan:    common.Big0,
+		RestrictingPlanHes: common.Big0,
+		CumulativeIncome:   common.Big0,
+		RewardPerUnit:      common.Big0,
+	}
+	state.AddBalance(vm.StakingContractAddr, new(big.Int).Mul(amount, common.Big2))
+	dstCan.DelegateEpoch = epoch
+	dstCan.DelegateTotalHes = new(big.Int).Set(amount)
+	if err := StakingInstance().db.SetCanMutableStore(blockHash, dstCanAddr, dstCan.CandidateMutable); nil != err {
+		t.Error("Failed to SetCanMutableStore", err)
+		return
+	}
+
+	move := new(big.Int).Div(amount, big.NewInt(2))
+	_, err = StakingInstance().Redelegate(state, blockHash, number, move, delAddr, srcCan.NodeId,
+		srcCan.StakingBlockNum, srcDel, dstCanAddr, dstCan, dstDel)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to Redelegate: %v", err)) {
+		return
+	}
+
+	dstDel, err = StakingInstance().GetDelegateInfo(blockHash, delAddr, dstCan.NodeId, dstCan.StakingBlockNum)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to GetDelegateInfo of target: %v", err)) {
+		return
+	}
+	// the moved von is in effect, and the hesitation of the target delegation is kept
+	assert.Equal(t, epoch, dstDel.DelegateEpoch)
+	assert.Equal(t, move, dstDel.Released)
+	assert.Equal(t, amount, dstDel.ReleasedHes)
+
+	dstCan, err = getCandidate(blockHash, dstIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	assert.Equal(t, move, dstCan.DelegateTotal)
+	assert.Equal(t, amount, dstCan.DelegateTotalHes)
+}
+
+func TestStakingPlugin_DecodeLegacyRecords(t *testing.T) {
+
+	// the layouts stored before the delegate reward
+	type legacyBase struct {
+		NodeId          discover.NodeID
+		BlsPubKey       bls.PublicKeyHex
+		StakingAddress  common.Address
+		BenefitAddress  common.Address
+		StakingTxIndex  uint32
+		ProgramVersion  uint32
+		StakingBlockNum uint64
+		staking.Description
+	}
+	type legacyMutable struct {
+		Status             staking.CandidateStatus
+		StakingEpoch       uint32
+		Shares             *big.Int
+		Released           *big.Int
+		ReleasedHes        *big.Int
+		RestrictingPlan    *big.Int
+		RestrictingPlanHes *big.Int
+	}
+	type legacyDelegation struct {
+		DelegateEpoch      uint32
+		Released           *big.Int
+		ReleasedHes        *big.Int
+		RestrictingPlan    *big.Int
+		RestrictingPlanHes *big.Int
+	}
+
+	baseByte, _ := rlp.EncodeToBytes(&legacyBase{
+		NodeId:          nodeIdArr[0],
+		StakingAddress:  sender,
+		BenefitAddress:  addrArr[0],
+		StakingBlockNum: 1,
+		Description:     staking.Description{NodeName: nodeNameArr[0]},
+	})
+	var base staking.CandidateBase
+	if assert.Nil(t, rlp.DecodeBytes(baseByte, &base)) {
+		assert.Equal(t, nodeIdArr[0], base.NodeId)
+		assert.Equal(t, uint64(1), base.StakingBlockNum)
+		assert.Equal(t, nodeNameArr[0], base.NodeName)
+		assert.Equal(t, uint16(0), base.RewardPer)
+		assert.True(t, base.IsLegacy())
+	}
+
+	mutableByte, _ := rlp.EncodeToBytes(&legacyMutable{
+		Shares:             big.NewInt(100),
+		Released:           big.NewInt(30),
+		ReleasedHes:        big.NewInt(10),
+		RestrictingPlan:    big.NewInt(20),
+		RestrictingPlanHes: big.NewInt(0),
+	})
+	var mutable staking.CandidateMutable
+	if assert.Nil(t, rlp.DecodeBytes(mutableByte, &mutable)) {
+		assert.Equal(t, big.NewInt(100), mutable.Shares)
+		assert.Equal(t, 0, mutable.DelegateTotal.Sign())
+		assert.Equal(t, 0, mutable.DelegateTotalHes.Sign())
+		assert.True(t, mutable.IsLegacy())
+	}
+
+	delByte, _ := rlp.EncodeToBytes(&legacyDelegation{
+		DelegateEpoch:      2,
+		Released:           big.NewInt(40),
+		ReleasedHes:        big.NewInt(0),
+		RestrictingPlan:    big.NewInt(0),
+		RestrictingPlanHes: big.NewInt(0),
+	})
+	var del staking.Delegation
+	if assert.Nil(t, rlp.DecodeBytes(delByte, &del)) {
+		assert.Equal(t, uint32(2), del.DelegateEpoch)
+		assert.Equal(t, big.NewInt(40), del.Released)
+		assert.Equal(t, 0, del.CumulativeIncome.Sign())
+		assert.Equal(t, 0, del.RewardPerUnit.Sign())
+		assert.True(t, del.IsLegacy())
+	}
+
+	// the legacy records are stored in the layout they are decoded from
+	b, _ := rlp.EncodeToBytes(&base)
+	assert.Equal(t, baseByte, b)
+	b, _ = rlp.EncodeToBytes(&mutable)
+	assert.Equal(t, mutableByte, b)
+	b, _ = rlp.EncodeToBytes(&del)
+	assert.Equal(t, delByte, b)
+
+	// the records stored now are decoded as they are
+	base.SetLegacy(false)
+	base.SetNextRewardPer(100, 1)
+	baseByte, _ = rlp.EncodeToBytes(&base)
+	var current staking.CandidateBase
+	if assert.Nil(t, rlp.DecodeBytes(baseByte, &current)) {
+		assert.Equal(t, base, current)
+	}
+}
+
+func TestStakingPlugin_migrateDelegateReward(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	index := 1
+	if err := create_staking(state, blockNumber, blockHash, index, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+	can, err := getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+
+	// the records stored before the delegate reward
+	can.CandidateBase.SetLegacy(true)
+	can.CandidateMutable.SetLegacy(true)
+	if err := StakingInstance().db.SetCandidateStore(blockHash, canAddr, can); nil != err {
+		t.Error("Failed to SetCandidateStore", err)
+		return
+	}
+	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
+	effective, hesitate := big.NewInt(300), big.NewInt(200)
+	del := &staking.Delegation{
+		DelegateEpoch:      uint32(epoch),
+		Released:           effective,
+		ReleasedHes:        common.Big0,
+		RestrictingPlan:    common.Big0,
+		RestrictingPlanHes: hesitate,
+	}
+	del.SetLegacy(true)
+	delAddr := addrArr[index+1]
+	if err := StakingInstance().db.SetDelegateStore(blockHash, delAddr, can.NodeId, can.StakingBlockNum, del); nil != err {
+		t.Error("Failed to SetDelegateStore", err)
+		return
+	}
+	// the delegation on the withdrawn staking is not counted
+	if err := StakingInstance().db.SetDelegateStore(blockHash, delAddr, can.NodeId, can.StakingBlockNum-1, del); nil != err {
+		t.Error("Failed to SetDelegateStore", err)
+		return
+	}
+
+	if !assert.Nil(t, StakingInstance().migrateDelegateReward(blockHash, blockNumber.Uint64())) {
+		return
+	}
+
+	can, err = getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	assert.False(t, can.CandidateBase.IsLegacy())
+	assert.False(t, can.CandidateMutable.IsLegacy())
+	assert.Equal(t, effective, can.DelegateTotal)
+	assert.Equal(t, hesitate, can.DelegateTotalHes)
+	assert.Equal(t, uint32(epoch), can.DelegateEpoch)
+
+	del, err = StakingInstance().GetDelegateInfo(blockHash, delAddr, can.NodeId, can.StakingBlockNum)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to GetDelegateInfo: %v", err)) {
+		return
+	}
+	assert.False(t, del.IsLegacy())
+	assert.Equal(t, effective, del.Released)
+	assert.Equal(t, 0, del.CumulativeIncome.Sign())
+	assert.Equal(t, 0, del.RewardPerUnit.Sign())
+}
@@ -24,6 +24,7 @@ var (
 	RestrictingKeyPrefix    = []byte("RestrictInfo")
 	RestrictRecordKeyPrefix = []byte("RestrictRecord")
 	EpochPrefix             = []byte("RestrictEpoch")
+	FunderKeyPrefix         = []byte("RestrictFunder")
 )
 
 // RestrictingKey used for search restricting info. key: prefix + account
@@ -49,6 +50,18 @@ func GetReleaseAccountKey(epoch uint64, index uint32) []byte {
 	return append(RestrictRecordKeyPrefix, releaseIndex...)
 }
 
+// RestrictingFunderKey used for search the senders who funded the restricting plans of the account. key: prefix + account
+func GetRestrictingFunderKey(account common.Address) []byte {
+	return append(FunderKeyPrefix, account.Bytes()...)
+}
+
+// ReleaseFunderAmountKey used for search the amount funded by the sender to be released at target epoch.
+// key: prefix + epoch + account + funder
+func GetReleaseFunderAmountKey(epoch uint64, account, funder common.Address) []byte {
+	release := append(common.Uint64ToBytes(epoch), account.Bytes()...)
+	return append(FunderKeyPrefix, append(release, funder.Bytes()...)...)
+}
+
 func GetLatestEpochKey() []byte {
 	return append(EpochPrefix, []byte("latest")...)
 }
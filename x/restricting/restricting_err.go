@@ -24,6 +24,9 @@ import (
 
 const (
 	RestrictTxPlanSize = 36
+
+	// MaxRestrictingFunders is the max number of the funders who can revoke the restricting plans of an account.
+	MaxRestrictingFunders = 10
 )
 
 var (
@@ -40,4 +43,9 @@ var (
 	ErrCreatePlanAmountLessThanZero      = common.NewBizError(304011, "create plan each amount can't less than 0")
 	ErrStakingAmountInvalid              = common.NewBizError(304012, "staking return amount is wrong")
 	ErrRestrictBalanceNotEnough          = common.NewBizError(304013, "the user restricting balance is not enough for pledge lock funds")
+	ErrRestrictingPlanNotFunded          = common.NewBizError(304014, "the sender has no unreleased restricting plan on the account")
+	ErrRevokeAmountPledged               = common.NewBizError(304015, "the unreleased restricting funds are all pledged, nothing can be revoked")
+	ErrTransferPledgedPlan               = common.NewBizError(304016, "the restricting plan with pledged funds can't be transferred")
+	ErrTransferToSelf                    = common.NewBizError(304017, "the restricting plan can't be transferred to the account itself")
+	ErrTooManyFunders                    = common.NewBizError(304018, fmt.Sprintf("the number of the funders of the restricting plans can't be more than %d", MaxRestrictingFunders))
 )
@@ -34,3 +34,25 @@ func GetHistoryIncreaseKey(year uint32) []byte {
 func HistoryBalancePrefix(year uint32) []byte {
 	return append(LastYearEndBalancePrefix, common.Uint32ToBytes(year)...)
 }
+
+var (
+	DelegateRewardPerUnitPrefix = []byte("DelegateRewardPerUnit")
+	DelegateRewardHistoryPrefix = []byte("DelegateRewardHistory")
+)
+
+// DelegateRewardPerUnitKey used for search the cumulative delegate reward per unit of the candidate
+func DelegateRewardPerUnitKey(nodeAddr common.Address, stakingNum uint64) []byte {
+	key := make([]byte, 0, len(DelegateRewardPerUnitPrefix)+common.AddressLength+8)
+	key = append(key, DelegateRewardPerUnitPrefix...)
+	key = append(key, nodeAddr.Bytes()...)
+	return append(key, common.Uint64ToBytes(stakingNum)...)
+}
+
+// DelegateRewardHistoryKey used for search the cumulative delegate reward per unit of the candidate at the end of epoch
+func DelegateRewardHistoryKey(nodeAddr common.Address, stakingNum, epoch uint64) []byte {
+	key := make([]byte, 0, len(DelegateRewardHistoryPrefix)+common.AddressLength+16)
+	key = append(key, DelegateRewardHistoryPrefix...)
+	key = append(key, nodeAddr.Bytes()...)
+	key = append(key, common.Uint64ToBytes(stakingNum)...)
+	return append(key, common.Uint64ToBytes(epoch)...)
+}
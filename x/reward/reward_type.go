@@ -0,0 +1,49 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package reward
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+// The delegate reward that the delegator can withdraw from one candidate
+type DelegateReward struct {
+	NodeId          discover.NodeID
+	StakingBlockNum uint64
+	Reward          *hexutil.Big
+}
+
+func (dr *DelegateReward) String() string {
+	return fmt.Sprintf(`{"NodeId": "%s","StakingBlockNum": %d,"Reward": "%s"}`,
+		fmt.Sprintf("%x", dr.NodeId.Bytes()),
+		dr.StakingBlockNum,
+		dr.Reward)
+}
+
+type DelegateRewardQueue []*DelegateReward
+
+func (queue DelegateRewardQueue) String() string {
+	arr := make([]string, len(queue))
+	for i, r := range queue {
+		arr[i] = r.String()
+	}
+	return "[" + strings.Join(arr, ",") + "]"
+}
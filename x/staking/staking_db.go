@@ -483,6 +483,114 @@ func (db *StakingDB) DelDelegateStoreBySuffix(blockHash common.Hash, suffix []by
 	return db.del(blockHash, key)
 }
 
+// about redelegate ...
+
+func (db *StakingDB) GetRedelegateStore(blockHash common.Hash, canAddr common.Address, stakeBlockNumber uint64) (RedelegationQueue, error) {
+
+	key := GetRedelegateKey(canAddr, stakeBlockNumber)
+
+	queueByte, err := db.get(blockHash, key)
+	if nil != err {
+		return nil, err
+	}
+
+	var queue RedelegationQueue
+	if err := rlp.DecodeBytes(queueByte, &queue); nil != err {
+		return nil, err
+	}
+	return queue, nil
+}
+
+func (db *StakingDB) SetRedelegateStore(blockHash common.Hash, canAddr common.Address, stakeBlockNumber uint64,
+	queue RedelegationQueue) error {
+
+	key := GetRedelegateKey(canAddr, stakeBlockNumber)
+
+	queueByte, err := rlp.EncodeToBytes(queue)
+	if nil != err {
+		return err
+	}
+
+	return db.put(blockHash, key, queueByte)
+}
+
+func (db *StakingDB) DelRedelegateStore(blockHash common.Hash, canAddr common.Address, stakeBlockNumber uint64) error {
+	key := GetRedelegateKey(canAddr, stakeBlockNumber)
+
+	return db.del(blockHash, key)
+}
+
+// about jail ...
+
+// GetCanJailStore returns the epoch from which the jailed candidate can be unjailed.
+func (db *StakingDB) GetCanJailStore(blockHash common.Hash, canAddr common.Address) (uint64, error) {
+	epochByte, err := db.get(blockHash, GetCanJailKey(canAddr))
+	if nil != err {
+		return 0, err
+	}
+	return common.BytesToUint64(epochByte), nil
+}
+
+func (db *StakingDB) SetCanJailStore(blockHash common.Hash, canAddr common.Address, epoch uint64) error {
+	return db.put(blockHash, GetCanJailKey(canAddr), common.Uint64ToBytes(epoch))
+}
+
+func (db *StakingDB) DelCanJailStore(blockHash common.Hash, canAddr common.Address) error {
+	return db.del(blockHash, GetCanJailKey(canAddr))
+}
+
+// about staking controller ...
+
+func (db *StakingDB) GetCanControllerStore(blockHash common.Hash, canAddr common.Address) (*StakingController, error) {
+	controllerByte, err := db.get(blockHash, GetCanControllerKey(canAddr))
+	if nil != err {
+		return nil, err
+	}
+
+	var controller StakingController
+	if err := rlp.DecodeBytes(controllerByte, &controller); nil != err {
+		return nil, err
+	}
+	return &controller, nil
+}
+
+func (db *StakingDB) SetCanControllerStore(blockHash common.Hash, canAddr common.Address, controller *StakingController) error {
+	controllerByte, err := rlp.EncodeToBytes(controller)
+	if nil != err {
+		return err
+	}
+	return db.put(blockHash, GetCanControllerKey(canAddr), controllerByte)
+}
+
+func (db *StakingDB) DelCanControllerStore(blockHash common.Hash, canAddr common.Address) error {
+	return db.del(blockHash, GetCanControllerKey(canAddr))
+}
+
+func (db *StakingDB) GetPendingActionStore(blockHash common.Hash, canAddr common.Address) (*PendingActionQueue, error) {
+	queueByte, err := db.get(blockHash, GetPendingActionKey(canAddr))
+	if nil != err {
+		return nil, err
+	}
+
+	var queue PendingActionQueue
+	if err := rlp.DecodeBytes(queueByte, &queue); nil != err {
+		return nil, err
+	}
+	return &queue, nil
+}
+
+func (db *StakingDB) SetPendingActionStore(blockHash common.Hash, canAddr common.Address, queue *PendingActionQueue) error {
+	queueByte, err := rlp.EncodeToBytes(queue)
+	if nil != err {
+		return err
+	}
+	return db.put(blockHash, GetPendingActionKey(canAddr), queueByte)
+}
+
+func (db *StakingDB) DelPendingActionStore(blockHash common.Hash, canAddr common.Address) error {
+	return db.del(blockHash, GetPendingActionKey(canAddr))
+}
+
 // about epoch validates ...
 
 func (db *StakingDB) SetEpochValIndex(blockHash common.Hash, indexArr ValArrIndexQueue) error {
@@ -646,6 +754,14 @@ func (db *StakingDB) IteratorDelegateByBlockHashWithAddr(blockHash common.Hash,
 	return db.ranking(blockHash, prefix, ranges)
 }
 
+func (db *StakingDB) IteratorCanBaseByBlockHash(blockHash common.Hash, ranges int) iterator.Iterator {
+	return db.ranking(blockHash, CanBaseKeyPrefix, ranges)
+}
+
+func (db *StakingDB) IteratorDelegateByBlockHash(blockHash common.Hash, ranges int) iterator.Iterator {
+	return db.ranking(blockHash, DelegateKeyPrefix, ranges)
+}
+
 // about account staking reference count ...
 
 func (db *StakingDB) AddAccountStakeRc(blockHash common.Hash, addr common.Address) error {
@@ -43,6 +43,10 @@ const (
 	PPOSHASHStr                = "PPOSHASH"
 	RoundValAddrArrPrefixStr   = "RoundValAddrArr"
 	RoundAddrBoundaryPrefixStr = "RoundAddrBoundary"
+	RedelegatePrefixStr        = "Redelegate"
+	CanJailPrefixStr           = "CanJail"
+	CanControllerPrefixStr     = "CanController"
+	PendingActionPrefixStr     = "PendingAction"
 )
 
 var (
@@ -60,6 +64,10 @@ var (
 	PPOSHASHKey             = []byte(PPOSHASHStr)
 	RoundValAddrArrPrefix   = []byte(RoundValAddrArrPrefixStr)
 	RoundAddrBoundaryPrefix = []byte(RoundAddrBoundaryPrefixStr)
+	RedelegateKeyPrefix     = []byte(RedelegatePrefixStr)
+	CanJailKeyPrefix        = []byte(CanJailPrefixStr)
+	CanControllerKeyPrefix  = []byte(CanControllerPrefixStr)
+	PendingActionKeyPrefix  = []byte(PendingActionPrefixStr)
 
 	b104Len = len(math.MaxBig104.Bytes())
 )
@@ -242,3 +250,32 @@ func GetRoundValAddrArrKey(round uint64) []byte {
 func GetRoundAddrBoundaryKey() []byte {
 	return RoundAddrBoundaryPrefix
 }
+
+func GetRedelegateKey(canAddr common.Address, stakeBlockNumber uint64) []byte {
+
+	addrByte := canAddr.Bytes()
+	stakeNumByte := common.Uint64ToBytes(stakeBlockNumber)
+
+	markPre := len(RedelegateKeyPrefix)
+	markAddr := markPre + len(addrByte)
+	size := markAddr + len(stakeNumByte)
+
+	key := make([]byte, size)
+	copy(key[:markPre], RedelegateKeyPrefix)
+	copy(key[markPre:markAddr], addrByte)
+	copy(key[markAddr:], stakeNumByte)
+
+	return key
+}
+
+func GetCanJailKey(canAddr common.Address) []byte {
+	return append(CanJailKeyPrefix, canAddr.Bytes()...)
+}
+
+func GetCanControllerKey(canAddr common.Address) []byte {
+	return append(CanControllerKeyPrefix, canAddr.Bytes()...)
+}
+
+func GetPendingActionKey(canAddr common.Address) []byte {
+	return append(PendingActionKeyPrefix, canAddr.Bytes()...)
+}
@@ -26,6 +26,7 @@ var (
 	ErrProgramVersionTooLow      = common.NewBizError(301004, "The program version of the relates node's is too low")
 	ErrDeclVsFialedCreateCan     = common.NewBizError(301005, "DeclareVersion is failed on create staking")
 	ErrNoSameStakingAddr         = common.NewBizError(301006, "The address must be the same as initiated staking")
+	ErrWrongRewardPer            = common.NewBizError(301007, "The commission rate of delegate reward is wrong")
 	ErrStakeVonTooLow            = common.NewBizError(301100, "Staking deposit too low")
 	ErrCanAlreadyExist           = common.NewBizError(301101, "This candidate is already exist")
 	ErrCanNoExist                = common.NewBizError(301102, "This candidate is not exist")
@@ -46,10 +47,25 @@ var (
 	ErrWrongSlashType            = common.NewBizError(301117, "The slashing type is wrong")
 	ErrSlashVonOverflow          = common.NewBizError(301118, "Slashing amount is overflow")
 	ErrWrongSlashVonCalc         = common.NewBizError(301119, "Slashing candidate von calculate is wrong")
+	ErrDelegateRewardNoExist     = common.NewBizError(301120, "The delegate reward is not exist")
+	ErrRedelegateSameNode        = common.NewBizError(301121, "The source and target candidate of redelegation are the same")
+	ErrWrongRedelegateVonCalc    = common.NewBizError(301122, "Redelegation von calculation is wrong")
+	ErrCanNotJailed              = common.NewBizError(301123, "This candidate is not jailed")
+	ErrJailNotExpired            = common.NewBizError(301124, "The jail duration of the candidate is not expired")
+	ErrWrongStakingController    = common.NewBizError(301125, "The operators or threshold of the staking controller is wrong")
+	ErrNotStakingOperator        = common.NewBizError(301126, "The address is not an operator of the staking controller")
+	ErrPendingActionNoExist      = common.NewBizError(301127, "The pending staking action is not exist")
+	ErrPendingActionApproved     = common.NewBizError(301128, "The pending staking action is already approved by the address")
+	ErrTooManyPendingActions     = common.NewBizError(301129, "The pending staking actions of the candidate are too many")
+	ErrTooManyOperatorActions    = common.NewBizError(301130, "The pending staking actions submitted by the operator are too many")
+	ErrNotActionProposer         = common.NewBizError(301131, "The address is not the proposer of the pending staking action")
 	ErrGetVerifierList           = common.NewBizError(301200, "Getting verifierList is failed")
 	ErrGetValidatorList          = common.NewBizError(301201, "Getting validatorList is failed")
 	ErrGetCandidateList          = common.NewBizError(301202, "Getting candidateList is failed")
 	ErrGetDelegateRelated        = common.NewBizError(301203, "Getting related of delegate is failed")
 	ErrQueryCandidateInfo        = common.NewBizError(301204, "Query candidate info failed")
 	ErrQueryDelegateInfo         = common.NewBizError(301205, "Query delegate info failed")
+	ErrQueryDelegateReward       = common.NewBizError(301206, "Query delegate reward failed")
+	ErrQueryStakingController    = common.NewBizError(301207, "Query staking controller failed")
+	ErrQueryPendingActions       = common.NewBizError(301208, "Query pending staking actions failed")
 )
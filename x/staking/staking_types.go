@@ -18,6 +18,7 @@ package staking
 
 import (
 	"fmt"
+	"io"
 	"math/big"
 	"strings"
 
@@ -28,6 +29,7 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
 const (
@@ -42,6 +44,8 @@ const (
 	DuplicateSign                             // 1000: The Duplicate package or Duplicate sign
 	LowRatioDel                               // 0001,0000: The lowRatio AND must delete
 	Withdrew                                  // 0010,0000: The Active withdrew
+	LowVoteRatio                              // 0100,0000: The validator missed too many votes of the QCs AND was invalided (jailed since the jail feature)
+	Jailed                                    // 1000,0000: The candidate was jailed AND can be unjailed after the jail duration
 	Valided       = 0                         // 0000: The current candidate is in force
 	NotExist      = 1 << 31                   // 1000,xxxx,... : The candidate is not exist
 )
@@ -124,6 +128,18 @@ func (status CandidateStatus) IsInvalidWithdrew() bool {
 	return status&(Invalided|Withdrew) == (Invalided | Withdrew)
 }
 
+func (status CandidateStatus) IsLowVoteRatio() bool {
+	return status&LowVoteRatio == LowVoteRatio
+}
+
+func (status CandidateStatus) IsInvalidLowVoteRatio() bool {
+	return status&(Invalided|LowVoteRatio) == (Invalided | LowVoteRatio)
+}
+
+func (status CandidateStatus) IsJailed() bool {
+	return status&Jailed == Jailed
+}
+
 // The Candidate info
 type Candidate struct {
 	*CandidateBase
@@ -131,7 +147,7 @@ type Candidate struct {
 }
 
 func (can *Candidate) String() string {
-	return fmt.Sprintf(`{"NodeId": "%s","BlsPubKey": "%s","StakingAddress": "%s","BenefitAddress": "%s","StakingTxIndex": %d,"ProgramVersion": %d,"Status": %d,"StakingEpoch": %d,"StakingBlockNum": %d,"Shares": %d,"Released": %d,"ReleasedHes": %d,"RestrictingPlan": %d,"RestrictingPlanHes": %d,"ExternalId": "%s","NodeName": "%s","Website": "%s","Details": "%s"}`,
+	return fmt.Sprintf(`{"NodeId": "%s","BlsPubKey": "%s","StakingAddress": "%s","BenefitAddress": "%s","StakingTxIndex": %d,"ProgramVersion": %d,"Status": %d,"StakingEpoch": %d,"StakingBlockNum": %d,"Shares": %d,"Released": %d,"ReleasedHes": %d,"RestrictingPlan": %d,"RestrictingPlanHes": %d,"RewardPer": %d,"NextRewardPer": %d,"RewardPerChangeEpoch": %d,"DelegateEpoch": %d,"DelegateTotal": %d,"DelegateTotalHes": %d,"ExternalId": "%s","NodeName": "%s","Website": "%s","Details": "%s"}`,
 		fmt.Sprintf("%x", can.NodeId.Bytes()),
 		fmt.Sprintf("%x", can.BlsPubKey.Bytes()),
 		fmt.Sprintf("%x", can.StakingAddress.Bytes()),
@@ -146,6 +162,12 @@ func (can *Candidate) String() string {
 		can.ReleasedHes,
 		can.RestrictingPlan,
 		can.RestrictingPlanHes,
+		can.RewardPer,
+		can.NextRewardPer,
+		can.RewardPerChangeEpoch,
+		can.DelegateEpoch,
+		can.DelegateTotal,
+		can.DelegateTotalHes,
 		can.ExternalId,
 		can.NodeName,
 		can.Website,
@@ -175,12 +197,22 @@ type CandidateBase struct {
 	ProgramVersion uint32
 	// Block height at the time of staking
 	StakingBlockNum uint64
+	// The commission rate that the candidate keeps from the rewards of its delegators
+	// (in basis points, 10000 means 100%)
+	RewardPer uint16
+	// The commission rate edited, it takes the place of RewardPer since RewardPerChangeEpoch
+	NextRewardPer uint16
+	// The epoch number since which NextRewardPer is in effect, 0 means no pending change
+	RewardPerChangeEpoch uint32
 	// Node desc
 	Description
+
+	// stored in the layout before the delegate reward
+	legacy bool
 }
 
 func (can *CandidateBase) String() string {
-	return fmt.Sprintf(`{"NodeId": "%s","BlsPubKey": "%s","StakingAddress": "%s","BenefitAddress": "%s","StakingTxIndex": %d,"ProgramVersion": %d,"StakingBlockNum": %d,"ExternalId": "%s","NodeName": "%s","Website": "%s","Details": "%s"}`,
+	return fmt.Sprintf(`{"NodeId": "%s","BlsPubKey": "%s","StakingAddress": "%s","BenefitAddress": "%s","StakingTxIndex": %d,"ProgramVersion": %d,"StakingBlockNum": %d,"RewardPer": %d,"NextRewardPer": %d,"RewardPerChangeEpoch": %d,"ExternalId": "%s","NodeName": "%s","Website": "%s","Details": "%s"}`,
 		fmt.Sprintf("%x", can.NodeId.Bytes()),
 		fmt.Sprintf("%x", can.BlsPubKey.Bytes()),
 		fmt.Sprintf("%x", can.StakingAddress.Bytes()),
@@ -188,12 +220,31 @@ func (can *CandidateBase) String() string {
 		can.StakingTxIndex,
 		can.ProgramVersion,
 		can.StakingBlockNum,
+		can.RewardPer,
+		can.NextRewardPer,
+		can.RewardPerChangeEpoch,
 		can.ExternalId,
 		can.NodeName,
 		can.Website,
 		can.Details)
 }
 
+// CalcRewardPer puts the edited commission rate in effect once its epoch is reached
+func (can *CandidateBase) CalcRewardPer(epoch uint64) {
+	if can.RewardPerChangeEpoch != 0 && epoch >= uint64(can.RewardPerChangeEpoch) {
+		can.RewardPer = can.NextRewardPer
+		can.RewardPerChangeEpoch = 0
+	}
+}
+
+// SetNextRewardPer records the commission rate edited on the epoch, it's in effect since the next epoch,
+// so the reward of the current epoch is still shared by the rate that the delegators have seen
+func (can *CandidateBase) SetNextRewardPer(rewardPer uint16, epoch uint64) {
+	can.CalcRewardPer(epoch)
+	can.NextRewardPer = rewardPer
+	can.RewardPerChangeEpoch = uint32(epoch) + 1
+}
+
 func (can *CandidateBase) IsNotEmpty() bool {
 	return !can.IsEmpty()
 }
@@ -202,6 +253,69 @@ func (can *CandidateBase) IsEmpty() bool {
 	return nil == can
 }
 
+// IsLegacy reports whether the CandidateBase is stored in the layout before the delegate reward
+func (can *CandidateBase) IsLegacy() bool {
+	return can.legacy
+}
+
+// SetLegacy sets the layout the CandidateBase is stored in,
+// the layout before the delegate reward is kept until the delegate reward is activated.
+func (can *CandidateBase) SetLegacy(legacy bool) {
+	can.legacy = legacy
+}
+
+// candidateBaseRLP is the CandidateBase decoded by the default rules of rlp
+type candidateBaseRLP CandidateBase
+
+// candidateBaseV0 is the layout of the CandidateBase stored before the delegate reward
+type candidateBaseV0 struct {
+	NodeId          discover.NodeID
+	BlsPubKey       bls.PublicKeyHex
+	StakingAddress  common.Address
+	BenefitAddress  common.Address
+	StakingTxIndex  uint32
+	ProgramVersion  uint32
+	StakingBlockNum uint64
+	Description
+}
+
+// DecodeRLP implements rlp.Decoder, the CandidateBase stored before the delegate reward can be decoded too
+func (can *CandidateBase) DecodeRLP(s *rlp.Stream) error {
+	var v0 candidateBaseV0
+	if legacy, err := decodeLegacyRLP(s, 8, (*candidateBaseRLP)(can), &v0); nil != err || !legacy {
+		return err
+	}
+	*can = CandidateBase{
+		NodeId:          v0.NodeId,
+		BlsPubKey:       v0.BlsPubKey,
+		StakingAddress:  v0.StakingAddress,
+		BenefitAddress:  v0.BenefitAddress,
+		StakingTxIndex:  v0.StakingTxIndex,
+		ProgramVersion:  v0.ProgramVersion,
+		StakingBlockNum: v0.StakingBlockNum,
+		Description:     v0.Description,
+		legacy:          true,
+	}
+	return nil
+}
+
+// EncodeRLP implements rlp.Encoder, the legacy CandidateBase is encoded in the layout before the delegate reward
+func (can *CandidateBase) EncodeRLP(w io.Writer) error {
+	if !can.legacy {
+		return rlp.Encode(w, (*candidateBaseRLP)(can))
+	}
+	return rlp.Encode(w, &candidateBaseV0{
+		NodeId:          can.NodeId,
+		BlsPubKey:       can.BlsPubKey,
+		StakingAddress:  can.StakingAddress,
+		BenefitAddress:  can.BenefitAddress,
+		StakingTxIndex:  can.StakingTxIndex,
+		ProgramVersion:  can.ProgramVersion,
+		StakingBlockNum: can.StakingBlockNum,
+		Description:     can.Description,
+	})
+}
+
 type CandidateMutable struct {
 	// The candidate status
 	// Reference `THE CANDIDATE  STATUS`
@@ -218,17 +332,29 @@ type CandidateMutable struct {
 	RestrictingPlan *big.Int
 	// The staking von  is RestrictingPlan for hesitant epoch (in hesitation)
 	RestrictingPlanHes *big.Int
+	// The epoch number at the last delegate or withdrew delegate
+	DelegateEpoch uint32
+	// The total delegated von of the candidate for effective epoch (in effect)
+	DelegateTotal *big.Int
+	// The total delegated von of the candidate for hesitant epoch (in hesitation)
+	DelegateTotalHes *big.Int
+
+	// stored in the layout before the delegate reward
+	legacy bool
 }
 
 func (can *CandidateMutable) String() string {
-	return fmt.Sprintf(`{"Status": %d,"StakingEpoch": %d,"Shares": %d,"Released": %d,"ReleasedHes": %d,"RestrictingPlan": %d,"RestrictingPlanHes": %d}`,
+	return fmt.Sprintf(`{"Status": %d,"StakingEpoch": %d,"Shares": %d,"Released": %d,"ReleasedHes": %d,"RestrictingPlan": %d,"RestrictingPlanHes": %d,"DelegateEpoch": %d,"DelegateTotal": %d,"DelegateTotalHes": %d}`,
 		can.Status,
 		can.StakingEpoch,
 		can.Shares,
 		can.Released,
 		can.ReleasedHes,
 		can.RestrictingPlan,
-		can.RestrictingPlanHes)
+		can.RestrictingPlanHes,
+		can.DelegateEpoch,
+		can.DelegateTotal,
+		can.DelegateTotalHes)
 }
 
 func (can *CandidateMutable) CleanLowRatioStatus() {
@@ -255,6 +381,90 @@ func (can *CandidateMutable) IsEmpty() bool {
 	return nil == can
 }
 
+// IsLegacy reports whether the CandidateMutable is stored in the layout before the delegate reward
+func (can *CandidateMutable) IsLegacy() bool {
+	return can.legacy
+}
+
+// SetLegacy sets the layout the CandidateMutable is stored in,
+// the layout before the delegate reward is kept until the delegate reward is activated.
+func (can *CandidateMutable) SetLegacy(legacy bool) {
+	can.legacy = legacy
+}
+
+// candidateMutableRLP is the CandidateMutable decoded by the default rules of rlp
+type candidateMutableRLP CandidateMutable
+
+// candidateMutableV0 is the layout of the CandidateMutable stored before the delegate reward
+type candidateMutableV0 struct {
+	Status             CandidateStatus
+	StakingEpoch       uint32
+	Shares             *big.Int
+	Released           *big.Int
+	ReleasedHes        *big.Int
+	RestrictingPlan    *big.Int
+	RestrictingPlanHes *big.Int
+}
+
+// DecodeRLP implements rlp.Decoder, the CandidateMutable stored before the delegate reward can be decoded too.
+// Its delegate total is zero until the delegations are counted at the activation of the delegate reward.
+func (can *CandidateMutable) DecodeRLP(s *rlp.Stream) error {
+	var v0 candidateMutableV0
+	if legacy, err := decodeLegacyRLP(s, 7, (*candidateMutableRLP)(can), &v0); nil != err || !legacy {
+		return err
+	}
+	*can = CandidateMutable{
+		Status:             v0.Status,
+		StakingEpoch:       v0.StakingEpoch,
+		Shares:             v0.Shares,
+		Released:           v0.Released,
+		ReleasedHes:        v0.ReleasedHes,
+		RestrictingPlan:    v0.RestrictingPlan,
+		RestrictingPlanHes: v0.RestrictingPlanHes,
+		DelegateTotal:      new(big.Int),
+		DelegateTotalHes:   new(big.Int),
+		legacy:             true,
+	}
+	return nil
+}
+
+// EncodeRLP implements rlp.Encoder, the legacy CandidateMutable is encoded in the layout before the delegate reward
+func (can *CandidateMutable) EncodeRLP(w io.Writer) error {
+	if !can.legacy {
+		return rlp.Encode(w, (*candidateMutableRLP)(can))
+	}
+	return rlp.Encode(w, &candidateMutableV0{
+		Status:             can.Status,
+		StakingEpoch:       can.StakingEpoch,
+		Shares:             can.Shares,
+		Released:           can.Released,
+		ReleasedHes:        can.ReleasedHes,
+		RestrictingPlan:    can.RestrictingPlan,
+		RestrictingPlanHes: can.RestrictingPlanHes,
+	})
+}
+
+// decodeLegacyRLP decodes the rlp list into legacy if it has as many values as the legacy layout,
+// otherwise into cur. It returns whether the legacy layout was decoded.
+func decodeLegacyRLP(s *rlp.Stream, legacyCount int, cur, legacy interface{}) (bool, error) {
+	raw, err := s.Raw()
+	if nil != err {
+		return false, err
+	}
+	content, _, err := rlp.SplitList(raw)
+	if nil != err {
+		return false, err
+	}
+	count, err := rlp.CountValues(content)
+	if nil != err {
+		return false, err
+	}
+	if count == legacyCount {
+		return true, rlp.DecodeBytes(raw, legacy)
+	}
+	return false, rlp.DecodeBytes(raw, cur)
+}
+
 func (can *CandidateMutable) IsValid() bool {
 	return can.Status.IsValid()
 }
@@ -331,27 +541,45 @@ func (can *CandidateMutable) IsInvalidWithdrew() bool {
 	return can.Status.IsInvalidWithdrew()
 }
 
+func (can *CandidateMutable) IsLowVoteRatio() bool {
+	return can.Status.IsLowVoteRatio()
+}
+
+func (can *CandidateMutable) IsInvalidLowVoteRatio() bool {
+	return can.Status.IsInvalidLowVoteRatio()
+}
+
+func (can *CandidateMutable) IsJailed() bool {
+	return can.Status.IsJailed()
+}
+
 // Display amount field using 0x hex
 type CandidateHex struct {
-	NodeId             discover.NodeID
-	BlsPubKey          bls.PublicKeyHex
-	StakingAddress     common.Address
-	BenefitAddress     common.Address
-	StakingTxIndex     uint32
-	ProgramVersion     uint32
-	Status             CandidateStatus
-	StakingEpoch       uint32
-	StakingBlockNum    uint64
-	Shares             *hexutil.Big
-	Released           *hexutil.Big
-	ReleasedHes        *hexutil.Big
-	RestrictingPlan    *hexutil.Big
-	RestrictingPlanHes *hexutil.Big
+	NodeId               discover.NodeID
+	BlsPubKey            bls.PublicKeyHex
+	StakingAddress       common.Address
+	BenefitAddress       common.Address
+	StakingTxIndex       uint32
+	ProgramVersion       uint32
+	Status               CandidateStatus
+	StakingEpoch         uint32
+	StakingBlockNum      uint64
+	Shares               *hexutil.Big
+	Released             *hexutil.Big
+	ReleasedHes          *hexutil.Big
+	RestrictingPlan      *hexutil.Big
+	RestrictingPlanHes   *hexutil.Big
+	RewardPer            uint16
+	NextRewardPer        uint16
+	RewardPerChangeEpoch uint32
+	DelegateEpoch        uint32
+	DelegateTotal        *hexutil.Big
+	DelegateTotalHes     *hexutil.Big
 	Description
 }
 
 func (can *CandidateHex) String() string {
-	return fmt.Sprintf(`{"NodeId": "%s","BlsPubKey": "%s","StakingAddress": "%s","BenefitAddress": "%s","StakingTxIndex": %d,"ProgramVersion": %d,"Status": %d,"StakingEpoch": %d,"StakingBlockNum": %d,"Shares": "%s","Released": "%s","ReleasedHes": "%s","RestrictingPlan": "%s","RestrictingPlanHes": "%s","ExternalId": "%s","NodeName": "%s","Website": "%s","Details": "%s"}`,
+	return fmt.Sprintf(`{"NodeId": "%s","BlsPubKey": "%s","StakingAddress": "%s","BenefitAddress": "%s","StakingTxIndex": %d,"ProgramVersion": %d,"Status": %d,"StakingEpoch": %d,"StakingBlockNum": %d,"Shares": "%s","Released": "%s","ReleasedHes": "%s","RestrictingPlan": "%s","RestrictingPlanHes": "%s","RewardPer": %d,"NextRewardPer": %d,"RewardPerChangeEpoch": %d,"DelegateEpoch": %d,"DelegateTotal": "%s","DelegateTotalHes": "%s","ExternalId": "%s","NodeName": "%s","Website": "%s","Details": "%s"}`,
 		fmt.Sprintf("%x", can.NodeId.Bytes()),
 		fmt.Sprintf("%x", can.BlsPubKey.Bytes()),
 		fmt.Sprintf("%x", can.StakingAddress.Bytes()),
@@ -366,6 +594,12 @@ func (can *CandidateHex) String() string {
 		can.ReleasedHes,
 		can.RestrictingPlan,
 		can.RestrictingPlanHes,
+		can.RewardPer,
+		can.NextRewardPer,
+		can.RewardPerChangeEpoch,
+		can.DelegateEpoch,
+		can.DelegateTotal,
+		can.DelegateTotalHes,
 		can.ExternalId,
 		can.NodeName,
 		can.Website,
@@ -401,6 +635,9 @@ const (
 	MaxDetailsLen    = 280
 )
 
+// The max commission rate of the delegate reward (in basis points, 10000 means 100%)
+const MaxRewardPer = 10000
+
 type Description struct {
 	// External Id for the third party to pull the node description (with length limit)
 	ExternalId string
@@ -661,8 +898,6 @@ func CompareDefault(removes NeedRemoveCans, left, right *Validator) int {
 //
 // What is the invalid ?  That are DuplicateSign and lowRatio&invalid and lowVersion and withdrew&NotInEpochValidators
 //
-//
-//
 // Invalid Status: From invalid to valid
 // ProgramVersion: From small to big
 // validaotorTerm: From big to small
@@ -670,7 +905,6 @@ func CompareDefault(removes NeedRemoveCans, left, right *Validator) int {
 // BlockNumber: From big to small
 // TxIndex: From big to small
 //
-//
 // Compare Left And Right
 // 1: Left > Right
 // 0: Left == Right
@@ -771,14 +1005,16 @@ func CompareForDel(removes NeedRemoveCans, left, right *Validator) int {
 			// compare Shares
 			return compareSharesFunc(left, right)
 		default:
-			// compare low ratio delete
+			// compare low ratio delete (or low vote ratio)
 			// compare low ratio
+			lDel := lCan.IsLowRatioDel() || lCan.IsLowVoteRatio()
+			rDel := rCan.IsLowRatioDel() || rCan.IsLowVoteRatio()
 			switch {
-			case lCan.IsLowRatioDel() && !rCan.IsLowRatioDel():
+			case lDel && !rDel:
 				return 1
-			case !lCan.IsLowRatioDel() && rCan.IsLowRatioDel():
+			case !lDel && rDel:
 				return -1
-			case lCan.IsLowRatioDel() && rCan.IsLowRatioDel():
+			case lDel && rDel:
 				// compare Shares
 				return compareSharesFunc(left, right)
 			default:
@@ -951,15 +1187,24 @@ type Delegation struct {
 	RestrictingPlan *big.Int
 	// The delegate von  is RestrictingPlan for hesitant epoch (in hesitation)
 	RestrictingPlanHes *big.Int
+	// The delegate reward that has been settled but not yet withdrawn
+	CumulativeIncome *big.Int
+	// The cumulative reward per unit of the candidate at the last settlement
+	RewardPerUnit *big.Int
+
+	// stored in the layout before the delegate reward
+	legacy bool
 }
 
 func (del *Delegation) String() string {
-	return fmt.Sprintf(`{"DelegateEpoch": "%d","Released": "%d","ReleasedHes": %d,"RestrictingPlan": %d,"RestrictingPlanHes": %d}`,
+	return fmt.Sprintf(`{"DelegateEpoch": "%d","Released": "%d","ReleasedHes": %d,"RestrictingPlan": %d,"RestrictingPlanHes": %d,"CumulativeIncome": %d,"RewardPerUnit": %d}`,
 		del.DelegateEpoch,
 		del.Released,
 		del.ReleasedHes,
 		del.RestrictingPlan,
-		del.RestrictingPlanHes)
+		del.RestrictingPlanHes,
+		del.CumulativeIncome,
+		del.RewardPerUnit)
 }
 
 func (del *Delegation) IsNotEmpty() bool {
@@ -970,6 +1215,63 @@ func (del *Delegation) IsEmpty() bool {
 	return nil == del
 }
 
+// IsLegacy reports whether the Delegation is stored in the layout before the delegate reward
+func (del *Delegation) IsLegacy() bool {
+	return del.legacy
+}
+
+// SetLegacy sets the layout the Delegation is stored in,
+// the layout before the delegate reward is kept until the delegate reward is activated.
+func (del *Delegation) SetLegacy(legacy bool) {
+	del.legacy = legacy
+}
+
+// delegationRLP is the Delegation decoded by the default rules of rlp
+type delegationRLP Delegation
+
+// delegationV0 is the layout of the Delegation stored before the delegate reward
+type delegationV0 struct {
+	DelegateEpoch      uint32
+	Released           *big.Int
+	ReleasedHes        *big.Int
+	RestrictingPlan    *big.Int
+	RestrictingPlanHes *big.Int
+}
+
+// DecodeRLP implements rlp.Decoder, the Delegation stored before the delegate reward can be decoded too.
+// It has shared no reward yet.
+func (del *Delegation) DecodeRLP(s *rlp.Stream) error {
+	var v0 delegationV0
+	if legacy, err := decodeLegacyRLP(s, 5, (*delegationRLP)(del), &v0); nil != err || !legacy {
+		return err
+	}
+	*del = Delegation{
+		DelegateEpoch:      v0.DelegateEpoch,
+		Released:           v0.Released,
+		ReleasedHes:        v0.ReleasedHes,
+		RestrictingPlan:    v0.RestrictingPlan,
+		RestrictingPlanHes: v0.RestrictingPlanHes,
+		CumulativeIncome:   new(big.Int),
+		RewardPerUnit:      new(big.Int),
+		legacy:             true,
+	}
+	return nil
+}
+
+// EncodeRLP implements rlp.Encoder, the legacy Delegation is encoded in the layout before the delegate reward
+func (del *Delegation) EncodeRLP(w io.Writer) error {
+	if !del.legacy {
+		return rlp.Encode(w, (*delegationRLP)(del))
+	}
+	return rlp.Encode(w, &delegationV0{
+		DelegateEpoch:      del.DelegateEpoch,
+		Released:           del.Released,
+		ReleasedHes:        del.ReleasedHes,
+		RestrictingPlan:    del.RestrictingPlan,
+		RestrictingPlanHes: del.RestrictingPlanHes,
+	})
+}
+
 type DelegationHex struct {
 	// The epoch number at delegate or edit
 	DelegateEpoch uint32
@@ -981,15 +1283,18 @@ type DelegationHex struct {
 	RestrictingPlan *hexutil.Big
 	// The delegate von  is RestrictingPlan for hesitant epoch (in hesitation)
 	RestrictingPlanHes *hexutil.Big
+	// The delegate reward that has been settled but not yet withdrawn
+	CumulativeIncome *hexutil.Big
 }
 
 func (delHex *DelegationHex) String() string {
-	return fmt.Sprintf(`{"DelegateEpoch": "%d","Released": "%s","ReleasedHes": %s,"RestrictingPlan": %s,"RestrictingPlanHes": %s}`,
+	return fmt.Sprintf(`{"DelegateEpoch": "%d","Released": "%s","ReleasedHes": %s,"RestrictingPlan": %s,"RestrictingPlanHes": %s,"CumulativeIncome": %s}`,
 		delHex.DelegateEpoch,
 		delHex.Released,
 		delHex.ReleasedHes,
 		delHex.RestrictingPlan,
-		delHex.RestrictingPlanHes)
+		delHex.RestrictingPlanHes,
+		delHex.CumulativeIncome)
 }
 
 func (del *DelegationHex) IsNotEmpty() bool {
@@ -1008,7 +1313,7 @@ type DelegationEx struct {
 }
 
 func (dex *DelegationEx) String() string {
-	return fmt.Sprintf(`{"Addr": "%s","NodeId": "%s","StakingBlockNum": "%d","DelegateEpoch": "%d","Released": "%s","ReleasedHes": %s,"RestrictingPlan": %s,"RestrictingPlanHes": %s}`,
+	return fmt.Sprintf(`{"Addr": "%s","NodeId": "%s","StakingBlockNum": "%d","DelegateEpoch": "%d","Released": "%s","ReleasedHes": %s,"RestrictingPlan": %s,"RestrictingPlanHes": %s,"CumulativeIncome": %s}`,
 		dex.Addr.String(),
 		fmt.Sprintf("%x", dex.NodeId.Bytes()),
 		dex.StakingBlockNum,
@@ -1016,7 +1321,8 @@ func (dex *DelegationEx) String() string {
 		dex.Released,
 		dex.ReleasedHes,
 		dex.RestrictingPlan,
-		dex.RestrictingPlanHes)
+		dex.RestrictingPlanHes,
+		dex.CumulativeIncome)
 }
 
 func (dex *DelegationEx) IsNotEmpty() bool {
@@ -1066,6 +1372,179 @@ func (queue DelRelatedQueue) String() string {
 	return "[" + strings.Join(arr, ",") + "]"
 }
 
+// The record of the delegation von which had been moved from the source candidate to the target candidate.
+// It is kept with the source candidate for `MaxEvidenceAge` epochs,
+// so that the moved von can still be slashed for the offence of the source candidate before the moving.
+type Redelegation struct {
+	// The delegate address
+	DelAddr common.Address
+	// The target candidate which the von had been moved to
+	DstNodeId          discover.NodeID
+	DstStakingBlockNum uint64
+	// The blockNumber of moving
+	BlockNumber uint64
+	// The moved von which is circulating (in effect)
+	Released *big.Int
+	// The moved von which is RestrictingPlan (in effect)
+	RestrictingPlan *big.Int
+}
+
+func (r *Redelegation) String() string {
+	return fmt.Sprintf(`{"DelAddr": "%s","DstNodeId": "%s","DstStakingBlockNum": %d,"BlockNumber": %d,"Released": %d,"RestrictingPlan": %d}`,
+		r.DelAddr.String(),
+		fmt.Sprintf("%x", r.DstNodeId.Bytes()),
+		r.DstStakingBlockNum,
+		r.BlockNumber,
+		r.Released,
+		r.RestrictingPlan)
+}
+
+type RedelegationQueue []*Redelegation
+
+func (queue RedelegationQueue) IsEmpty() bool {
+	return len(queue) == 0
+}
+
+func (queue RedelegationQueue) String() string {
+	arr := make([]string, len(queue))
+	for i, r := range queue {
+		arr[i] = r.String()
+	}
+	return "[" + strings.Join(arr, ",") + "]"
+}
+
+// The upper limits of the staking controller
+const (
+	MaxControllerOperators       = 20
+	MaxPendingActions            = 16
+	MaxPendingActionsPerOperator = 4
+	// The pending action is dropped if it is not approved by enough operators within the epochs
+	PendingActionExpiredEpochs = 2
+)
+
+// The multi-signature controller of the candidate.
+// Once it is registered, the staking operations of the candidate (editCandidate, increaseStaking,
+// withdrewStaking, unjail and setStakingController) are submitted by the operators instead of the
+// staking address, and they are pending until `Threshold` operators approve them.
+// The von is still paid and refunded by the staking address.
+type StakingController struct {
+	// The stakingBlockNum of the candidate which registered the controller,
+	// the controller is dropped when the candidate withdrew and staked again
+	StakingBlockNum uint64
+	Operators       []common.Address
+	Threshold       uint32
+}
+
+func (c *StakingController) IsOperator(addr common.Address) bool {
+	for _, operator := range c.Operators {
+		if operator == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *StakingController) String() string {
+	arr := make([]string, len(c.Operators))
+	for i, operator := range c.Operators {
+		arr[i] = `"` + operator.String() + `"`
+	}
+	return fmt.Sprintf(`{"StakingBlockNum": %d,"Operators": [%s],"Threshold": %d}`,
+		c.StakingBlockNum,
+		strings.Join(arr, ","),
+		c.Threshold)
+}
+
+// The staking operation of the controlled candidate waiting for the approvals of the operators.
+type PendingAction struct {
+	// The hash of the stakingBlockNum of the candidate and the Input
+	ActionId common.Hash
+	// The fn type of the operation
+	FuncType uint16
+	// The rlp encoded input of the staking contract, it is executed as it is once approved
+	Input []byte
+	// The operator submitted the operation
+	Proposer    common.Address
+	BlockNumber uint64
+	// The operators approved the operation, including the proposer
+	Approvals []common.Address
+}
+
+// IsExpired returns true if the action is submitted PendingActionExpiredEpochs epochs before the block.
+func (a *PendingAction) IsExpired(blockNumber uint64) bool {
+	return xutil.CalculateEpoch(a.BlockNumber)+PendingActionExpiredEpochs <= xutil.CalculateEpoch(blockNumber)
+}
+
+func (a *PendingAction) IsApprovedBy(addr common.Address) bool {
+	for _, approval := range a.Approvals {
+		if approval == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *PendingAction) String() string {
+	arr := make([]string, len(a.Approvals))
+	for i, approval := range a.Approvals {
+		arr[i] = `"` + approval.String() + `"`
+	}
+	return fmt.Sprintf(`{"ActionId": "%s","FuncType": %d,"Input": "%x","Proposer": "%s","BlockNumber": %d,"Approvals": [%s]}`,
+		a.ActionId.Hex(),
+		a.FuncType,
+		a.Input,
+		a.Proposer.String(),
+		a.BlockNumber,
+		strings.Join(arr, ","))
+}
+
+// The pending actions of the controlled candidate, they are dropped along with the controller.
+type PendingActionQueue struct {
+	StakingBlockNum uint64
+	Actions         []*PendingAction
+}
+
+func (queue *PendingActionQueue) Find(actionId common.Hash) (int, *PendingAction) {
+	for i, action := range queue.Actions {
+		if action.ActionId == actionId {
+			return i, action
+		}
+	}
+	return -1, nil
+}
+
+// CountByProposer returns the number of the actions submitted by the operator.
+func (queue *PendingActionQueue) CountByProposer(operator common.Address) int {
+	count := 0
+	for _, action := range queue.Actions {
+		if action.Proposer == operator {
+			count++
+		}
+	}
+	return count
+}
+
+// DropExpired drops the actions expired at the block, and returns true if any is dropped.
+func (queue *PendingActionQueue) DropExpired(blockNumber uint64) bool {
+	actions := make([]*PendingAction, 0, len(queue.Actions))
+	for _, action := range queue.Actions {
+		if !action.IsExpired(blockNumber) {
+			actions = append(actions, action)
+		}
+	}
+	dropped := len(actions) != len(queue.Actions)
+	queue.Actions = actions
+	return dropped
+}
+
+func (queue *PendingActionQueue) String() string {
+	arr := make([]string, len(queue.Actions))
+	for i, action := range queue.Actions {
+		arr[i] = action.String()
+	}
+	return fmt.Sprintf(`{"StakingBlockNum": %d,"Actions": [%s]}`, queue.StakingBlockNum, strings.Join(arr, ","))
+}
+
 type UnStakeItem struct {
 	// this is the nodeAddress
 	NodeAddress     common.Address
@@ -1116,10 +1595,13 @@ type SlashNodeItem struct {
 	SlashType CandidateStatus
 	// the benefit adrr who will receive the slash amount of von
 	BenefitAddr common.Address
+	// the blockNumber of the offence,
+	// the von moved away by redelegate since this block will be slashed too
+	OffenceBlockNum uint64
 }
 
 func (s *SlashNodeItem) String() string {
-	return fmt.Sprintf(`{"nodeId": %s, "amount": %d, "slashType": %d, "benefitAddr": %s}`, s.NodeId.String(), s.Amount, s.SlashType, s.BenefitAddr.Hex())
+	return fmt.Sprintf(`{"nodeId": %s, "amount": %d, "slashType": %d, "benefitAddr": %s, "offenceBlockNum": %d}`, s.NodeId.String(), s.Amount, s.SlashType, s.BenefitAddr.Hex(), s.OffenceBlockNum)
 }
 
 type SlashQueue []*SlashNodeItem
@@ -50,6 +50,11 @@ const (
 	PositiveInfinity          = "+∞"
 	CeilUnStakeFreezeDuration = 28 * 4
 	CeilMaxEvidenceAge        = CeilUnStakeFreezeDuration - 1
+	CeilVoteSignedWindow      = 10000
+	CeilJailDuration          = CeilUnStakeFreezeDuration
+	CeilHesitateRatio         = CeilUnStakeFreezeDuration
+	CeilTallyMode             = 1
+	CeilProposalVoteDuration  = 28 * 24 * 3600
 )
 
 var (
@@ -93,6 +98,10 @@ type slashingConfig struct {
 	DuplicateSignReportReward  uint32 `json:"duplicateSignReportReward"`  // The percentage of rewards for whistleblowers, calculated from the penalty
 	MaxEvidenceAge             uint32 `json:"maxEvidenceAge"`             // Validity period of evidence (unit is  epochs)
 	SlashBlocksReward          uint32 `json:"slashBlocksReward"`          // the number of blockReward to slashing per round
+	VoteSignedWindow           uint32 `json:"voteSignedWindow"`           // the number of recent QCs counted for the vote participation of a validator, zero disables it
+	MinVoteSignedRatio         uint32 `json:"minVoteSignedRatio"`         // the minimum percentage of the QCs signed by a validator within the window
+	SlashFractionLowVote       uint32 `json:"slashFractionLowVote"`       // Proportion of fines when the vote participation is too low
+	JailDuration               uint32 `json:"jailDuration"`               // The minimum jail duration of the jailed candidate (unit is epochs)
 
 }
 
@@ -107,6 +116,11 @@ type governanceConfig struct {
 	ParamProposalVoteDurationSeconds   uint64  `json:"paramProposalVoteDurationSeconds"`   // voting duration, it will count into Epoch Round.
 	ParamProposalVoteRate              float64 `json:"paramProposalVoteRate"`              // the param proposal will pass if the vote rate exceeds this value.
 	ParamProposalSupportRate           float64 `json:"paramProposalSupportRate"`           // the param proposal will pass if the vote support reaches this value.
+	SpendProposalVoteDurationSeconds   uint64  `json:"spendProposalVoteDurationSeconds"`   // voting duration, it will count into Epoch Round.
+	SpendProposalVoteRate              float64 `json:"spendProposalVoteRate"`              // the spend proposal will pass if the vote rate exceeds this value.
+	SpendProposalSupportRate           float64 `json:"spendProposalSupportRate"`           // the spend proposal will pass if the vote support reaches this value.
+	TextProposalTallyMode              uint32  `json:"textProposalTallyMode"`              // the text proposal is tallied by, 0: one vote per verifier, 1: the stake of the verifiers and delegators
+	ParamProposalTallyMode             uint32  `json:"paramProposalTallyMode"`             // the param proposal is tallied by, 0: one vote per verifier, 1: the stake of the verifiers and delegators
 }
 
 type rewardConfig struct {
@@ -146,6 +160,24 @@ func GetEc(netId int8) *EconomicModel {
 	return ec
 }
 
+// CopyEconomicModel returns a copy of the economic model, the big integers are copied as well
+func CopyEconomicModel() *EconomicModel {
+	cpy := *ec
+	if nil != ec.Staking.StakeThreshold {
+		cpy.Staking.StakeThreshold = new(big.Int).Set(ec.Staking.StakeThreshold)
+	}
+	if nil != ec.Staking.OperatingThreshold {
+		cpy.Staking.OperatingThreshold = new(big.Int).Set(ec.Staking.OperatingThreshold)
+	}
+	if nil != ec.InnerAcc.PlatONFundBalance {
+		cpy.InnerAcc.PlatONFundBalance = new(big.Int).Set(ec.InnerAcc.PlatONFundBalance)
+	}
+	if nil != ec.InnerAcc.CDFBalance {
+		cpy.InnerAcc.CDFBalance = new(big.Int).Set(ec.InnerAcc.CDFBalance)
+	}
+	return &cpy
+}
+
 func ResetEconomicDefaultConfig(newEc *EconomicModel) {
 	ec = newEc
 }
@@ -188,6 +220,10 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				DuplicateSignReportReward:  uint32(50),
 				MaxEvidenceAge:             uint32(27),
 				SlashBlocksReward:          uint32(0),
+				VoteSignedWindow:           uint32(1000),
+				MinVoteSignedRatio:         uint32(50),
+				SlashFractionLowVote:       uint32(10),
+				JailDuration:               uint32(2),
 			},
 			Gov: governanceConfig{
 				VersionProposalVoteDurationSeconds: uint64(14 * 24 * 3600),
@@ -201,6 +237,11 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				ParamProposalVoteDurationSeconds: uint64(14 * 24 * 3600),
 				ParamProposalVoteRate:            float64(0.50),
 				ParamProposalSupportRate:         float64(0.667),
+				SpendProposalVoteDurationSeconds: uint64(14 * 24 * 3600),
+				SpendProposalVoteRate:            float64(0.50),
+				SpendProposalSupportRate:         float64(0.667),
+				TextProposalTallyMode:            uint32(0),
+				ParamProposalTallyMode:           uint32(0),
 			},
 			Reward: rewardConfig{
 				NewBlockRate:         50,
@@ -235,6 +276,10 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				DuplicateSignReportReward:  uint32(50),
 				MaxEvidenceAge:             uint32(1),
 				SlashBlocksReward:          uint32(0),
+				VoteSignedWindow:           uint32(1000),
+				MinVoteSignedRatio:         uint32(50),
+				SlashFractionLowVote:       uint32(10),
+				JailDuration:               uint32(1),
 			},
 			Gov: governanceConfig{
 				VersionProposalVoteDurationSeconds: uint64(160),
@@ -248,6 +293,11 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				ParamProposalVoteDurationSeconds: uint64(160),
 				ParamProposalVoteRate:            float64(0.50),
 				ParamProposalSupportRate:         float64(0.667),
+				SpendProposalVoteDurationSeconds: uint64(160),
+				SpendProposalVoteRate:            float64(0.50),
+				SpendProposalSupportRate:         float64(0.667),
+				TextProposalTallyMode:            uint32(0),
+				ParamProposalTallyMode:           uint32(0),
 			},
 			Reward: rewardConfig{
 				NewBlockRate:         50,
@@ -283,6 +333,10 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				DuplicateSignReportReward:  uint32(50),
 				MaxEvidenceAge:             uint32(1),
 				SlashBlocksReward:          uint32(0),
+				VoteSignedWindow:           uint32(20),
+				MinVoteSignedRatio:         uint32(50),
+				SlashFractionLowVote:       uint32(10),
+				JailDuration:               uint32(1),
 			},
 			Gov: governanceConfig{
 				VersionProposalVoteDurationSeconds: uint64(160),
@@ -296,6 +350,11 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				ParamProposalVoteDurationSeconds: uint64(160),
 				ParamProposalVoteRate:            float64(0.50),
 				ParamProposalSupportRate:         float64(0.667),
+				SpendProposalVoteDurationSeconds: uint64(160),
+				SpendProposalVoteRate:            float64(0.50),
+				SpendProposalSupportRate:         float64(0.667),
+				TextProposalTallyMode:            uint32(0),
+				ParamProposalTallyMode:           uint32(0),
 			},
 			Reward: rewardConfig{
 				NewBlockRate:         50,
@@ -335,6 +394,13 @@ func CheckMaxValidators(num int) error {
 	return nil
 }
 
+func CheckHesitateRatio(ratio int) error {
+	if ratio < 1 || ratio > CeilHesitateRatio {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The HesitateRatio must be [%d, %d]", 1, CeilHesitateRatio))
+	}
+	return nil
+}
+
 func CheckUnStakeFreezeDuration(duration, maxEvidenceAge int) error {
 	if duration <= maxEvidenceAge || duration > CeilUnStakeFreezeDuration {
 		return common.InvalidParameter.Wrap(fmt.Sprintf("The UnStakeFreezeDuration must be (%d, %d]", maxEvidenceAge, CeilUnStakeFreezeDuration))
@@ -372,6 +438,73 @@ func CheckSlashBlocksReward(rewards int) error {
 	return nil
 }
 
+func CheckVoteSignedWindow(window int) error {
+	if window < Zero || window > CeilVoteSignedWindow {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The VoteSignedWindow must be [%d, %d]", Zero, CeilVoteSignedWindow))
+	}
+	return nil
+}
+
+func CheckMinVoteSignedRatio(ratio int) error {
+	if ratio < Zero || ratio > Hundred {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The MinVoteSignedRatio must be [%d, %d]", Zero, Hundred))
+	}
+	return nil
+}
+
+func CheckSlashFractionLowVote(fraction int) error {
+	if fraction < Zero || fraction > TenThousand {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The SlashFractionLowVote must be [%d, %d]", Zero, TenThousand))
+	}
+	return nil
+}
+
+func CheckJailDuration(duration int) error {
+	if duration < Zero || duration > CeilJailDuration {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The JailDuration must be [%d, %d]", Zero, CeilJailDuration))
+	}
+	return nil
+}
+
+func CheckTallyMode(mode int) error {
+	if mode < Zero || mode > CeilTallyMode {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The TallyMode must be [%d, %d]", Zero, CeilTallyMode))
+	}
+	return nil
+}
+
+func CheckPlatONFoundationYear(year int) error {
+	if year < 1 {
+		return common.InvalidParameter.Wrap("The PlatONFoundationYear must be greater than or equal to 1")
+	}
+	return nil
+}
+
+func CheckNewBlockRate(rate int) error {
+	if rate < Zero || rate > Hundred {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The NewBlockRate must be [%d, %d]", Zero, Hundred))
+	}
+	return nil
+}
+
+// CheckProposalVoteDuration checks the voting duration of the proposals,
+// it must last one round at least, the round is a consensus round or an epoch.
+func CheckProposalVoteDuration(seconds, roundSeconds uint64) error {
+	if seconds < roundSeconds || seconds > CeilProposalVoteDuration {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The proposal vote duration must be [%d, %d]", roundSeconds, CeilProposalVoteDuration))
+	}
+	return nil
+}
+
+// CheckProposalRate checks the vote rate and support rate of the proposals
+func CheckProposalRate(rate float64) error {
+	// the vote rate must be exceeded, it's never exceeded if the rate is 1
+	if rate <= Zero || rate >= 1 {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The proposal rate must be (%d, %d)", Zero, 1))
+	}
+	return nil
+}
+
 func CheckEconomicModel() error {
 	if nil == ec {
 		return errors.New("EconomicModel config is nil")
@@ -424,20 +557,20 @@ func CheckEconomicModel() error {
 		return err
 	}
 
-	if ec.Staking.HesitateRatio < 1 {
-		return errors.New("The HesitateRatio must be greater than or equal to 1")
+	if err := CheckHesitateRatio(int(ec.Staking.HesitateRatio)); nil != err {
+		return err
 	}
 
 	if err := CheckUnStakeFreezeDuration(int(ec.Staking.UnStakeFreezeDuration), int(ec.Slashing.MaxEvidenceAge)); nil != err {
 		return err
 	}
 
-	if ec.Reward.PlatONFoundationYear < 1 {
-		return errors.New("The PlatONFoundationYear must be greater than or equal to 1")
+	if err := CheckPlatONFoundationYear(int(ec.Reward.PlatONFoundationYear)); nil != err {
+		return err
 	}
 
-	if ec.Reward.NewBlockRate < 0 || ec.Reward.NewBlockRate > 100 {
-		return errors.New("The NewBlockRate must be greater than or equal to 0 and less than or equal to 100")
+	if err := CheckNewBlockRate(int(ec.Reward.NewBlockRate)); nil != err {
+		return err
 	}
 
 	if err := CheckSlashFractionDuplicateSign(int(ec.Slashing.SlashFractionDuplicateSign)); nil != err {
@@ -456,6 +589,30 @@ func CheckEconomicModel() error {
 		return err
 	}
 
+	if err := CheckVoteSignedWindow(int(ec.Slashing.VoteSignedWindow)); nil != err {
+		return err
+	}
+
+	if err := CheckMinVoteSignedRatio(int(ec.Slashing.MinVoteSignedRatio)); nil != err {
+		return err
+	}
+
+	if err := CheckSlashFractionLowVote(int(ec.Slashing.SlashFractionLowVote)); nil != err {
+		return err
+	}
+
+	if err := CheckJailDuration(int(ec.Slashing.JailDuration)); nil != err {
+		return err
+	}
+
+	if err := CheckTallyMode(int(ec.Gov.TextProposalTallyMode)); nil != err {
+		return err
+	}
+
+	if err := CheckTallyMode(int(ec.Gov.ParamProposalTallyMode)); nil != err {
+		return err
+	}
+
 	return nil
 }
 
@@ -543,6 +700,22 @@ func SlashBlocksReward() uint32 {
 	return ec.Slashing.SlashBlocksReward
 }
 
+func VoteSignedWindow() uint32 {
+	return ec.Slashing.VoteSignedWindow
+}
+
+func MinVoteSignedRatio() uint32 {
+	return ec.Slashing.MinVoteSignedRatio
+}
+
+func SlashFractionLowVote() uint32 {
+	return ec.Slashing.SlashFractionLowVote
+}
+
+func JailDuration() uint32 {
+	return ec.Slashing.JailDuration
+}
+
 /******
  * Reward config
  ******/
@@ -607,6 +780,26 @@ func ParamProposal_SupportRate() float64 {
 	return ec.Gov.ParamProposalSupportRate
 }
 
+func TextProposal_TallyMode() uint32 {
+	return ec.Gov.TextProposalTallyMode
+}
+
+func ParamProposal_TallyMode() uint32 {
+	return ec.Gov.ParamProposalTallyMode
+}
+
+func SpendProposalVote_DurationSeconds() uint64 {
+	return ec.Gov.SpendProposalVoteDurationSeconds
+}
+
+func SpendProposal_VoteRate() float64 {
+	return ec.Gov.SpendProposalVoteRate
+}
+
+func SpendProposal_SupportRate() float64 {
+	return ec.Gov.SpendProposalSupportRate
+}
+
 /******
  * Inner Account Config
  ******/
@@ -117,6 +117,28 @@ func AddLog(state StateDB, blockNumber uint64, contractAddr common.Address, even
 	})
 }
 
+// AddEventLog adds a typed event log of the system contract,
+// the topics is [Keccak256(event), indexed...] and the data is RLP(data).
+func AddEventLog(state StateDB, blockNumber uint64, contractAddr common.Address, event string, indexed []common.Hash, data []interface{}) {
+
+	buf := new(bytes.Buffer)
+	if err := rlp.Encode(buf, data); nil != err {
+		log.Error("Cannot RlpEncode the event log data", "event", event, "data", data)
+		panic("Cannot RlpEncode the event log data")
+	}
+
+	topics := make([]common.Hash, 0, len(indexed)+1)
+	topics = append(topics, common.BytesToHash(crypto.Keccak256([]byte(event))))
+	topics = append(topics, indexed...)
+
+	state.AddLog(&types.Log{
+		Address:     contractAddr,
+		Topics:      topics,
+		Data:        buf.Bytes(),
+		BlockNumber: blockNumber,
+	})
+}
+
 func PrintObject(s string, obj interface{}) {
 	objs, _ := json.Marshal(obj)
 	log.Debug(s + " == " + string(objs))